@@ -0,0 +1,341 @@
+// Package block is the registry of vanilla blocks: every block, its
+// properties, its default state, its light and the global state IDs that
+// palettes and the protocol use. The tables in blocks_gen.go are generated
+// from the blocks.json data reports and light.json in the reports
+// directory; run go generate after replacing them.
+package block
+
+//go:generate go run ./internal/gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// Air is the state ID of minecraft:air.
+const Air uint32 = 0
+
+// Property is a block state property and the values it takes, in the order
+// state IDs count them.
+type Property struct {
+	Name   string
+	Values []string
+}
+
+func (p *Property) index(value string) int {
+	for i, v := range p.Values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// Block is a block type. Its states have consecutive IDs from MinState, in
+// the order of every combination of property values with the last property
+// changing fastest.
+type Block struct {
+	Name       string
+	Properties []*Property
+	MinState   uint32
+	// DefaultState is the state the block is placed in when none is given.
+	DefaultState uint32
+	// Light is the light the block gives off in its brightest state and
+	// Opacity how much light passing through it loses, 15 for blocks that
+	// stop it. LightEmission and LightOpacity give them for one state.
+	Light, Opacity uint8
+}
+
+// States returns the number of states the block has.
+func (b *Block) States() int {
+	n := 1
+	for _, p := range b.Properties {
+		n *= len(p.Values)
+	}
+	return n
+}
+
+// Property returns the property with the given name.
+func (b *Block) Property(name string) (*Property, bool) {
+	for _, p := range b.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// State returns the state with the given property values. Properties that
+// are left out keep the value of the default state.
+func (b *Block) State(values map[string]string) (uint32, error) {
+	id := b.DefaultState
+	for name, value := range values {
+		var err error
+		if id, err = b.with(id, name, value); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// With returns state id of the block with one property changed.
+func (b *Block) With(id uint32, name, value string) (uint32, error) {
+	if !b.Has(id) {
+		return 0, fmt.Errorf("block: state %d is not %s", id, b.Name)
+	}
+	return b.with(id, name, value)
+}
+
+func (b *Block) with(id uint32, name, value string) (uint32, error) {
+	stride := uint32(1)
+	for i := len(b.Properties) - 1; i >= 0; i-- {
+		p := b.Properties[i]
+		n := uint32(len(p.Values))
+		if p.Name == name {
+			v := p.index(value)
+			if v < 0 {
+				return 0, fmt.Errorf("block: %s has no %s=%s", b.Name, name, value)
+			}
+			old := (id - b.MinState) / stride % n
+			return id - old*stride + uint32(v)*stride, nil
+		}
+		stride *= n
+	}
+	return 0, fmt.Errorf("block: %s has no property %s", b.Name, name)
+}
+
+// Has reports whether id is a state of the block.
+func (b *Block) Has(id uint32) bool {
+	return id >= b.MinState && id < b.MinState+uint32(b.States())
+}
+
+// Value returns the value of a property in state id of the block.
+func (b *Block) Value(id uint32, name string) (string, bool) {
+	values := b.Values(id)
+	for i, p := range b.Properties {
+		if p.Name == name {
+			return values[i], true
+		}
+	}
+	return "", false
+}
+
+// Values returns the property values of state id of the block, in the order
+// of its properties.
+func (b *Block) Values(id uint32) []string {
+	values := make([]string, len(b.Properties))
+	offset := id - b.MinState
+	for i := len(b.Properties) - 1; i >= 0; i-- {
+		p := b.Properties[i]
+		n := uint32(len(p.Values))
+		values[i] = p.Values[offset%n]
+		offset /= n
+	}
+	return values
+}
+
+var byName = make(map[string]*Block, len(blocks))
+
+func init() {
+	for i := range blocks {
+		byName[blocks[i].Name] = &blocks[i]
+	}
+}
+
+// Lookup returns the block with the given name. The minecraft namespace may
+// be left out.
+func Lookup(name string) (*Block, bool) {
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+	b, ok := byName[name]
+	return b, ok
+}
+
+// Blocks returns every block in state ID order.
+func Blocks() []*Block {
+	list := make([]*Block, len(blocks))
+	for i := range blocks {
+		list[i] = &blocks[i]
+	}
+	return list
+}
+
+// StateCount returns the number of block states, one more than the highest
+// state ID.
+func StateCount() int { return stateCount }
+
+// ByState returns the block a state ID belongs to.
+func ByState(id uint32) (*Block, bool) {
+	if int(id) >= stateCount {
+		return nil, false
+	}
+	i := sort.Search(len(blocks), func(i int) bool { return blocks[i].MinState > id }) - 1
+	return &blocks[i], true
+}
+
+// IsAir reports whether a state is air, cave air or void air.
+func IsAir(id uint32) bool {
+	return id == Air || id == caveAir || id == voidAir
+}
+
+var (
+	caveAir = mustDefault("minecraft:cave_air")
+	voidAir = mustDefault("minecraft:void_air")
+)
+
+func mustDefault(name string) uint32 {
+	for _, b := range blocks {
+		if b.Name == name {
+			return b.DefaultState
+		}
+	}
+	panic("block: no " + name)
+}
+
+// Format returns a state in the syntax of commands, listing every property:
+// minecraft:oak_stairs[facing=north,half=top,shape=straight,waterlogged=true].
+func Format(id uint32) string {
+	b, ok := ByState(id)
+	if !ok {
+		return fmt.Sprintf("unknown[%d]", id)
+	}
+	if len(b.Properties) == 0 {
+		return b.Name
+	}
+	var sb strings.Builder
+	sb.WriteString(b.Name)
+	sb.WriteByte('[')
+	for i, v := range b.Values(id) {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(b.Properties[i].Name)
+		sb.WriteByte('=')
+		sb.WriteString(v)
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// Parse reads a state in the syntax of commands, such as
+// oak_stairs[facing=east]. Properties that are left out keep the value of
+// the default state; unknown blocks, properties and values are errors.
+func Parse(s string) (uint32, error) {
+	name, props, hasProps := strings.Cut(strings.TrimSpace(s), "[")
+	b, ok := Lookup(strings.TrimSpace(name))
+	if !ok {
+		return 0, fmt.Errorf("block: unknown block %q", name)
+	}
+	id := b.DefaultState
+	if !hasProps {
+		return id, nil
+	}
+	props, ok = strings.CutSuffix(strings.TrimSpace(props), "]")
+	if !ok {
+		return 0, fmt.Errorf("block: unterminated properties in %q", s)
+	}
+	if strings.TrimSpace(props) == "" {
+		return id, nil
+	}
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(props, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return 0, fmt.Errorf("block: expected property=value in %q", s)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if seen[key] {
+			return 0, fmt.Errorf("block: property %s given twice in %q", key, s)
+		}
+		seen[key] = true
+		var err error
+		if id, err = b.with(id, key, value); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// ToNBT returns a state in the form chunks and structures save it: its Name
+// and, for blocks that have any, its Properties.
+func ToNBT(id uint32) (*nbt.CompoundTag, bool) {
+	b, ok := ByState(id)
+	if !ok {
+		return nil, false
+	}
+	tag := nbt.NewCompoundTag()
+	tag.Put("Name", &nbt.StringTag{Value: b.Name})
+	if len(b.Properties) > 0 {
+		props := nbt.NewCompoundTag()
+		for i, v := range b.Values(id) {
+			props.Put(b.Properties[i].Name, &nbt.StringTag{Value: v})
+		}
+		tag.Put("Properties", props)
+	}
+	return tag, true
+}
+
+// FromNBT reads a state in its saved form.
+func FromNBT(tag *nbt.CompoundTag) (uint32, error) {
+	name, ok := tag.GetString("Name")
+	if !ok {
+		return 0, fmt.Errorf("block: state without a name")
+	}
+	b, ok := Lookup(name)
+	if !ok {
+		return 0, fmt.Errorf("block: unknown block %q", name)
+	}
+	id := b.DefaultState
+	props, ok := tag.GetCompound("Properties")
+	if !ok {
+		return id, nil
+	}
+	for key := range props.Value {
+		value, ok := props.GetString(key)
+		if !ok {
+			return 0, fmt.Errorf("block: property %s of %s is not a string", key, name)
+		}
+		var err error
+		if id, err = b.with(id, key, value); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// stateRun maps n consecutive state IDs from from on to consecutive IDs of
+// another version from to on.
+type stateRun struct {
+	from, to, n uint32
+}
+
+// stateTable describes the block states of a protocol version.
+type stateTable struct {
+	count int
+	// runs is nil when the version's IDs are the ones of this package.
+	runs []stateRun
+}
+
+// ProtocolStates returns how many block states a protocol version has and
+// how this package's state IDs map to that version's. The mapping is nil
+// when the IDs are the same; states the version lacks map to air.
+func ProtocolStates(protocol int32) (count int, toNetwork func(id uint32) uint32, ok bool) {
+	t, ok := protocolStates[protocol]
+	if !ok {
+		return 0, nil, false
+	}
+	if t.runs == nil {
+		return t.count, nil, true
+	}
+	return t.count, func(id uint32) uint32 {
+		i := sort.Search(len(t.runs), func(i int) bool { return t.runs[i].from > id }) - 1
+		if i < 0 || id-t.runs[i].from >= t.runs[i].n {
+			return Air
+		}
+		return t.runs[i].to + id - t.runs[i].from
+	}, true
+}
@@ -0,0 +1,199 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/Advik-B/Golem/nbt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	assert.Equal(t, 26684, StateCount())
+	air, ok := Lookup("air")
+	require.True(t, ok)
+	assert.Equal(t, Air, air.DefaultState)
+	assert.True(t, IsAir(Air))
+	caveAir, _ := Lookup("minecraft:cave_air")
+	assert.True(t, IsAir(caveAir.DefaultState))
+	stone, _ := Lookup("stone")
+	assert.Equal(t, uint32(1), stone.DefaultState)
+	assert.False(t, IsAir(stone.DefaultState))
+
+	stairs, ok := Lookup("minecraft:oak_stairs")
+	require.True(t, ok)
+	assert.Equal(t, uint32(2874), stairs.MinState)
+	assert.Equal(t, uint32(2885), stairs.DefaultState)
+	assert.Equal(t, 80, stairs.States())
+	for _, id := range []uint32{2874, 2885, 2953} {
+		b, ok := ByState(id)
+		require.True(t, ok)
+		assert.Equal(t, stairs, b)
+	}
+	_, ok = ByState(uint32(StateCount()))
+	assert.False(t, ok)
+
+	// Every state belongs to the block before the next one's first state.
+	blocks := Blocks()
+	for i, b := range blocks[1:] {
+		assert.Equal(t, blocks[i].MinState+uint32(blocks[i].States()), b.MinState, b.Name)
+	}
+}
+
+func TestStates(t *testing.T) {
+	stairs, _ := Lookup("oak_stairs")
+	assert.Equal(t, []string{"north", "bottom", "straight", "false"}, stairs.Values(stairs.DefaultState))
+
+	id, err := stairs.State(map[string]string{"facing": "east", "waterlogged": "true"})
+	require.NoError(t, err)
+	v, _ := stairs.Value(id, "facing")
+	assert.Equal(t, "east", v)
+	v, _ = stairs.Value(id, "half")
+	assert.Equal(t, "bottom", v)
+
+	id, err = stairs.With(id, "shape", "outer_right")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"east", "bottom", "outer_right", "true"}, stairs.Values(id))
+
+	_, err = stairs.State(map[string]string{"facing": "up"})
+	assert.Error(t, err)
+	_, err = stairs.State(map[string]string{"axis": "x"})
+	assert.Error(t, err)
+	_, err = stairs.With(Air, "facing", "east")
+	assert.Error(t, err)
+}
+
+func TestParseFormat(t *testing.T) {
+	id, err := Parse("minecraft:oak_stairs[facing=north,half=top,shape=straight,waterlogged=true]")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2874), id)
+	assert.Equal(t, "minecraft:oak_stairs[facing=north,half=top,shape=straight,waterlogged=true]", Format(id))
+
+	id, err = Parse(" oak_stairs [ half = top ] ")
+	require.NoError(t, err)
+	assert.Equal(t, "minecraft:oak_stairs[facing=north,half=top,shape=straight,waterlogged=false]", Format(id))
+
+	id, err = Parse("stone[]")
+	require.NoError(t, err)
+	assert.Equal(t, "minecraft:stone", Format(id))
+
+	for _, bad := range []string{
+		"minecraft:nothing",
+		"oak_stairs[facing=north",
+		"oak_stairs[facing]",
+		"oak_stairs[facing=sideways]",
+		"oak_stairs[color=red]",
+		"oak_stairs[facing=north,facing=south]",
+	} {
+		_, err := Parse(bad)
+		assert.Error(t, err, bad)
+	}
+
+	// Every state survives a round trip.
+	for id := uint32(0); id < uint32(StateCount()); id++ {
+		parsed, err := Parse(Format(id))
+		require.NoError(t, err)
+		require.Equal(t, id, parsed)
+	}
+}
+
+func TestNBT(t *testing.T) {
+	id, _ := Parse("oak_stairs[facing=west,half=top]")
+	tag, ok := ToNBT(id)
+	require.True(t, ok)
+	assert.Equal(t, `{Name: "minecraft:oak_stairs", Properties: {facing: "west", half: "top", shape: "straight", waterlogged: "false"}}`, nbt.ToCompactSNBT(tag))
+	back, err := FromNBT(tag)
+	require.NoError(t, err)
+	assert.Equal(t, id, back)
+
+	tag, _ = ToNBT(Air)
+	_, ok = tag.Get("Properties")
+	assert.False(t, ok)
+
+	unknown, err := nbt.ParseSNBT(`{Name:"minecraft:oak_stairs",Properties:{facing:"up"}}`)
+	require.NoError(t, err)
+	_, err = FromNBT(unknown)
+	assert.Error(t, err)
+}
+
+func TestProtocolStates(t *testing.T) {
+	count, toNetwork, ok := ProtocolStates(767)
+	require.True(t, ok)
+	assert.Equal(t, StateCount(), count)
+	assert.Nil(t, toNetwork)
+
+	count, toNetwork, ok = ProtocolStates(765)
+	require.True(t, ok)
+	assert.Equal(t, 26644, count)
+	stairs, _ := Lookup("oak_stairs")
+	assert.Equal(t, stairs.DefaultState, toNetwork(stairs.DefaultState))
+	// 1.20.4 has no vault and no ominous trial spawners.
+	vault, _ := Lookup("vault")
+	assert.Equal(t, Air, toNetwork(vault.DefaultState))
+	ominous, err := Parse("trial_spawner[ominous=true,trial_spawner_state=active]")
+	require.NoError(t, err)
+	normal, _ := Parse("trial_spawner[ominous=false,trial_spawner_state=active]")
+	assert.Equal(t, toNetwork(normal), toNetwork(ominous))
+	for id := uint32(0); id < uint32(StateCount()); id++ {
+		require.Less(t, toNetwork(id), uint32(count))
+	}
+
+	_, _, ok = ProtocolStates(1)
+	assert.False(t, ok)
+}
+
+func TestLight(t *testing.T) {
+	light := func(s string) int {
+		id, err := Parse(s)
+		require.NoError(t, err, s)
+		return LightEmission(id)
+	}
+	opacity := func(s string) int {
+		id, err := Parse(s)
+		require.NoError(t, err, s)
+		return LightOpacity(id)
+	}
+	assert.Equal(t, 0, light("air"))
+	assert.Equal(t, 14, light("torch"))
+	assert.Equal(t, 15, light("glowstone"))
+	assert.Equal(t, 15, light("lava[level=3]"))
+	assert.Equal(t, 13, light("furnace[lit=true]"))
+	assert.Equal(t, 0, light("furnace"))
+	assert.Equal(t, 9, light("candle[candles=3,lit=true]"))
+	assert.Equal(t, 0, light("candle[candles=3]"))
+	assert.Equal(t, 7, light("light[level=7]"))
+	assert.Equal(t, 15, light("sea_pickle[pickles=4]"))
+	assert.Equal(t, 0, light("sea_pickle[waterlogged=false]"))
+	assert.Equal(t, 7, light("respawn_anchor[charges=2]"))
+	assert.Equal(t, 14, light("cave_vines[berries=true]"))
+	assert.Equal(t, 0, light("cave_vines"))
+
+	assert.Equal(t, 0, opacity("air"))
+	assert.Equal(t, 0, opacity("glass"))
+	assert.Equal(t, 15, opacity("stone"))
+	assert.Equal(t, 1, opacity("water"))
+	assert.Equal(t, 1, opacity("oak_leaves"))
+	assert.Equal(t, 0, opacity("oak_slab[waterlogged=false]"))
+	assert.Equal(t, 1, opacity("oak_slab[waterlogged=true]"))
+	assert.Equal(t, 15, opacity("stone_slab[type=double]"))
+}
+
+func TestFullCube(t *testing.T) {
+	for s, want := range map[string]bool{
+		"stone":                                  true,
+		"stone_slab[type=double]":                true,
+		"piston":                                 true,
+		"air":                                    false,
+		"water":                                  false,
+		"glass":                                  false,
+		"oak_slab":                               false,
+		"oak_stairs":                             false,
+		"soul_sand":                              false,
+		"piston[extended=true]":                  false,
+		"sticky_piston[facing=up,extended=true]": false,
+	} {
+		id, err := Parse(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, FullCube(id), s)
+	}
+}
@@ -0,0 +1,1192 @@
+// Code generated by internal/gen from reports/1.21.1.json.gz; DO NOT EDIT.
+
+package block
+
+var properties = [...]Property{
+	{Name: "snowy", Values: []string{"true", "false"}},
+	{Name: "stage", Values: []string{"0", "1"}},
+	{Name: "age", Values: []string{"0", "1", "2", "3", "4"}},
+	{Name: "hanging", Values: []string{"true", "false"}},
+	{Name: "waterlogged", Values: []string{"true", "false"}},
+	{Name: "level", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15"}},
+	{Name: "dusted", Values: []string{"0", "1", "2", "3"}},
+	{Name: "axis", Values: []string{"x", "y", "z"}},
+	{Name: "distance", Values: []string{"1", "2", "3", "4", "5", "6", "7"}},
+	{Name: "persistent", Values: []string{"true", "false"}},
+	{Name: "facing", Values: []string{"north", "east", "south", "west", "up", "down"}},
+	{Name: "triggered", Values: []string{"true", "false"}},
+	{Name: "instrument", Values: []string{"harp", "basedrum", "snare", "hat", "bass", "flute", "bell", "guitar", "chime", "xylophone", "iron_xylophone", "cow_bell", "didgeridoo", "bit", "banjo", "pling", "zombie", "skeleton", "creeper", "dragon", "wither_skeleton", "piglin", "custom_head"}},
+	{Name: "note", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17", "18", "19", "20", "21", "22", "23", "24"}},
+	{Name: "powered", Values: []string{"true", "false"}},
+	{Name: "facing", Values: []string{"north", "south", "west", "east"}},
+	{Name: "occupied", Values: []string{"true", "false"}},
+	{Name: "part", Values: []string{"head", "foot"}},
+	{Name: "shape", Values: []string{"north_south", "east_west", "ascending_east", "ascending_west", "ascending_north", "ascending_south"}},
+	{Name: "extended", Values: []string{"true", "false"}},
+	{Name: "half", Values: []string{"upper", "lower"}},
+	{Name: "short", Values: []string{"true", "false"}},
+	{Name: "type", Values: []string{"normal", "sticky"}},
+	{Name: "unstable", Values: []string{"true", "false"}},
+	{Name: "slot_0_occupied", Values: []string{"true", "false"}},
+	{Name: "slot_1_occupied", Values: []string{"true", "false"}},
+	{Name: "slot_2_occupied", Values: []string{"true", "false"}},
+	{Name: "slot_3_occupied", Values: []string{"true", "false"}},
+	{Name: "slot_4_occupied", Values: []string{"true", "false"}},
+	{Name: "slot_5_occupied", Values: []string{"true", "false"}},
+	{Name: "age", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15"}},
+	{Name: "east", Values: []string{"true", "false"}},
+	{Name: "north", Values: []string{"true", "false"}},
+	{Name: "south", Values: []string{"true", "false"}},
+	{Name: "up", Values: []string{"true", "false"}},
+	{Name: "west", Values: []string{"true", "false"}},
+	{Name: "half", Values: []string{"top", "bottom"}},
+	{Name: "shape", Values: []string{"straight", "inner_left", "inner_right", "outer_left", "outer_right"}},
+	{Name: "type", Values: []string{"single", "left", "right"}},
+	{Name: "east", Values: []string{"up", "side", "none"}},
+	{Name: "north", Values: []string{"up", "side", "none"}},
+	{Name: "power", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15"}},
+	{Name: "south", Values: []string{"up", "side", "none"}},
+	{Name: "west", Values: []string{"up", "side", "none"}},
+	{Name: "age", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7"}},
+	{Name: "moisture", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7"}},
+	{Name: "lit", Values: []string{"true", "false"}},
+	{Name: "rotation", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15"}},
+	{Name: "hinge", Values: []string{"left", "right"}},
+	{Name: "open", Values: []string{"true", "false"}},
+	{Name: "shape", Values: []string{"north_south", "east_west", "ascending_east", "ascending_west", "ascending_north", "ascending_south", "south_east", "south_west", "north_west", "north_east"}},
+	{Name: "attached", Values: []string{"true", "false"}},
+	{Name: "face", Values: []string{"floor", "wall", "ceiling"}},
+	{Name: "layers", Values: []string{"1", "2", "3", "4", "5", "6", "7", "8"}},
+	{Name: "has_record", Values: []string{"true", "false"}},
+	{Name: "axis", Values: []string{"x", "z"}},
+	{Name: "bites", Values: []string{"0", "1", "2", "3", "4", "5", "6"}},
+	{Name: "delay", Values: []string{"1", "2", "3", "4"}},
+	{Name: "locked", Values: []string{"true", "false"}},
+	{Name: "down", Values: []string{"true", "false"}},
+	{Name: "in_wall", Values: []string{"true", "false"}},
+	{Name: "age", Values: []string{"0", "1", "2", "3"}},
+	{Name: "has_bottle_0", Values: []string{"true", "false"}},
+	{Name: "has_bottle_1", Values: []string{"true", "false"}},
+	{Name: "has_bottle_2", Values: []string{"true", "false"}},
+	{Name: "level", Values: []string{"1", "2", "3"}},
+	{Name: "eye", Values: []string{"true", "false"}},
+	{Name: "age", Values: []string{"0", "1", "2"}},
+	{Name: "disarmed", Values: []string{"true", "false"}},
+	{Name: "conditional", Values: []string{"true", "false"}},
+	{Name: "east", Values: []string{"none", "low", "tall"}},
+	{Name: "north", Values: []string{"none", "low", "tall"}},
+	{Name: "south", Values: []string{"none", "low", "tall"}},
+	{Name: "west", Values: []string{"none", "low", "tall"}},
+	{Name: "mode", Values: []string{"compare", "subtract"}},
+	{Name: "inverted", Values: []string{"true", "false"}},
+	{Name: "enabled", Values: []string{"true", "false"}},
+	{Name: "facing", Values: []string{"down", "north", "south", "west", "east"}},
+	{Name: "type", Values: []string{"top", "bottom", "double"}},
+	{Name: "age", Values: []string{"0", "1", "2", "3", "4", "5"}},
+	{Name: "age", Values: []string{"0", "1"}},
+	{Name: "age", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17", "18", "19", "20", "21", "22", "23", "24", "25"}},
+	{Name: "eggs", Values: []string{"1", "2", "3", "4"}},
+	{Name: "hatch", Values: []string{"0", "1", "2"}},
+	{Name: "pickles", Values: []string{"1", "2", "3", "4"}},
+	{Name: "leaves", Values: []string{"none", "small", "large"}},
+	{Name: "drag", Values: []string{"true", "false"}},
+	{Name: "bottom", Values: []string{"true", "false"}},
+	{Name: "distance", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7"}},
+	{Name: "has_book", Values: []string{"true", "false"}},
+	{Name: "attachment", Values: []string{"floor", "ceiling", "single_wall", "double_wall"}},
+	{Name: "signal_fire", Values: []string{"true", "false"}},
+	{Name: "mode", Values: []string{"save", "load", "corner", "data"}},
+	{Name: "orientation", Values: []string{"down_east", "down_north", "down_south", "down_west", "up_east", "up_north", "up_south", "up_west", "west_up", "east_up", "north_up", "south_up"}},
+	{Name: "level", Values: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8"}},
+	{Name: "honey_level", Values: []string{"0", "1", "2", "3", "4", "5"}},
+	{Name: "charges", Values: []string{"0", "1", "2", "3", "4"}},
+	{Name: "candles", Values: []string{"1", "2", "3", "4"}},
+	{Name: "sculk_sensor_phase", Values: []string{"inactive", "active", "cooldown"}},
+	{Name: "bloom", Values: []string{"true", "false"}},
+	{Name: "can_summon", Values: []string{"true", "false"}},
+	{Name: "shrieking", Values: []string{"true", "false"}},
+	{Name: "thickness", Values: []string{"tip_merge", "tip", "frustum", "middle", "base"}},
+	{Name: "vertical_direction", Values: []string{"up", "down"}},
+	{Name: "berries", Values: []string{"true", "false"}},
+	{Name: "flower_amount", Values: []string{"1", "2", "3", "4"}},
+	{Name: "tilt", Values: []string{"none", "unstable", "partial", "full"}},
+	{Name: "cracked", Values: []string{"true", "false"}},
+	{Name: "crafting", Values: []string{"true", "false"}},
+	{Name: "ominous", Values: []string{"true", "false"}},
+	{Name: "trial_spawner_state", Values: []string{"inactive", "waiting_for_players", "active", "waiting_for_reward_ejection", "ejecting_reward", "cooldown"}},
+	{Name: "vault_state", Values: []string{"inactive", "active", "unlocking", "ejecting"}},
+}
+
+var blocks = [...]Block{
+	{Name: "minecraft:air", MinState: 0, DefaultState: 0},
+	{Name: "minecraft:stone", MinState: 1, DefaultState: 1, Opacity: 15},
+	{Name: "minecraft:granite", MinState: 2, DefaultState: 2, Opacity: 15},
+	{Name: "minecraft:polished_granite", MinState: 3, DefaultState: 3, Opacity: 15},
+	{Name: "minecraft:diorite", MinState: 4, DefaultState: 4, Opacity: 15},
+	{Name: "minecraft:polished_diorite", MinState: 5, DefaultState: 5, Opacity: 15},
+	{Name: "minecraft:andesite", MinState: 6, DefaultState: 6, Opacity: 15},
+	{Name: "minecraft:polished_andesite", MinState: 7, DefaultState: 7, Opacity: 15},
+	{Name: "minecraft:grass_block", Properties: []*Property{&properties[0]}, MinState: 8, DefaultState: 9, Opacity: 15},
+	{Name: "minecraft:dirt", MinState: 10, DefaultState: 10, Opacity: 15},
+	{Name: "minecraft:coarse_dirt", MinState: 11, DefaultState: 11, Opacity: 15},
+	{Name: "minecraft:podzol", Properties: []*Property{&properties[0]}, MinState: 12, DefaultState: 13, Opacity: 15},
+	{Name: "minecraft:cobblestone", MinState: 14, DefaultState: 14, Opacity: 15},
+	{Name: "minecraft:oak_planks", MinState: 15, DefaultState: 15, Opacity: 15},
+	{Name: "minecraft:spruce_planks", MinState: 16, DefaultState: 16, Opacity: 15},
+	{Name: "minecraft:birch_planks", MinState: 17, DefaultState: 17, Opacity: 15},
+	{Name: "minecraft:jungle_planks", MinState: 18, DefaultState: 18, Opacity: 15},
+	{Name: "minecraft:acacia_planks", MinState: 19, DefaultState: 19, Opacity: 15},
+	{Name: "minecraft:cherry_planks", MinState: 20, DefaultState: 20, Opacity: 15},
+	{Name: "minecraft:dark_oak_planks", MinState: 21, DefaultState: 21, Opacity: 15},
+	{Name: "minecraft:mangrove_planks", MinState: 22, DefaultState: 22, Opacity: 15},
+	{Name: "minecraft:bamboo_planks", MinState: 23, DefaultState: 23, Opacity: 15},
+	{Name: "minecraft:bamboo_mosaic", MinState: 24, DefaultState: 24, Opacity: 15},
+	{Name: "minecraft:oak_sapling", Properties: []*Property{&properties[1]}, MinState: 25, DefaultState: 25},
+	{Name: "minecraft:spruce_sapling", Properties: []*Property{&properties[1]}, MinState: 27, DefaultState: 27},
+	{Name: "minecraft:birch_sapling", Properties: []*Property{&properties[1]}, MinState: 29, DefaultState: 29},
+	{Name: "minecraft:jungle_sapling", Properties: []*Property{&properties[1]}, MinState: 31, DefaultState: 31},
+	{Name: "minecraft:acacia_sapling", Properties: []*Property{&properties[1]}, MinState: 33, DefaultState: 33},
+	{Name: "minecraft:cherry_sapling", Properties: []*Property{&properties[1]}, MinState: 35, DefaultState: 35},
+	{Name: "minecraft:dark_oak_sapling", Properties: []*Property{&properties[1]}, MinState: 37, DefaultState: 37},
+	{Name: "minecraft:mangrove_propagule", Properties: []*Property{&properties[2], &properties[3], &properties[1], &properties[4]}, MinState: 39, DefaultState: 44},
+	{Name: "minecraft:bedrock", MinState: 79, DefaultState: 79, Opacity: 15},
+	{Name: "minecraft:water", Properties: []*Property{&properties[5]}, MinState: 80, DefaultState: 80, Opacity: 1},
+	{Name: "minecraft:lava", Properties: []*Property{&properties[5]}, MinState: 96, DefaultState: 96, Light: 15, Opacity: 1},
+	{Name: "minecraft:sand", MinState: 112, DefaultState: 112, Opacity: 15},
+	{Name: "minecraft:suspicious_sand", Properties: []*Property{&properties[6]}, MinState: 113, DefaultState: 113, Opacity: 15},
+	{Name: "minecraft:red_sand", MinState: 117, DefaultState: 117, Opacity: 15},
+	{Name: "minecraft:gravel", MinState: 118, DefaultState: 118, Opacity: 15},
+	{Name: "minecraft:suspicious_gravel", Properties: []*Property{&properties[6]}, MinState: 119, DefaultState: 119, Opacity: 15},
+	{Name: "minecraft:gold_ore", MinState: 123, DefaultState: 123, Opacity: 15},
+	{Name: "minecraft:deepslate_gold_ore", MinState: 124, DefaultState: 124, Opacity: 15},
+	{Name: "minecraft:iron_ore", MinState: 125, DefaultState: 125, Opacity: 15},
+	{Name: "minecraft:deepslate_iron_ore", MinState: 126, DefaultState: 126, Opacity: 15},
+	{Name: "minecraft:coal_ore", MinState: 127, DefaultState: 127, Opacity: 15},
+	{Name: "minecraft:deepslate_coal_ore", MinState: 128, DefaultState: 128, Opacity: 15},
+	{Name: "minecraft:nether_gold_ore", MinState: 129, DefaultState: 129, Opacity: 15},
+	{Name: "minecraft:oak_log", Properties: []*Property{&properties[7]}, MinState: 130, DefaultState: 131, Opacity: 15},
+	{Name: "minecraft:spruce_log", Properties: []*Property{&properties[7]}, MinState: 133, DefaultState: 134, Opacity: 15},
+	{Name: "minecraft:birch_log", Properties: []*Property{&properties[7]}, MinState: 136, DefaultState: 137, Opacity: 15},
+	{Name: "minecraft:jungle_log", Properties: []*Property{&properties[7]}, MinState: 139, DefaultState: 140, Opacity: 15},
+	{Name: "minecraft:acacia_log", Properties: []*Property{&properties[7]}, MinState: 142, DefaultState: 143, Opacity: 15},
+	{Name: "minecraft:cherry_log", Properties: []*Property{&properties[7]}, MinState: 145, DefaultState: 146, Opacity: 15},
+	{Name: "minecraft:dark_oak_log", Properties: []*Property{&properties[7]}, MinState: 148, DefaultState: 149, Opacity: 15},
+	{Name: "minecraft:mangrove_log", Properties: []*Property{&properties[7]}, MinState: 151, DefaultState: 152, Opacity: 15},
+	{Name: "minecraft:mangrove_roots", Properties: []*Property{&properties[4]}, MinState: 154, DefaultState: 155, Opacity: 1},
+	{Name: "minecraft:muddy_mangrove_roots", Properties: []*Property{&properties[7]}, MinState: 156, DefaultState: 157, Opacity: 15},
+	{Name: "minecraft:bamboo_block", Properties: []*Property{&properties[7]}, MinState: 159, DefaultState: 160, Opacity: 15},
+	{Name: "minecraft:stripped_spruce_log", Properties: []*Property{&properties[7]}, MinState: 162, DefaultState: 163, Opacity: 15},
+	{Name: "minecraft:stripped_birch_log", Properties: []*Property{&properties[7]}, MinState: 165, DefaultState: 166, Opacity: 15},
+	{Name: "minecraft:stripped_jungle_log", Properties: []*Property{&properties[7]}, MinState: 168, DefaultState: 169, Opacity: 15},
+	{Name: "minecraft:stripped_acacia_log", Properties: []*Property{&properties[7]}, MinState: 171, DefaultState: 172, Opacity: 15},
+	{Name: "minecraft:stripped_cherry_log", Properties: []*Property{&properties[7]}, MinState: 174, DefaultState: 175, Opacity: 15},
+	{Name: "minecraft:stripped_dark_oak_log", Properties: []*Property{&properties[7]}, MinState: 177, DefaultState: 178, Opacity: 15},
+	{Name: "minecraft:stripped_oak_log", Properties: []*Property{&properties[7]}, MinState: 180, DefaultState: 181, Opacity: 15},
+	{Name: "minecraft:stripped_mangrove_log", Properties: []*Property{&properties[7]}, MinState: 183, DefaultState: 184, Opacity: 15},
+	{Name: "minecraft:stripped_bamboo_block", Properties: []*Property{&properties[7]}, MinState: 186, DefaultState: 187, Opacity: 15},
+	{Name: "minecraft:oak_wood", Properties: []*Property{&properties[7]}, MinState: 189, DefaultState: 190, Opacity: 15},
+	{Name: "minecraft:spruce_wood", Properties: []*Property{&properties[7]}, MinState: 192, DefaultState: 193, Opacity: 15},
+	{Name: "minecraft:birch_wood", Properties: []*Property{&properties[7]}, MinState: 195, DefaultState: 196, Opacity: 15},
+	{Name: "minecraft:jungle_wood", Properties: []*Property{&properties[7]}, MinState: 198, DefaultState: 199, Opacity: 15},
+	{Name: "minecraft:acacia_wood", Properties: []*Property{&properties[7]}, MinState: 201, DefaultState: 202, Opacity: 15},
+	{Name: "minecraft:cherry_wood", Properties: []*Property{&properties[7]}, MinState: 204, DefaultState: 205, Opacity: 15},
+	{Name: "minecraft:dark_oak_wood", Properties: []*Property{&properties[7]}, MinState: 207, DefaultState: 208, Opacity: 15},
+	{Name: "minecraft:mangrove_wood", Properties: []*Property{&properties[7]}, MinState: 210, DefaultState: 211, Opacity: 15},
+	{Name: "minecraft:stripped_oak_wood", Properties: []*Property{&properties[7]}, MinState: 213, DefaultState: 214, Opacity: 15},
+	{Name: "minecraft:stripped_spruce_wood", Properties: []*Property{&properties[7]}, MinState: 216, DefaultState: 217, Opacity: 15},
+	{Name: "minecraft:stripped_birch_wood", Properties: []*Property{&properties[7]}, MinState: 219, DefaultState: 220, Opacity: 15},
+	{Name: "minecraft:stripped_jungle_wood", Properties: []*Property{&properties[7]}, MinState: 222, DefaultState: 223, Opacity: 15},
+	{Name: "minecraft:stripped_acacia_wood", Properties: []*Property{&properties[7]}, MinState: 225, DefaultState: 226, Opacity: 15},
+	{Name: "minecraft:stripped_cherry_wood", Properties: []*Property{&properties[7]}, MinState: 228, DefaultState: 229, Opacity: 15},
+	{Name: "minecraft:stripped_dark_oak_wood", Properties: []*Property{&properties[7]}, MinState: 231, DefaultState: 232, Opacity: 15},
+	{Name: "minecraft:stripped_mangrove_wood", Properties: []*Property{&properties[7]}, MinState: 234, DefaultState: 235, Opacity: 15},
+	{Name: "minecraft:oak_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 237, DefaultState: 264, Opacity: 1},
+	{Name: "minecraft:spruce_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 265, DefaultState: 292, Opacity: 1},
+	{Name: "minecraft:birch_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 293, DefaultState: 320, Opacity: 1},
+	{Name: "minecraft:jungle_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 321, DefaultState: 348, Opacity: 1},
+	{Name: "minecraft:acacia_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 349, DefaultState: 376, Opacity: 1},
+	{Name: "minecraft:cherry_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 377, DefaultState: 404, Opacity: 1},
+	{Name: "minecraft:dark_oak_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 405, DefaultState: 432, Opacity: 1},
+	{Name: "minecraft:mangrove_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 433, DefaultState: 460, Opacity: 1},
+	{Name: "minecraft:azalea_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 461, DefaultState: 488, Opacity: 1},
+	{Name: "minecraft:flowering_azalea_leaves", Properties: []*Property{&properties[8], &properties[9], &properties[4]}, MinState: 489, DefaultState: 516, Opacity: 1},
+	{Name: "minecraft:sponge", MinState: 517, DefaultState: 517, Opacity: 15},
+	{Name: "minecraft:wet_sponge", MinState: 518, DefaultState: 518, Opacity: 15},
+	{Name: "minecraft:glass", MinState: 519, DefaultState: 519},
+	{Name: "minecraft:lapis_ore", MinState: 520, DefaultState: 520, Opacity: 15},
+	{Name: "minecraft:deepslate_lapis_ore", MinState: 521, DefaultState: 521, Opacity: 15},
+	{Name: "minecraft:lapis_block", MinState: 522, DefaultState: 522, Opacity: 15},
+	{Name: "minecraft:dispenser", Properties: []*Property{&properties[10], &properties[11]}, MinState: 523, DefaultState: 524, Opacity: 15},
+	{Name: "minecraft:sandstone", MinState: 535, DefaultState: 535, Opacity: 15},
+	{Name: "minecraft:chiseled_sandstone", MinState: 536, DefaultState: 536, Opacity: 15},
+	{Name: "minecraft:cut_sandstone", MinState: 537, DefaultState: 537, Opacity: 15},
+	{Name: "minecraft:note_block", Properties: []*Property{&properties[12], &properties[13], &properties[14]}, MinState: 538, DefaultState: 539, Opacity: 15},
+	{Name: "minecraft:white_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1688, DefaultState: 1691},
+	{Name: "minecraft:orange_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1704, DefaultState: 1707},
+	{Name: "minecraft:magenta_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1720, DefaultState: 1723},
+	{Name: "minecraft:light_blue_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1736, DefaultState: 1739},
+	{Name: "minecraft:yellow_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1752, DefaultState: 1755},
+	{Name: "minecraft:lime_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1768, DefaultState: 1771},
+	{Name: "minecraft:pink_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1784, DefaultState: 1787},
+	{Name: "minecraft:gray_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1800, DefaultState: 1803},
+	{Name: "minecraft:light_gray_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1816, DefaultState: 1819},
+	{Name: "minecraft:cyan_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1832, DefaultState: 1835},
+	{Name: "minecraft:purple_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1848, DefaultState: 1851},
+	{Name: "minecraft:blue_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1864, DefaultState: 1867},
+	{Name: "minecraft:brown_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1880, DefaultState: 1883},
+	{Name: "minecraft:green_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1896, DefaultState: 1899},
+	{Name: "minecraft:red_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1912, DefaultState: 1915},
+	{Name: "minecraft:black_bed", Properties: []*Property{&properties[15], &properties[16], &properties[17]}, MinState: 1928, DefaultState: 1931},
+	{Name: "minecraft:powered_rail", Properties: []*Property{&properties[14], &properties[18], &properties[4]}, MinState: 1944, DefaultState: 1957},
+	{Name: "minecraft:detector_rail", Properties: []*Property{&properties[14], &properties[18], &properties[4]}, MinState: 1968, DefaultState: 1981},
+	{Name: "minecraft:sticky_piston", Properties: []*Property{&properties[19], &properties[10]}, MinState: 1992, DefaultState: 1998, Opacity: 15},
+	{Name: "minecraft:cobweb", MinState: 2004, DefaultState: 2004, Opacity: 1},
+	{Name: "minecraft:short_grass", MinState: 2005, DefaultState: 2005},
+	{Name: "minecraft:fern", MinState: 2006, DefaultState: 2006},
+	{Name: "minecraft:dead_bush", MinState: 2007, DefaultState: 2007},
+	{Name: "minecraft:seagrass", MinState: 2008, DefaultState: 2008, Opacity: 1},
+	{Name: "minecraft:tall_seagrass", Properties: []*Property{&properties[20]}, MinState: 2009, DefaultState: 2010, Opacity: 1},
+	{Name: "minecraft:piston", Properties: []*Property{&properties[19], &properties[10]}, MinState: 2011, DefaultState: 2017, Opacity: 15},
+	{Name: "minecraft:piston_head", Properties: []*Property{&properties[10], &properties[21], &properties[22]}, MinState: 2023, DefaultState: 2025},
+	{Name: "minecraft:white_wool", MinState: 2047, DefaultState: 2047, Opacity: 15},
+	{Name: "minecraft:orange_wool", MinState: 2048, DefaultState: 2048, Opacity: 15},
+	{Name: "minecraft:magenta_wool", MinState: 2049, DefaultState: 2049, Opacity: 15},
+	{Name: "minecraft:light_blue_wool", MinState: 2050, DefaultState: 2050, Opacity: 15},
+	{Name: "minecraft:yellow_wool", MinState: 2051, DefaultState: 2051, Opacity: 15},
+	{Name: "minecraft:lime_wool", MinState: 2052, DefaultState: 2052, Opacity: 15},
+	{Name: "minecraft:pink_wool", MinState: 2053, DefaultState: 2053, Opacity: 15},
+	{Name: "minecraft:gray_wool", MinState: 2054, DefaultState: 2054, Opacity: 15},
+	{Name: "minecraft:light_gray_wool", MinState: 2055, DefaultState: 2055, Opacity: 15},
+	{Name: "minecraft:cyan_wool", MinState: 2056, DefaultState: 2056, Opacity: 15},
+	{Name: "minecraft:purple_wool", MinState: 2057, DefaultState: 2057, Opacity: 15},
+	{Name: "minecraft:blue_wool", MinState: 2058, DefaultState: 2058, Opacity: 15},
+	{Name: "minecraft:brown_wool", MinState: 2059, DefaultState: 2059, Opacity: 15},
+	{Name: "minecraft:green_wool", MinState: 2060, DefaultState: 2060, Opacity: 15},
+	{Name: "minecraft:red_wool", MinState: 2061, DefaultState: 2061, Opacity: 15},
+	{Name: "minecraft:black_wool", MinState: 2062, DefaultState: 2062, Opacity: 15},
+	{Name: "minecraft:moving_piston", Properties: []*Property{&properties[10], &properties[22]}, MinState: 2063, DefaultState: 2063},
+	{Name: "minecraft:dandelion", MinState: 2075, DefaultState: 2075},
+	{Name: "minecraft:torchflower", MinState: 2076, DefaultState: 2076},
+	{Name: "minecraft:poppy", MinState: 2077, DefaultState: 2077},
+	{Name: "minecraft:blue_orchid", MinState: 2078, DefaultState: 2078},
+	{Name: "minecraft:allium", MinState: 2079, DefaultState: 2079},
+	{Name: "minecraft:azure_bluet", MinState: 2080, DefaultState: 2080},
+	{Name: "minecraft:red_tulip", MinState: 2081, DefaultState: 2081},
+	{Name: "minecraft:orange_tulip", MinState: 2082, DefaultState: 2082},
+	{Name: "minecraft:white_tulip", MinState: 2083, DefaultState: 2083},
+	{Name: "minecraft:pink_tulip", MinState: 2084, DefaultState: 2084},
+	{Name: "minecraft:oxeye_daisy", MinState: 2085, DefaultState: 2085},
+	{Name: "minecraft:cornflower", MinState: 2086, DefaultState: 2086},
+	{Name: "minecraft:wither_rose", MinState: 2087, DefaultState: 2087},
+	{Name: "minecraft:lily_of_the_valley", MinState: 2088, DefaultState: 2088},
+	{Name: "minecraft:brown_mushroom", MinState: 2089, DefaultState: 2089, Light: 1},
+	{Name: "minecraft:red_mushroom", MinState: 2090, DefaultState: 2090},
+	{Name: "minecraft:gold_block", MinState: 2091, DefaultState: 2091, Opacity: 15},
+	{Name: "minecraft:iron_block", MinState: 2092, DefaultState: 2092, Opacity: 15},
+	{Name: "minecraft:bricks", MinState: 2093, DefaultState: 2093, Opacity: 15},
+	{Name: "minecraft:tnt", Properties: []*Property{&properties[23]}, MinState: 2094, DefaultState: 2095, Opacity: 15},
+	{Name: "minecraft:bookshelf", MinState: 2096, DefaultState: 2096, Opacity: 15},
+	{Name: "minecraft:chiseled_bookshelf", Properties: []*Property{&properties[15], &properties[24], &properties[25], &properties[26], &properties[27], &properties[28], &properties[29]}, MinState: 2097, DefaultState: 2160, Opacity: 15},
+	{Name: "minecraft:mossy_cobblestone", MinState: 2353, DefaultState: 2353, Opacity: 15},
+	{Name: "minecraft:obsidian", MinState: 2354, DefaultState: 2354, Opacity: 15},
+	{Name: "minecraft:torch", MinState: 2355, DefaultState: 2355, Light: 14},
+	{Name: "minecraft:wall_torch", Properties: []*Property{&properties[15]}, MinState: 2356, DefaultState: 2356, Light: 14},
+	{Name: "minecraft:fire", Properties: []*Property{&properties[30], &properties[31], &properties[32], &properties[33], &properties[34], &properties[35]}, MinState: 2360, DefaultState: 2391, Light: 15},
+	{Name: "minecraft:soul_fire", MinState: 2872, DefaultState: 2872, Light: 10},
+	{Name: "minecraft:spawner", MinState: 2873, DefaultState: 2873, Opacity: 1},
+	{Name: "minecraft:oak_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 2874, DefaultState: 2885},
+	{Name: "minecraft:chest", Properties: []*Property{&properties[15], &properties[38], &properties[4]}, MinState: 2954, DefaultState: 2955},
+	{Name: "minecraft:redstone_wire", Properties: []*Property{&properties[39], &properties[40], &properties[41], &properties[42], &properties[43]}, MinState: 2978, DefaultState: 4138},
+	{Name: "minecraft:diamond_ore", MinState: 4274, DefaultState: 4274, Opacity: 15},
+	{Name: "minecraft:deepslate_diamond_ore", MinState: 4275, DefaultState: 4275, Opacity: 15},
+	{Name: "minecraft:diamond_block", MinState: 4276, DefaultState: 4276, Opacity: 15},
+	{Name: "minecraft:crafting_table", MinState: 4277, DefaultState: 4277, Opacity: 15},
+	{Name: "minecraft:wheat", Properties: []*Property{&properties[44]}, MinState: 4278, DefaultState: 4278},
+	{Name: "minecraft:farmland", Properties: []*Property{&properties[45]}, MinState: 4286, DefaultState: 4286},
+	{Name: "minecraft:furnace", Properties: []*Property{&properties[15], &properties[46]}, MinState: 4294, DefaultState: 4295, Light: 13, Opacity: 15},
+	{Name: "minecraft:oak_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4302, DefaultState: 4303},
+	{Name: "minecraft:spruce_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4334, DefaultState: 4335},
+	{Name: "minecraft:birch_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4366, DefaultState: 4367},
+	{Name: "minecraft:acacia_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4398, DefaultState: 4399},
+	{Name: "minecraft:cherry_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4430, DefaultState: 4431},
+	{Name: "minecraft:jungle_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4462, DefaultState: 4463},
+	{Name: "minecraft:dark_oak_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4494, DefaultState: 4495},
+	{Name: "minecraft:mangrove_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4526, DefaultState: 4527},
+	{Name: "minecraft:bamboo_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 4558, DefaultState: 4559},
+	{Name: "minecraft:oak_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 4590, DefaultState: 4601},
+	{Name: "minecraft:ladder", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4654, DefaultState: 4655},
+	{Name: "minecraft:rail", Properties: []*Property{&properties[50], &properties[4]}, MinState: 4662, DefaultState: 4663},
+	{Name: "minecraft:cobblestone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 4682, DefaultState: 4693},
+	{Name: "minecraft:oak_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4762, DefaultState: 4763},
+	{Name: "minecraft:spruce_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4770, DefaultState: 4771},
+	{Name: "minecraft:birch_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4778, DefaultState: 4779},
+	{Name: "minecraft:acacia_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4786, DefaultState: 4787},
+	{Name: "minecraft:cherry_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4794, DefaultState: 4795},
+	{Name: "minecraft:jungle_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4802, DefaultState: 4803},
+	{Name: "minecraft:dark_oak_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4810, DefaultState: 4811},
+	{Name: "minecraft:mangrove_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4818, DefaultState: 4819},
+	{Name: "minecraft:bamboo_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 4826, DefaultState: 4827},
+	{Name: "minecraft:oak_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 4834, DefaultState: 4867},
+	{Name: "minecraft:spruce_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 4898, DefaultState: 4931},
+	{Name: "minecraft:birch_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 4962, DefaultState: 4995},
+	{Name: "minecraft:acacia_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 5026, DefaultState: 5059},
+	{Name: "minecraft:cherry_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 5090, DefaultState: 5123},
+	{Name: "minecraft:jungle_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 5154, DefaultState: 5187},
+	{Name: "minecraft:dark_oak_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 5218, DefaultState: 5251},
+	{Name: "minecraft:crimson_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 5282, DefaultState: 5315},
+	{Name: "minecraft:warped_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 5346, DefaultState: 5379},
+	{Name: "minecraft:mangrove_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 5410, DefaultState: 5443},
+	{Name: "minecraft:bamboo_hanging_sign", Properties: []*Property{&properties[51], &properties[47], &properties[4]}, MinState: 5474, DefaultState: 5507},
+	{Name: "minecraft:oak_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5538, DefaultState: 5539},
+	{Name: "minecraft:spruce_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5546, DefaultState: 5547},
+	{Name: "minecraft:birch_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5554, DefaultState: 5555},
+	{Name: "minecraft:acacia_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5562, DefaultState: 5563},
+	{Name: "minecraft:cherry_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5570, DefaultState: 5571},
+	{Name: "minecraft:jungle_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5578, DefaultState: 5579},
+	{Name: "minecraft:dark_oak_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5586, DefaultState: 5587},
+	{Name: "minecraft:mangrove_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5594, DefaultState: 5595},
+	{Name: "minecraft:crimson_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5602, DefaultState: 5603},
+	{Name: "minecraft:warped_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5610, DefaultState: 5611},
+	{Name: "minecraft:bamboo_wall_hanging_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 5618, DefaultState: 5619},
+	{Name: "minecraft:lever", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 5626, DefaultState: 5635},
+	{Name: "minecraft:stone_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5650, DefaultState: 5651},
+	{Name: "minecraft:iron_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 5652, DefaultState: 5663},
+	{Name: "minecraft:oak_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5716, DefaultState: 5717},
+	{Name: "minecraft:spruce_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5718, DefaultState: 5719},
+	{Name: "minecraft:birch_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5720, DefaultState: 5721},
+	{Name: "minecraft:jungle_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5722, DefaultState: 5723},
+	{Name: "minecraft:acacia_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5724, DefaultState: 5725},
+	{Name: "minecraft:cherry_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5726, DefaultState: 5727},
+	{Name: "minecraft:dark_oak_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5728, DefaultState: 5729},
+	{Name: "minecraft:mangrove_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5730, DefaultState: 5731},
+	{Name: "minecraft:bamboo_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 5732, DefaultState: 5733},
+	{Name: "minecraft:redstone_ore", Properties: []*Property{&properties[46]}, MinState: 5734, DefaultState: 5735, Light: 9, Opacity: 15},
+	{Name: "minecraft:deepslate_redstone_ore", Properties: []*Property{&properties[46]}, MinState: 5736, DefaultState: 5737, Light: 9, Opacity: 15},
+	{Name: "minecraft:redstone_torch", Properties: []*Property{&properties[46]}, MinState: 5738, DefaultState: 5738, Light: 7},
+	{Name: "minecraft:redstone_wall_torch", Properties: []*Property{&properties[15], &properties[46]}, MinState: 5740, DefaultState: 5740, Light: 7},
+	{Name: "minecraft:stone_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 5748, DefaultState: 5757},
+	{Name: "minecraft:snow", Properties: []*Property{&properties[53]}, MinState: 5772, DefaultState: 5772},
+	{Name: "minecraft:ice", MinState: 5780, DefaultState: 5780, Opacity: 1},
+	{Name: "minecraft:snow_block", MinState: 5781, DefaultState: 5781, Opacity: 15},
+	{Name: "minecraft:cactus", Properties: []*Property{&properties[30]}, MinState: 5782, DefaultState: 5782},
+	{Name: "minecraft:clay", MinState: 5798, DefaultState: 5798, Opacity: 15},
+	{Name: "minecraft:sugar_cane", Properties: []*Property{&properties[30]}, MinState: 5799, DefaultState: 5799},
+	{Name: "minecraft:jukebox", Properties: []*Property{&properties[54]}, MinState: 5815, DefaultState: 5816, Opacity: 15},
+	{Name: "minecraft:oak_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 5817, DefaultState: 5848},
+	{Name: "minecraft:netherrack", MinState: 5849, DefaultState: 5849, Opacity: 15},
+	{Name: "minecraft:soul_sand", MinState: 5850, DefaultState: 5850, Opacity: 15},
+	{Name: "minecraft:soul_soil", MinState: 5851, DefaultState: 5851, Opacity: 15},
+	{Name: "minecraft:basalt", Properties: []*Property{&properties[7]}, MinState: 5852, DefaultState: 5853, Opacity: 15},
+	{Name: "minecraft:polished_basalt", Properties: []*Property{&properties[7]}, MinState: 5855, DefaultState: 5856, Opacity: 15},
+	{Name: "minecraft:soul_torch", MinState: 5858, DefaultState: 5858, Light: 10},
+	{Name: "minecraft:soul_wall_torch", Properties: []*Property{&properties[15]}, MinState: 5859, DefaultState: 5859, Light: 10},
+	{Name: "minecraft:glowstone", MinState: 5863, DefaultState: 5863, Light: 15, Opacity: 15},
+	{Name: "minecraft:nether_portal", Properties: []*Property{&properties[55]}, MinState: 5864, DefaultState: 5864, Light: 11},
+	{Name: "minecraft:carved_pumpkin", Properties: []*Property{&properties[15]}, MinState: 5866, DefaultState: 5866, Opacity: 15},
+	{Name: "minecraft:jack_o_lantern", Properties: []*Property{&properties[15]}, MinState: 5870, DefaultState: 5870, Light: 15, Opacity: 15},
+	{Name: "minecraft:cake", Properties: []*Property{&properties[56]}, MinState: 5874, DefaultState: 5874},
+	{Name: "minecraft:repeater", Properties: []*Property{&properties[57], &properties[15], &properties[58], &properties[14]}, MinState: 5881, DefaultState: 5884},
+	{Name: "minecraft:white_stained_glass", MinState: 5945, DefaultState: 5945},
+	{Name: "minecraft:orange_stained_glass", MinState: 5946, DefaultState: 5946},
+	{Name: "minecraft:magenta_stained_glass", MinState: 5947, DefaultState: 5947},
+	{Name: "minecraft:light_blue_stained_glass", MinState: 5948, DefaultState: 5948},
+	{Name: "minecraft:yellow_stained_glass", MinState: 5949, DefaultState: 5949},
+	{Name: "minecraft:lime_stained_glass", MinState: 5950, DefaultState: 5950},
+	{Name: "minecraft:pink_stained_glass", MinState: 5951, DefaultState: 5951},
+	{Name: "minecraft:gray_stained_glass", MinState: 5952, DefaultState: 5952},
+	{Name: "minecraft:light_gray_stained_glass", MinState: 5953, DefaultState: 5953},
+	{Name: "minecraft:cyan_stained_glass", MinState: 5954, DefaultState: 5954},
+	{Name: "minecraft:purple_stained_glass", MinState: 5955, DefaultState: 5955},
+	{Name: "minecraft:blue_stained_glass", MinState: 5956, DefaultState: 5956},
+	{Name: "minecraft:brown_stained_glass", MinState: 5957, DefaultState: 5957},
+	{Name: "minecraft:green_stained_glass", MinState: 5958, DefaultState: 5958},
+	{Name: "minecraft:red_stained_glass", MinState: 5959, DefaultState: 5959},
+	{Name: "minecraft:black_stained_glass", MinState: 5960, DefaultState: 5960},
+	{Name: "minecraft:oak_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 5961, DefaultState: 5976},
+	{Name: "minecraft:spruce_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 6025, DefaultState: 6040},
+	{Name: "minecraft:birch_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 6089, DefaultState: 6104},
+	{Name: "minecraft:jungle_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 6153, DefaultState: 6168},
+	{Name: "minecraft:acacia_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 6217, DefaultState: 6232},
+	{Name: "minecraft:cherry_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 6281, DefaultState: 6296},
+	{Name: "minecraft:dark_oak_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 6345, DefaultState: 6360},
+	{Name: "minecraft:mangrove_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 6409, DefaultState: 6424},
+	{Name: "minecraft:bamboo_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 6473, DefaultState: 6488},
+	{Name: "minecraft:stone_bricks", MinState: 6537, DefaultState: 6537, Opacity: 15},
+	{Name: "minecraft:mossy_stone_bricks", MinState: 6538, DefaultState: 6538, Opacity: 15},
+	{Name: "minecraft:cracked_stone_bricks", MinState: 6539, DefaultState: 6539, Opacity: 15},
+	{Name: "minecraft:chiseled_stone_bricks", MinState: 6540, DefaultState: 6540, Opacity: 15},
+	{Name: "minecraft:packed_mud", MinState: 6541, DefaultState: 6541, Opacity: 15},
+	{Name: "minecraft:mud_bricks", MinState: 6542, DefaultState: 6542, Opacity: 15},
+	{Name: "minecraft:infested_stone", MinState: 6543, DefaultState: 6543, Opacity: 15},
+	{Name: "minecraft:infested_cobblestone", MinState: 6544, DefaultState: 6544, Opacity: 15},
+	{Name: "minecraft:infested_stone_bricks", MinState: 6545, DefaultState: 6545, Opacity: 15},
+	{Name: "minecraft:infested_mossy_stone_bricks", MinState: 6546, DefaultState: 6546, Opacity: 15},
+	{Name: "minecraft:infested_cracked_stone_bricks", MinState: 6547, DefaultState: 6547, Opacity: 15},
+	{Name: "minecraft:infested_chiseled_stone_bricks", MinState: 6548, DefaultState: 6548, Opacity: 15},
+	{Name: "minecraft:brown_mushroom_block", Properties: []*Property{&properties[59], &properties[31], &properties[32], &properties[33], &properties[34], &properties[35]}, MinState: 6549, DefaultState: 6549, Opacity: 15},
+	{Name: "minecraft:red_mushroom_block", Properties: []*Property{&properties[59], &properties[31], &properties[32], &properties[33], &properties[34], &properties[35]}, MinState: 6613, DefaultState: 6613, Opacity: 15},
+	{Name: "minecraft:mushroom_stem", Properties: []*Property{&properties[59], &properties[31], &properties[32], &properties[33], &properties[34], &properties[35]}, MinState: 6677, DefaultState: 6677, Opacity: 15},
+	{Name: "minecraft:iron_bars", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 6741, DefaultState: 6772},
+	{Name: "minecraft:chain", Properties: []*Property{&properties[7], &properties[4]}, MinState: 6773, DefaultState: 6776},
+	{Name: "minecraft:glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 6779, DefaultState: 6810},
+	{Name: "minecraft:pumpkin", MinState: 6811, DefaultState: 6811, Opacity: 15},
+	{Name: "minecraft:melon", MinState: 6812, DefaultState: 6812, Opacity: 15},
+	{Name: "minecraft:attached_pumpkin_stem", Properties: []*Property{&properties[15]}, MinState: 6813, DefaultState: 6813},
+	{Name: "minecraft:attached_melon_stem", Properties: []*Property{&properties[15]}, MinState: 6817, DefaultState: 6817},
+	{Name: "minecraft:pumpkin_stem", Properties: []*Property{&properties[44]}, MinState: 6821, DefaultState: 6821},
+	{Name: "minecraft:melon_stem", Properties: []*Property{&properties[44]}, MinState: 6829, DefaultState: 6829},
+	{Name: "minecraft:vine", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[34], &properties[35]}, MinState: 6837, DefaultState: 6868},
+	{Name: "minecraft:glow_lichen", Properties: []*Property{&properties[59], &properties[31], &properties[32], &properties[33], &properties[34], &properties[4], &properties[35]}, MinState: 6869, DefaultState: 6996, Light: 7},
+	{Name: "minecraft:oak_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 6997, DefaultState: 7004},
+	{Name: "minecraft:brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 7029, DefaultState: 7040},
+	{Name: "minecraft:stone_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 7109, DefaultState: 7120},
+	{Name: "minecraft:mud_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 7189, DefaultState: 7200},
+	{Name: "minecraft:mycelium", Properties: []*Property{&properties[0]}, MinState: 7269, DefaultState: 7270, Opacity: 15},
+	{Name: "minecraft:lily_pad", MinState: 7271, DefaultState: 7271},
+	{Name: "minecraft:nether_bricks", MinState: 7272, DefaultState: 7272, Opacity: 15},
+	{Name: "minecraft:nether_brick_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 7273, DefaultState: 7304},
+	{Name: "minecraft:nether_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 7305, DefaultState: 7316},
+	{Name: "minecraft:nether_wart", Properties: []*Property{&properties[61]}, MinState: 7385, DefaultState: 7385},
+	{Name: "minecraft:enchanting_table", MinState: 7389, DefaultState: 7389, Light: 7},
+	{Name: "minecraft:brewing_stand", Properties: []*Property{&properties[62], &properties[63], &properties[64]}, MinState: 7390, DefaultState: 7397, Light: 1},
+	{Name: "minecraft:cauldron", MinState: 7398, DefaultState: 7398},
+	{Name: "minecraft:water_cauldron", Properties: []*Property{&properties[65]}, MinState: 7399, DefaultState: 7399},
+	{Name: "minecraft:lava_cauldron", MinState: 7402, DefaultState: 7402, Light: 15},
+	{Name: "minecraft:powder_snow_cauldron", Properties: []*Property{&properties[65]}, MinState: 7403, DefaultState: 7403},
+	{Name: "minecraft:end_portal", MinState: 7406, DefaultState: 7406, Light: 15},
+	{Name: "minecraft:end_portal_frame", Properties: []*Property{&properties[66], &properties[15]}, MinState: 7407, DefaultState: 7411, Light: 1},
+	{Name: "minecraft:end_stone", MinState: 7415, DefaultState: 7415, Opacity: 15},
+	{Name: "minecraft:dragon_egg", MinState: 7416, DefaultState: 7416, Light: 1},
+	{Name: "minecraft:redstone_lamp", Properties: []*Property{&properties[46]}, MinState: 7417, DefaultState: 7418, Light: 15, Opacity: 15},
+	{Name: "minecraft:cocoa", Properties: []*Property{&properties[67], &properties[15]}, MinState: 7419, DefaultState: 7419},
+	{Name: "minecraft:sandstone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 7431, DefaultState: 7442},
+	{Name: "minecraft:emerald_ore", MinState: 7511, DefaultState: 7511, Opacity: 15},
+	{Name: "minecraft:deepslate_emerald_ore", MinState: 7512, DefaultState: 7512, Opacity: 15},
+	{Name: "minecraft:ender_chest", Properties: []*Property{&properties[15], &properties[4]}, MinState: 7513, DefaultState: 7514, Light: 7},
+	{Name: "minecraft:tripwire_hook", Properties: []*Property{&properties[51], &properties[15], &properties[14]}, MinState: 7521, DefaultState: 7530},
+	{Name: "minecraft:tripwire", Properties: []*Property{&properties[51], &properties[68], &properties[31], &properties[32], &properties[14], &properties[33], &properties[35]}, MinState: 7537, DefaultState: 7664},
+	{Name: "minecraft:emerald_block", MinState: 7665, DefaultState: 7665, Opacity: 15},
+	{Name: "minecraft:spruce_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 7666, DefaultState: 7677},
+	{Name: "minecraft:birch_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 7746, DefaultState: 7757},
+	{Name: "minecraft:jungle_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 7826, DefaultState: 7837},
+	{Name: "minecraft:command_block", Properties: []*Property{&properties[69], &properties[10]}, MinState: 7906, DefaultState: 7912, Opacity: 15},
+	{Name: "minecraft:beacon", MinState: 7918, DefaultState: 7918, Light: 15, Opacity: 1},
+	{Name: "minecraft:cobblestone_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 7919, DefaultState: 7922},
+	{Name: "minecraft:mossy_cobblestone_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 8243, DefaultState: 8246},
+	{Name: "minecraft:flower_pot", MinState: 8567, DefaultState: 8567},
+	{Name: "minecraft:potted_torchflower", MinState: 8568, DefaultState: 8568},
+	{Name: "minecraft:potted_oak_sapling", MinState: 8569, DefaultState: 8569},
+	{Name: "minecraft:potted_spruce_sapling", MinState: 8570, DefaultState: 8570},
+	{Name: "minecraft:potted_birch_sapling", MinState: 8571, DefaultState: 8571},
+	{Name: "minecraft:potted_jungle_sapling", MinState: 8572, DefaultState: 8572},
+	{Name: "minecraft:potted_acacia_sapling", MinState: 8573, DefaultState: 8573},
+	{Name: "minecraft:potted_cherry_sapling", MinState: 8574, DefaultState: 8574},
+	{Name: "minecraft:potted_dark_oak_sapling", MinState: 8575, DefaultState: 8575},
+	{Name: "minecraft:potted_mangrove_propagule", MinState: 8576, DefaultState: 8576},
+	{Name: "minecraft:potted_fern", MinState: 8577, DefaultState: 8577},
+	{Name: "minecraft:potted_dandelion", MinState: 8578, DefaultState: 8578},
+	{Name: "minecraft:potted_poppy", MinState: 8579, DefaultState: 8579},
+	{Name: "minecraft:potted_blue_orchid", MinState: 8580, DefaultState: 8580},
+	{Name: "minecraft:potted_allium", MinState: 8581, DefaultState: 8581},
+	{Name: "minecraft:potted_azure_bluet", MinState: 8582, DefaultState: 8582},
+	{Name: "minecraft:potted_red_tulip", MinState: 8583, DefaultState: 8583},
+	{Name: "minecraft:potted_orange_tulip", MinState: 8584, DefaultState: 8584},
+	{Name: "minecraft:potted_white_tulip", MinState: 8585, DefaultState: 8585},
+	{Name: "minecraft:potted_pink_tulip", MinState: 8586, DefaultState: 8586},
+	{Name: "minecraft:potted_oxeye_daisy", MinState: 8587, DefaultState: 8587},
+	{Name: "minecraft:potted_cornflower", MinState: 8588, DefaultState: 8588},
+	{Name: "minecraft:potted_lily_of_the_valley", MinState: 8589, DefaultState: 8589},
+	{Name: "minecraft:potted_wither_rose", MinState: 8590, DefaultState: 8590},
+	{Name: "minecraft:potted_red_mushroom", MinState: 8591, DefaultState: 8591},
+	{Name: "minecraft:potted_brown_mushroom", MinState: 8592, DefaultState: 8592},
+	{Name: "minecraft:potted_dead_bush", MinState: 8593, DefaultState: 8593},
+	{Name: "minecraft:potted_cactus", MinState: 8594, DefaultState: 8594},
+	{Name: "minecraft:carrots", Properties: []*Property{&properties[44]}, MinState: 8595, DefaultState: 8595},
+	{Name: "minecraft:potatoes", Properties: []*Property{&properties[44]}, MinState: 8603, DefaultState: 8603},
+	{Name: "minecraft:oak_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8611, DefaultState: 8620},
+	{Name: "minecraft:spruce_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8635, DefaultState: 8644},
+	{Name: "minecraft:birch_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8659, DefaultState: 8668},
+	{Name: "minecraft:jungle_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8683, DefaultState: 8692},
+	{Name: "minecraft:acacia_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8707, DefaultState: 8716},
+	{Name: "minecraft:cherry_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8731, DefaultState: 8740},
+	{Name: "minecraft:dark_oak_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8755, DefaultState: 8764},
+	{Name: "minecraft:mangrove_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8779, DefaultState: 8788},
+	{Name: "minecraft:bamboo_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 8803, DefaultState: 8812},
+	{Name: "minecraft:skeleton_skull", Properties: []*Property{&properties[14], &properties[47]}, MinState: 8827, DefaultState: 8843},
+	{Name: "minecraft:skeleton_wall_skull", Properties: []*Property{&properties[15], &properties[14]}, MinState: 8859, DefaultState: 8860},
+	{Name: "minecraft:wither_skeleton_skull", Properties: []*Property{&properties[14], &properties[47]}, MinState: 8867, DefaultState: 8883},
+	{Name: "minecraft:wither_skeleton_wall_skull", Properties: []*Property{&properties[15], &properties[14]}, MinState: 8899, DefaultState: 8900},
+	{Name: "minecraft:zombie_head", Properties: []*Property{&properties[14], &properties[47]}, MinState: 8907, DefaultState: 8923},
+	{Name: "minecraft:zombie_wall_head", Properties: []*Property{&properties[15], &properties[14]}, MinState: 8939, DefaultState: 8940},
+	{Name: "minecraft:player_head", Properties: []*Property{&properties[14], &properties[47]}, MinState: 8947, DefaultState: 8963},
+	{Name: "minecraft:player_wall_head", Properties: []*Property{&properties[15], &properties[14]}, MinState: 8979, DefaultState: 8980},
+	{Name: "minecraft:creeper_head", Properties: []*Property{&properties[14], &properties[47]}, MinState: 8987, DefaultState: 9003},
+	{Name: "minecraft:creeper_wall_head", Properties: []*Property{&properties[15], &properties[14]}, MinState: 9019, DefaultState: 9020},
+	{Name: "minecraft:dragon_head", Properties: []*Property{&properties[14], &properties[47]}, MinState: 9027, DefaultState: 9043},
+	{Name: "minecraft:dragon_wall_head", Properties: []*Property{&properties[15], &properties[14]}, MinState: 9059, DefaultState: 9060},
+	{Name: "minecraft:piglin_head", Properties: []*Property{&properties[14], &properties[47]}, MinState: 9067, DefaultState: 9083},
+	{Name: "minecraft:piglin_wall_head", Properties: []*Property{&properties[15], &properties[14]}, MinState: 9099, DefaultState: 9100},
+	{Name: "minecraft:anvil", Properties: []*Property{&properties[15]}, MinState: 9107, DefaultState: 9107},
+	{Name: "minecraft:chipped_anvil", Properties: []*Property{&properties[15]}, MinState: 9111, DefaultState: 9111},
+	{Name: "minecraft:damaged_anvil", Properties: []*Property{&properties[15]}, MinState: 9115, DefaultState: 9115},
+	{Name: "minecraft:trapped_chest", Properties: []*Property{&properties[15], &properties[38], &properties[4]}, MinState: 9119, DefaultState: 9120},
+	{Name: "minecraft:light_weighted_pressure_plate", Properties: []*Property{&properties[41]}, MinState: 9143, DefaultState: 9143},
+	{Name: "minecraft:heavy_weighted_pressure_plate", Properties: []*Property{&properties[41]}, MinState: 9159, DefaultState: 9159},
+	{Name: "minecraft:comparator", Properties: []*Property{&properties[15], &properties[74], &properties[14]}, MinState: 9175, DefaultState: 9176},
+	{Name: "minecraft:daylight_detector", Properties: []*Property{&properties[75], &properties[41]}, MinState: 9191, DefaultState: 9207},
+	{Name: "minecraft:redstone_block", MinState: 9223, DefaultState: 9223, Opacity: 15},
+	{Name: "minecraft:nether_quartz_ore", MinState: 9224, DefaultState: 9224, Opacity: 15},
+	{Name: "minecraft:hopper", Properties: []*Property{&properties[76], &properties[77]}, MinState: 9225, DefaultState: 9225},
+	{Name: "minecraft:quartz_block", MinState: 9235, DefaultState: 9235, Opacity: 15},
+	{Name: "minecraft:chiseled_quartz_block", MinState: 9236, DefaultState: 9236, Opacity: 15},
+	{Name: "minecraft:quartz_pillar", Properties: []*Property{&properties[7]}, MinState: 9237, DefaultState: 9238, Opacity: 15},
+	{Name: "minecraft:quartz_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 9240, DefaultState: 9251},
+	{Name: "minecraft:activator_rail", Properties: []*Property{&properties[14], &properties[18], &properties[4]}, MinState: 9320, DefaultState: 9333},
+	{Name: "minecraft:dropper", Properties: []*Property{&properties[10], &properties[11]}, MinState: 9344, DefaultState: 9345, Opacity: 15},
+	{Name: "minecraft:white_terracotta", MinState: 9356, DefaultState: 9356, Opacity: 15},
+	{Name: "minecraft:orange_terracotta", MinState: 9357, DefaultState: 9357, Opacity: 15},
+	{Name: "minecraft:magenta_terracotta", MinState: 9358, DefaultState: 9358, Opacity: 15},
+	{Name: "minecraft:light_blue_terracotta", MinState: 9359, DefaultState: 9359, Opacity: 15},
+	{Name: "minecraft:yellow_terracotta", MinState: 9360, DefaultState: 9360, Opacity: 15},
+	{Name: "minecraft:lime_terracotta", MinState: 9361, DefaultState: 9361, Opacity: 15},
+	{Name: "minecraft:pink_terracotta", MinState: 9362, DefaultState: 9362, Opacity: 15},
+	{Name: "minecraft:gray_terracotta", MinState: 9363, DefaultState: 9363, Opacity: 15},
+	{Name: "minecraft:light_gray_terracotta", MinState: 9364, DefaultState: 9364, Opacity: 15},
+	{Name: "minecraft:cyan_terracotta", MinState: 9365, DefaultState: 9365, Opacity: 15},
+	{Name: "minecraft:purple_terracotta", MinState: 9366, DefaultState: 9366, Opacity: 15},
+	{Name: "minecraft:blue_terracotta", MinState: 9367, DefaultState: 9367, Opacity: 15},
+	{Name: "minecraft:brown_terracotta", MinState: 9368, DefaultState: 9368, Opacity: 15},
+	{Name: "minecraft:green_terracotta", MinState: 9369, DefaultState: 9369, Opacity: 15},
+	{Name: "minecraft:red_terracotta", MinState: 9370, DefaultState: 9370, Opacity: 15},
+	{Name: "minecraft:black_terracotta", MinState: 9371, DefaultState: 9371, Opacity: 15},
+	{Name: "minecraft:white_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9372, DefaultState: 9403},
+	{Name: "minecraft:orange_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9404, DefaultState: 9435},
+	{Name: "minecraft:magenta_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9436, DefaultState: 9467},
+	{Name: "minecraft:light_blue_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9468, DefaultState: 9499},
+	{Name: "minecraft:yellow_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9500, DefaultState: 9531},
+	{Name: "minecraft:lime_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9532, DefaultState: 9563},
+	{Name: "minecraft:pink_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9564, DefaultState: 9595},
+	{Name: "minecraft:gray_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9596, DefaultState: 9627},
+	{Name: "minecraft:light_gray_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9628, DefaultState: 9659},
+	{Name: "minecraft:cyan_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9660, DefaultState: 9691},
+	{Name: "minecraft:purple_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9692, DefaultState: 9723},
+	{Name: "minecraft:blue_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9724, DefaultState: 9755},
+	{Name: "minecraft:brown_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9756, DefaultState: 9787},
+	{Name: "minecraft:green_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9788, DefaultState: 9819},
+	{Name: "minecraft:red_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9820, DefaultState: 9851},
+	{Name: "minecraft:black_stained_glass_pane", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 9852, DefaultState: 9883},
+	{Name: "minecraft:acacia_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 9884, DefaultState: 9895},
+	{Name: "minecraft:cherry_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 9964, DefaultState: 9975},
+	{Name: "minecraft:dark_oak_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 10044, DefaultState: 10055},
+	{Name: "minecraft:mangrove_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 10124, DefaultState: 10135},
+	{Name: "minecraft:bamboo_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 10204, DefaultState: 10215},
+	{Name: "minecraft:bamboo_mosaic_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 10284, DefaultState: 10295},
+	{Name: "minecraft:slime_block", MinState: 10364, DefaultState: 10364, Opacity: 1},
+	{Name: "minecraft:barrier", Properties: []*Property{&properties[4]}, MinState: 10365, DefaultState: 10366},
+	{Name: "minecraft:light", Properties: []*Property{&properties[5], &properties[4]}, MinState: 10367, DefaultState: 10398, Light: 15},
+	{Name: "minecraft:iron_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 10399, DefaultState: 10414},
+	{Name: "minecraft:prismarine", MinState: 10463, DefaultState: 10463, Opacity: 15},
+	{Name: "minecraft:prismarine_bricks", MinState: 10464, DefaultState: 10464, Opacity: 15},
+	{Name: "minecraft:dark_prismarine", MinState: 10465, DefaultState: 10465, Opacity: 15},
+	{Name: "minecraft:prismarine_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 10466, DefaultState: 10477},
+	{Name: "minecraft:prismarine_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 10546, DefaultState: 10557},
+	{Name: "minecraft:dark_prismarine_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 10626, DefaultState: 10637},
+	{Name: "minecraft:prismarine_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 10706, DefaultState: 10709},
+	{Name: "minecraft:prismarine_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 10712, DefaultState: 10715},
+	{Name: "minecraft:dark_prismarine_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 10718, DefaultState: 10721},
+	{Name: "minecraft:sea_lantern", MinState: 10724, DefaultState: 10724, Light: 15, Opacity: 15},
+	{Name: "minecraft:hay_block", Properties: []*Property{&properties[7]}, MinState: 10725, DefaultState: 10726, Opacity: 15},
+	{Name: "minecraft:white_carpet", MinState: 10728, DefaultState: 10728},
+	{Name: "minecraft:orange_carpet", MinState: 10729, DefaultState: 10729},
+	{Name: "minecraft:magenta_carpet", MinState: 10730, DefaultState: 10730},
+	{Name: "minecraft:light_blue_carpet", MinState: 10731, DefaultState: 10731},
+	{Name: "minecraft:yellow_carpet", MinState: 10732, DefaultState: 10732},
+	{Name: "minecraft:lime_carpet", MinState: 10733, DefaultState: 10733},
+	{Name: "minecraft:pink_carpet", MinState: 10734, DefaultState: 10734},
+	{Name: "minecraft:gray_carpet", MinState: 10735, DefaultState: 10735},
+	{Name: "minecraft:light_gray_carpet", MinState: 10736, DefaultState: 10736},
+	{Name: "minecraft:cyan_carpet", MinState: 10737, DefaultState: 10737},
+	{Name: "minecraft:purple_carpet", MinState: 10738, DefaultState: 10738},
+	{Name: "minecraft:blue_carpet", MinState: 10739, DefaultState: 10739},
+	{Name: "minecraft:brown_carpet", MinState: 10740, DefaultState: 10740},
+	{Name: "minecraft:green_carpet", MinState: 10741, DefaultState: 10741},
+	{Name: "minecraft:red_carpet", MinState: 10742, DefaultState: 10742},
+	{Name: "minecraft:black_carpet", MinState: 10743, DefaultState: 10743},
+	{Name: "minecraft:terracotta", MinState: 10744, DefaultState: 10744, Opacity: 15},
+	{Name: "minecraft:coal_block", MinState: 10745, DefaultState: 10745, Opacity: 15},
+	{Name: "minecraft:packed_ice", MinState: 10746, DefaultState: 10746, Opacity: 15},
+	{Name: "minecraft:sunflower", Properties: []*Property{&properties[20]}, MinState: 10747, DefaultState: 10748},
+	{Name: "minecraft:lilac", Properties: []*Property{&properties[20]}, MinState: 10749, DefaultState: 10750},
+	{Name: "minecraft:rose_bush", Properties: []*Property{&properties[20]}, MinState: 10751, DefaultState: 10752},
+	{Name: "minecraft:peony", Properties: []*Property{&properties[20]}, MinState: 10753, DefaultState: 10754},
+	{Name: "minecraft:tall_grass", Properties: []*Property{&properties[20]}, MinState: 10755, DefaultState: 10756},
+	{Name: "minecraft:large_fern", Properties: []*Property{&properties[20]}, MinState: 10757, DefaultState: 10758},
+	{Name: "minecraft:white_banner", Properties: []*Property{&properties[47]}, MinState: 10759, DefaultState: 10759},
+	{Name: "minecraft:orange_banner", Properties: []*Property{&properties[47]}, MinState: 10775, DefaultState: 10775},
+	{Name: "minecraft:magenta_banner", Properties: []*Property{&properties[47]}, MinState: 10791, DefaultState: 10791},
+	{Name: "minecraft:light_blue_banner", Properties: []*Property{&properties[47]}, MinState: 10807, DefaultState: 10807},
+	{Name: "minecraft:yellow_banner", Properties: []*Property{&properties[47]}, MinState: 10823, DefaultState: 10823},
+	{Name: "minecraft:lime_banner", Properties: []*Property{&properties[47]}, MinState: 10839, DefaultState: 10839},
+	{Name: "minecraft:pink_banner", Properties: []*Property{&properties[47]}, MinState: 10855, DefaultState: 10855},
+	{Name: "minecraft:gray_banner", Properties: []*Property{&properties[47]}, MinState: 10871, DefaultState: 10871},
+	{Name: "minecraft:light_gray_banner", Properties: []*Property{&properties[47]}, MinState: 10887, DefaultState: 10887},
+	{Name: "minecraft:cyan_banner", Properties: []*Property{&properties[47]}, MinState: 10903, DefaultState: 10903},
+	{Name: "minecraft:purple_banner", Properties: []*Property{&properties[47]}, MinState: 10919, DefaultState: 10919},
+	{Name: "minecraft:blue_banner", Properties: []*Property{&properties[47]}, MinState: 10935, DefaultState: 10935},
+	{Name: "minecraft:brown_banner", Properties: []*Property{&properties[47]}, MinState: 10951, DefaultState: 10951},
+	{Name: "minecraft:green_banner", Properties: []*Property{&properties[47]}, MinState: 10967, DefaultState: 10967},
+	{Name: "minecraft:red_banner", Properties: []*Property{&properties[47]}, MinState: 10983, DefaultState: 10983},
+	{Name: "minecraft:black_banner", Properties: []*Property{&properties[47]}, MinState: 10999, DefaultState: 10999},
+	{Name: "minecraft:white_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11015, DefaultState: 11015},
+	{Name: "minecraft:orange_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11019, DefaultState: 11019},
+	{Name: "minecraft:magenta_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11023, DefaultState: 11023},
+	{Name: "minecraft:light_blue_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11027, DefaultState: 11027},
+	{Name: "minecraft:yellow_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11031, DefaultState: 11031},
+	{Name: "minecraft:lime_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11035, DefaultState: 11035},
+	{Name: "minecraft:pink_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11039, DefaultState: 11039},
+	{Name: "minecraft:gray_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11043, DefaultState: 11043},
+	{Name: "minecraft:light_gray_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11047, DefaultState: 11047},
+	{Name: "minecraft:cyan_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11051, DefaultState: 11051},
+	{Name: "minecraft:purple_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11055, DefaultState: 11055},
+	{Name: "minecraft:blue_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11059, DefaultState: 11059},
+	{Name: "minecraft:brown_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11063, DefaultState: 11063},
+	{Name: "minecraft:green_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11067, DefaultState: 11067},
+	{Name: "minecraft:red_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11071, DefaultState: 11071},
+	{Name: "minecraft:black_wall_banner", Properties: []*Property{&properties[15]}, MinState: 11075, DefaultState: 11075},
+	{Name: "minecraft:red_sandstone", MinState: 11079, DefaultState: 11079, Opacity: 15},
+	{Name: "minecraft:chiseled_red_sandstone", MinState: 11080, DefaultState: 11080, Opacity: 15},
+	{Name: "minecraft:cut_red_sandstone", MinState: 11081, DefaultState: 11081, Opacity: 15},
+	{Name: "minecraft:red_sandstone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 11082, DefaultState: 11093},
+	{Name: "minecraft:oak_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11162, DefaultState: 11165},
+	{Name: "minecraft:spruce_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11168, DefaultState: 11171},
+	{Name: "minecraft:birch_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11174, DefaultState: 11177},
+	{Name: "minecraft:jungle_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11180, DefaultState: 11183},
+	{Name: "minecraft:acacia_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11186, DefaultState: 11189},
+	{Name: "minecraft:cherry_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11192, DefaultState: 11195},
+	{Name: "minecraft:dark_oak_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11198, DefaultState: 11201},
+	{Name: "minecraft:mangrove_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11204, DefaultState: 11207},
+	{Name: "minecraft:bamboo_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11210, DefaultState: 11213},
+	{Name: "minecraft:bamboo_mosaic_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11216, DefaultState: 11219},
+	{Name: "minecraft:stone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11222, DefaultState: 11225},
+	{Name: "minecraft:smooth_stone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11228, DefaultState: 11231},
+	{Name: "minecraft:sandstone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11234, DefaultState: 11237},
+	{Name: "minecraft:cut_sandstone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11240, DefaultState: 11243},
+	{Name: "minecraft:petrified_oak_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11246, DefaultState: 11249},
+	{Name: "minecraft:cobblestone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11252, DefaultState: 11255},
+	{Name: "minecraft:brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11258, DefaultState: 11261},
+	{Name: "minecraft:stone_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11264, DefaultState: 11267},
+	{Name: "minecraft:mud_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11270, DefaultState: 11273},
+	{Name: "minecraft:nether_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11276, DefaultState: 11279},
+	{Name: "minecraft:quartz_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11282, DefaultState: 11285},
+	{Name: "minecraft:red_sandstone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11288, DefaultState: 11291},
+	{Name: "minecraft:cut_red_sandstone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11294, DefaultState: 11297},
+	{Name: "minecraft:purpur_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 11300, DefaultState: 11303},
+	{Name: "minecraft:smooth_stone", MinState: 11306, DefaultState: 11306, Opacity: 15},
+	{Name: "minecraft:smooth_sandstone", MinState: 11307, DefaultState: 11307, Opacity: 15},
+	{Name: "minecraft:smooth_quartz", MinState: 11308, DefaultState: 11308, Opacity: 15},
+	{Name: "minecraft:smooth_red_sandstone", MinState: 11309, DefaultState: 11309, Opacity: 15},
+	{Name: "minecraft:spruce_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 11310, DefaultState: 11317},
+	{Name: "minecraft:birch_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 11342, DefaultState: 11349},
+	{Name: "minecraft:jungle_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 11374, DefaultState: 11381},
+	{Name: "minecraft:acacia_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 11406, DefaultState: 11413},
+	{Name: "minecraft:cherry_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 11438, DefaultState: 11445},
+	{Name: "minecraft:dark_oak_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 11470, DefaultState: 11477},
+	{Name: "minecraft:mangrove_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 11502, DefaultState: 11509},
+	{Name: "minecraft:bamboo_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 11534, DefaultState: 11541},
+	{Name: "minecraft:spruce_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 11566, DefaultState: 11597},
+	{Name: "minecraft:birch_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 11598, DefaultState: 11629},
+	{Name: "minecraft:jungle_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 11630, DefaultState: 11661},
+	{Name: "minecraft:acacia_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 11662, DefaultState: 11693},
+	{Name: "minecraft:cherry_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 11694, DefaultState: 11725},
+	{Name: "minecraft:dark_oak_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 11726, DefaultState: 11757},
+	{Name: "minecraft:mangrove_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 11758, DefaultState: 11789},
+	{Name: "minecraft:bamboo_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 11790, DefaultState: 11821},
+	{Name: "minecraft:spruce_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 11822, DefaultState: 11833},
+	{Name: "minecraft:birch_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 11886, DefaultState: 11897},
+	{Name: "minecraft:jungle_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 11950, DefaultState: 11961},
+	{Name: "minecraft:acacia_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 12014, DefaultState: 12025},
+	{Name: "minecraft:cherry_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 12078, DefaultState: 12089},
+	{Name: "minecraft:dark_oak_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 12142, DefaultState: 12153},
+	{Name: "minecraft:mangrove_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 12206, DefaultState: 12217},
+	{Name: "minecraft:bamboo_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 12270, DefaultState: 12281},
+	{Name: "minecraft:end_rod", Properties: []*Property{&properties[10]}, MinState: 12334, DefaultState: 12338, Light: 14},
+	{Name: "minecraft:chorus_plant", Properties: []*Property{&properties[59], &properties[31], &properties[32], &properties[33], &properties[34], &properties[35]}, MinState: 12340, DefaultState: 12403, Opacity: 1},
+	{Name: "minecraft:chorus_flower", Properties: []*Property{&properties[79]}, MinState: 12404, DefaultState: 12404, Opacity: 1},
+	{Name: "minecraft:purpur_block", MinState: 12410, DefaultState: 12410, Opacity: 15},
+	{Name: "minecraft:purpur_pillar", Properties: []*Property{&properties[7]}, MinState: 12411, DefaultState: 12412, Opacity: 15},
+	{Name: "minecraft:purpur_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 12414, DefaultState: 12425},
+	{Name: "minecraft:end_stone_bricks", MinState: 12494, DefaultState: 12494, Opacity: 15},
+	{Name: "minecraft:torchflower_crop", Properties: []*Property{&properties[80]}, MinState: 12495, DefaultState: 12495},
+	{Name: "minecraft:pitcher_crop", Properties: []*Property{&properties[2], &properties[20]}, MinState: 12497, DefaultState: 12498},
+	{Name: "minecraft:pitcher_plant", Properties: []*Property{&properties[20]}, MinState: 12507, DefaultState: 12508},
+	{Name: "minecraft:beetroots", Properties: []*Property{&properties[61]}, MinState: 12509, DefaultState: 12509},
+	{Name: "minecraft:dirt_path", MinState: 12513, DefaultState: 12513},
+	{Name: "minecraft:end_gateway", MinState: 12514, DefaultState: 12514, Light: 15, Opacity: 1},
+	{Name: "minecraft:repeating_command_block", Properties: []*Property{&properties[69], &properties[10]}, MinState: 12515, DefaultState: 12521, Opacity: 15},
+	{Name: "minecraft:chain_command_block", Properties: []*Property{&properties[69], &properties[10]}, MinState: 12527, DefaultState: 12533, Opacity: 15},
+	{Name: "minecraft:frosted_ice", Properties: []*Property{&properties[61]}, MinState: 12539, DefaultState: 12539, Opacity: 1},
+	{Name: "minecraft:magma_block", MinState: 12543, DefaultState: 12543, Light: 3, Opacity: 15},
+	{Name: "minecraft:nether_wart_block", MinState: 12544, DefaultState: 12544, Opacity: 15},
+	{Name: "minecraft:red_nether_bricks", MinState: 12545, DefaultState: 12545, Opacity: 15},
+	{Name: "minecraft:bone_block", Properties: []*Property{&properties[7]}, MinState: 12546, DefaultState: 12547, Opacity: 15},
+	{Name: "minecraft:structure_void", MinState: 12549, DefaultState: 12549},
+	{Name: "minecraft:observer", Properties: []*Property{&properties[10], &properties[14]}, MinState: 12550, DefaultState: 12555, Opacity: 15},
+	{Name: "minecraft:shulker_box", Properties: []*Property{&properties[10]}, MinState: 12562, DefaultState: 12566, Opacity: 1},
+	{Name: "minecraft:white_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12568, DefaultState: 12572, Opacity: 1},
+	{Name: "minecraft:orange_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12574, DefaultState: 12578, Opacity: 1},
+	{Name: "minecraft:magenta_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12580, DefaultState: 12584, Opacity: 1},
+	{Name: "minecraft:light_blue_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12586, DefaultState: 12590, Opacity: 1},
+	{Name: "minecraft:yellow_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12592, DefaultState: 12596, Opacity: 1},
+	{Name: "minecraft:lime_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12598, DefaultState: 12602, Opacity: 1},
+	{Name: "minecraft:pink_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12604, DefaultState: 12608, Opacity: 1},
+	{Name: "minecraft:gray_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12610, DefaultState: 12614, Opacity: 1},
+	{Name: "minecraft:light_gray_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12616, DefaultState: 12620, Opacity: 1},
+	{Name: "minecraft:cyan_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12622, DefaultState: 12626, Opacity: 1},
+	{Name: "minecraft:purple_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12628, DefaultState: 12632, Opacity: 1},
+	{Name: "minecraft:blue_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12634, DefaultState: 12638, Opacity: 1},
+	{Name: "minecraft:brown_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12640, DefaultState: 12644, Opacity: 1},
+	{Name: "minecraft:green_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12646, DefaultState: 12650, Opacity: 1},
+	{Name: "minecraft:red_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12652, DefaultState: 12656, Opacity: 1},
+	{Name: "minecraft:black_shulker_box", Properties: []*Property{&properties[10]}, MinState: 12658, DefaultState: 12662, Opacity: 1},
+	{Name: "minecraft:white_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12664, DefaultState: 12664, Opacity: 15},
+	{Name: "minecraft:orange_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12668, DefaultState: 12668, Opacity: 15},
+	{Name: "minecraft:magenta_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12672, DefaultState: 12672, Opacity: 15},
+	{Name: "minecraft:light_blue_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12676, DefaultState: 12676, Opacity: 15},
+	{Name: "minecraft:yellow_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12680, DefaultState: 12680, Opacity: 15},
+	{Name: "minecraft:lime_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12684, DefaultState: 12684, Opacity: 15},
+	{Name: "minecraft:pink_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12688, DefaultState: 12688, Opacity: 15},
+	{Name: "minecraft:gray_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12692, DefaultState: 12692, Opacity: 15},
+	{Name: "minecraft:light_gray_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12696, DefaultState: 12696, Opacity: 15},
+	{Name: "minecraft:cyan_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12700, DefaultState: 12700, Opacity: 15},
+	{Name: "minecraft:purple_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12704, DefaultState: 12704, Opacity: 15},
+	{Name: "minecraft:blue_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12708, DefaultState: 12708, Opacity: 15},
+	{Name: "minecraft:brown_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12712, DefaultState: 12712, Opacity: 15},
+	{Name: "minecraft:green_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12716, DefaultState: 12716, Opacity: 15},
+	{Name: "minecraft:red_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12720, DefaultState: 12720, Opacity: 15},
+	{Name: "minecraft:black_glazed_terracotta", Properties: []*Property{&properties[15]}, MinState: 12724, DefaultState: 12724, Opacity: 15},
+	{Name: "minecraft:white_concrete", MinState: 12728, DefaultState: 12728, Opacity: 15},
+	{Name: "minecraft:orange_concrete", MinState: 12729, DefaultState: 12729, Opacity: 15},
+	{Name: "minecraft:magenta_concrete", MinState: 12730, DefaultState: 12730, Opacity: 15},
+	{Name: "minecraft:light_blue_concrete", MinState: 12731, DefaultState: 12731, Opacity: 15},
+	{Name: "minecraft:yellow_concrete", MinState: 12732, DefaultState: 12732, Opacity: 15},
+	{Name: "minecraft:lime_concrete", MinState: 12733, DefaultState: 12733, Opacity: 15},
+	{Name: "minecraft:pink_concrete", MinState: 12734, DefaultState: 12734, Opacity: 15},
+	{Name: "minecraft:gray_concrete", MinState: 12735, DefaultState: 12735, Opacity: 15},
+	{Name: "minecraft:light_gray_concrete", MinState: 12736, DefaultState: 12736, Opacity: 15},
+	{Name: "minecraft:cyan_concrete", MinState: 12737, DefaultState: 12737, Opacity: 15},
+	{Name: "minecraft:purple_concrete", MinState: 12738, DefaultState: 12738, Opacity: 15},
+	{Name: "minecraft:blue_concrete", MinState: 12739, DefaultState: 12739, Opacity: 15},
+	{Name: "minecraft:brown_concrete", MinState: 12740, DefaultState: 12740, Opacity: 15},
+	{Name: "minecraft:green_concrete", MinState: 12741, DefaultState: 12741, Opacity: 15},
+	{Name: "minecraft:red_concrete", MinState: 12742, DefaultState: 12742, Opacity: 15},
+	{Name: "minecraft:black_concrete", MinState: 12743, DefaultState: 12743, Opacity: 15},
+	{Name: "minecraft:white_concrete_powder", MinState: 12744, DefaultState: 12744, Opacity: 15},
+	{Name: "minecraft:orange_concrete_powder", MinState: 12745, DefaultState: 12745, Opacity: 15},
+	{Name: "minecraft:magenta_concrete_powder", MinState: 12746, DefaultState: 12746, Opacity: 15},
+	{Name: "minecraft:light_blue_concrete_powder", MinState: 12747, DefaultState: 12747, Opacity: 15},
+	{Name: "minecraft:yellow_concrete_powder", MinState: 12748, DefaultState: 12748, Opacity: 15},
+	{Name: "minecraft:lime_concrete_powder", MinState: 12749, DefaultState: 12749, Opacity: 15},
+	{Name: "minecraft:pink_concrete_powder", MinState: 12750, DefaultState: 12750, Opacity: 15},
+	{Name: "minecraft:gray_concrete_powder", MinState: 12751, DefaultState: 12751, Opacity: 15},
+	{Name: "minecraft:light_gray_concrete_powder", MinState: 12752, DefaultState: 12752, Opacity: 15},
+	{Name: "minecraft:cyan_concrete_powder", MinState: 12753, DefaultState: 12753, Opacity: 15},
+	{Name: "minecraft:purple_concrete_powder", MinState: 12754, DefaultState: 12754, Opacity: 15},
+	{Name: "minecraft:blue_concrete_powder", MinState: 12755, DefaultState: 12755, Opacity: 15},
+	{Name: "minecraft:brown_concrete_powder", MinState: 12756, DefaultState: 12756, Opacity: 15},
+	{Name: "minecraft:green_concrete_powder", MinState: 12757, DefaultState: 12757, Opacity: 15},
+	{Name: "minecraft:red_concrete_powder", MinState: 12758, DefaultState: 12758, Opacity: 15},
+	{Name: "minecraft:black_concrete_powder", MinState: 12759, DefaultState: 12759, Opacity: 15},
+	{Name: "minecraft:kelp", Properties: []*Property{&properties[81]}, MinState: 12760, DefaultState: 12760, Opacity: 1},
+	{Name: "minecraft:kelp_plant", MinState: 12786, DefaultState: 12786, Opacity: 1},
+	{Name: "minecraft:dried_kelp_block", MinState: 12787, DefaultState: 12787, Opacity: 15},
+	{Name: "minecraft:turtle_egg", Properties: []*Property{&properties[82], &properties[83]}, MinState: 12788, DefaultState: 12788},
+	{Name: "minecraft:sniffer_egg", Properties: []*Property{&properties[83]}, MinState: 12800, DefaultState: 12800},
+	{Name: "minecraft:dead_tube_coral_block", MinState: 12803, DefaultState: 12803, Opacity: 15},
+	{Name: "minecraft:dead_brain_coral_block", MinState: 12804, DefaultState: 12804, Opacity: 15},
+	{Name: "minecraft:dead_bubble_coral_block", MinState: 12805, DefaultState: 12805, Opacity: 15},
+	{Name: "minecraft:dead_fire_coral_block", MinState: 12806, DefaultState: 12806, Opacity: 15},
+	{Name: "minecraft:dead_horn_coral_block", MinState: 12807, DefaultState: 12807, Opacity: 15},
+	{Name: "minecraft:tube_coral_block", MinState: 12808, DefaultState: 12808, Opacity: 15},
+	{Name: "minecraft:brain_coral_block", MinState: 12809, DefaultState: 12809, Opacity: 15},
+	{Name: "minecraft:bubble_coral_block", MinState: 12810, DefaultState: 12810, Opacity: 15},
+	{Name: "minecraft:fire_coral_block", MinState: 12811, DefaultState: 12811, Opacity: 15},
+	{Name: "minecraft:horn_coral_block", MinState: 12812, DefaultState: 12812, Opacity: 15},
+	{Name: "minecraft:dead_tube_coral", Properties: []*Property{&properties[4]}, MinState: 12813, DefaultState: 12813, Opacity: 1},
+	{Name: "minecraft:dead_brain_coral", Properties: []*Property{&properties[4]}, MinState: 12815, DefaultState: 12815, Opacity: 1},
+	{Name: "minecraft:dead_bubble_coral", Properties: []*Property{&properties[4]}, MinState: 12817, DefaultState: 12817, Opacity: 1},
+	{Name: "minecraft:dead_fire_coral", Properties: []*Property{&properties[4]}, MinState: 12819, DefaultState: 12819, Opacity: 1},
+	{Name: "minecraft:dead_horn_coral", Properties: []*Property{&properties[4]}, MinState: 12821, DefaultState: 12821, Opacity: 1},
+	{Name: "minecraft:tube_coral", Properties: []*Property{&properties[4]}, MinState: 12823, DefaultState: 12823, Opacity: 1},
+	{Name: "minecraft:brain_coral", Properties: []*Property{&properties[4]}, MinState: 12825, DefaultState: 12825, Opacity: 1},
+	{Name: "minecraft:bubble_coral", Properties: []*Property{&properties[4]}, MinState: 12827, DefaultState: 12827, Opacity: 1},
+	{Name: "minecraft:fire_coral", Properties: []*Property{&properties[4]}, MinState: 12829, DefaultState: 12829, Opacity: 1},
+	{Name: "minecraft:horn_coral", Properties: []*Property{&properties[4]}, MinState: 12831, DefaultState: 12831, Opacity: 1},
+	{Name: "minecraft:dead_tube_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12833, DefaultState: 12833, Opacity: 1},
+	{Name: "minecraft:dead_brain_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12835, DefaultState: 12835, Opacity: 1},
+	{Name: "minecraft:dead_bubble_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12837, DefaultState: 12837, Opacity: 1},
+	{Name: "minecraft:dead_fire_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12839, DefaultState: 12839, Opacity: 1},
+	{Name: "minecraft:dead_horn_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12841, DefaultState: 12841, Opacity: 1},
+	{Name: "minecraft:tube_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12843, DefaultState: 12843, Opacity: 1},
+	{Name: "minecraft:brain_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12845, DefaultState: 12845, Opacity: 1},
+	{Name: "minecraft:bubble_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12847, DefaultState: 12847, Opacity: 1},
+	{Name: "minecraft:fire_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12849, DefaultState: 12849, Opacity: 1},
+	{Name: "minecraft:horn_coral_fan", Properties: []*Property{&properties[4]}, MinState: 12851, DefaultState: 12851, Opacity: 1},
+	{Name: "minecraft:dead_tube_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12853, DefaultState: 12853, Opacity: 1},
+	{Name: "minecraft:dead_brain_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12861, DefaultState: 12861, Opacity: 1},
+	{Name: "minecraft:dead_bubble_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12869, DefaultState: 12869, Opacity: 1},
+	{Name: "minecraft:dead_fire_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12877, DefaultState: 12877, Opacity: 1},
+	{Name: "minecraft:dead_horn_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12885, DefaultState: 12885, Opacity: 1},
+	{Name: "minecraft:tube_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12893, DefaultState: 12893, Opacity: 1},
+	{Name: "minecraft:brain_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12901, DefaultState: 12901, Opacity: 1},
+	{Name: "minecraft:bubble_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12909, DefaultState: 12909, Opacity: 1},
+	{Name: "minecraft:fire_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12917, DefaultState: 12917, Opacity: 1},
+	{Name: "minecraft:horn_coral_wall_fan", Properties: []*Property{&properties[15], &properties[4]}, MinState: 12925, DefaultState: 12925, Opacity: 1},
+	{Name: "minecraft:sea_pickle", Properties: []*Property{&properties[84], &properties[4]}, MinState: 12933, DefaultState: 12933, Light: 6, Opacity: 1},
+	{Name: "minecraft:blue_ice", MinState: 12941, DefaultState: 12941, Opacity: 15},
+	{Name: "minecraft:conduit", Properties: []*Property{&properties[4]}, MinState: 12942, DefaultState: 12942, Light: 15, Opacity: 1},
+	{Name: "minecraft:bamboo_sapling", MinState: 12944, DefaultState: 12944},
+	{Name: "minecraft:bamboo", Properties: []*Property{&properties[80], &properties[85], &properties[1]}, MinState: 12945, DefaultState: 12945},
+	{Name: "minecraft:potted_bamboo", MinState: 12957, DefaultState: 12957},
+	{Name: "minecraft:void_air", MinState: 12958, DefaultState: 12958},
+	{Name: "minecraft:cave_air", MinState: 12959, DefaultState: 12959},
+	{Name: "minecraft:bubble_column", Properties: []*Property{&properties[86]}, MinState: 12960, DefaultState: 12960, Opacity: 1},
+	{Name: "minecraft:polished_granite_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 12962, DefaultState: 12973},
+	{Name: "minecraft:smooth_red_sandstone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13042, DefaultState: 13053},
+	{Name: "minecraft:mossy_stone_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13122, DefaultState: 13133},
+	{Name: "minecraft:polished_diorite_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13202, DefaultState: 13213},
+	{Name: "minecraft:mossy_cobblestone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13282, DefaultState: 13293},
+	{Name: "minecraft:end_stone_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13362, DefaultState: 13373},
+	{Name: "minecraft:stone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13442, DefaultState: 13453},
+	{Name: "minecraft:smooth_sandstone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13522, DefaultState: 13533},
+	{Name: "minecraft:smooth_quartz_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13602, DefaultState: 13613},
+	{Name: "minecraft:granite_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13682, DefaultState: 13693},
+	{Name: "minecraft:andesite_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13762, DefaultState: 13773},
+	{Name: "minecraft:red_nether_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13842, DefaultState: 13853},
+	{Name: "minecraft:polished_andesite_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 13922, DefaultState: 13933},
+	{Name: "minecraft:diorite_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 14002, DefaultState: 14013},
+	{Name: "minecraft:polished_granite_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14082, DefaultState: 14085},
+	{Name: "minecraft:smooth_red_sandstone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14088, DefaultState: 14091},
+	{Name: "minecraft:mossy_stone_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14094, DefaultState: 14097},
+	{Name: "minecraft:polished_diorite_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14100, DefaultState: 14103},
+	{Name: "minecraft:mossy_cobblestone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14106, DefaultState: 14109},
+	{Name: "minecraft:end_stone_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14112, DefaultState: 14115},
+	{Name: "minecraft:smooth_sandstone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14118, DefaultState: 14121},
+	{Name: "minecraft:smooth_quartz_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14124, DefaultState: 14127},
+	{Name: "minecraft:granite_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14130, DefaultState: 14133},
+	{Name: "minecraft:andesite_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14136, DefaultState: 14139},
+	{Name: "minecraft:red_nether_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14142, DefaultState: 14145},
+	{Name: "minecraft:polished_andesite_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14148, DefaultState: 14151},
+	{Name: "minecraft:diorite_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 14154, DefaultState: 14157},
+	{Name: "minecraft:brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 14160, DefaultState: 14163},
+	{Name: "minecraft:prismarine_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 14484, DefaultState: 14487},
+	{Name: "minecraft:red_sandstone_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 14808, DefaultState: 14811},
+	{Name: "minecraft:mossy_stone_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 15132, DefaultState: 15135},
+	{Name: "minecraft:granite_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 15456, DefaultState: 15459},
+	{Name: "minecraft:stone_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 15780, DefaultState: 15783},
+	{Name: "minecraft:mud_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 16104, DefaultState: 16107},
+	{Name: "minecraft:nether_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 16428, DefaultState: 16431},
+	{Name: "minecraft:andesite_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 16752, DefaultState: 16755},
+	{Name: "minecraft:red_nether_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 17076, DefaultState: 17079},
+	{Name: "minecraft:sandstone_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 17400, DefaultState: 17403},
+	{Name: "minecraft:end_stone_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 17724, DefaultState: 17727},
+	{Name: "minecraft:diorite_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 18048, DefaultState: 18051},
+	{Name: "minecraft:scaffolding", Properties: []*Property{&properties[87], &properties[88], &properties[4]}, MinState: 18372, DefaultState: 18403},
+	{Name: "minecraft:loom", Properties: []*Property{&properties[15]}, MinState: 18404, DefaultState: 18404, Opacity: 15},
+	{Name: "minecraft:barrel", Properties: []*Property{&properties[10], &properties[49]}, MinState: 18408, DefaultState: 18409, Opacity: 15},
+	{Name: "minecraft:smoker", Properties: []*Property{&properties[15], &properties[46]}, MinState: 18420, DefaultState: 18421, Light: 13, Opacity: 15},
+	{Name: "minecraft:blast_furnace", Properties: []*Property{&properties[15], &properties[46]}, MinState: 18428, DefaultState: 18429, Light: 13, Opacity: 15},
+	{Name: "minecraft:cartography_table", MinState: 18436, DefaultState: 18436, Opacity: 15},
+	{Name: "minecraft:fletching_table", MinState: 18437, DefaultState: 18437, Opacity: 15},
+	{Name: "minecraft:grindstone", Properties: []*Property{&properties[52], &properties[15]}, MinState: 18438, DefaultState: 18442},
+	{Name: "minecraft:lectern", Properties: []*Property{&properties[15], &properties[89], &properties[14]}, MinState: 18450, DefaultState: 18453},
+	{Name: "minecraft:smithing_table", MinState: 18466, DefaultState: 18466, Opacity: 15},
+	{Name: "minecraft:stonecutter", Properties: []*Property{&properties[15]}, MinState: 18467, DefaultState: 18467},
+	{Name: "minecraft:bell", Properties: []*Property{&properties[90], &properties[15], &properties[14]}, MinState: 18471, DefaultState: 18472},
+	{Name: "minecraft:lantern", Properties: []*Property{&properties[3], &properties[4]}, MinState: 18503, DefaultState: 18506, Light: 15},
+	{Name: "minecraft:soul_lantern", Properties: []*Property{&properties[3], &properties[4]}, MinState: 18507, DefaultState: 18510, Light: 10},
+	{Name: "minecraft:campfire", Properties: []*Property{&properties[15], &properties[46], &properties[91], &properties[4]}, MinState: 18511, DefaultState: 18514, Light: 15},
+	{Name: "minecraft:soul_campfire", Properties: []*Property{&properties[15], &properties[46], &properties[91], &properties[4]}, MinState: 18543, DefaultState: 18546, Light: 10},
+	{Name: "minecraft:sweet_berry_bush", Properties: []*Property{&properties[61]}, MinState: 18575, DefaultState: 18575},
+	{Name: "minecraft:warped_stem", Properties: []*Property{&properties[7]}, MinState: 18579, DefaultState: 18580, Opacity: 15},
+	{Name: "minecraft:stripped_warped_stem", Properties: []*Property{&properties[7]}, MinState: 18582, DefaultState: 18583, Opacity: 15},
+	{Name: "minecraft:warped_hyphae", Properties: []*Property{&properties[7]}, MinState: 18585, DefaultState: 18586, Opacity: 15},
+	{Name: "minecraft:stripped_warped_hyphae", Properties: []*Property{&properties[7]}, MinState: 18588, DefaultState: 18589, Opacity: 15},
+	{Name: "minecraft:warped_nylium", MinState: 18591, DefaultState: 18591, Opacity: 15},
+	{Name: "minecraft:warped_fungus", MinState: 18592, DefaultState: 18592},
+	{Name: "minecraft:warped_wart_block", MinState: 18593, DefaultState: 18593, Opacity: 15},
+	{Name: "minecraft:warped_roots", MinState: 18594, DefaultState: 18594},
+	{Name: "minecraft:nether_sprouts", MinState: 18595, DefaultState: 18595},
+	{Name: "minecraft:crimson_stem", Properties: []*Property{&properties[7]}, MinState: 18596, DefaultState: 18597, Opacity: 15},
+	{Name: "minecraft:stripped_crimson_stem", Properties: []*Property{&properties[7]}, MinState: 18599, DefaultState: 18600, Opacity: 15},
+	{Name: "minecraft:crimson_hyphae", Properties: []*Property{&properties[7]}, MinState: 18602, DefaultState: 18603, Opacity: 15},
+	{Name: "minecraft:stripped_crimson_hyphae", Properties: []*Property{&properties[7]}, MinState: 18605, DefaultState: 18606, Opacity: 15},
+	{Name: "minecraft:crimson_nylium", MinState: 18608, DefaultState: 18608, Opacity: 15},
+	{Name: "minecraft:crimson_fungus", MinState: 18609, DefaultState: 18609},
+	{Name: "minecraft:shroomlight", MinState: 18610, DefaultState: 18610, Light: 15, Opacity: 15},
+	{Name: "minecraft:weeping_vines", Properties: []*Property{&properties[81]}, MinState: 18611, DefaultState: 18611},
+	{Name: "minecraft:weeping_vines_plant", MinState: 18637, DefaultState: 18637},
+	{Name: "minecraft:twisting_vines", Properties: []*Property{&properties[81]}, MinState: 18638, DefaultState: 18638},
+	{Name: "minecraft:twisting_vines_plant", MinState: 18664, DefaultState: 18664},
+	{Name: "minecraft:crimson_roots", MinState: 18665, DefaultState: 18665},
+	{Name: "minecraft:crimson_planks", MinState: 18666, DefaultState: 18666, Opacity: 15},
+	{Name: "minecraft:warped_planks", MinState: 18667, DefaultState: 18667, Opacity: 15},
+	{Name: "minecraft:crimson_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 18668, DefaultState: 18671},
+	{Name: "minecraft:warped_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 18674, DefaultState: 18677},
+	{Name: "minecraft:crimson_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 18680, DefaultState: 18681},
+	{Name: "minecraft:warped_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 18682, DefaultState: 18683},
+	{Name: "minecraft:crimson_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 18684, DefaultState: 18715},
+	{Name: "minecraft:warped_fence", Properties: []*Property{&properties[31], &properties[32], &properties[33], &properties[4], &properties[35]}, MinState: 18716, DefaultState: 18747},
+	{Name: "minecraft:crimson_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 18748, DefaultState: 18763},
+	{Name: "minecraft:warped_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 18812, DefaultState: 18827},
+	{Name: "minecraft:crimson_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 18876, DefaultState: 18883},
+	{Name: "minecraft:warped_fence_gate", Properties: []*Property{&properties[15], &properties[60], &properties[49], &properties[14]}, MinState: 18908, DefaultState: 18915},
+	{Name: "minecraft:crimson_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 18940, DefaultState: 18951},
+	{Name: "minecraft:warped_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 19020, DefaultState: 19031},
+	{Name: "minecraft:crimson_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 19100, DefaultState: 19109},
+	{Name: "minecraft:warped_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 19124, DefaultState: 19133},
+	{Name: "minecraft:crimson_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 19148, DefaultState: 19159},
+	{Name: "minecraft:warped_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 19212, DefaultState: 19223},
+	{Name: "minecraft:crimson_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 19276, DefaultState: 19277},
+	{Name: "minecraft:warped_sign", Properties: []*Property{&properties[47], &properties[4]}, MinState: 19308, DefaultState: 19309},
+	{Name: "minecraft:crimson_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 19340, DefaultState: 19341},
+	{Name: "minecraft:warped_wall_sign", Properties: []*Property{&properties[15], &properties[4]}, MinState: 19348, DefaultState: 19349},
+	{Name: "minecraft:structure_block", Properties: []*Property{&properties[92]}, MinState: 19356, DefaultState: 19357, Opacity: 15},
+	{Name: "minecraft:jigsaw", Properties: []*Property{&properties[93]}, MinState: 19360, DefaultState: 19370, Opacity: 15},
+	{Name: "minecraft:composter", Properties: []*Property{&properties[94]}, MinState: 19372, DefaultState: 19372},
+	{Name: "minecraft:target", Properties: []*Property{&properties[41]}, MinState: 19381, DefaultState: 19381, Opacity: 15},
+	{Name: "minecraft:bee_nest", Properties: []*Property{&properties[15], &properties[95]}, MinState: 19397, DefaultState: 19397, Opacity: 15},
+	{Name: "minecraft:beehive", Properties: []*Property{&properties[15], &properties[95]}, MinState: 19421, DefaultState: 19421, Opacity: 15},
+	{Name: "minecraft:honey_block", MinState: 19445, DefaultState: 19445, Opacity: 1},
+	{Name: "minecraft:honeycomb_block", MinState: 19446, DefaultState: 19446, Opacity: 15},
+	{Name: "minecraft:netherite_block", MinState: 19447, DefaultState: 19447, Opacity: 15},
+	{Name: "minecraft:ancient_debris", MinState: 19448, DefaultState: 19448, Opacity: 15},
+	{Name: "minecraft:crying_obsidian", MinState: 19449, DefaultState: 19449, Light: 10, Opacity: 15},
+	{Name: "minecraft:respawn_anchor", Properties: []*Property{&properties[96]}, MinState: 19450, DefaultState: 19450, Light: 15, Opacity: 15},
+	{Name: "minecraft:potted_crimson_fungus", MinState: 19455, DefaultState: 19455},
+	{Name: "minecraft:potted_warped_fungus", MinState: 19456, DefaultState: 19456},
+	{Name: "minecraft:potted_crimson_roots", MinState: 19457, DefaultState: 19457},
+	{Name: "minecraft:potted_warped_roots", MinState: 19458, DefaultState: 19458},
+	{Name: "minecraft:lodestone", MinState: 19459, DefaultState: 19459, Opacity: 15},
+	{Name: "minecraft:blackstone", MinState: 19460, DefaultState: 19460, Opacity: 15},
+	{Name: "minecraft:blackstone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 19461, DefaultState: 19472},
+	{Name: "minecraft:blackstone_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 19541, DefaultState: 19544},
+	{Name: "minecraft:blackstone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 19865, DefaultState: 19868},
+	{Name: "minecraft:polished_blackstone", MinState: 19871, DefaultState: 19871, Opacity: 15},
+	{Name: "minecraft:polished_blackstone_bricks", MinState: 19872, DefaultState: 19872, Opacity: 15},
+	{Name: "minecraft:cracked_polished_blackstone_bricks", MinState: 19873, DefaultState: 19873, Opacity: 15},
+	{Name: "minecraft:chiseled_polished_blackstone", MinState: 19874, DefaultState: 19874, Opacity: 15},
+	{Name: "minecraft:polished_blackstone_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 19875, DefaultState: 19878},
+	{Name: "minecraft:polished_blackstone_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 19881, DefaultState: 19892},
+	{Name: "minecraft:polished_blackstone_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 19961, DefaultState: 19964},
+	{Name: "minecraft:gilded_blackstone", MinState: 20285, DefaultState: 20285, Opacity: 15},
+	{Name: "minecraft:polished_blackstone_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 20286, DefaultState: 20297},
+	{Name: "minecraft:polished_blackstone_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 20366, DefaultState: 20369},
+	{Name: "minecraft:polished_blackstone_pressure_plate", Properties: []*Property{&properties[14]}, MinState: 20372, DefaultState: 20373},
+	{Name: "minecraft:polished_blackstone_button", Properties: []*Property{&properties[52], &properties[15], &properties[14]}, MinState: 20374, DefaultState: 20383},
+	{Name: "minecraft:polished_blackstone_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 20398, DefaultState: 20401},
+	{Name: "minecraft:chiseled_nether_bricks", MinState: 20722, DefaultState: 20722, Opacity: 15},
+	{Name: "minecraft:cracked_nether_bricks", MinState: 20723, DefaultState: 20723, Opacity: 15},
+	{Name: "minecraft:quartz_bricks", MinState: 20724, DefaultState: 20724, Opacity: 15},
+	{Name: "minecraft:candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20725, DefaultState: 20728, Light: 3},
+	{Name: "minecraft:white_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20741, DefaultState: 20744, Light: 3},
+	{Name: "minecraft:orange_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20757, DefaultState: 20760, Light: 3},
+	{Name: "minecraft:magenta_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20773, DefaultState: 20776, Light: 3},
+	{Name: "minecraft:light_blue_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20789, DefaultState: 20792, Light: 3},
+	{Name: "minecraft:yellow_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20805, DefaultState: 20808, Light: 3},
+	{Name: "minecraft:lime_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20821, DefaultState: 20824, Light: 3},
+	{Name: "minecraft:pink_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20837, DefaultState: 20840, Light: 3},
+	{Name: "minecraft:gray_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20853, DefaultState: 20856, Light: 3},
+	{Name: "minecraft:light_gray_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20869, DefaultState: 20872, Light: 3},
+	{Name: "minecraft:cyan_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20885, DefaultState: 20888, Light: 3},
+	{Name: "minecraft:purple_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20901, DefaultState: 20904, Light: 3},
+	{Name: "minecraft:blue_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20917, DefaultState: 20920, Light: 3},
+	{Name: "minecraft:brown_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20933, DefaultState: 20936, Light: 3},
+	{Name: "minecraft:green_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20949, DefaultState: 20952, Light: 3},
+	{Name: "minecraft:red_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20965, DefaultState: 20968, Light: 3},
+	{Name: "minecraft:black_candle", Properties: []*Property{&properties[97], &properties[46], &properties[4]}, MinState: 20981, DefaultState: 20984, Light: 3},
+	{Name: "minecraft:candle_cake", Properties: []*Property{&properties[46]}, MinState: 20997, DefaultState: 20998, Light: 3},
+	{Name: "minecraft:white_candle_cake", Properties: []*Property{&properties[46]}, MinState: 20999, DefaultState: 21000, Light: 3},
+	{Name: "minecraft:orange_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21001, DefaultState: 21002, Light: 3},
+	{Name: "minecraft:magenta_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21003, DefaultState: 21004, Light: 3},
+	{Name: "minecraft:light_blue_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21005, DefaultState: 21006, Light: 3},
+	{Name: "minecraft:yellow_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21007, DefaultState: 21008, Light: 3},
+	{Name: "minecraft:lime_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21009, DefaultState: 21010, Light: 3},
+	{Name: "minecraft:pink_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21011, DefaultState: 21012, Light: 3},
+	{Name: "minecraft:gray_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21013, DefaultState: 21014, Light: 3},
+	{Name: "minecraft:light_gray_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21015, DefaultState: 21016, Light: 3},
+	{Name: "minecraft:cyan_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21017, DefaultState: 21018, Light: 3},
+	{Name: "minecraft:purple_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21019, DefaultState: 21020, Light: 3},
+	{Name: "minecraft:blue_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21021, DefaultState: 21022, Light: 3},
+	{Name: "minecraft:brown_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21023, DefaultState: 21024, Light: 3},
+	{Name: "minecraft:green_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21025, DefaultState: 21026, Light: 3},
+	{Name: "minecraft:red_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21027, DefaultState: 21028, Light: 3},
+	{Name: "minecraft:black_candle_cake", Properties: []*Property{&properties[46]}, MinState: 21029, DefaultState: 21030, Light: 3},
+	{Name: "minecraft:amethyst_block", MinState: 21031, DefaultState: 21031, Opacity: 15},
+	{Name: "minecraft:budding_amethyst", MinState: 21032, DefaultState: 21032, Opacity: 15},
+	{Name: "minecraft:amethyst_cluster", Properties: []*Property{&properties[10], &properties[4]}, MinState: 21033, DefaultState: 21042, Light: 5},
+	{Name: "minecraft:large_amethyst_bud", Properties: []*Property{&properties[10], &properties[4]}, MinState: 21045, DefaultState: 21054, Light: 4},
+	{Name: "minecraft:medium_amethyst_bud", Properties: []*Property{&properties[10], &properties[4]}, MinState: 21057, DefaultState: 21066, Light: 2},
+	{Name: "minecraft:small_amethyst_bud", Properties: []*Property{&properties[10], &properties[4]}, MinState: 21069, DefaultState: 21078, Light: 1},
+	{Name: "minecraft:tuff", MinState: 21081, DefaultState: 21081, Opacity: 15},
+	{Name: "minecraft:tuff_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 21082, DefaultState: 21085},
+	{Name: "minecraft:tuff_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 21088, DefaultState: 21099},
+	{Name: "minecraft:tuff_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 21168, DefaultState: 21171},
+	{Name: "minecraft:polished_tuff", MinState: 21492, DefaultState: 21492, Opacity: 15},
+	{Name: "minecraft:polished_tuff_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 21493, DefaultState: 21496},
+	{Name: "minecraft:polished_tuff_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 21499, DefaultState: 21510},
+	{Name: "minecraft:polished_tuff_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 21579, DefaultState: 21582},
+	{Name: "minecraft:chiseled_tuff", MinState: 21903, DefaultState: 21903, Opacity: 15},
+	{Name: "minecraft:tuff_bricks", MinState: 21904, DefaultState: 21904, Opacity: 15},
+	{Name: "minecraft:tuff_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 21905, DefaultState: 21908},
+	{Name: "minecraft:tuff_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 21911, DefaultState: 21922},
+	{Name: "minecraft:tuff_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 21991, DefaultState: 21994},
+	{Name: "minecraft:chiseled_tuff_bricks", MinState: 22315, DefaultState: 22315, Opacity: 15},
+	{Name: "minecraft:calcite", MinState: 22316, DefaultState: 22316, Opacity: 15},
+	{Name: "minecraft:tinted_glass", MinState: 22317, DefaultState: 22317, Opacity: 15},
+	{Name: "minecraft:powder_snow", MinState: 22318, DefaultState: 22318, Opacity: 1},
+	{Name: "minecraft:sculk_sensor", Properties: []*Property{&properties[41], &properties[98], &properties[4]}, MinState: 22319, DefaultState: 22320, Light: 1},
+	{Name: "minecraft:calibrated_sculk_sensor", Properties: []*Property{&properties[15], &properties[41], &properties[98], &properties[4]}, MinState: 22415, DefaultState: 22416, Light: 1},
+	{Name: "minecraft:sculk", MinState: 22799, DefaultState: 22799, Opacity: 15},
+	{Name: "minecraft:sculk_vein", Properties: []*Property{&properties[59], &properties[31], &properties[32], &properties[33], &properties[34], &properties[4], &properties[35]}, MinState: 22800, DefaultState: 22927, Opacity: 1},
+	{Name: "minecraft:sculk_catalyst", Properties: []*Property{&properties[99]}, MinState: 22928, DefaultState: 22929, Light: 6, Opacity: 15},
+	{Name: "minecraft:sculk_shrieker", Properties: []*Property{&properties[100], &properties[101], &properties[4]}, MinState: 22930, DefaultState: 22937, Opacity: 1},
+	{Name: "minecraft:copper_block", MinState: 22938, DefaultState: 22938, Opacity: 15},
+	{Name: "minecraft:exposed_copper", MinState: 22939, DefaultState: 22939, Opacity: 15},
+	{Name: "minecraft:weathered_copper", MinState: 22940, DefaultState: 22940, Opacity: 15},
+	{Name: "minecraft:oxidized_copper", MinState: 22941, DefaultState: 22941, Opacity: 15},
+	{Name: "minecraft:copper_ore", MinState: 22942, DefaultState: 22942, Opacity: 15},
+	{Name: "minecraft:deepslate_copper_ore", MinState: 22943, DefaultState: 22943, Opacity: 15},
+	{Name: "minecraft:oxidized_cut_copper", MinState: 22944, DefaultState: 22944, Opacity: 15},
+	{Name: "minecraft:weathered_cut_copper", MinState: 22945, DefaultState: 22945, Opacity: 15},
+	{Name: "minecraft:exposed_cut_copper", MinState: 22946, DefaultState: 22946, Opacity: 15},
+	{Name: "minecraft:cut_copper", MinState: 22947, DefaultState: 22947, Opacity: 15},
+	{Name: "minecraft:oxidized_chiseled_copper", MinState: 22948, DefaultState: 22948, Opacity: 15},
+	{Name: "minecraft:weathered_chiseled_copper", MinState: 22949, DefaultState: 22949, Opacity: 15},
+	{Name: "minecraft:exposed_chiseled_copper", MinState: 22950, DefaultState: 22950, Opacity: 15},
+	{Name: "minecraft:chiseled_copper", MinState: 22951, DefaultState: 22951, Opacity: 15},
+	{Name: "minecraft:waxed_oxidized_chiseled_copper", MinState: 22952, DefaultState: 22952, Opacity: 15},
+	{Name: "minecraft:waxed_weathered_chiseled_copper", MinState: 22953, DefaultState: 22953, Opacity: 15},
+	{Name: "minecraft:waxed_exposed_chiseled_copper", MinState: 22954, DefaultState: 22954, Opacity: 15},
+	{Name: "minecraft:waxed_chiseled_copper", MinState: 22955, DefaultState: 22955, Opacity: 15},
+	{Name: "minecraft:oxidized_cut_copper_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 22956, DefaultState: 22967},
+	{Name: "minecraft:weathered_cut_copper_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 23036, DefaultState: 23047},
+	{Name: "minecraft:exposed_cut_copper_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 23116, DefaultState: 23127},
+	{Name: "minecraft:cut_copper_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 23196, DefaultState: 23207},
+	{Name: "minecraft:oxidized_cut_copper_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 23276, DefaultState: 23279},
+	{Name: "minecraft:weathered_cut_copper_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 23282, DefaultState: 23285},
+	{Name: "minecraft:exposed_cut_copper_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 23288, DefaultState: 23291},
+	{Name: "minecraft:cut_copper_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 23294, DefaultState: 23297},
+	{Name: "minecraft:waxed_copper_block", MinState: 23300, DefaultState: 23300, Opacity: 15},
+	{Name: "minecraft:waxed_weathered_copper", MinState: 23301, DefaultState: 23301, Opacity: 15},
+	{Name: "minecraft:waxed_exposed_copper", MinState: 23302, DefaultState: 23302, Opacity: 15},
+	{Name: "minecraft:waxed_oxidized_copper", MinState: 23303, DefaultState: 23303, Opacity: 15},
+	{Name: "minecraft:waxed_oxidized_cut_copper", MinState: 23304, DefaultState: 23304, Opacity: 15},
+	{Name: "minecraft:waxed_weathered_cut_copper", MinState: 23305, DefaultState: 23305, Opacity: 15},
+	{Name: "minecraft:waxed_exposed_cut_copper", MinState: 23306, DefaultState: 23306, Opacity: 15},
+	{Name: "minecraft:waxed_cut_copper", MinState: 23307, DefaultState: 23307, Opacity: 15},
+	{Name: "minecraft:waxed_oxidized_cut_copper_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 23308, DefaultState: 23319},
+	{Name: "minecraft:waxed_weathered_cut_copper_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 23388, DefaultState: 23399},
+	{Name: "minecraft:waxed_exposed_cut_copper_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 23468, DefaultState: 23479},
+	{Name: "minecraft:waxed_cut_copper_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 23548, DefaultState: 23559},
+	{Name: "minecraft:waxed_oxidized_cut_copper_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 23628, DefaultState: 23631},
+	{Name: "minecraft:waxed_weathered_cut_copper_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 23634, DefaultState: 23637},
+	{Name: "minecraft:waxed_exposed_cut_copper_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 23640, DefaultState: 23643},
+	{Name: "minecraft:waxed_cut_copper_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 23646, DefaultState: 23649},
+	{Name: "minecraft:copper_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 23652, DefaultState: 23663},
+	{Name: "minecraft:exposed_copper_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 23716, DefaultState: 23727},
+	{Name: "minecraft:oxidized_copper_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 23780, DefaultState: 23791},
+	{Name: "minecraft:weathered_copper_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 23844, DefaultState: 23855},
+	{Name: "minecraft:waxed_copper_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 23908, DefaultState: 23919},
+	{Name: "minecraft:waxed_exposed_copper_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 23972, DefaultState: 23983},
+	{Name: "minecraft:waxed_oxidized_copper_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 24036, DefaultState: 24047},
+	{Name: "minecraft:waxed_weathered_copper_door", Properties: []*Property{&properties[15], &properties[20], &properties[48], &properties[49], &properties[14]}, MinState: 24100, DefaultState: 24111},
+	{Name: "minecraft:copper_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 24164, DefaultState: 24179},
+	{Name: "minecraft:exposed_copper_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 24228, DefaultState: 24243},
+	{Name: "minecraft:oxidized_copper_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 24292, DefaultState: 24307},
+	{Name: "minecraft:weathered_copper_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 24356, DefaultState: 24371},
+	{Name: "minecraft:waxed_copper_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 24420, DefaultState: 24435},
+	{Name: "minecraft:waxed_exposed_copper_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 24484, DefaultState: 24499},
+	{Name: "minecraft:waxed_oxidized_copper_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 24548, DefaultState: 24563},
+	{Name: "minecraft:waxed_weathered_copper_trapdoor", Properties: []*Property{&properties[15], &properties[36], &properties[49], &properties[14], &properties[4]}, MinState: 24612, DefaultState: 24627},
+	{Name: "minecraft:copper_grate", Properties: []*Property{&properties[4]}, MinState: 24676, DefaultState: 24677},
+	{Name: "minecraft:exposed_copper_grate", Properties: []*Property{&properties[4]}, MinState: 24678, DefaultState: 24679},
+	{Name: "minecraft:weathered_copper_grate", Properties: []*Property{&properties[4]}, MinState: 24680, DefaultState: 24681},
+	{Name: "minecraft:oxidized_copper_grate", Properties: []*Property{&properties[4]}, MinState: 24682, DefaultState: 24683},
+	{Name: "minecraft:waxed_copper_grate", Properties: []*Property{&properties[4]}, MinState: 24684, DefaultState: 24685},
+	{Name: "minecraft:waxed_exposed_copper_grate", Properties: []*Property{&properties[4]}, MinState: 24686, DefaultState: 24687},
+	{Name: "minecraft:waxed_weathered_copper_grate", Properties: []*Property{&properties[4]}, MinState: 24688, DefaultState: 24689},
+	{Name: "minecraft:waxed_oxidized_copper_grate", Properties: []*Property{&properties[4]}, MinState: 24690, DefaultState: 24691},
+	{Name: "minecraft:copper_bulb", Properties: []*Property{&properties[46], &properties[14]}, MinState: 24692, DefaultState: 24695, Light: 15, Opacity: 15},
+	{Name: "minecraft:exposed_copper_bulb", Properties: []*Property{&properties[46], &properties[14]}, MinState: 24696, DefaultState: 24699, Light: 12, Opacity: 15},
+	{Name: "minecraft:weathered_copper_bulb", Properties: []*Property{&properties[46], &properties[14]}, MinState: 24700, DefaultState: 24703, Light: 8, Opacity: 15},
+	{Name: "minecraft:oxidized_copper_bulb", Properties: []*Property{&properties[46], &properties[14]}, MinState: 24704, DefaultState: 24707, Light: 4, Opacity: 15},
+	{Name: "minecraft:waxed_copper_bulb", Properties: []*Property{&properties[46], &properties[14]}, MinState: 24708, DefaultState: 24711, Light: 15, Opacity: 15},
+	{Name: "minecraft:waxed_exposed_copper_bulb", Properties: []*Property{&properties[46], &properties[14]}, MinState: 24712, DefaultState: 24715, Light: 12, Opacity: 15},
+	{Name: "minecraft:waxed_weathered_copper_bulb", Properties: []*Property{&properties[46], &properties[14]}, MinState: 24716, DefaultState: 24719, Light: 8, Opacity: 15},
+	{Name: "minecraft:waxed_oxidized_copper_bulb", Properties: []*Property{&properties[46], &properties[14]}, MinState: 24720, DefaultState: 24723, Light: 4, Opacity: 15},
+	{Name: "minecraft:lightning_rod", Properties: []*Property{&properties[10], &properties[14], &properties[4]}, MinState: 24724, DefaultState: 24743},
+	{Name: "minecraft:pointed_dripstone", Properties: []*Property{&properties[102], &properties[103], &properties[4]}, MinState: 24748, DefaultState: 24753},
+	{Name: "minecraft:dripstone_block", MinState: 24768, DefaultState: 24768, Opacity: 15},
+	{Name: "minecraft:cave_vines", Properties: []*Property{&properties[81], &properties[104]}, MinState: 24769, DefaultState: 24770, Light: 14},
+	{Name: "minecraft:cave_vines_plant", Properties: []*Property{&properties[104]}, MinState: 24821, DefaultState: 24822, Light: 14},
+	{Name: "minecraft:spore_blossom", MinState: 24823, DefaultState: 24823},
+	{Name: "minecraft:azalea", MinState: 24824, DefaultState: 24824},
+	{Name: "minecraft:flowering_azalea", MinState: 24825, DefaultState: 24825},
+	{Name: "minecraft:moss_carpet", MinState: 24826, DefaultState: 24826},
+	{Name: "minecraft:pink_petals", Properties: []*Property{&properties[15], &properties[105]}, MinState: 24827, DefaultState: 24827},
+	{Name: "minecraft:moss_block", MinState: 24843, DefaultState: 24843, Opacity: 15},
+	{Name: "minecraft:big_dripleaf", Properties: []*Property{&properties[15], &properties[106], &properties[4]}, MinState: 24844, DefaultState: 24845},
+	{Name: "minecraft:big_dripleaf_stem", Properties: []*Property{&properties[15], &properties[4]}, MinState: 24876, DefaultState: 24877},
+	{Name: "minecraft:small_dripleaf", Properties: []*Property{&properties[15], &properties[20], &properties[4]}, MinState: 24884, DefaultState: 24887},
+	{Name: "minecraft:hanging_roots", Properties: []*Property{&properties[4]}, MinState: 24900, DefaultState: 24901},
+	{Name: "minecraft:rooted_dirt", MinState: 24902, DefaultState: 24902, Opacity: 15},
+	{Name: "minecraft:mud", MinState: 24903, DefaultState: 24903, Opacity: 15},
+	{Name: "minecraft:deepslate", Properties: []*Property{&properties[7]}, MinState: 24904, DefaultState: 24905, Opacity: 15},
+	{Name: "minecraft:cobbled_deepslate", MinState: 24907, DefaultState: 24907, Opacity: 15},
+	{Name: "minecraft:cobbled_deepslate_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 24908, DefaultState: 24919},
+	{Name: "minecraft:cobbled_deepslate_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 24988, DefaultState: 24991},
+	{Name: "minecraft:cobbled_deepslate_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 24994, DefaultState: 24997},
+	{Name: "minecraft:polished_deepslate", MinState: 25318, DefaultState: 25318, Opacity: 15},
+	{Name: "minecraft:polished_deepslate_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 25319, DefaultState: 25330},
+	{Name: "minecraft:polished_deepslate_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 25399, DefaultState: 25402},
+	{Name: "minecraft:polished_deepslate_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 25405, DefaultState: 25408},
+	{Name: "minecraft:deepslate_tiles", MinState: 25729, DefaultState: 25729, Opacity: 15},
+	{Name: "minecraft:deepslate_tile_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 25730, DefaultState: 25741},
+	{Name: "minecraft:deepslate_tile_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 25810, DefaultState: 25813},
+	{Name: "minecraft:deepslate_tile_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 25816, DefaultState: 25819},
+	{Name: "minecraft:deepslate_bricks", MinState: 26140, DefaultState: 26140, Opacity: 15},
+	{Name: "minecraft:deepslate_brick_stairs", Properties: []*Property{&properties[15], &properties[36], &properties[37], &properties[4]}, MinState: 26141, DefaultState: 26152},
+	{Name: "minecraft:deepslate_brick_slab", Properties: []*Property{&properties[78], &properties[4]}, MinState: 26221, DefaultState: 26224},
+	{Name: "minecraft:deepslate_brick_wall", Properties: []*Property{&properties[70], &properties[71], &properties[72], &properties[34], &properties[4], &properties[73]}, MinState: 26227, DefaultState: 26230},
+	{Name: "minecraft:chiseled_deepslate", MinState: 26551, DefaultState: 26551, Opacity: 15},
+	{Name: "minecraft:cracked_deepslate_bricks", MinState: 26552, DefaultState: 26552, Opacity: 15},
+	{Name: "minecraft:cracked_deepslate_tiles", MinState: 26553, DefaultState: 26553, Opacity: 15},
+	{Name: "minecraft:infested_deepslate", Properties: []*Property{&properties[7]}, MinState: 26554, DefaultState: 26555, Opacity: 15},
+	{Name: "minecraft:smooth_basalt", MinState: 26557, DefaultState: 26557, Opacity: 15},
+	{Name: "minecraft:raw_iron_block", MinState: 26558, DefaultState: 26558, Opacity: 15},
+	{Name: "minecraft:raw_copper_block", MinState: 26559, DefaultState: 26559, Opacity: 15},
+	{Name: "minecraft:raw_gold_block", MinState: 26560, DefaultState: 26560, Opacity: 15},
+	{Name: "minecraft:potted_azalea_bush", MinState: 26561, DefaultState: 26561},
+	{Name: "minecraft:potted_flowering_azalea_bush", MinState: 26562, DefaultState: 26562},
+	{Name: "minecraft:ochre_froglight", Properties: []*Property{&properties[7]}, MinState: 26563, DefaultState: 26564, Light: 15, Opacity: 15},
+	{Name: "minecraft:verdant_froglight", Properties: []*Property{&properties[7]}, MinState: 26566, DefaultState: 26567, Light: 15, Opacity: 15},
+	{Name: "minecraft:pearlescent_froglight", Properties: []*Property{&properties[7]}, MinState: 26569, DefaultState: 26570, Light: 15, Opacity: 15},
+	{Name: "minecraft:frogspawn", MinState: 26572, DefaultState: 26572},
+	{Name: "minecraft:reinforced_deepslate", MinState: 26573, DefaultState: 26573, Opacity: 15},
+	{Name: "minecraft:decorated_pot", Properties: []*Property{&properties[107], &properties[15], &properties[4]}, MinState: 26574, DefaultState: 26583},
+	{Name: "minecraft:crafter", Properties: []*Property{&properties[108], &properties[93], &properties[11]}, MinState: 26590, DefaultState: 26635, Opacity: 15},
+	{Name: "minecraft:trial_spawner", Properties: []*Property{&properties[109], &properties[110]}, MinState: 26638, DefaultState: 26644, Opacity: 1},
+	{Name: "minecraft:vault", Properties: []*Property{&properties[15], &properties[109], &properties[111]}, MinState: 26650, DefaultState: 26654, Light: 6, Opacity: 1},
+	{Name: "minecraft:heavy_core", Properties: []*Property{&properties[4]}, MinState: 26682, DefaultState: 26683},
+}
+
+const stateCount = 26684
+
+var protocolStates = map[int32]stateTable{
+	765: {count: 26644, runs: []stateRun{
+		{0, 0, 26644},
+		{26644, 26638, 6},
+	}},
+	766: {count: 26684},
+	767: {count: 26684},
+}
@@ -0,0 +1,298 @@
+// Command gen writes blocks_gen.go from the blocks.json data reports in the
+// reports directory, which the game writes with
+//
+//	java -DbundlerMainClass=net.minecraft.data.Main -jar server.jar --reports
+//
+// The game does not report light, so reports/light.json lists the light
+// each block gives off in its brightest state and how much it absorbs, for
+// the blocks where either is not zero.
+//
+// The newest report decides the state IDs of the block package; the others
+// only decide how those IDs map to older protocol versions.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// versions lists the report each protocol version uses. The newest comes
+// last.
+var versions = []struct {
+	protocol int32
+	report   string
+}{
+	{765, "1.20.4"},
+	{766, "1.21.1"},
+	{767, "1.21.1"},
+}
+
+type reportState struct {
+	ID         uint32            `json:"id"`
+	Default    bool              `json:"default"`
+	Properties map[string]string `json:"properties"`
+}
+
+type reportBlock struct {
+	Properties map[string][]string `json:"properties"`
+	States     []reportState       `json:"states"`
+}
+
+type property struct {
+	name   string
+	values []string
+}
+
+type block struct {
+	name         string
+	properties   []property
+	minState     uint32
+	defaultState uint32
+	states       int
+	light        int
+	opacity      int
+}
+
+// stateOf returns the ID of the state with the given values.
+func (b *block) stateOf(values map[string]string) (uint32, bool) {
+	id, stride := uint32(0), uint32(1)
+	for i := len(b.properties) - 1; i >= 0; i-- {
+		p := b.properties[i]
+		v := indexOf(p.values, values[p.name])
+		if v < 0 {
+			return 0, false
+		}
+		id += uint32(v) * stride
+		stride *= uint32(len(p.values))
+	}
+	return b.minState + id, true
+}
+
+// valuesOf returns the property values of a state.
+func (b *block) valuesOf(id uint32) map[string]string {
+	values := make(map[string]string)
+	offset := id - b.minState
+	for i := len(b.properties) - 1; i >= 0; i-- {
+		p := b.properties[i]
+		values[p.name] = p.values[offset%uint32(len(p.values))]
+		offset /= uint32(len(p.values))
+	}
+	return values
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// load reads a report and checks that its state IDs follow the order the
+// block package assumes.
+func load(name string) ([]*block, error) {
+	f, err := os.Open(filepath.Join("reports", name+".json.gz"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	var report map[string]reportBlock
+	if err := json.NewDecoder(zr).Decode(&report); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	var blocks []*block
+	for blockName, rb := range report {
+		if len(rb.States) == 0 {
+			return nil, fmt.Errorf("%s: %s has no states", name, blockName)
+		}
+		b := &block{name: blockName, minState: rb.States[0].ID, states: len(rb.States)}
+		// The game orders properties by name.
+		for propName, values := range rb.Properties {
+			b.properties = append(b.properties, property{propName, values})
+		}
+		sort.Slice(b.properties, func(i, j int) bool { return b.properties[i].name < b.properties[j].name })
+		defaults := 0
+		for _, s := range rb.States {
+			if s.ID < b.minState {
+				b.minState = s.ID
+			}
+		}
+		for _, s := range rb.States {
+			if id, ok := b.stateOf(s.Properties); !ok || id != s.ID {
+				return nil, fmt.Errorf("%s: state %d of %s is out of order", name, s.ID, blockName)
+			}
+			if s.Default {
+				b.defaultState = s.ID
+				defaults++
+			}
+		}
+		if defaults != 1 {
+			return nil, fmt.Errorf("%s: %s has %d default states", name, blockName, defaults)
+		}
+		blocks = append(blocks, b)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].minState < blocks[j].minState })
+	next := uint32(0)
+	for _, b := range blocks {
+		if b.minState != next {
+			return nil, fmt.Errorf("%s: state IDs skip from %d to %d", name, next, b.minState)
+		}
+		next += uint32(b.states)
+	}
+	return blocks, nil
+}
+
+type run struct{ from, to, n uint32 }
+
+// mapStates maps every state of blocks to the state of the same block in
+// old with the same values. Properties old lacks are dropped and those it
+// has in addition keep their default; blocks old lacks are left out.
+func mapStates(blocks, old []*block) []run {
+	byName := make(map[string]*block, len(old))
+	for _, b := range old {
+		byName[b.name] = b
+	}
+	var runs []run
+	for _, b := range blocks {
+		ob, ok := byName[b.name]
+		if !ok {
+			continue
+		}
+		defaults := ob.valuesOf(ob.defaultState)
+		for id := b.minState; id < b.minState+uint32(b.states); id++ {
+			values := b.valuesOf(id)
+			for _, p := range ob.properties {
+				if indexOf(p.values, values[p.name]) < 0 {
+					values[p.name] = defaults[p.name]
+				}
+			}
+			to, _ := ob.stateOf(values)
+			if n := len(runs); n > 0 && runs[n-1].from+runs[n-1].n == id && runs[n-1].to+runs[n-1].n == to {
+				runs[n-1].n++
+				continue
+			}
+			runs = append(runs, run{id, to, 1})
+		}
+	}
+	return runs
+}
+
+func main() {
+	newest := versions[len(versions)-1].report
+	blocks, err := load(newest)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	light, err := os.ReadFile(filepath.Join("reports", "light.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var levels map[string][2]int
+	if err := json.Unmarshal(light, &levels); err != nil {
+		log.Fatalf("light.json: %v", err)
+	}
+	names := make(map[string]*block, len(blocks))
+	for _, b := range blocks {
+		names[b.name] = b
+	}
+	for name, l := range levels {
+		b, ok := names[name]
+		if !ok {
+			log.Fatalf("light.json: unknown block %s", name)
+		}
+		b.light, b.opacity = l[0], l[1]
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by internal/gen from reports/%s.json.gz; DO NOT EDIT.\n\npackage block\n\n", newest)
+
+	// Blocks share their properties where the name and values match.
+	var props []property
+	propIndex := make(map[string]int)
+	key := func(p property) string { return p.name + "=" + strings.Join(p.values, ",") }
+	for _, b := range blocks {
+		for _, p := range b.properties {
+			if _, ok := propIndex[key(p)]; !ok {
+				propIndex[key(p)] = len(props)
+				props = append(props, p)
+			}
+		}
+	}
+	fmt.Fprintf(&buf, "var properties = [...]Property{\n")
+	for _, p := range props {
+		fmt.Fprintf(&buf, "\t{Name: %q, Values: %#v},\n", p.name, p.values)
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	stateCount := 0
+	fmt.Fprintf(&buf, "var blocks = [...]Block{\n")
+	for _, b := range blocks {
+		var refs []string
+		for _, p := range b.properties {
+			refs = append(refs, fmt.Sprintf("&properties[%d]", propIndex[key(p)]))
+		}
+		propList := ""
+		if len(refs) > 0 {
+			propList = fmt.Sprintf("Properties: []*Property{%s}, ", strings.Join(refs, ", "))
+		}
+		lightFields := ""
+		if b.light > 0 {
+			lightFields += fmt.Sprintf(", Light: %d", b.light)
+		}
+		if b.opacity > 0 {
+			lightFields += fmt.Sprintf(", Opacity: %d", b.opacity)
+		}
+		fmt.Fprintf(&buf, "\t{Name: %q, %sMinState: %d, DefaultState: %d%s},\n", b.name, propList, b.minState, b.defaultState, lightFields)
+		stateCount += b.states
+	}
+	fmt.Fprintf(&buf, "}\n\nconst stateCount = %d\n\n", stateCount)
+
+	reports := map[string][]*block{newest: blocks}
+	fmt.Fprintf(&buf, "var protocolStates = map[int32]stateTable{\n")
+	for _, v := range versions {
+		old, ok := reports[v.report]
+		if !ok {
+			if old, err = load(v.report); err != nil {
+				log.Fatal(err)
+			}
+			reports[v.report] = old
+		}
+		count := 0
+		for _, b := range old {
+			count += b.states
+		}
+		if v.report == newest {
+			fmt.Fprintf(&buf, "\t%d: {count: %d},\n", v.protocol, count)
+			continue
+		}
+		fmt.Fprintf(&buf, "\t%d: {count: %d, runs: []stateRun{\n", v.protocol, count)
+		for _, r := range mapStates(blocks, old) {
+			fmt.Fprintf(&buf, "\t\t{%d, %d, %d},\n", r.from, r.to, r.n)
+		}
+		fmt.Fprintf(&buf, "\t}},\n")
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("blocks_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,92 @@
+package block
+
+import (
+	"strconv"
+	"strings"
+)
+
+// emission and opacity hold the light of every state, which light engines
+// look up for every block they visit.
+var emission, opacity = func() (emission, opacity []uint8) {
+	emission, opacity = make([]uint8, stateCount), make([]uint8, stateCount)
+	for i := range blocks {
+		b := &blocks[i]
+		for id := b.MinState; id < b.MinState+uint32(b.States()); id++ {
+			emission[id], opacity[id] = uint8(stateEmission(b, id)), uint8(stateOpacity(b, id))
+		}
+	}
+	return emission, opacity
+}()
+
+// LightEmission returns the light a state gives off, from 0 to 15. Blocks
+// such as furnaces only glow when lit, and candles, sea pickles and
+// respawn anchors glow brighter the more of them or the more charges
+// there are.
+func LightEmission(id uint32) int {
+	if int(id) >= stateCount {
+		return 0
+	}
+	return int(emission[id])
+}
+
+// LightOpacity returns how much light loses passing through a state, from
+// 0 to 15. Light loses at least 1 for every block it travels anyway, so
+// only blocks that dim it more, such as water and leaves, or stop it have
+// one above 0.
+func LightOpacity(id uint32) int {
+	if int(id) >= stateCount {
+		return 0
+	}
+	return int(opacity[id])
+}
+
+func stateEmission(b *Block, id uint32) int {
+	if b.Light == 0 {
+		return 0
+	}
+	light := int(b.Light)
+	if v, ok := b.Value(id, "lit"); ok && v == "false" {
+		return 0
+	}
+	switch b.Name {
+	case "minecraft:light":
+		return intValue(b, id, "level")
+	case "minecraft:sea_pickle":
+		if v, _ := b.Value(id, "waterlogged"); v != "true" {
+			return 0
+		}
+		return 3 + 3*intValue(b, id, "pickles")
+	case "minecraft:respawn_anchor":
+		return intValue(b, id, "charges") * 15 / 4
+	case "minecraft:cave_vines", "minecraft:cave_vines_plant":
+		if v, _ := b.Value(id, "berries"); v != "true" {
+			return 0
+		}
+	}
+	if strings.HasSuffix(b.Name, "candle") {
+		return light * intValue(b, id, "candles")
+	}
+	return light
+}
+
+func stateOpacity(b *Block, id uint32) int {
+	opacity := int(b.Opacity)
+	if opacity < 15 && strings.HasSuffix(b.Name, "_slab") {
+		if v, _ := b.Value(id, "type"); v == "double" {
+			return 15
+		}
+	}
+	if opacity == 0 {
+		if v, ok := b.Value(id, "waterlogged"); ok && v == "true" {
+			return 1
+		}
+	}
+	return opacity
+}
+
+// intValue returns a numeric property of a state.
+func intValue(b *Block, id uint32, name string) int {
+	v, _ := b.Value(id, name)
+	n, _ := strconv.Atoi(v)
+	return n
+}
@@ -0,0 +1,22 @@
+package block
+
+// FullCube reports whether a state fills its whole block, so that nothing
+// moves through it. The reports hold no shapes, so only the states that
+// stop light count, which are full cubes except for the few blocks listed
+// here. Slabs, stairs, fences and the like do not, so collisions with them
+// go unnoticed rather than wrongly found.
+func FullCube(id uint32) bool {
+	if LightOpacity(id) < 15 {
+		return false
+	}
+	b, _ := ByState(id)
+	switch b.Name {
+	case "minecraft:soul_sand", "minecraft:mud":
+		// 14 pixels tall.
+		return false
+	case "minecraft:piston", "minecraft:sticky_piston":
+		extended, _ := b.Value(id, "extended")
+		return extended != "true"
+	}
+	return true
+}
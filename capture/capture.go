@@ -0,0 +1,172 @@
+// Package capture records the packets of a connection to a file, one JSON
+// object per line, and reads such files back for replay.
+//
+// Serverbound packets that the protocol package can decode are recorded with
+// their fields as well as their raw payload, which is always kept so a
+// capture can be replayed byte for byte.
+package capture
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// Record is one captured packet.
+type Record struct {
+	// Time is when the packet was seen, in milliseconds since the capture
+	// started.
+	Time      int64  `json:"t"`
+	State     string `json:"state"`
+	Direction string `json:"dir"`
+	ID        int32  `json:"id"`
+	// Packet is the packet's name, or empty if the ID is unknown.
+	Packet string `json:"packet,omitempty"`
+	// Fields is the decoded packet, for serverbound packets with a typed form.
+	Fields json.RawMessage `json:"fields,omitempty"`
+	// Error is why decoding failed, if it did.
+	Error string `json:"error,omitempty"`
+	// Data is the payload after the packet ID, hex encoded.
+	Data string `json:"data"`
+}
+
+// Serverbound reports whether the client sent the packet.
+func (r *Record) Serverbound() bool { return r.Direction == protocol.Serverbound.String() }
+
+// Payload decodes Data.
+func (r *Record) Payload() ([]byte, error) { return hex.DecodeString(r.Data) }
+
+// Writer records packets as a protocol.Observer.
+type Writer struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+	start  time.Time
+	err    error
+}
+
+// NewWriter records to w. The caller must call Flush or Close when done.
+func NewWriter(w io.Writer) *Writer {
+	cw := &Writer{w: bufio.NewWriter(w), start: time.Now()}
+	if c, ok := w.(io.Closer); ok {
+		cw.closer = c
+	}
+	return cw
+}
+
+// Create records to a new file in dir, named after the time and the remote
+// address so concurrent connections never share a file.
+func Create(dir string, remote string) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s.jsonl", time.Now().Format("20060102-150405.000"), sanitize(remote))
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return NewWriter(f), nil
+}
+
+// sanitize makes an address usable in a file name.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '/', '\\', '[', ']', '%':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// ObservePacket records a packet. Errors are kept and returned by Close.
+func (w *Writer) ObservePacket(dir protocol.Direction, state protocol.State, id int32, p protocol.Packet, payload []byte) {
+	rec := Record{
+		State:     state.String(),
+		Direction: dir.String(),
+		ID:        id,
+		Data:      hex.EncodeToString(payload),
+	}
+	if p != protocol.UnknownPacket {
+		rec.Packet = p.String()
+	}
+	if dir == protocol.Serverbound {
+		raw := &protocol.RawPacket{ID: id, Packet: p, Data: payload}
+		if d, ok, err := protocol.DecodeServerbound(raw); err != nil {
+			rec.Error = err.Error()
+		} else if ok {
+			rec.Fields, _ = json.Marshal(d)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rec.Time = time.Since(w.start).Milliseconds()
+	line, err := json.Marshal(rec)
+	if err == nil {
+		_, err = w.w.Write(append(line, '\n'))
+	}
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+}
+
+// Flush writes buffered records.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.w.Flush(); err != nil && w.err == nil {
+		w.err = err
+	}
+	return w.err
+}
+
+// Close flushes the records and closes the underlying writer if it is a
+// Closer.
+func (w *Writer) Close() error {
+	err := w.Flush()
+	if w.closer != nil {
+		if cerr := w.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Read reads every record from r.
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	// A chunk packet is up to 2 MiB, twice that in hex.
+	scanner.Buffer(nil, 2*protocol.MaxPacketLength+64*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("capture: line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// ReadFile reads every record from the file at path.
+func ReadFile(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
@@ -0,0 +1,48 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	handshake := append(protocol.WriteVarInt(767), protocol.WriteString("localhost")...)
+	handshake = append(handshake, protocol.WriteUnsignedShort(25565)...)
+	handshake = append(handshake, protocol.WriteVarInt(protocol.IntentStatus)...)
+	w.ObservePacket(protocol.Serverbound, protocol.Handshaking, 0x00, protocol.ServerboundHandshakeIntention, handshake)
+	w.ObservePacket(protocol.Serverbound, protocol.Status, 0x01, protocol.ServerboundStatusPing, []byte{1, 2})
+	w.ObservePacket(protocol.Clientbound, protocol.Status, 0x01, protocol.ClientboundStatusPong, protocol.WriteLong(42))
+	require.NoError(t, w.Close())
+
+	records, err := Read(&buf)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	hs := records[0]
+	assert.True(t, hs.Serverbound())
+	assert.Equal(t, "handshaking", hs.State)
+	assert.Equal(t, "HandshakeIntention", hs.Packet)
+	var fields protocol.Handshake
+	require.NoError(t, json.Unmarshal(hs.Fields, &fields))
+	assert.Equal(t, protocol.Handshake{ProtocolVersion: 767, ServerAddress: "localhost", ServerPort: 25565, Intent: 1}, fields)
+	payload, err := hs.Payload()
+	require.NoError(t, err)
+	assert.Equal(t, handshake, payload)
+
+	// A truncated packet is still recorded, with the decoding error.
+	assert.NotEmpty(t, records[1].Error)
+	assert.Empty(t, records[1].Fields)
+
+	pong := records[2]
+	assert.False(t, pong.Serverbound())
+	assert.Equal(t, "000000000000002a", pong.Data)
+	assert.Empty(t, pong.Fields)
+}
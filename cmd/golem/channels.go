@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// Channels that the server handles itself.
+const (
+	ChannelBrand      = "minecraft:brand"
+	ChannelRegister   = "minecraft:register"
+	ChannelUnregister = "minecraft:unregister"
+)
+
+// ServerBrand is what the server reports on ChannelBrand, shown in the
+// client's debug screen.
+const ServerBrand = "Golem"
+
+// channelPattern matches a resource location as used for channel names.
+var channelPattern = regexp.MustCompile(`^[a-z0-9_.-]+:[a-z0-9_./-]+$`)
+
+// ChannelHandler receives a plugin message a player sent on a registered
+// channel.
+type ChannelHandler func(p *Player, channel string, data []byte)
+
+// Channels is the registry of plugin message channels. Go subsystems and
+// plugins register handlers here; the channel names are announced to clients
+// with minecraft:register when they log in.
+type Channels struct {
+	mu       sync.RWMutex
+	handlers map[string][]ChannelHandler
+}
+
+// NewChannels creates an empty channel registry.
+func NewChannels() *Channels {
+	return &Channels{handlers: make(map[string][]ChannelHandler)}
+}
+
+// Register adds a handler for channel. The minecraft namespace is reserved.
+func (c *Channels) Register(channel string, h ChannelHandler) error {
+	if !channelPattern.MatchString(channel) {
+		return fmt.Errorf("invalid channel name %q", channel)
+	}
+	if strings.HasPrefix(channel, "minecraft:") {
+		return fmt.Errorf("channel %s is reserved", channel)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[channel] = append(c.handlers[channel], h)
+	return nil
+}
+
+// Unregister removes every handler of channel.
+func (c *Channels) Unregister(channel string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.handlers, channel)
+}
+
+// Registered returns the registered channel names, sorted.
+func (c *Channels) Registered() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.handlers))
+	for name := range c.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dispatch hands a message to the channel's handlers. Messages on channels
+// nobody registered are dropped, as vanilla does.
+func (c *Channels) dispatch(p *Player, channel string, data []byte) {
+	c.mu.RLock()
+	handlers := c.handlers[channel]
+	c.mu.RUnlock()
+	for _, h := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Handler for channel %s panicked: %v", channel, r)
+				}
+			}()
+			h(p, channel, data)
+		}()
+	}
+}
+
+// handlePluginMessage handles a serverbound Plugin Message in the
+// configuration or play state.
+func (s *session) handlePluginMessage(raw *protocol.RawPacket) error {
+	var msg protocol.PluginMessage
+	if err := raw.Decode(&msg); err != nil {
+		return err
+	}
+	switch msg.Channel {
+	case ChannelBrand:
+		brand, err := protocol.ReadString(bytes.NewReader(msg.Data), protocol.DefaultStringLength)
+		if err != nil {
+			return err
+		}
+		s.player.setClientBrand(brand)
+	case ChannelRegister:
+		s.player.listen(splitChannels(msg.Data), true)
+	case ChannelUnregister:
+		s.player.listen(splitChannels(msg.Data), false)
+	default:
+		s.srv.channels.dispatch(s.player, msg.Channel, msg.Data)
+	}
+	return nil
+}
+
+// sendServerChannels sends the server brand and announces the registered
+// channels. It is called when the client enters the configuration state.
+func (s *session) sendServerChannels() error {
+	if err := s.player.SendPluginMessage(ChannelBrand, protocol.WriteString(ServerBrand)); err != nil {
+		return err
+	}
+	if channels := s.srv.channels.Registered(); len(channels) > 0 {
+		return s.player.SendPluginMessage(ChannelRegister, []byte(strings.Join(channels, "\x00")))
+	}
+	return nil
+}
+
+// splitChannels parses the NUL-separated list of minecraft:register.
+func splitChannels(data []byte) []string {
+	var channels []string
+	for _, name := range strings.Split(string(data), "\x00") {
+		if channelPattern.MatchString(name) {
+			channels = append(channels, name)
+		}
+	}
+	return channels
+}
@@ -0,0 +1,491 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	js "github.com/Advik-B/Golem/javascript"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/text"
+)
+
+// Chat errors, in vanilla's wording.
+const (
+	illegalCharactersMessage = "Illegal characters in chat"
+	chatValidationMessage    = "Chat message validation failure"
+	outOfOrderChatMessage    = "Out-of-order chat packet received. Did your system time change?"
+	tooManyPendingMessage    = "Too many unacknowledged chat messages"
+	missingProfileKeyMessage = "Chat disabled due to missing profile public key. Please try reconnecting."
+	expiredProfileKeyMessage = "Chat disabled due to expired profile public key. Please try reconnecting."
+	chainBrokenMessage       = "Chat disabled due to broken chain. Please try reconnecting."
+	invalidSignatureMessage  = "Chat had an invalid signature. Please try reconnecting."
+	invalidPublicKeyMessage  = "Invalid signature for profile public key. Try restarting your game."
+	expiredPublicKeyMessage  = "Expired profile public key. Check that your system time is synchronized, and try restarting your game."
+)
+
+// maxPendingMessages is how many signed messages a client may leave
+// unacknowledged before it is disconnected.
+const maxPendingMessages = 4096
+
+// PlayerMessage is a chat message a player sent, after validation.
+type PlayerMessage struct {
+	Sender *Player
+	// Index is the message's position in the sender's chain.
+	Index int32
+	// Signature is nil for unsigned messages, which clients mark as not
+	// secure.
+	Signature []byte
+	Message   string
+	// Timestamp is in milliseconds since the Unix epoch.
+	Timestamp int64
+	Salt      int64
+	// LastSeen are the signatures of the messages the sender acknowledged,
+	// oldest first.
+	LastSeen [][]byte
+}
+
+// ChatFilter reports whether player to may see a chat message from player
+// from. Filters run for every recipient, including the sender.
+type ChatFilter func(from, to *Player, message string) bool
+
+// chatError is a rejected chat packet. Some rejections only tell the player
+// that their chat is disabled; the rest disconnect them.
+type chatError struct {
+	reason     string
+	disconnect bool
+}
+
+func (e *chatError) Error() string { return e.reason }
+
+// chatSession is the profile public key a player signs chat with.
+type chatSession struct {
+	protocol.PlayerSession
+	key *rsa.PublicKey
+}
+
+func (s *chatSession) expired(now time.Time) bool {
+	return now.UnixMilli() > s.ExpiresAt
+}
+
+// chatState is a player's part in secure chat: its signing session and chain,
+// and which signed messages it has seen.
+type chatState struct {
+	mu      sync.Mutex
+	session *chatSession
+	// nextIndex is the index the next message in the chain must have.
+	nextIndex   int32
+	chainBroken bool
+	// lastTimestamp is the timestamp of the newest message or command, in
+	// milliseconds, to detect out-of-order chat.
+	lastTimestamp int64
+	lastSeen      lastSeenValidator
+}
+
+// trackedMessage is a signed message sent to a client, in the window the
+// client acknowledges.
+type trackedMessage struct {
+	signature []byte
+	pending   bool
+}
+
+// lastSeenValidator mirrors the client's window of the last signed messages
+// it received, to rebuild the list of acknowledged signatures each message
+// is signed over. It follows vanilla's LastSeenMessagesValidator.
+type lastSeenValidator struct {
+	tracked     []*trackedMessage
+	lastPending []byte
+}
+
+func (v *lastSeenValidator) init() {
+	if v.tracked == nil {
+		v.tracked = make([]*trackedMessage, protocol.LastSeenWindow)
+	}
+}
+
+// addPending records a signed message sent to the client.
+func (v *lastSeenValidator) addPending(signature []byte) {
+	v.init()
+	if !bytes.Equal(signature, v.lastPending) {
+		v.tracked = append(v.tracked, &trackedMessage{signature: signature, pending: true})
+		v.lastPending = signature
+	}
+}
+
+// applyOffset slides the window over the messages the client has received.
+func (v *lastSeenValidator) applyOffset(offset int32) error {
+	v.init()
+	max := len(v.tracked) - protocol.LastSeenWindow
+	if offset < 0 || int(offset) > max {
+		return fmt.Errorf("advanced last seen window by %d messages, but expected at most %d", offset, max)
+	}
+	v.tracked = v.tracked[offset:]
+	return nil
+}
+
+// applyUpdate applies a client's acknowledgements and returns the signatures
+// of the messages it saw.
+func (v *lastSeenValidator) applyUpdate(u protocol.LastSeenUpdate) ([][]byte, error) {
+	if err := v.applyOffset(u.Offset); err != nil {
+		return nil, err
+	}
+	if u.Acknowledged[2]&0xF0 != 0 {
+		return nil, fmt.Errorf("last seen update acknowledged messages outside the window")
+	}
+	var seen [][]byte
+	for i := 0; i < protocol.LastSeenWindow; i++ {
+		entry := v.tracked[i]
+		if u.Seen(i) {
+			if entry == nil {
+				return nil, fmt.Errorf("last seen update acknowledged unknown or previously ignored message at index %d", i)
+			}
+			v.tracked[i] = &trackedMessage{signature: entry.signature}
+			seen = append(seen, entry.signature)
+		} else {
+			if entry != nil && !entry.pending {
+				return nil, fmt.Errorf("last seen update ignored previously acknowledged message at index %d", i)
+			}
+			v.tracked[i] = nil
+		}
+	}
+	return seen, nil
+}
+
+// isAllowedChat reports whether a chat line has only characters a client can
+// type: no formatting codes and no control characters.
+func isAllowedChat(s string) bool {
+	for _, r := range s {
+		if r == '§' || r < ' ' || r == 0x7F {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptChat checks the order of a chat packet and applies its last seen
+// update.
+func (c *chatState) acceptChat(timestamp int64, update protocol.LastSeenUpdate) ([][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if timestamp < c.lastTimestamp {
+		return nil, &chatError{outOfOrderChatMessage, true}
+	}
+	c.lastTimestamp = timestamp
+	lastSeen, err := c.lastSeen.applyUpdate(update)
+	if err != nil {
+		return nil, &chatError{chatValidationMessage, true}
+	}
+	return lastSeen, nil
+}
+
+// unpack validates a chat message against the sender's session and chain.
+// Without a session the message is accepted unsigned unless secure chat is
+// enforced.
+func (c *chatState) unpack(p *Player, msg *protocol.ChatMessage, lastSeen [][]byte, enforce bool) (*PlayerMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := &PlayerMessage{Sender: p, Message: msg.Message, Timestamp: msg.Timestamp, Salt: msg.Salt, LastSeen: lastSeen}
+	if c.session == nil {
+		if enforce {
+			return nil, &chatError{missingProfileKeyMessage, false}
+		}
+		return m, nil
+	}
+	switch {
+	case msg.Signature == nil:
+		return nil, &chatError{missingProfileKeyMessage, false}
+	case c.session.expired(time.Now()):
+		return nil, &chatError{expiredProfileKeyMessage, false}
+	case c.chainBroken:
+		return nil, &chatError{chainBrokenMessage, true}
+	}
+	m.Index, m.Signature = c.nextIndex, msg.Signature
+	payload := protocol.SignedMessagePayload(p.UUID, c.session.SessionID, m.Index, m.Salt, m.Timestamp, m.Message, lastSeen)
+	digest := sha256.Sum256(payload)
+	if rsa.VerifyPKCS1v15(c.session.key, crypto.SHA256, digest[:], msg.Signature) != nil {
+		c.chainBroken = true
+		return nil, &chatError{invalidSignatureMessage, true}
+	}
+	c.nextIndex++
+	return m, nil
+}
+
+// startSession replaces the player's chat session, which restarts its chain.
+func (c *chatState) startSession(session *protocol.PlayerSession, now time.Time) error {
+	key, err := x509.ParsePKIXPublicKey(session.PublicKey)
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if err != nil || !ok {
+		return &chatError{invalidPublicKeyMessage, true}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.UnixMilli() > session.ExpiresAt || (c.session != nil && session.ExpiresAt < c.session.ExpiresAt) {
+		return &chatError{expiredPublicKeyMessage, true}
+	}
+	// The key's signature by Mojang is not checked: the server runs in
+	// offline mode and has no session service keys to check it against.
+	c.session = &chatSession{PlayerSession: *session, key: rsaKey}
+	c.nextIndex = 0
+	c.chainBroken = false
+	return nil
+}
+
+// handleChatError tells the player why a chat packet was rejected.
+func (s *session) handleChatError(err error) error {
+	ce, ok := err.(*chatError)
+	if !ok {
+		return err
+	}
+	if ce.disconnect {
+		log.Printf("%s: %s", s.player.Name, ce.reason)
+		s.conn.Disconnect(ce.reason)
+	} else {
+		s.player.SendMessage(ce.reason)
+	}
+	return nil
+}
+
+// handleChatMessage handles a chat line typed by the player.
+func (s *session) handleChatMessage(raw *protocol.RawPacket) error {
+	var msg protocol.ChatMessage
+	if err := raw.Decode(&msg); err != nil {
+		return err
+	}
+	if !isAllowedChat(msg.Message) {
+		s.conn.Disconnect(illegalCharactersMessage)
+		return nil
+	}
+	p := s.player
+	lastSeen, err := p.chat.acceptChat(msg.Timestamp, msg.LastSeen)
+	if err != nil {
+		return s.handleChatError(err)
+	}
+	m, err := p.chat.unpack(p, &msg, lastSeen, s.srv.cfg.Server.EnforceSecureChat)
+	if err != nil {
+		return s.handleChatError(err)
+	}
+	s.srv.broadcastChat(m)
+	return nil
+}
+
+// handleChatCommand handles a command typed by the player, signed or not.
+func (s *session) handleChatCommand(raw *protocol.RawPacket) error {
+	var cmd protocol.ChatCommand
+	if raw.Packet == protocol.ServerboundPlaySignedChatCommand {
+		var signed protocol.SignedChatCommand
+		if err := raw.Decode(&signed); err != nil {
+			return err
+		}
+		cmd = signed.ChatCommand
+	} else if err := raw.Decode(&cmd); err != nil {
+		return err
+	}
+	if !isAllowedChat(cmd.Command) {
+		s.conn.Disconnect(illegalCharactersMessage)
+		return nil
+	}
+	if cmd.Signed {
+		// No command takes an argument clients sign, so the signatures are
+		// ignored, but the acknowledgements still move the window.
+		if _, err := s.player.chat.acceptChat(cmd.Timestamp, cmd.LastSeen); err != nil {
+			return s.handleChatError(err)
+		}
+	}
+	log.Printf("%s issued server command: /%s", s.player.Name, cmd.Command)
+	s.srv.commands.Dispatch(s.player, cmd.Command)
+	return nil
+}
+
+// handlePlayerSession handles a new chat session from the player and tells
+// every client about it, so they can verify the player's messages.
+func (s *session) handlePlayerSession(raw *protocol.RawPacket) error {
+	var session protocol.PlayerSession
+	if err := raw.Decode(&session); err != nil {
+		return err
+	}
+	if err := s.player.chat.startSession(&session, time.Now()); err != nil {
+		return s.handleChatError(err)
+	}
+	s.srv.broadcastPlayerInfo(infoInitializeChat, s.player)
+	return nil
+}
+
+// handleAcknowledgeMessage handles the acknowledgement of received messages.
+func (s *session) handleAcknowledgeMessage(raw *protocol.RawPacket) error {
+	var ack protocol.AcknowledgeMessage
+	if err := raw.Decode(&ack); err != nil {
+		return err
+	}
+	c := &s.player.chat
+	c.mu.Lock()
+	err := c.lastSeen.applyOffset(ack.Offset)
+	c.mu.Unlock()
+	if err != nil {
+		return s.handleChatError(&chatError{chatValidationMessage, true})
+	}
+	return nil
+}
+
+// AddChatFilter adds a filter that can hide chat messages from players.
+func (s *Server) AddChatFilter(f ChatFilter) {
+	s.mu.Lock()
+	s.chatFilters = append(s.chatFilters, f)
+	s.mu.Unlock()
+}
+
+// broadcastChat delivers a player's message to every player the filters and
+// the playerChat event let see it.
+func (s *Server) broadcastChat(m *PlayerMessage) {
+	recipients := s.Players()
+	s.mu.RLock()
+	filters := s.chatFilters
+	s.mu.RUnlock()
+	if len(filters) > 0 {
+		allowed := recipients[:0]
+		for _, to := range recipients {
+			if allowedBy(filters, m.Sender, to, m.Message) {
+				allowed = append(allowed, to)
+			}
+		}
+		recipients = allowed
+	}
+	recipients, ok := s.firePlayerChat(m, recipients)
+	if !ok {
+		return
+	}
+
+	if m.Signature == nil {
+		log.Printf("[Not Secure] <%s> %s", m.Sender.Name, m.Message)
+	} else {
+		log.Printf("<%s> %s", m.Sender.Name, m.Message)
+	}
+	for _, to := range recipients {
+		if err := to.sendPlayerChat(m); err != nil {
+			log.Printf("Could not send chat to %s: %v", to.Name, err)
+		}
+	}
+}
+
+func allowedBy(filters []ChatFilter, from, to *Player, message string) bool {
+	for _, f := range filters {
+		if !f(from, to, message) {
+			return false
+		}
+	}
+	return true
+}
+
+// firePlayerChat lets plugins cancel a chat message or change who sees it.
+func (s *Server) firePlayerChat(m *PlayerMessage, recipients []*Player) ([]*Player, bool) {
+	if s.plugins == nil || !s.plugins.Events.Has("playerChat") {
+		return recipients, true
+	}
+	names := make([]interface{}, len(recipients))
+	for i, p := range recipients {
+		names[i] = p.Name
+	}
+	event := js.Event{
+		"player":     jsPlayer(m.Sender),
+		"message":    m.Message,
+		"secure":     m.Signature != nil,
+		"recipients": &names,
+		"cancelled":  false,
+	}
+	s.plugins.Events.Fire("playerChat", event)
+	if event.Cancelled() {
+		return nil, false
+	}
+	keep := make(map[string]bool)
+	for _, name := range event.Strings("recipients") {
+		keep[name] = true
+	}
+	filtered := recipients[:0]
+	for _, p := range recipients {
+		if keep[p.Name] {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, true
+}
+
+// sendPlayerChat sends a Player Chat Message and, for signed messages, adds
+// it to the messages the client must acknowledge.
+func (p *Player) sendPlayerChat(m *PlayerMessage) error {
+	v := p.conn.Version()
+	regs, err := v.Registries()
+	if err != nil {
+		return err
+	}
+	chatTypes, _ := regs.Get("minecraft:chat_type")
+	chatType, ok := chatTypes.Index("minecraft:chat")
+	if !ok {
+		return fmt.Errorf("no minecraft:chat chat type in %s", v)
+	}
+	if v.Has(protocol.FeatureRegistryHolders) {
+		chatType++
+	}
+
+	payload := [][]byte{protocol.WriteUUID(m.Sender.UUID), protocol.WriteVarInt(int(m.Index))}
+	payload = append(payload, protocol.WriteBool(m.Signature != nil), m.Signature)
+	payload = append(payload,
+		protocol.WriteString(m.Message),
+		protocol.WriteLong(m.Timestamp),
+		protocol.WriteLong(m.Salt),
+		protocol.WriteVarInt(len(m.LastSeen)),
+	)
+	for _, sig := range m.LastSeen {
+		// ID 0 sends the signature in full rather than from the client's cache.
+		payload = append(payload, protocol.WriteVarInt(0), sig)
+	}
+	payload = append(payload,
+		protocol.WriteBool(false), // unsigned content
+		protocol.WriteVarInt(0),   // filter: pass through
+		protocol.WriteVarInt(chatType),
+		protocol.WriteNBT(text.Plain(m.Sender.Name).ToNBT()),
+		protocol.WriteBool(false), // target name
+	)
+
+	// Sending and tracking happen under the lock so the tracked order is
+	// the order the client receives the messages in.
+	p.chat.mu.Lock()
+	err = p.conn.WritePacket(protocol.ClientboundPlayPlayerChatMessage, payload...)
+	pending := 0
+	if err == nil && m.Signature != nil {
+		p.chat.lastSeen.addPending(m.Signature)
+		pending = len(p.chat.lastSeen.tracked)
+	}
+	p.chat.mu.Unlock()
+	if pending > maxPendingMessages {
+		p.Disconnect(tooManyPendingMessage)
+	}
+	return err
+}
+
+// Broadcast sends a system message to every player filter accepts, or to
+// everyone if filter is nil, and logs it.
+func (s *Server) Broadcast(text string, filter func(p *Player) bool) {
+	log.Print(text)
+	for _, p := range s.Players() {
+		if filter == nil || filter(p) {
+			p.SendMessage(text)
+		}
+	}
+}
+
+// PlayerByName returns the online player with the given name, ignoring case,
+// or nil.
+func (s *Server) PlayerByName(name string) *Player {
+	for _, p := range s.Players() {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
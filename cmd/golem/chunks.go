@@ -0,0 +1,159 @@
+package main
+
+import (
+	"math"
+	"sync"
+
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/world"
+)
+
+// chunkView is the square of chunks around a player that its client is
+// sent. A player ticket keeps them loaded.
+type chunkView struct {
+	mu     sync.Mutex
+	ids    *world.NetworkIDs
+	ticket *world.Ticket
+	// sent holds the chunks the client has.
+	sent map[world.ChunkPos]bool
+}
+
+// chunkAt returns the chunk holding a location.
+func chunkAt(loc Location) world.ChunkPos {
+	return world.ChunkPosOf(int(math.Floor(loc.X)), int(math.Floor(loc.Z)))
+}
+
+// openView sends a joining player the loaded chunks around loc; the others
+// follow as they load. Without a world the player gets one empty chunk.
+func (s *Server) openView(p *Player, ids *world.NetworkIDs, loc Location, minY, height int) error {
+	center := chunkAt(loc)
+	if err := p.conn.WritePacket(protocol.ClientboundPlaySetCenterChunk,
+		protocol.WriteVarInt(int(center.X)), protocol.WriteVarInt(int(center.Z))); err != nil {
+		return err
+	}
+	if s.chunks == nil {
+		chunk := world.NewChunk(s.registry, center, minY, height)
+		return p.conn.WritePacket(protocol.ClientboundPlayChunkDataAndUpdateLight, chunk.ChunkDataPacket(ids)...)
+	}
+	v := &p.view
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.ids, v.sent = ids, make(map[world.ChunkPos]bool)
+	v.ticket = s.chunks.AddTicket(world.TicketPlayer, center, s.cfg.World.ViewDistance)
+	// Chunks loading from now on are sent by chunkLoaded.
+	s.mu.Lock()
+	s.viewers[p] = struct{}{}
+	s.mu.Unlock()
+	return s.sendLoaded(p)
+}
+
+// moveView follows a player into another chunk: the client is told its new
+// center, forgets the chunks it no longer sees and gets the new ones.
+func (s *Server) moveView(p *Player, loc Location) error {
+	v := &p.view
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	center := chunkAt(loc)
+	if v.ticket == nil || v.ticket.Pos == center {
+		return nil
+	}
+	s.chunks.MoveTicket(v.ticket, center)
+	if err := p.conn.WritePacket(protocol.ClientboundPlaySetCenterChunk,
+		protocol.WriteVarInt(int(center.X)), protocol.WriteVarInt(int(center.Z))); err != nil {
+		return err
+	}
+	for pos := range v.sent {
+		if v.ticket.Covers(pos) {
+			continue
+		}
+		delete(v.sent, pos)
+		if err := p.conn.WritePacket(protocol.ClientboundPlayUnloadChunk,
+			protocol.WriteInt(int(pos.Z)), protocol.WriteInt(int(pos.X))); err != nil {
+			return err
+		}
+	}
+	return s.sendLoaded(p)
+}
+
+// closeView releases the chunks a leaving player kept loaded.
+func (s *Server) closeView(p *Player) {
+	s.mu.Lock()
+	delete(s.viewers, p)
+	s.mu.Unlock()
+	v := &p.view
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.ticket != nil {
+		s.chunks.RemoveTicket(v.ticket)
+		v.ticket = nil
+	}
+}
+
+// sendLoaded sends the loaded chunks of a player's view that its client
+// does not have, nearest first. p.view.mu must be held.
+func (s *Server) sendLoaded(p *Player) error {
+	v := &p.view
+	center, radius := v.ticket.Pos, int32(v.ticket.Radius)
+	for r := int32(0); r <= radius; r++ {
+		for x := center.X - r; x <= center.X+r; x++ {
+			for z := center.Z - r; z <= center.Z+r; z++ {
+				// Only the ring at distance r.
+				if x != center.X-r && x != center.X+r && z != center.Z-r && z != center.Z+r {
+					continue
+				}
+				pos := world.ChunkPos{X: x, Z: z}
+				if v.sent[pos] {
+					continue
+				}
+				if chunk := s.chunks.Chunk(pos); chunk != nil {
+					if err := s.sendChunk(p, chunk); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sendChunk sends a chunk to a player. p.view.mu must be held.
+func (s *Server) sendChunk(p *Player, chunk *world.Chunk) error {
+	p.view.sent[chunk.Pos] = true
+	return p.conn.WritePacket(protocol.ClientboundPlayChunkDataAndUpdateLight, chunk.ChunkDataPacket(p.view.ids)...)
+}
+
+// chunkLoaded sends a chunk that finished loading to the players who see it.
+func (s *Server) chunkLoaded(chunk *world.Chunk) {
+	for _, p := range s.chunkViewers() {
+		v := &p.view
+		v.mu.Lock()
+		if v.ticket != nil && v.ticket.Covers(chunk.Pos) && !v.sent[chunk.Pos] {
+			// A failed write means the client is gone; its session cleans up.
+			s.sendChunk(p, chunk)
+		}
+		v.mu.Unlock()
+	}
+}
+
+// chunkRelit sends the new light of a chunk to the players who have it.
+func (s *Server) chunkRelit(chunk *world.Chunk) {
+	for _, p := range s.chunkViewers() {
+		v := &p.view
+		v.mu.Lock()
+		if v.sent[chunk.Pos] {
+			p.conn.WritePacket(protocol.ClientboundPlayUpdateLight, chunk.UpdateLightPacket()...)
+		}
+		v.mu.Unlock()
+	}
+}
+
+// chunkViewers returns the players who are sent chunks.
+func (s *Server) chunkViewers() []*Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	viewers := make([]*Player, 0, len(s.viewers))
+	for p := range s.viewers {
+		viewers = append(viewers, p)
+	}
+	return viewers
+}
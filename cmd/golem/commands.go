@@ -0,0 +1,304 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// CommandSender is whoever runs a command: a player, the console or an RCON
+// client.
+type CommandSender interface {
+	// DisplayName names the sender in messages, such as "[Steve] hello".
+	DisplayName() string
+	// SendMessage shows a line of feedback to the sender.
+	SendMessage(text string)
+}
+
+// ErrUsage makes the dispatcher show the command's usage.
+var ErrUsage = errors.New("invalid usage")
+
+// Command is a command that players, the console and RCON can run.
+type Command struct {
+	Name    string
+	Aliases []string
+	// Usage describes the arguments, such as "<player> <message>".
+	Usage       string
+	Description string
+	// Run executes the command. A returned error is shown to the sender;
+	// ErrUsage shows the usage instead.
+	Run func(sender CommandSender, args []string) error
+}
+
+// Commands is the command registry. Every way of running a command goes
+// through Dispatch, so a command behaves the same for players, the console
+// and RCON.
+type Commands struct {
+	mu       sync.RWMutex
+	commands map[string]*Command // by name and alias
+}
+
+// NewCommands creates an empty registry.
+func NewCommands() *Commands {
+	return &Commands{commands: make(map[string]*Command)}
+}
+
+// Register adds cmd under its name and aliases, which must not be taken.
+func (c *Commands) Register(cmd *Command) error {
+	names := append([]string{cmd.Name}, cmd.Aliases...)
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, " /") || strings.ToLower(name) != name {
+			return fmt.Errorf("invalid command name %q", name)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		if _, ok := c.commands[name]; ok {
+			return fmt.Errorf("command %s is already registered", name)
+		}
+	}
+	for _, name := range names {
+		c.commands[name] = cmd
+	}
+	return nil
+}
+
+// Unregister removes the command called name, with all its aliases.
+func (c *Commands) Unregister(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cmd, ok := c.commands[name]
+	if !ok {
+		return
+	}
+	for n, other := range c.commands {
+		if other == cmd {
+			delete(c.commands, n)
+		}
+	}
+}
+
+// Lookup finds a command by name or alias.
+func (c *Commands) Lookup(name string) (*Command, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cmd, ok := c.commands[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// List returns the registered commands sorted by name.
+func (c *Commands) List() []*Command {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var list []*Command
+	for name, cmd := range c.commands {
+		if name == cmd.Name {
+			list = append(list, cmd)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Dispatch runs a command line, with or without the leading slash, for
+// sender. It reports whether the command exists; feedback, including for
+// unknown commands and failures, goes to the sender.
+func (c *Commands) Dispatch(sender CommandSender, line string) bool {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "/"))
+	if len(fields) == 0 {
+		return false
+	}
+	cmd, ok := c.Lookup(fields[0])
+	if !ok {
+		sender.SendMessage(fmt.Sprintf("Unknown command %q. Type \"/help\" for help.", fields[0]))
+		return false
+	}
+	if err := runCommand(cmd, sender, fields[1:]); err != nil {
+		if errors.Is(err, ErrUsage) {
+			sender.SendMessage(fmt.Sprintf("Usage: /%s %s", cmd.Name, cmd.Usage))
+		} else {
+			sender.SendMessage(err.Error())
+		}
+	}
+	return true
+}
+
+// runCommand runs cmd, turning a panic into an error so a broken command
+// cannot take down the connection that ran it.
+func runCommand(cmd *Command, sender CommandSender, args []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Command /%s panicked: %v", cmd.Name, r)
+			err = errors.New("An internal error occurred while running the command.")
+		}
+	}()
+	return cmd.Run(sender, args)
+}
+
+// registerBuiltinCommands adds the commands every server has.
+func (s *Server) registerBuiltinCommands() {
+	for _, cmd := range []*Command{
+		{
+			Name:        "help",
+			Aliases:     []string{"?"},
+			Description: "Lists the commands",
+			Run: func(sender CommandSender, args []string) error {
+				for _, cmd := range s.commands.List() {
+					line := "/" + cmd.Name
+					if cmd.Usage != "" {
+						line += " " + cmd.Usage
+					}
+					if cmd.Description != "" {
+						line += " - " + cmd.Description
+					}
+					sender.SendMessage(line)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "list",
+			Description: "Lists the online players",
+			Run: func(sender CommandSender, args []string) error {
+				players := s.Players()
+				names := make([]string, len(players))
+				for i, p := range players {
+					names[i] = p.Name
+				}
+				sender.SendMessage(fmt.Sprintf("There are %d of a max of %d players online: %s",
+					len(players), s.cfg.Server.MaxPlayers, strings.Join(names, ", ")))
+				return nil
+			},
+		},
+		{
+			Name:        "say",
+			Usage:       "<message>",
+			Description: "Broadcasts a message",
+			Run: func(sender CommandSender, args []string) error {
+				if len(args) == 0 {
+					return ErrUsage
+				}
+				s.Broadcast(fmt.Sprintf("[%s] %s", sender.DisplayName(), strings.Join(args, " ")), nil)
+				return nil
+			},
+		},
+		{
+			Name:        "msg",
+			Aliases:     []string{"tell", "w"},
+			Usage:       "<player> <message>",
+			Description: "Sends a private message",
+			Run: func(sender CommandSender, args []string) error {
+				if len(args) < 2 {
+					return ErrUsage
+				}
+				target := s.PlayerByName(args[0])
+				if target == nil {
+					return fmt.Errorf("No player was found")
+				}
+				message := strings.Join(args[1:], " ")
+				target.SendMessage(fmt.Sprintf("%s whispers to you: %s", sender.DisplayName(), message))
+				sender.SendMessage(fmt.Sprintf("You whisper to %s: %s", target.Name, message))
+				return nil
+			},
+		},
+		{
+			Name:        "tps",
+			Aliases:     []string{"mspt"},
+			Description: "Shows the ticks per second and how long ticks take",
+			Run: func(sender CommandSender, args []string) error {
+				st := s.loop.Stats()
+				sender.SendMessage(fmt.Sprintf("TPS from last 1m, 5m, 15m: %.1f, %.1f, %.1f", st.TPS1m, st.TPS5m, st.TPS15m))
+				sender.SendMessage(fmt.Sprintf("Tick time from last 5s, 1m: %s, %s (max %s)",
+					millis(st.MSPT5s), millis(st.MSPT1m), millis(st.MSPTMax)))
+				phases := make([]string, numPhases)
+				for p, d := range st.Phases {
+					phases[p] = fmt.Sprintf("%s %s", TickPhase(p), millis(d))
+				}
+				sender.SendMessage("Phases from last 5s: " + strings.Join(phases, ", "))
+				return nil
+			},
+		},
+		{
+			Name:        "limits",
+			Description: "Shows how many connections the limits turned away",
+			Run: func(sender CommandSender, args []string) error {
+				st := s.LimitStats()
+				sender.SendMessage(fmt.Sprintf("Turned away since start: %d throttled, %d over the per-address limit, %d while busy, %d kicked for packet floods",
+					st.Throttled, st.TooManyConnections, st.Busy, st.PacketFlood))
+				return nil
+			},
+		},
+		{
+			Name:        "chunks",
+			Description: "Shows how many chunks are loaded and how far loading lags",
+			Run: func(sender CommandSender, args []string) error {
+				if s.chunks == nil {
+					return fmt.Errorf("No world is loaded")
+				}
+				st := s.chunks.Stats()
+				sender.SendMessage(fmt.Sprintf("Chunks: %d loaded, %d cached", st.Loaded, st.Cached))
+				sender.SendMessage(fmt.Sprintf("Queue: %d waiting (peak %d), %d loading, %d saves pending",
+					st.Queued, st.QueuePeak, st.Loading, st.PendingSaves))
+				sender.SendMessage(fmt.Sprintf("Since start: %d read, %d generated, %d saved, %d failed, %s per chunk",
+					st.Loads, st.Generated, st.Saves, st.Failures, millis(st.LoadTime)))
+				return nil
+			},
+		},
+	} {
+		if err := s.commands.Register(cmd); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// millis formats a duration in milliseconds with one decimal, as /tps
+// shows tick times.
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
+
+// commandTree encodes the registered commands as a Commands packet, so the
+// client can complete their names. Every command takes the rest of the line
+// as a single greedy string argument, which clients never sign.
+func (c *Commands) commandTree() [][]byte {
+	const (
+		nodeRoot       = 0
+		nodeLiteral    = 1
+		nodeArgument   = 2
+		flagExecutable = 0x04
+		parserString   = 5 // brigadier:string
+		greedyPhrase   = 2
+	)
+	c.mu.RLock()
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	c.mu.RUnlock()
+	sort.Strings(names)
+
+	// Node 0 is the root, node 1 the shared argument, then one literal per name.
+	nodes := [][]byte{
+		append([]byte{nodeRoot}, protocol.WriteVarInt(len(names))...),
+		append([]byte{nodeArgument | flagExecutable}, protocol.WriteVarInt(0)...),
+	}
+	for i := range names {
+		nodes[0] = append(nodes[0], protocol.WriteVarInt(i+2)...)
+	}
+	nodes[1] = append(nodes[1], protocol.WriteString("args")...)
+	nodes[1] = append(nodes[1], protocol.WriteVarInt(parserString)...)
+	nodes[1] = append(nodes[1], protocol.WriteVarInt(greedyPhrase)...)
+	for _, name := range names {
+		node := append([]byte{nodeLiteral | flagExecutable}, protocol.WriteVarInt(1)...)
+		node = append(node, protocol.WriteVarInt(1)...)
+		nodes = append(nodes, append(node, protocol.WriteString(name)...))
+	}
+	return append(append([][]byte{protocol.WriteVarInt(len(nodes))}, nodes...), protocol.WriteVarInt(0))
+}
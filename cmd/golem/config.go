@@ -0,0 +1,309 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/Advik-B/Golem/world"
+	"gopkg.in/yaml.v3"
+)
+
+// Config maps golem.yml. Every field has a default, so a missing file or a
+// partial file is fine.
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Status   StatusConfig   `yaml:"status"`
+	Network  NetworkConfig  `yaml:"network"`
+	Limits   LimitsConfig   `yaml:"limits"`
+	Query    QueryConfig    `yaml:"query"`
+	RCON     RCONConfig     `yaml:"rcon"`
+	Movement MovementConfig `yaml:"movement"`
+	World    WorldConfig    `yaml:"world"`
+	Proxy    ProxyConfig    `yaml:"proxy"`
+	Debug    DebugConfig    `yaml:"debug"`
+}
+
+type ServerConfig struct {
+	// Address is the TCP address the server listens on.
+	Address    string `yaml:"address"`
+	MaxPlayers int    `yaml:"max-players"`
+	// EnforceSecureChat requires clients to sign their chat messages.
+	EnforceSecureChat bool `yaml:"enforce-secure-chat"`
+	// AcceptTransfers lets clients that another server sent here with Transfer
+	// log in, like accepts-transfers in server.properties.
+	AcceptTransfers bool `yaml:"accept-transfers"`
+	// PluginDir holds one directory per JavaScript plugin.
+	PluginDir string `yaml:"plugin-dir"`
+	// MaxTickTime is how long a tick may run before the watchdog logs the
+	// stacks of every goroutine, to show where it hangs; zero turns the
+	// watchdog off. Unlike max-tick-time in server.properties it does not
+	// stop the server.
+	MaxTickTime time.Duration `yaml:"max-tick-time"`
+}
+
+type StatusConfig struct {
+	// MOTD is the message of the day shown in the server list.
+	MOTD string `yaml:"motd"`
+	// Icon is a 64x64 PNG shown in the server list. It is skipped if missing.
+	Icon string `yaml:"icon"`
+	// HideOnlinePlayers leaves the player sample out of the response.
+	HideOnlinePlayers bool `yaml:"hide-online-players"`
+	// HidePlayerCount leaves the player counts out entirely; clients show "???".
+	HidePlayerCount bool `yaml:"hide-player-count"`
+	// SampleSize is the number of player names shown when hovering the count.
+	SampleSize int `yaml:"sample-size"`
+}
+
+type NetworkConfig struct {
+	ReadTimeout       time.Duration `yaml:"read-timeout"`
+	KeepAliveInterval time.Duration `yaml:"keep-alive-interval"`
+	KeepAliveTimeout  time.Duration `yaml:"keep-alive-timeout"`
+	// SendQueue is how many packets may be queued for a client before it is
+	// considered too slow and disconnected.
+	SendQueue int `yaml:"send-queue"`
+}
+
+// LimitsConfig protects the server from clients that connect or send too
+// much. A zero value turns a limit off.
+type LimitsConfig struct {
+	// ConnectionThrottle is how long an address must wait between logins, like
+	// connection-throttle in bukkit.yml. Behind a proxy it applies to the
+	// forwarded address. Loopback clients are exempt.
+	ConnectionThrottle time.Duration `yaml:"connection-throttle"`
+	// MaxConnectionsPerIP caps the open connections from one address. It is
+	// not enforced with proxy forwarding, where they all come from the proxy.
+	MaxConnectionsPerIP int `yaml:"max-connections-per-ip"`
+	// MaxPendingLogins caps the clients between the handshake and joining the
+	// game; more are told the server is busy.
+	MaxPendingLogins int `yaml:"max-pending-logins"`
+	// A client sending more than PacketLimit packets per PacketInterval, on
+	// average, is kicked.
+	PacketLimit    int           `yaml:"packet-limit"`
+	PacketInterval time.Duration `yaml:"packet-interval"`
+}
+
+// MovementConfig holds the checks on the moves players send, like vanilla's
+// anti-cheat. A zero value turns a check off.
+type MovementConfig struct {
+	// AllowFlight stops kicking players who float in the air, like
+	// allow-flight in server.properties.
+	AllowFlight bool `yaml:"allow-flight"`
+	// MaxMoveDistance is how far a player may move in one packet; a longer
+	// move "moved too quickly" and the player is sent back. Vanilla allows
+	// 10 blocks.
+	MaxMoveDistance float64 `yaml:"max-move-distance"`
+	// WrongMoveDistance is how far a move may end from where the world lets
+	// the player go; further, it "moved wrongly" and the player is sent back.
+	// Only full blocks stop players. Vanilla allows 0.25 blocks.
+	WrongMoveDistance float64 `yaml:"wrong-move-distance"`
+	// FlyingKickTime is how long a player who may not fly can hang in the
+	// air, with no block around it, before it is kicked. Vanilla allows 80
+	// ticks.
+	FlyingKickTime time.Duration `yaml:"flying-kick-time"`
+}
+
+// QueryConfig is the GameSpy4 query listener that server browsers use.
+type QueryConfig struct {
+	// Enabled answers queries, like enable-query in server.properties.
+	Enabled bool `yaml:"enabled"`
+	// Address is the UDP address queries are answered on.
+	Address string `yaml:"address"`
+}
+
+// RCONConfig is the remote console that runs commands over TCP.
+type RCONConfig struct {
+	// Enabled accepts RCON connections, like enable-rcon in
+	// server.properties. It needs a Password.
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+	// Password is what clients log in with.
+	Password string `yaml:"password"`
+	// An address that sends MaxAuthFailures wrong passwords within BanTime
+	// is refused for BanTime.
+	MaxAuthFailures int           `yaml:"max-auth-failures"`
+	BanTime         time.Duration `yaml:"ban-time"`
+}
+
+// Level types for WorldConfig.LevelType.
+const (
+	LevelTypeNormal = "minecraft:normal"
+	LevelTypeFlat   = "minecraft:flat"
+)
+
+// Forwarding modes for ProxyConfig.Forwarding.
+const (
+	ForwardingNone       = "none"
+	ForwardingBungeeCord = "bungeecord"
+	ForwardingVelocity   = "velocity"
+)
+
+type ProxyConfig struct {
+	// Forwarding is how the proxy in front of the server passes on the real
+	// client address, UUID and skin: "none", "bungeecord" or "velocity".
+	Forwarding string `yaml:"forwarding"`
+	// VelocitySecret is the forwarding secret configured in Velocity.
+	VelocitySecret string `yaml:"velocity-secret"`
+	// ProxyProtocol expects every connection to start with a HAProxy PROXY
+	// header and takes the client address from it.
+	ProxyProtocol bool `yaml:"proxy-protocol"`
+}
+
+type WorldConfig struct {
+	// Directory holds the world: its level.dat and the region files of its
+	// dimensions, as vanilla lays them out. Its last element is the level
+	// name.
+	Directory string `yaml:"directory"`
+	// Seed, LevelType and GeneratorSettings shape a new world, like
+	// level-seed, level-type and generator-settings in server.properties.
+	// The seed is a number or any text; empty picks one at random. The
+	// level type is minecraft:normal or minecraft:flat, whose layers
+	// GeneratorSettings gives as a superflat preset such as
+	// "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains".
+	Seed              string `yaml:"seed"`
+	LevelType         string `yaml:"level-type"`
+	GeneratorSettings string `yaml:"generator-settings"`
+	// ViewDistance is how many chunks around them players see, from 2 to
+	// 32, like view-distance in server.properties.
+	ViewDistance int `yaml:"view-distance"`
+	// EntityBroadcastRange scales how far away players see entities, in
+	// percent of vanilla's range for each type, like
+	// entity-broadcast-range-percentage in server.properties. The view
+	// distance caps it; 0 shows players no entities.
+	EntityBroadcastRange int `yaml:"entity-broadcast-range"`
+	// ChunkWorkers is how many chunks are loaded, generated or saved at once.
+	ChunkWorkers int `yaml:"chunk-workers"`
+	// CachedChunks is how many chunks no player sees stay in memory before
+	// the least recently used are saved and unloaded.
+	CachedChunks int `yaml:"cached-chunks"`
+	// AutosaveInterval is how often changed chunks are saved; zero saves
+	// them only when they are unloaded. Vanilla saves every five minutes.
+	AutosaveInterval time.Duration `yaml:"autosave-interval"`
+}
+
+type DebugConfig struct {
+	// Capture records every packet of every connection to a file in
+	// CaptureDir. The files can be fed back with "golem replay".
+	Capture    bool   `yaml:"capture"`
+	CaptureDir string `yaml:"capture-dir"`
+}
+
+// DefaultConfig returns the configuration used when golem.yml is missing.
+func DefaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Address:           ":25565",
+			MaxPlayers:        20,
+			EnforceSecureChat: true,
+			PluginDir:         "plugins",
+			MaxTickTime:       time.Minute,
+		},
+		Status: StatusConfig{
+			MOTD:       "A Golem Server",
+			Icon:       "server-icon.png",
+			SampleSize: 12,
+		},
+		Network: NetworkConfig{
+			ReadTimeout:       30 * time.Second,
+			KeepAliveInterval: 15 * time.Second,
+			KeepAliveTimeout:  15 * time.Second,
+			SendQueue:         1024,
+		},
+		Limits: LimitsConfig{
+			ConnectionThrottle:  4 * time.Second,
+			MaxConnectionsPerIP: 16,
+			MaxPendingLogins:    64,
+			PacketLimit:         500,
+			PacketInterval:      7 * time.Second,
+		},
+		Query: QueryConfig{
+			Address: ":25565",
+		},
+		Movement: MovementConfig{
+			MaxMoveDistance:   10,
+			WrongMoveDistance: 0.25,
+			FlyingKickTime:    4 * time.Second,
+		},
+		RCON: RCONConfig{
+			Address:         ":25575",
+			MaxAuthFailures: 5,
+			BanTime:         5 * time.Minute,
+		},
+		World: WorldConfig{
+			Directory:            "worlds/world",
+			LevelType:            LevelTypeNormal,
+			GeneratorSettings:    world.FlatPresets["classic_flat"],
+			ViewDistance:         10,
+			EntityBroadcastRange: 100,
+			ChunkWorkers:         4,
+			CachedChunks:         1024,
+			AutosaveInterval:     5 * time.Minute,
+		},
+		Proxy: ProxyConfig{
+			Forwarding: ForwardingNone,
+		},
+		Debug: DebugConfig{
+			CaptureDir: "captures",
+		},
+	}
+}
+
+// LoadConfig reads the config at path on top of the defaults. When the file
+// does not exist the defaults are written there so they can be edited.
+func LoadConfig(path string) (*Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		out, err := yaml.Marshal(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return cfg, os.WriteFile(path, out, 0o644)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// The view distances vanilla allows.
+const (
+	minViewDistance = 2
+	maxViewDistance = 32
+)
+
+// validate rejects settings that cannot work together.
+func (c *Config) validate() error {
+	if d := c.World.ViewDistance; d < minViewDistance || d > maxViewDistance {
+		return fmt.Errorf("world.view-distance must be between %d and %d", minViewDistance, maxViewDistance)
+	}
+	if c.World.ChunkWorkers < 0 {
+		return errors.New("world.chunk-workers must not be negative")
+	}
+	if c.World.CachedChunks < 0 {
+		return errors.New("world.cached-chunks must not be negative")
+	}
+	switch c.World.LevelType {
+	case LevelTypeNormal, LevelTypeFlat:
+	default:
+		return fmt.Errorf("unknown world.level-type %q", c.World.LevelType)
+	}
+	switch c.Proxy.Forwarding {
+	case ForwardingNone, ForwardingBungeeCord:
+	case ForwardingVelocity:
+		if c.Proxy.VelocitySecret == "" {
+			return errors.New("proxy.velocity-secret must be set for velocity forwarding")
+		}
+	default:
+		return fmt.Errorf("unknown proxy.forwarding %q", c.Proxy.Forwarding)
+	}
+	return nil
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync/atomic"
+
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/world"
+)
+
+// PlayerEntityType is the entity type of players.
+const PlayerEntityType = "minecraft:player"
+
+// entityTracking is how far away players see an entity, in chunks, and how
+// often its movement is sent, in ticks.
+type entityTracking struct {
+	radius   int
+	interval int
+}
+
+// defaultTracking is how vanilla tracks the entity types it does not list
+// in entityTrackings, which are most mobs.
+var defaultTracking = entityTracking{radius: 5, interval: 3}
+
+// entityTrackings are vanilla's tracking ranges and update intervals of the
+// entity types that differ from defaultTracking. Entities that stay where
+// they are placed, such as item frames, are never sent moves.
+var entityTrackings = map[string]entityTracking{
+	PlayerEntityType:              {radius: 32, interval: 2},
+	"minecraft:item":              {radius: 6, interval: 20},
+	"minecraft:experience_orb":    {radius: 6, interval: 20},
+	"minecraft:falling_block":     {radius: 10, interval: 20},
+	"minecraft:tnt":               {radius: 10, interval: 10},
+	"minecraft:ender_dragon":      {radius: 10, interval: 3},
+	"minecraft:arrow":             {radius: 4, interval: 20},
+	"minecraft:spectral_arrow":    {radius: 4, interval: 20},
+	"minecraft:trident":           {radius: 4, interval: 20},
+	"minecraft:firework_rocket":   {radius: 4, interval: 10},
+	"minecraft:fishing_bobber":    {radius: 4, interval: 5},
+	"minecraft:eye_of_ender":      {radius: 4, interval: 4},
+	"minecraft:minecart":          {radius: 8, interval: 3},
+	"minecraft:boat":              {radius: 10, interval: 3},
+	"minecraft:item_frame":        {radius: 10, interval: math.MaxInt32},
+	"minecraft:glow_item_frame":   {radius: 10, interval: math.MaxInt32},
+	"minecraft:painting":          {radius: 10, interval: math.MaxInt32},
+	"minecraft:leash_knot":        {radius: 10, interval: math.MaxInt32},
+	"minecraft:end_crystal":       {radius: 16, interval: math.MaxInt32},
+	"minecraft:area_effect_cloud": {radius: 10, interval: math.MaxInt32},
+	"minecraft:lightning_bolt":    {radius: 16, interval: math.MaxInt32},
+	// Markers live only on the server.
+	"minecraft:marker": {radius: 0, interval: math.MaxInt32},
+}
+
+// teleportInterval is how many ticks at most pass between the absolute
+// positions sent for a moving entity, which undo the rounding of the
+// relative moves in between.
+const teleportInterval = 400
+
+// EntityTracker holds the entities of the world and sends them to the
+// players near them: Spawn Entity when a player comes within range, the
+// moves, turns and metadata changes while it stays, and Remove Entities
+// when it leaves or the entity is removed. Players are entities too and
+// see each other.
+//
+// The tracker belongs to the tick goroutine, which runs it in the entity
+// phase; only NewID may be called from elsewhere.
+type EntityTracker struct {
+	srv    *Server
+	nextID atomic.Int32
+
+	entities map[int32]*trackedEntity
+	// players are the players in the world, with their entities.
+	players map[*Player]*trackedEntity
+}
+
+// trackedEntity is an entity with the players who see it and what they were
+// last sent about it.
+type trackedEntity struct {
+	e        *world.Entity
+	player   *Player
+	tracking entityTracking
+	viewers  map[*Player]bool
+
+	ticks      int
+	teleported int
+	// pos is the position last sent in 1/4096 blocks, which relative moves
+	// count from.
+	pos                 [3]int64
+	yaw, pitch, headYaw byte
+	onGround            bool
+	motion              world.Vec3
+	metadata            []protocol.MetadataEntry
+}
+
+func newEntityTracker(s *Server) *EntityTracker {
+	return &EntityTracker{
+		srv:      s,
+		entities: make(map[int32]*trackedEntity),
+		players:  make(map[*Player]*trackedEntity),
+	}
+}
+
+// NewID returns an unused entity ID. Players and entities share the IDs.
+func (t *EntityTracker) NewID() int32 {
+	return t.nextID.Add(1)
+}
+
+// Spawn adds e to the world, giving it an ID if it has none. Players near it
+// see it from the next tick.
+func (t *EntityTracker) Spawn(e *world.Entity) error {
+	if e.Type == PlayerEntityType {
+		return fmt.Errorf("players cannot be spawned as entities")
+	}
+	if e.ID == 0 {
+		e.ID = t.NewID()
+	}
+	if _, ok := t.entities[e.ID]; ok {
+		return fmt.Errorf("entity %d is already spawned", e.ID)
+	}
+	t.track(e, nil)
+	return nil
+}
+
+// Remove takes e out of the world and tells the players who see it. It
+// reports whether e was in the world.
+func (t *EntityTracker) Remove(e *world.Entity) bool {
+	te, ok := t.entities[e.ID]
+	if !ok || te.e != e || te.player != nil {
+		return false
+	}
+	delete(t.entities, e.ID)
+	for p := range te.viewers {
+		sendRemoveEntities(p, []int32{e.ID})
+	}
+	return true
+}
+
+// Entity returns the entity or player entity with the given ID, or nil.
+func (t *EntityTracker) Entity(id int32) *world.Entity {
+	if te, ok := t.entities[id]; ok {
+		return te.e
+	}
+	return nil
+}
+
+// Entities returns the entities in the world, players included, by ID.
+func (t *EntityTracker) Entities() []*world.Entity {
+	list := make([]*world.Entity, 0, len(t.entities))
+	for _, te := range t.sorted() {
+		list = append(list, te.e)
+	}
+	return list
+}
+
+func (t *EntityTracker) track(e *world.Entity, p *Player) *trackedEntity {
+	tracking, ok := entityTrackings[e.Type]
+	if !ok {
+		tracking = defaultTracking
+	}
+	te := &trackedEntity{e: e, player: p, tracking: tracking, viewers: make(map[*Player]bool)}
+	te.remember()
+	t.entities[e.ID] = te
+	return te
+}
+
+// addPlayer puts a player that joined into the world, where other players
+// see it and it sees the entities around it.
+func (t *EntityTracker) addPlayer(p *Player) {
+	p.syncEntity()
+	t.players[p] = t.track(p.entity, p)
+}
+
+// removePlayer takes a player that left out of the world.
+func (t *EntityTracker) removePlayer(p *Player) {
+	te, ok := t.players[p]
+	if !ok {
+		return
+	}
+	delete(t.players, p)
+	delete(t.entities, te.e.ID)
+	for viewer := range te.viewers {
+		sendRemoveEntities(viewer, []int32{te.e.ID})
+	}
+	for _, other := range t.entities {
+		delete(other.viewers, p)
+	}
+}
+
+// tick is the entity phase: it sends what changed about each entity to the
+// players who see it, then spawns and removes entities for the players who
+// came within or went out of range.
+func (t *EntityTracker) tick() {
+	for p := range t.players {
+		p.syncEntity()
+	}
+	removed := make(map[*Player][]int32)
+	for _, te := range t.sorted() {
+		te.sendChanges()
+		for p, pte := range t.players {
+			if p == te.player {
+				continue
+			}
+			switch sees := t.sees(pte, te); {
+			case sees && !te.viewers[p]:
+				te.viewers[p] = true
+				// A failed write means the client is gone; its session
+				// cleans up.
+				te.spawnFor(p)
+			case !sees && te.viewers[p]:
+				delete(te.viewers, p)
+				removed[p] = append(removed[p], te.e.ID)
+			}
+		}
+	}
+	for p, ids := range removed {
+		sendRemoveEntities(p, ids)
+	}
+}
+
+// sorted returns the tracked entities by ID, so players are sent them in a
+// steady order.
+func (t *EntityTracker) sorted() []*trackedEntity {
+	list := make([]*trackedEntity, 0, len(t.entities))
+	for _, te := range t.entities {
+		list = append(list, te)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].e.ID < list[j].e.ID })
+	return list
+}
+
+// sees reports whether the player with entity viewer is close enough to see
+// te: within its type's range, scaled by world.entity-broadcast-range and
+// capped by the view distance, and in a chunk the player's client has.
+func (t *EntityTracker) sees(viewer, te *trackedEntity) bool {
+	cfg := t.srv.cfg.World
+	radius := float64(min(te.tracking.radius*cfg.EntityBroadcastRange/100, cfg.ViewDistance) * 16)
+	if radius <= 0 {
+		return false
+	}
+	from, to := viewer.e.Pos, te.e.Pos
+	if math.Abs(to.X-from.X) > radius || math.Abs(to.Z-from.Z) > radius {
+		return false
+	}
+	if t.srv.chunks == nil {
+		return true
+	}
+	v := &viewer.player.view
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.sent[world.ChunkPosOf(int(math.Floor(to.X)), int(math.Floor(to.Z)))]
+}
+
+// remember records the entity as it is now as what its viewers have.
+func (te *trackedEntity) remember() {
+	e := te.e
+	te.pos = encodePosition(e.Pos)
+	te.yaw, te.pitch, te.headYaw = angle(e.Yaw), angle(e.Pitch), angle(e.HeadYaw)
+	te.onGround = e.OnGround
+	te.motion = e.Motion
+	te.metadata = e.Metadata()
+	te.teleported = te.ticks
+}
+
+// sendChanges sends the viewers how the entity moved, turned and changed
+// since they were last told, moving at most every tracking interval.
+func (te *trackedEntity) sendChanges() {
+	te.ticks++
+	e := te.e
+	id := protocol.WriteVarInt(int(e.ID))
+	var packets []entityPacket
+	if te.ticks%te.tracking.interval == 0 {
+		pos := encodePosition(e.Pos)
+		yaw, pitch := angle(e.Yaw), angle(e.Pitch)
+		var delta [3]int64
+		far := false
+		for i := range pos {
+			delta[i] = pos[i] - te.pos[i]
+			far = far || delta[i] < math.MinInt16 || delta[i] > math.MaxInt16
+		}
+		moved := delta != [3]int64{}
+		turned := yaw != te.yaw || pitch != te.pitch
+		switch {
+		case far || e.OnGround != te.onGround || (moved && te.ticks-te.teleported > teleportInterval):
+			packets = append(packets, entityPacket{protocol.ClientboundPlayTeleportEntity, [][]byte{id,
+				protocol.WriteDouble(e.Pos.X), protocol.WriteDouble(e.Pos.Y), protocol.WriteDouble(e.Pos.Z),
+				{yaw}, {pitch}, protocol.WriteBool(e.OnGround)}})
+			te.teleported = te.ticks
+		case moved && turned:
+			packets = append(packets, entityPacket{protocol.ClientboundPlayUpdateEntityPositionAndRotation, [][]byte{id,
+				protocol.WriteShort(int16(delta[0])), protocol.WriteShort(int16(delta[1])), protocol.WriteShort(int16(delta[2])),
+				{yaw}, {pitch}, protocol.WriteBool(e.OnGround)}})
+		case moved:
+			packets = append(packets, entityPacket{protocol.ClientboundPlayUpdateEntityPosition, [][]byte{id,
+				protocol.WriteShort(int16(delta[0])), protocol.WriteShort(int16(delta[1])), protocol.WriteShort(int16(delta[2])),
+				protocol.WriteBool(e.OnGround)}})
+		case turned:
+			packets = append(packets, entityPacket{protocol.ClientboundPlayUpdateEntityRotation, [][]byte{id,
+				{yaw}, {pitch}, protocol.WriteBool(e.OnGround)}})
+		}
+		te.pos, te.yaw, te.pitch, te.onGround = pos, yaw, pitch, e.OnGround
+
+		if headYaw := angle(e.HeadYaw); headYaw != te.headYaw {
+			packets = append(packets, entityPacket{protocol.ClientboundPlaySetHeadRotation, [][]byte{id, {headYaw}}})
+			te.headYaw = headYaw
+		}
+		// Clients work out how players move themselves.
+		if te.player == nil && e.Motion != te.motion {
+			packets = append(packets, entityPacket{protocol.ClientboundPlaySetEntityVelocity, append([][]byte{id}, velocity(e.Motion)...)})
+			te.motion = e.Motion
+		}
+	}
+
+	// Metadata is sent as soon as it changes.
+	metadata := e.Metadata()
+	var changed []protocol.MetadataEntry
+	for _, entry := range metadata {
+		if !hasEntry(te.metadata, entry) {
+			changed = append(changed, entry)
+		}
+	}
+	te.metadata = metadata
+
+	for p := range te.viewers {
+		for _, pkt := range packets {
+			p.conn.WritePacket(pkt.packet, pkt.fields...)
+		}
+		if len(changed) > 0 {
+			p.conn.WritePacket(protocol.ClientboundPlaySetEntityMetadata, id, p.conn.Version().WriteMetadata(changed))
+		}
+	}
+}
+
+// spawnFor sends a player that came within range the entity as its viewers
+// last had it, so later relative moves count from the same position.
+func (te *trackedEntity) spawnFor(p *Player) error {
+	e := te.e
+	v := p.conn.Version()
+	typ, ok := v.EntityType(e.Type)
+	if !ok {
+		// Entities the client does not know cannot be shown.
+		return nil
+	}
+	id := protocol.WriteVarInt(int(e.ID))
+	fields := [][]byte{id, protocol.WriteUUID(e.UUID), protocol.WriteVarInt(int(typ))}
+	for _, c := range te.pos {
+		fields = append(fields, protocol.WriteDouble(float64(c)/4096))
+	}
+	fields = append(fields, []byte{te.pitch}, []byte{te.yaw}, []byte{te.headYaw},
+		protocol.WriteVarInt(0)) // data
+	fields = append(fields, velocity(te.motion)...)
+	if err := p.conn.WritePacket(protocol.ClientboundPlaySpawnEntity, fields...); err != nil {
+		return err
+	}
+	return p.conn.WritePacket(protocol.ClientboundPlaySetEntityMetadata, id, v.WriteMetadata(te.metadata))
+}
+
+// entityPacket is a packet about an entity that is the same for every
+// client.
+type entityPacket struct {
+	packet protocol.Packet
+	fields [][]byte
+}
+
+// sendRemoveEntities tells a player's client to forget entities.
+func sendRemoveEntities(p *Player, ids []int32) error {
+	fields := [][]byte{protocol.WriteVarInt(len(ids))}
+	for _, id := range ids {
+		fields = append(fields, protocol.WriteVarInt(int(id)))
+	}
+	return p.conn.WritePacket(protocol.ClientboundPlayRemoveEntities, fields...)
+}
+
+// encodePosition returns a position in 1/4096 blocks, the unit of relative
+// moves.
+func encodePosition(pos world.Vec3) [3]int64 {
+	return [3]int64{int64(math.Round(pos.X * 4096)), int64(math.Round(pos.Y * 4096)), int64(math.Round(pos.Z * 4096))}
+}
+
+// angle returns an angle in degrees as the fraction of a turn in a byte.
+func angle(degrees float32) byte {
+	return protocol.WriteAngle(degrees)[0]
+}
+
+// velocity encodes a velocity in blocks per tick as clients take it, in
+// 1/8000 blocks and at most 3.9 blocks per tick on each axis.
+func velocity(v world.Vec3) [][]byte {
+	fields := make([][]byte, 3)
+	for i, c := range []float64{v.X, v.Y, v.Z} {
+		fields[i] = protocol.WriteShort(int16(max(-3.9, min(3.9, c)) * 8000))
+	}
+	return fields
+}
+
+func hasEntry(entries []protocol.MetadataEntry, e protocol.MetadataEntry) bool {
+	for _, o := range entries {
+		if o.Equal(e) {
+			return true
+		}
+	}
+	return false
+}
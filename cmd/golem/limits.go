@@ -0,0 +1,188 @@
+package main
+
+import (
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Disconnect reasons for clients turned away by the limits.
+const (
+	throttledMessage   = "Connection throttled! Please wait before reconnecting."
+	busyMessage        = "The server is busy, please try again in a moment."
+	packetFloodMessage = "Kicked for exceeding packet rate limit"
+)
+
+// LimitStats counts the connections the limits turned away since the server
+// started.
+type LimitStats struct {
+	// TooManyConnections is connections closed because their address already
+	// had max-connections-per-ip open.
+	TooManyConnections int64 `json:"tooManyConnections"`
+	// Throttled is logins refused by the connection throttle.
+	Throttled int64 `json:"throttled"`
+	// Busy is logins refused because max-pending-logins were in progress.
+	Busy int64 `json:"busy"`
+	// PacketFlood is clients kicked for sending packets too fast.
+	PacketFlood int64 `json:"packetFlood"`
+}
+
+// limiter enforces a server's LimitsConfig. The per-address limits only apply
+// to connections with an IP address.
+type limiter struct {
+	cfg LimitsConfig
+
+	mu sync.Mutex
+	// open counts the open connections per address.
+	open map[netip.Addr]int
+	// lastLogin is when each address last tried to log in, for the throttle.
+	lastLogin map[netip.Addr]time.Time
+	// logins counts throttle checks since lastLogin was last pruned.
+	logins int
+
+	pending atomic.Int32
+
+	tooManyConnections atomic.Int64
+	throttled          atomic.Int64
+	busy               atomic.Int64
+	packetFlood        atomic.Int64
+}
+
+func newLimiter(cfg LimitsConfig) *limiter {
+	return &limiter{
+		cfg:       cfg,
+		open:      make(map[netip.Addr]int),
+		lastLogin: make(map[netip.Addr]time.Time),
+	}
+}
+
+// stats returns the rejection counters.
+func (l *limiter) stats() LimitStats {
+	return LimitStats{
+		TooManyConnections: l.tooManyConnections.Load(),
+		Throttled:          l.throttled.Load(),
+		Busy:               l.busy.Load(),
+		PacketFlood:        l.packetFlood.Load(),
+	}
+}
+
+// clientIP returns the IP address of addr, if it has one.
+func clientIP(addr net.Addr) (netip.Addr, bool) {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+	ip, ok := netip.AddrFromSlice(tcp.IP)
+	return ip.Unmap(), ok
+}
+
+// openConn counts a new connection from addr and reports whether it may
+// stay open. If it may, release must be called once it is closed.
+func (l *limiter) openConn(addr net.Addr) (release func(), ok bool) {
+	ip, hasIP := clientIP(addr)
+	if l.cfg.MaxConnectionsPerIP <= 0 || !hasIP {
+		return func() {}, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.open[ip] >= l.cfg.MaxConnectionsPerIP {
+		l.tooManyConnections.Add(1)
+		return nil, false
+	}
+	l.open[ip]++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.open[ip]--; l.open[ip] <= 0 {
+			delete(l.open, ip)
+		}
+	}, true
+}
+
+// throttleLogin records a login attempt from addr and reports whether it
+// came too soon after the previous one. Like CraftBukkit, every attempt
+// restarts the wait, and loopback clients are exempt.
+func (l *limiter) throttleLogin(addr net.Addr, now time.Time) bool {
+	ip, ok := clientIP(addr)
+	if l.cfg.ConnectionThrottle <= 0 || !ok || ip.IsLoopback() {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	last, seen := l.lastLogin[ip]
+	l.lastLogin[ip] = now
+	if l.logins++; l.logins > 200 {
+		l.logins = 0
+		for ip, t := range l.lastLogin {
+			if now.Sub(t) >= l.cfg.ConnectionThrottle {
+				delete(l.lastLogin, ip)
+			}
+		}
+	}
+	if seen && now.Sub(last) < l.cfg.ConnectionThrottle {
+		l.throttled.Add(1)
+		return true
+	}
+	return false
+}
+
+// beginLogin reserves one of the pending login slots, reporting false if
+// they are all taken. A reserved slot must be given back with endLogin.
+func (l *limiter) beginLogin() bool {
+	if l.cfg.MaxPendingLogins <= 0 {
+		l.pending.Add(1)
+		return true
+	}
+	if l.pending.Add(1) > int32(l.cfg.MaxPendingLogins) {
+		l.pending.Add(-1)
+		l.busy.Add(1)
+		return false
+	}
+	return true
+}
+
+func (l *limiter) endLogin() { l.pending.Add(-1) }
+
+// packetRate returns a token bucket for one connection's packets, or nil if
+// the packet rate is not limited.
+func (l *limiter) packetRate() *packetRate {
+	if l.cfg.PacketLimit <= 0 || l.cfg.PacketInterval <= 0 {
+		return nil
+	}
+	return &packetRate{
+		limit:  float64(l.cfg.PacketLimit),
+		rate:   float64(l.cfg.PacketLimit) / l.cfg.PacketInterval.Seconds(),
+		tokens: float64(l.cfg.PacketLimit),
+	}
+}
+
+// packetRate is a token bucket that holds up to limit packets and refills at
+// limit per interval, so short bursts are fine but a sustained flood is not.
+type packetRate struct {
+	limit  float64
+	rate   float64 // tokens per second
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token for a packet read at now. A nil packetRate allows
+// everything.
+func (r *packetRate) allow(now time.Time) bool {
+	if r == nil {
+		return true
+	}
+	if !r.last.IsZero() {
+		r.tokens += now.Sub(r.last).Seconds() * r.rate
+		if r.tokens > r.limit {
+			r.tokens = r.limit
+		}
+	}
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
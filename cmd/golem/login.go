@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/proxy"
+)
+
+// handleLogin handles the login and configuration states.
+func (s *session) handleLogin(raw *protocol.RawPacket) error {
+	conn := s.conn
+	version := conn.Version()
+
+	switch raw.Packet {
+	case protocol.ServerboundLoginStart:
+		var start protocol.LoginStart
+		if err := raw.Decode(&start); err != nil {
+			return err
+		}
+		switch {
+		case s.srv.cfg.Proxy.Forwarding == ForwardingVelocity:
+			// Ask Velocity for the real player; login continues when it answers.
+			s.loginName = start.Name
+			return conn.WritePacket(protocol.ClientboundLoginPluginRequest,
+				protocol.WriteVarInt(velocityMessageID),
+				protocol.WriteString(proxy.VelocityChannel),
+				proxy.VelocityRequest(),
+			)
+		case s.forwarded != nil:
+			return s.finishLogin(start.Name, s.forwarded.UUID, s.forwarded.Properties)
+		}
+		return s.finishLogin(start.Name, protocol.OfflineUUID(start.Name), nil)
+
+	case protocol.ServerboundLoginPluginResponse:
+		var resp protocol.LoginPluginResponse
+		if err := raw.Decode(&resp); err != nil {
+			return err
+		}
+		if resp.MessageID != velocityMessageID || s.loginName == "" {
+			return fmt.Errorf("unexpected login plugin response %d", resp.MessageID)
+		}
+		if !resp.Successful {
+			conn.Disconnect("This server requires you to connect with Velocity.")
+			return nil
+		}
+		f, err := proxy.ParseVelocity([]byte(s.srv.cfg.Proxy.VelocitySecret), resp.Data)
+		if err != nil {
+			log.Printf("%s: %v", conn.RemoteAddr(), err)
+			conn.Disconnect("Unable to verify player details")
+			return nil
+		}
+		conn.SetRemoteAddr(f.Addr)
+		if s.throttled() {
+			return nil
+		}
+		return s.finishLogin(f.Name, f.UUID, f.Properties)
+
+	case protocol.ServerboundLoginAcknowledged:
+		if s.player == nil {
+			return fmt.Errorf("acknowledged login before logging in")
+		}
+		conn.SetState(protocol.Configuration)
+		if err := s.sendServerChannels(); err != nil {
+			return err
+		}
+		if version.Has(protocol.FeatureKnownPacks) {
+			// Offer the vanilla core pack; registries are sent once the
+			// client tells us which packs it already has.
+			packs := [][]byte{protocol.WriteVarInt(len(version.Names))}
+			for _, name := range version.Names {
+				packs = append(packs, protocol.WriteString("minecraft"), protocol.WriteString("core"), protocol.WriteString(name))
+			}
+			return conn.WritePacket(protocol.ClientboundConfigKnownPacks, packs...)
+		}
+		if err := sendRegistries(conn, false); err != nil {
+			return err
+		}
+		return conn.WritePacket(protocol.ClientboundConfigFinish)
+
+	case protocol.ServerboundConfigKnownPacks:
+		var known protocol.KnownPacks
+		if err := raw.Decode(&known); err != nil {
+			return err
+		}
+		if err := sendRegistries(conn, known.Has("minecraft", "core")); err != nil {
+			return err
+		}
+		return conn.WritePacket(protocol.ClientboundConfigFinish)
+
+	case protocol.ServerboundConfigPluginMessage:
+		return s.handlePluginMessage(raw)
+
+	case protocol.ServerboundLoginCookieResponse, protocol.ServerboundConfigCookieResponse:
+		return s.handleCookieResponse(raw)
+
+	case protocol.ServerboundConfigAcknowledgeFinish:
+		if s.player == nil {
+			return fmt.Errorf("finished configuration before logging in")
+		}
+		conn.SetState(protocol.Play)
+		return s.joinGame()
+	}
+	return nil
+}
+
+// velocityMessageID is the message ID of the Login Plugin Request sent to
+// Velocity. It is the only login plugin message the server sends.
+const velocityMessageID = 0
+
+// finishLogin sends Login Success for the given profile.
+func (s *session) finishLogin(name string, uuid protocol.UUID, properties []protocol.Property) error {
+	s.player = &Player{Name: name, UUID: uuid, Properties: properties, Transferred: s.transferred, conn: s.conn,
+		entity: newPlayerEntity(s.srv.entities.NewID(), uuid), left: make(chan struct{})}
+
+	loginSuccess := [][]byte{
+		protocol.WriteUUID(uuid),
+		protocol.WriteString(name),
+		protocol.WriteProperties(properties),
+	}
+	if s.conn.Version().Has(protocol.FeatureStrictErrorHandling) {
+		loginSuccess = append(loginSuccess, protocol.WriteBool(true))
+	}
+	return s.conn.WritePacket(protocol.ClientboundLoginSuccess, loginSuccess...)
+}
+
+// sendRegistries sends the synchronized registries. Versions with known packs
+// get one packet per registry, and the entry data is left out when the client
+// already has the vanilla core pack.
+func sendRegistries(conn *protocol.Conn, clientHasData bool) error {
+	v := conn.Version()
+	regs, err := v.Registries()
+	if err != nil {
+		return err
+	}
+	if !v.Has(protocol.FeatureKnownPacks) {
+		return conn.WritePacket(protocol.ClientboundConfigRegistryData, protocol.WriteNBT(regs.Codec()))
+	}
+	for _, reg := range regs.List() {
+		payload := [][]byte{protocol.WriteString(reg.Name), protocol.WriteVarInt(len(reg.Entries))}
+		for _, entry := range reg.Entries {
+			payload = append(payload, protocol.WriteString(entry.Name))
+			if clientHasData {
+				payload = append(payload, protocol.WriteBool(false))
+			} else {
+				payload = append(payload, protocol.WriteBool(true), protocol.WriteNBT(entry.Element))
+			}
+		}
+		if err := conn.WritePacket(protocol.ClientboundConfigRegistryData, payload...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,185 +1,79 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/binary"
-	"io"
+	"flag"
 	"log"
 	"net"
-)
+	"os"
+	"os/signal"
+	"syscall"
 
-const (
-	HandshakeState = 0
-	StatusState    = 1
-	LoginState     = 2
-	PlayState      = 3
-	ProtocolVer    = 765 // Minecraft 1.21.5
+	js "github.com/Advik-B/Golem/javascript"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/proxy"
 )
 
 func main() {
-	ln, err := net.Listen("tcp", ":25565")
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(replayCommand(os.Args[2:]))
+	}
+
+	configPath := flag.String("config", "golem.yml", "path to the server configuration")
+	flag.Parse()
+
+	cfg, err := LoadConfig(*configPath)
 	if err != nil {
 		log.Fatal(err)
 	}
-	log.Println("Listening on :25565")
-
-	for {
-		conn, err := ln.Accept()
-		if err != nil {
-			log.Println("Accept error:", err)
-			continue
-		}
-		go handleConnection(conn)
+	plugins := js.NewPluginManager()
+	srv, err := NewServer(cfg, plugins)
+	if err != nil {
+		log.Fatal(err)
 	}
-}
-
-func handleConnection(conn net.Conn) {
-	defer conn.Close()
-	r := bufio.NewReader(conn)
-
-	state := HandshakeState
-	var username string
-
-	for {
-		pktLen, _ := readVarInt(r)
-		data := make([]byte, pktLen)
-		if _, err := io.ReadFull(r, data); err != nil {
-			return
+	if err := plugins.LoadAll(cfg.Server.PluginDir); err != nil {
+		log.Fatal(err)
+	}
+	if err := srv.OpenWorld(cfg.World.Directory); err != nil {
+		log.Fatal(err)
+	}
+	// Save the world when stopped with Ctrl+C or by the service manager.
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+		log.Println("Stopping the server")
+		if err := srv.CloseWorld(); err != nil {
+			log.Fatal(err)
 		}
-		pkt := bufio.NewReader(io.NopCloser(bufio.NewReaderSize(bytesReader(data), len(data))))
-		pktID, _ := readVarInt(pkt)
-
-		switch state {
-		case HandshakeState:
-			_, _ = readVarInt(pkt)         // protocol version
-			_, _ = readString(pkt)         // server address
-			_, _ = binary.ReadUvarint(pkt) // port
-			nextState, _ := readVarInt(pkt)
-			state = nextState
+		os.Exit(0)
+	}()
 
-		case StatusState:
-			// status request (0x00)
-			if pktID == 0x00 {
-				resp := `{"version":{"name":"1.21.5","protocol":765},"players":{"max":1,"online":0},"description":{"text":"Void"}}`
-				writePacket(conn, 0x00, writeString(resp))
-			}
-		case LoginState:
-			if pktID == 0x00 {
-				username, _ = readString(pkt)
-				// Login success (0x02)
-				writePacket(conn, 0x02,
-					writeString("00000000-0000-0000-0000-000000000000"),
-					writeString(username),
-				)
-				state = PlayState
-
-				// Send Join Game
-				writePacket(conn, 0x26,
-					writeInt(1),                // Entity ID
-					writeByte(0), writeByte(0), // Game mode + previous
-					writeVarInt(1), writeString("minecraft:overworld"), // World count + name
-					writeString("minecraft:overworld"),              // dimension codec
-					writeLong(0),                                    // Hashed seed
-					writeVarInt(10), writeVarInt(0), writeVarInt(0), // max players, view/sim dist
-					writeBool(true), writeBool(true), writeBool(false), writeBool(false),
-				)
-
-				// Send empty chunk (0, 0)
-				writePacket(conn, 0x22,
-					writeInt(0), writeInt(0), // chunk XZ
-					writeBool(true), writeVarInt(0), writeVarInt(1),
-					[]byte{0x00},   // chunk data
-					writeVarInt(0), // block entities
-					writeBool(true),
-					writeVarInt(0), writeVarInt(0),
-					[]byte{}, []byte{}, // light arrays
-				)
-			}
-		}
+	ln, err := net.Listen("tcp", cfg.Server.Address)
+	if err != nil {
+		log.Fatal(err)
 	}
-}
-func writePacket(w io.Writer, id int, payload ...[]byte) {
-	p := writeVarInt(id)
-	for _, part := range payload {
-		p = append(p, part...)
+	if cfg.Proxy.ProxyProtocol {
+		ln = proxy.NewListener(ln)
 	}
-	final := writeVarInt(len(p))
-	final = append(final, p...)
-	w.Write(final)
-}
-
-func readVarInt(r *bufio.Reader) (int, error) {
-	var num int
-	var shift uint
-	for {
-		b, err := r.ReadByte()
+	if cfg.Query.Enabled {
+		pc, err := net.ListenPacket("udp", cfg.Query.Address)
 		if err != nil {
-			return 0, err
+			log.Fatal(err)
 		}
-		num |= int(b&0x7F) << shift
-		if (b & 0x80) == 0 {
-			break
-		}
-		shift += 7
+		log.Printf("Query listening on %s", cfg.Query.Address)
+		go srv.ServeQuery(pc)
 	}
-	return num, nil
-}
-
-func writeVarInt(n int) []byte {
-	var out []byte
-	for {
-		b := byte(n & 0x7F)
-		n >>= 7
-		if n != 0 {
-			b |= 0x80
+	if cfg.RCON.Enabled {
+		if cfg.RCON.Password == "" {
+			log.Fatal("rcon.password is not set; set it or disable rcon")
 		}
-		out = append(out, b)
-		if n == 0 {
-			break
+		rl, err := net.Listen("tcp", cfg.RCON.Address)
+		if err != nil {
+			log.Fatal(err)
 		}
+		log.Printf("RCON listening on %s", cfg.RCON.Address)
+		go srv.ServeRCON(rl)
 	}
-	return out
-}
-
-func writeString(s string) []byte {
-	out := writeVarInt(len(s))
-	out = append(out, []byte(s)...)
-	return out
-}
-
-func readString(r *bufio.Reader) (string, error) {
-	length, err := readVarInt(r)
-	if err != nil {
-		return "", err
-	}
-	buf := make([]byte, length)
-	if _, err := io.ReadFull(r, buf); err != nil {
-		return "", err
-	}
-	return string(buf), nil
-}
-
-func writeInt(i int) []byte {
-	var buf [4]byte
-	binary.BigEndian.PutUint32(buf[:], uint32(i))
-	return buf[:]
-}
-
-func writeLong(i int64) []byte {
-	var buf [8]byte
-	binary.BigEndian.PutUint64(buf[:], uint64(i))
-	return buf[:]
-}
-
-func writeByte(b byte) []byte { return []byte{b} }
-func writeBool(b bool) []byte {
-	if b {
-		return []byte{1}
-	}
-	return []byte{0}
-}
-
-func bytesReader(b []byte) *bufio.Reader {
-	return bufio.NewReaderSize(io.NopCloser(io.LimitReader(io.MultiReader(bytes.NewReader(b)), int64(len(b)))), len(b))
+	log.Printf("Listening on %s (Minecraft %s)", cfg.Server.Address, protocol.SupportedRange())
+	log.Fatal(srv.Serve(ln))
 }
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/block"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/world"
+)
+
+// Movement errors, in vanilla's wording.
+const (
+	invalidMovementMessage = "Invalid move player packet received"
+	flyingMessage          = "Flying is not enabled on this server"
+)
+
+// Vanilla's limits on coordinates a client may send.
+const (
+	maxHorizontalCoordinate = 3.0e7
+	maxVerticalCoordinate   = 2.0e7
+)
+
+// floatingDrop is how far a player must fall per packet not to count as
+// floating, as in vanilla.
+const floatingDrop = -0.03125
+
+// The size of a standing player, in blocks.
+const (
+	playerWidth  = 0.6
+	playerHeight = 1.8
+)
+
+// collisionEpsilon keeps boxes that only touch a block from counting as
+// inside it.
+const collisionEpsilon = 1e-7
+
+// Location is where a player stands and which way it faces. Y is the height
+// of the feet; angles are in degrees.
+type Location struct {
+	X, Y, Z    float64
+	Yaw, Pitch float32
+}
+
+// distanceSq returns the squared distance between the positions of l and o.
+func (l Location) distanceSq(o Location) float64 {
+	dx, dy, dz := o.X-l.X, o.Y-l.Y, o.Z-l.Z
+	return dx*dx + dy*dy + dz*dz
+}
+
+// spawnLocation is where players join.
+var spawnLocation = Location{Y: 64}
+
+// Collider moves a player from one location towards another the way the
+// world allows and returns where it ends up. It runs on the tick goroutine.
+type Collider func(p *Player, from, to Location) Location
+
+// SetCollider makes the movement checks ask c where moves end, so players
+// walking through blocks are sent back. It replaces the collisions with
+// the full blocks of the world, which are used when c is nil.
+func (s *Server) SetCollider(c Collider) {
+	s.mu.Lock()
+	s.collide = c
+	s.mu.Unlock()
+}
+
+// collider returns the collider set with SetCollider, or the world's. In
+// an empty world every move succeeds.
+func (s *Server) collider() Collider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.collide == nil && s.chunks != nil {
+		return s.collideWithWorld
+	}
+	return s.collide
+}
+
+// blockAt returns the block state at x, y, z, and false if its chunk is not
+// loaded. Without a world every block is air. Like the chunks, it belongs
+// to the tick goroutine.
+func (s *Server) blockAt(x, y, z int) (uint32, bool) {
+	if s.chunks == nil {
+		return block.Air, true
+	}
+	c := s.chunks.Chunk(world.ChunkPosOf(x, z))
+	if c == nil {
+		return block.Air, false
+	}
+	return c.Block(x, y, z), true
+}
+
+// box is an axis-aligned box in blocks, indexed by axis X, Y, Z.
+type box struct {
+	min, max [3]float64
+}
+
+// playerBox returns the box of a player standing at loc.
+func playerBox(loc Location) box {
+	const half = playerWidth / 2
+	return box{
+		min: [3]float64{loc.X - half, loc.Y, loc.Z - half},
+		max: [3]float64{loc.X + half, loc.Y + playerHeight, loc.Z + half},
+	}
+}
+
+// blocks returns the range of blocks b overlaps along axis.
+func (b box) blocks(axis int) (lo, hi int) {
+	return int(math.Floor(b.min[axis] + collisionEpsilon)), int(math.Ceil(b.max[axis]-collisionEpsilon)) - 1
+}
+
+// collideWithWorld is the collider of the world: the player's box moves
+// along Y, then along the longer and the shorter horizontal axis, like in
+// vanilla, and stops at the first full block in its way. Unloaded chunks
+// stop nothing.
+func (s *Server) collideWithWorld(p *Player, from, to Location) Location {
+	b := playerBox(from)
+	delta := [3]float64{to.X - from.X, to.Y - from.Y, to.Z - from.Z}
+	order := [3]int{1, 0, 2}
+	if math.Abs(delta[2]) > math.Abs(delta[0]) {
+		order = [3]int{1, 2, 0}
+	}
+	for _, axis := range order {
+		d := s.sweep(b, axis, delta[axis])
+		b.min[axis] += d
+		b.max[axis] += d
+		delta[axis] = d
+	}
+	to.X, to.Y, to.Z = from.X+delta[0], from.Y+delta[1], from.Z+delta[2]
+	return to
+}
+
+// sweep returns how far b can move along axis towards d before it hits a
+// full block. Blocks b is already in do not hold it back.
+func (s *Server) sweep(b box, axis int, d float64) float64 {
+	if d == 0 {
+		return 0
+	}
+	u, v := (axis+1)%3, (axis+2)%3
+	uLo, uHi := b.blocks(u)
+	vLo, vHi := b.blocks(v)
+	blocked := func(i int) bool {
+		var pos [3]int
+		pos[axis] = i
+		for pos[u] = uLo; pos[u] <= uHi; pos[u]++ {
+			for pos[v] = vLo; pos[v] <= vHi; pos[v]++ {
+				if state, _ := s.blockAt(pos[0], pos[1], pos[2]); block.FullCube(state) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if d > 0 {
+		end := int(math.Ceil(b.max[axis]+d)) - 1
+		for i := int(math.Ceil(b.max[axis] - collisionEpsilon)); i <= end; i++ {
+			if blocked(i) {
+				return max(0, float64(i)-b.max[axis])
+			}
+		}
+		return d
+	}
+	end := int(math.Floor(b.min[axis] + d))
+	for i := int(math.Floor(b.min[axis]+collisionEpsilon)) - 1; i >= end; i-- {
+		if blocked(i) {
+			return min(0, float64(i+1)-b.min[axis])
+		}
+	}
+	return d
+}
+
+// blocksAround reports whether there is anything but air next to or just
+// below a player at loc, such as water, a ladder or a cobweb, which lets it
+// hang in the air without flying. Unloaded chunks count as blocks.
+func (s *Server) blocksAround(loc Location) bool {
+	b := playerBox(loc)
+	for axis := range 3 {
+		b.min[axis] -= 0.0625
+		b.max[axis] += 0.0625
+	}
+	b.min[1] -= 0.55
+	xLo, xHi := b.blocks(0)
+	yLo, yHi := b.blocks(1)
+	zLo, zHi := b.blocks(2)
+	for x := xLo; x <= xHi; x++ {
+		for y := yLo; y <= yHi; y++ {
+			for z := zLo; z <= zHi; z++ {
+				if state, loaded := s.blockAt(x, y, z); !loaded || !block.IsAir(state) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// movementState is what the server believes about a player's position.
+type movementState struct {
+	mu       sync.Mutex
+	loc      Location
+	onGround bool
+	// allowFlight lets the player fly, flying is whether it says it does.
+	allowFlight bool
+	flying      bool
+	// teleportID is the ID of the last teleport sent. Until the client
+	// confirms it, awaiting holds its target and moves are ignored.
+	teleportID int32
+	awaiting   *Location
+	// floatingSince is when the player started hanging in the air, or zero.
+	floatingSince time.Time
+}
+
+// playerMove is a movement packet; a nil field was not sent.
+type playerMove struct {
+	x, y, z    *float64
+	yaw, pitch *float32
+	onGround   bool
+}
+
+// valid reports whether the packet holds finite coordinates within the
+// world's bounds.
+func (m *playerMove) valid() bool {
+	if m.x != nil {
+		for _, c := range []float64{*m.x, *m.y, *m.z} {
+			if math.IsNaN(c) || math.IsInf(c, 0) {
+				return false
+			}
+		}
+		if math.Abs(*m.x) > maxHorizontalCoordinate || math.Abs(*m.z) > maxHorizontalCoordinate ||
+			math.Abs(*m.y) > maxVerticalCoordinate {
+			return false
+		}
+	}
+	if m.yaw != nil {
+		for _, a := range []float32{*m.yaw, *m.pitch} {
+			if math.IsNaN(float64(a)) || math.IsInf(float64(a), 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Location returns where the player is.
+func (p *Player) Location() Location {
+	p.move.mu.Lock()
+	defer p.move.mu.Unlock()
+	return p.move.loc
+}
+
+// OnGround reports whether the client says the player stands on a block.
+func (p *Player) OnGround() bool {
+	p.move.mu.Lock()
+	defer p.move.mu.Unlock()
+	return p.move.onGround
+}
+
+// Flying reports whether the player is flying.
+func (p *Player) Flying() bool {
+	p.move.mu.Lock()
+	defer p.move.mu.Unlock()
+	return p.move.flying
+}
+
+// AllowFlight reports whether the player may fly.
+func (p *Player) AllowFlight() bool {
+	p.move.mu.Lock()
+	defer p.move.mu.Unlock()
+	return p.move.allowFlight
+}
+
+// SetAllowFlight lets the player fly, or takes that away and brings it down.
+func (p *Player) SetAllowFlight(allow bool) error {
+	p.move.mu.Lock()
+	defer p.move.mu.Unlock()
+	p.move.allowFlight = allow
+	p.move.flying = p.move.flying && allow
+	return p.sendAbilities()
+}
+
+// sendAbilities tells the client whether it may fly. p.move.mu must be held.
+func (p *Player) sendAbilities() error {
+	var flags byte
+	if p.move.allowFlight {
+		flags |= protocol.AbilityAllowFlying
+	}
+	if p.move.flying {
+		flags |= protocol.AbilityFlying
+	}
+	return p.conn.WritePacket(protocol.ClientboundPlayPlayerAbilities,
+		protocol.WriteByte(flags),
+		protocol.WriteFloat(0.05), // flying speed
+		protocol.WriteFloat(0.1),  // field of view modifier
+	)
+}
+
+// Teleport moves the player to loc. Moves the client sends before it
+// confirms the teleport are ignored.
+func (p *Player) Teleport(loc Location) error {
+	p.move.mu.Lock()
+	defer p.move.mu.Unlock()
+	return p.teleport(loc)
+}
+
+// teleport sends a Synchronize Player Position. Like in vanilla, the player
+// is at loc from then on, though its moves only count once the client has
+// confirmed. p.move.mu must be held.
+func (p *Player) teleport(loc Location) error {
+	p.move.teleportID++
+	if p.move.teleportID < 1 {
+		p.move.teleportID = 1
+	}
+	p.move.loc = loc
+	p.move.awaiting = &loc
+	p.move.floatingSince = time.Time{}
+	return p.conn.WritePacket(protocol.ClientboundPlaySynchronizePlayerPosition,
+		protocol.WriteDouble(loc.X), protocol.WriteDouble(loc.Y), protocol.WriteDouble(loc.Z),
+		protocol.WriteFloat(loc.Yaw), protocol.WriteFloat(loc.Pitch),
+		protocol.WriteByte(0), // every field is absolute
+		protocol.WriteVarInt(int(p.move.teleportID)),
+	)
+}
+
+// confirmTeleport handles the client's confirmation of a teleport. It
+// reports false if the client confirms one it was never sent.
+func (p *Player) confirmTeleport(id int32) bool {
+	p.move.mu.Lock()
+	defer p.move.mu.Unlock()
+	if id != p.move.teleportID {
+		// An older teleport, superseded by the one still pending.
+		return true
+	}
+	if p.move.awaiting == nil {
+		return false
+	}
+	p.move.loc = *p.move.awaiting
+	p.move.awaiting = nil
+	return true
+}
+
+// applyMove checks a move against cfg and applies it. A move that fails a
+// check sends the player back to where it was; the returned reason, if not
+// empty, is why the player must be kicked. A player is only floating when
+// blocksAround, if not nil, finds nothing around it.
+func (p *Player) applyMove(cfg *MovementConfig, collide Collider, blocksAround func(Location) bool, m *playerMove, now time.Time) string {
+	if !m.valid() {
+		return invalidMovementMessage
+	}
+	p.move.mu.Lock()
+	defer p.move.mu.Unlock()
+	st := &p.move
+	if st.awaiting != nil {
+		return ""
+	}
+
+	from, to := st.loc, st.loc
+	if m.x != nil {
+		to.X, to.Y, to.Z = *m.x, *m.y, *m.z
+	}
+	if m.yaw != nil {
+		to.Yaw = wrapDegrees(*m.yaw)
+		to.Pitch = max(-90, min(90, *m.pitch))
+	}
+
+	if m.x != nil {
+		if cfg.MaxMoveDistance > 0 && from.distanceSq(to) > cfg.MaxMoveDistance*cfg.MaxMoveDistance {
+			log.Printf("%s moved too quickly! %.2f,%.2f,%.2f", p.Name, to.X-from.X, to.Y-from.Y, to.Z-from.Z)
+			p.teleport(from)
+			return ""
+		}
+		if collide != nil && cfg.WrongMoveDistance > 0 {
+			got := collide(p, from, to)
+			if got.distanceSq(to) > cfg.WrongMoveDistance*cfg.WrongMoveDistance {
+				log.Printf("%s moved wrongly!", p.Name)
+				p.teleport(from)
+				return ""
+			}
+		}
+	}
+
+	if !cfg.AllowFlight && !st.allowFlight && cfg.FlyingKickTime > 0 {
+		floating := !m.onGround && to.Y-from.Y >= floatingDrop
+		if floating && blocksAround != nil && blocksAround(to) {
+			floating = false
+		}
+		if floating {
+			if st.floatingSince.IsZero() {
+				st.floatingSince = now
+			} else if now.Sub(st.floatingSince) > cfg.FlyingKickTime {
+				log.Printf("%s was kicked for floating too long!", p.Name)
+				return flyingMessage
+			}
+		} else {
+			st.floatingSince = time.Time{}
+		}
+	}
+
+	st.loc = to
+	st.onGround = m.onGround
+	return ""
+}
+
+// wrapDegrees wraps an angle to [-180, 180).
+func wrapDegrees(a float32) float32 {
+	a = float32(math.Mod(float64(a), 360))
+	if a >= 180 {
+		a -= 360
+	} else if a < -180 {
+		a += 360
+	}
+	return a
+}
+
+// handleConfirmTeleportation handles the client's confirmation of a
+// Synchronize Player Position.
+func (s *session) handleConfirmTeleportation(raw *protocol.RawPacket) error {
+	var confirm protocol.ConfirmTeleportation
+	if err := raw.Decode(&confirm); err != nil {
+		return err
+	}
+	// Confirms and moves are handled on the tick goroutine, in the order
+	// they came, where the world's blocks can be looked at.
+	s.srv.loop.Submit(func() {
+		if !s.player.confirmTeleport(confirm.TeleportID) {
+			s.conn.Disconnect(invalidMovementMessage)
+		}
+	})
+	return nil
+}
+
+// handleMovement handles the four packets that move and turn the player.
+func (s *session) handleMovement(raw *protocol.RawPacket) error {
+	var m playerMove
+	switch raw.Packet {
+	case protocol.ServerboundPlaySetPlayerPosition:
+		var pkt protocol.SetPlayerPosition
+		if err := raw.Decode(&pkt); err != nil {
+			return err
+		}
+		m = playerMove{x: &pkt.X, y: &pkt.Y, z: &pkt.Z, onGround: pkt.OnGround}
+	case protocol.ServerboundPlaySetPlayerPositionAndRotation:
+		var pkt protocol.SetPlayerPositionAndRotation
+		if err := raw.Decode(&pkt); err != nil {
+			return err
+		}
+		m = playerMove{x: &pkt.X, y: &pkt.Y, z: &pkt.Z, yaw: &pkt.Yaw, pitch: &pkt.Pitch, onGround: pkt.OnGround}
+	case protocol.ServerboundPlaySetPlayerRotation:
+		var pkt protocol.SetPlayerRotation
+		if err := raw.Decode(&pkt); err != nil {
+			return err
+		}
+		m = playerMove{yaw: &pkt.Yaw, pitch: &pkt.Pitch, onGround: pkt.OnGround}
+	case protocol.ServerboundPlaySetPlayerOnGround:
+		var pkt protocol.SetPlayerOnGround
+		if err := raw.Decode(&pkt); err != nil {
+			return err
+		}
+		m = playerMove{onGround: pkt.OnGround}
+	}
+	s.srv.loop.Submit(func() {
+		p := s.player
+		if reason := p.applyMove(&s.srv.cfg.Movement, s.srv.collider(), s.srv.blocksAround, &m, time.Now()); reason != "" {
+			s.conn.Disconnect(reason)
+			return
+		}
+		// A failed write means the client is gone; its session cleans up.
+		s.srv.moveView(p, p.Location())
+	})
+	return nil
+}
+
+// handlePlayerAbilities handles the player starting or stopping to fly. A
+// player that may not fly stays on the ground as far as the server is
+// concerned; the flying check catches it if it keeps floating.
+func (s *session) handlePlayerAbilities(raw *protocol.RawPacket) error {
+	var abilities protocol.PlayerAbilities
+	if err := raw.Decode(&abilities); err != nil {
+		return err
+	}
+	p := s.player
+	s.srv.loop.Submit(func() {
+		p.move.mu.Lock()
+		p.move.flying = abilities.Flying() && p.move.allowFlight
+		p.move.mu.Unlock()
+	})
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"log"
+
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// handlePlay handles packets in the play state.
+func (s *session) handlePlay(raw *protocol.RawPacket) error {
+	switch raw.Packet {
+	case protocol.ServerboundPlayPluginMessage:
+		return s.handlePluginMessage(raw)
+	case protocol.ServerboundPlayCookieResponse:
+		return s.handleCookieResponse(raw)
+	case protocol.ServerboundPlayChatMessage:
+		return s.handleChatMessage(raw)
+	case protocol.ServerboundPlayChatCommand, protocol.ServerboundPlaySignedChatCommand:
+		return s.handleChatCommand(raw)
+	case protocol.ServerboundPlayPlayerSession:
+		return s.handlePlayerSession(raw)
+	case protocol.ServerboundPlayAcknowledgeMessage:
+		return s.handleAcknowledgeMessage(raw)
+	case protocol.ServerboundPlayConfirmTeleportation:
+		return s.handleConfirmTeleportation(raw)
+	case protocol.ServerboundPlaySetPlayerPosition, protocol.ServerboundPlaySetPlayerPositionAndRotation,
+		protocol.ServerboundPlaySetPlayerRotation, protocol.ServerboundPlaySetPlayerOnGround:
+		return s.handleMovement(raw)
+	case protocol.ServerboundPlayPlayerAbilities:
+		return s.handlePlayerAbilities(raw)
+	}
+	return nil
+}
+
+// joinGame sends the packets that move a freshly configured client into the
+// world and adds it to the player list.
+func (s *session) joinGame() error {
+	conn := s.conn
+	cfg := s.srv.cfg
+	v := conn.Version()
+	regs, err := v.Registries()
+	if err != nil {
+		return err
+	}
+	dimensionTypes, _ := regs.Get("minecraft:dimension_type")
+	overworld, _ := dimensionTypes.Index("minecraft:overworld")
+
+	// Send Join Game
+	login := [][]byte{
+		protocol.WriteInt(int(s.player.EntityID())),                          // Entity ID
+		protocol.WriteBool(false),                                            // Hardcore
+		protocol.WriteVarInt(1), protocol.WriteString("minecraft:overworld"), // World count + names
+		protocol.WriteVarInt(cfg.Server.MaxPlayers), protocol.WriteVarInt(cfg.World.ViewDistance), protocol.WriteVarInt(cfg.World.ViewDistance), // max players, view/sim dist
+		protocol.WriteBool(false), protocol.WriteBool(true), protocol.WriteBool(false), // reduced debug, respawn screen, limited crafting
+	}
+	if v.Has(protocol.FeatureDimensionTypeID) {
+		login = append(login, protocol.WriteVarInt(overworld))
+	} else {
+		login = append(login, protocol.WriteString("minecraft:overworld"))
+	}
+	login = append(login,
+		protocol.WriteString("minecraft:overworld"),     // World name
+		protocol.WriteLong(0),                           // Hashed seed
+		protocol.WriteByte(0), protocol.WriteByte(0xFF), // Game mode + previous
+		protocol.WriteBool(false), protocol.WriteBool(false), // debug, flat
+		protocol.WriteBool(false), // death location
+		protocol.WriteVarInt(0),   // portal cooldown
+	)
+	if v.Has(protocol.FeatureDimensionTypeID) {
+		login = append(login, protocol.WriteBool(cfg.Server.EnforceSecureChat))
+	}
+	if err := conn.WritePacket(protocol.ClientboundPlayLogin, login...); err != nil {
+		return err
+	}
+
+	// Start waiting for level chunks
+	if err := conn.WritePacket(protocol.ClientboundPlayGameEvent, protocol.WriteByte(13), protocol.WriteFloat(0)); err != nil {
+		return err
+	}
+	// Send the chunks around the player, as high as the dimension.
+	minY, height, err := dimensionHeight(regs, "minecraft:overworld")
+	if err != nil {
+		return err
+	}
+	ids, err := s.srv.registry.networkIDs(v)
+	if err != nil {
+		return err
+	}
+	spawn := s.srv.spawn()
+	s.srv.loop.Call(func() { err = s.srv.openView(s.player, ids, spawn, minY, height) })
+	if err != nil {
+		return err
+	}
+
+	// Synchronize Player Position; the player stays put until it confirms.
+	if err := s.player.Teleport(spawn); err != nil {
+		return err
+	}
+
+	if err := conn.WritePacket(protocol.ClientboundPlayCommands, s.srv.commands.commandTree()...); err != nil {
+		return err
+	}
+
+	s.endLogin()
+	if !s.srv.addPlayer(s.player) {
+		return protocol.ErrConnClosed
+	}
+	s.srv.addToPlayerList(s.player)
+	// Other players are told who the player is before they see it.
+	p := s.player
+	s.srv.loop.Submit(func() { s.srv.entities.addPlayer(p) })
+	log.Printf("%s joined the game", s.player.Name)
+	s.srv.firePlayerEvent("playerJoin", s.player)
+	return nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/text"
+	"github.com/Advik-B/Golem/world"
+)
+
+// maxListenedChannels bounds how many channels a client may register, so a
+// client cannot grow the set without limit.
+const maxListenedChannels = 128
+
+// Player is a client that has finished logging in.
+type Player struct {
+	Name string
+	UUID protocol.UUID
+	// Properties holds the profile properties, such as the skin, forwarded
+	// by a proxy.
+	Properties []protocol.Property
+	// Transferred is set when another server sent the player here with
+	// Transfer.
+	Transferred bool
+
+	conn *protocol.Conn
+
+	mu          sync.RWMutex
+	clientBrand string
+	listening   map[string]bool
+	// cookieRequests holds the callbacks waiting for each cookie, oldest
+	// first.
+	cookieRequests map[string][]CookieHandler
+
+	chat chatState
+	move movementState
+	view chunkView
+	// entity is the player as other players see it. Its ID is fixed at
+	// login; the rest belongs to the tick goroutine.
+	entity *world.Entity
+	// left is closed once the player's connection has been cleaned up.
+	left chan struct{}
+}
+
+// ErrTransferUnsupported is returned by the transfer and cookie methods for
+// clients older than 1.20.5.
+var ErrTransferUnsupported = errors.New("the client does not support transfers and cookies")
+
+// CookieHandler receives the answer to a cookie request. ok is false if the
+// client has no cookie with that key.
+type CookieHandler func(payload []byte, ok bool)
+
+// newPlayerEntity returns the entity of a player logging in.
+func newPlayerEntity(id int32, uuid protocol.UUID) *world.Entity {
+	e := world.NewEntity(PlayerEntityType, world.Vec3{})
+	e.ID, e.UUID = id, uuid
+	return e
+}
+
+// EntityID returns the ID of the player's entity, which its client is told
+// in Login.
+func (p *Player) EntityID() int32 { return p.entity.ID }
+
+// syncEntity copies where the player is into its entity.
+func (p *Player) syncEntity() {
+	loc := p.Location()
+	e := p.entity
+	e.Pos = world.Vec3{X: loc.X, Y: loc.Y, Z: loc.Z}
+	e.Yaw, e.Pitch, e.HeadYaw = loc.Yaw, loc.Pitch, loc.Yaw
+	e.OnGround = p.OnGround()
+}
+
+// Conn returns the player's connection.
+func (p *Player) Conn() *protocol.Conn { return p.conn }
+
+// Disconnect kicks the player with the given reason.
+func (p *Player) Disconnect(reason string) { p.conn.Disconnect(reason) }
+
+// DisplayName returns the player's name, for CommandSender.
+func (p *Player) DisplayName() string { return p.Name }
+
+// SendMessage shows msg in the player's chat as a system message.
+func (p *Player) SendMessage(msg string) { p.SendText(text.Plain(msg)) }
+
+// SendText shows a component in the player's chat as a system message.
+// Messages sent before the player is in play are dropped.
+func (p *Player) SendText(c text.Component) {
+	p.sendSystemChat(c, false)
+}
+
+// SendActionBar shows a component above the player's hotbar.
+func (p *Player) SendActionBar(c text.Component) {
+	p.sendSystemChat(c, true)
+}
+
+func (p *Player) sendSystemChat(c text.Component, overlay bool) {
+	if p.conn.State() != protocol.Play {
+		return
+	}
+	p.conn.WritePacket(protocol.ClientboundPlaySystemChatMessage, protocol.WriteNBT(c.ToNBT()), protocol.WriteBool(overlay))
+}
+
+// ClientBrand returns the brand the client reported, such as "vanilla" or
+// "fabric", or "" if it has not sent one.
+func (p *Player) ClientBrand() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.clientBrand
+}
+
+func (p *Player) setClientBrand(brand string) {
+	p.mu.Lock()
+	p.clientBrand = brand
+	p.mu.Unlock()
+}
+
+// Listens reports whether the client registered channel with
+// minecraft:register.
+func (p *Player) Listens(channel string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.listening[channel]
+}
+
+// Channels returns the channels the client registered, sorted.
+func (p *Player) Channels() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	channels := make([]string, 0, len(p.listening))
+	for name := range p.listening {
+		channels = append(channels, name)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+func (p *Player) listen(channels []string, on bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.listening == nil {
+		p.listening = make(map[string]bool)
+	}
+	for _, name := range channels {
+		if !on {
+			delete(p.listening, name)
+		} else if len(p.listening) < maxListenedChannels {
+			p.listening[name] = true
+		}
+	}
+}
+
+// statePacket picks the configuration or the play variant of a packet for
+// the state the player is in.
+func (p *Player) statePacket(config, play protocol.Packet) protocol.Packet {
+	if p.conn.State() == protocol.Configuration {
+		return config
+	}
+	return play
+}
+
+// SendPluginMessage sends data on channel, in whichever of the configuration
+// and play states the player is in.
+func (p *Player) SendPluginMessage(channel string, data []byte) error {
+	packet := p.statePacket(protocol.ClientboundConfigPluginMessage, protocol.ClientboundPlayPluginMessage)
+	return p.conn.WritePacket(packet, protocol.WriteString(channel), data)
+}
+
+// Transfer tells the client to disconnect and connect to another server. The
+// client keeps its cookies, so the other server can read what this one
+// stored.
+func (p *Player) Transfer(host string, port int) error {
+	if !p.conn.Version().Has(protocol.FeatureTransfer) {
+		return ErrTransferUnsupported
+	}
+	if host == "" || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid transfer target %s:%d", host, port)
+	}
+	packet := p.statePacket(protocol.ClientboundConfigTransfer, protocol.ClientboundPlayTransfer)
+	return p.conn.WritePacket(packet, protocol.WriteString(host), protocol.WriteVarInt(port))
+}
+
+// StoreCookie asks the client to keep payload under key. Cookies last until
+// the client quits the game and survive transfers.
+func (p *Player) StoreCookie(key string, payload []byte) error {
+	if !p.conn.Version().Has(protocol.FeatureTransfer) {
+		return ErrTransferUnsupported
+	}
+	if !channelPattern.MatchString(key) {
+		return fmt.Errorf("invalid cookie key %q", key)
+	}
+	if len(payload) > protocol.MaxCookieLength {
+		return fmt.Errorf("cookie %s is too large: %d bytes", key, len(payload))
+	}
+	packet := p.statePacket(protocol.ClientboundConfigStoreCookie, protocol.ClientboundPlayStoreCookie)
+	return p.conn.WritePacket(packet, protocol.WriteString(key), protocol.WriteByteArray(payload))
+}
+
+// RequestCookie asks the client for the cookie stored under key. h is called
+// from the player's connection goroutine when the client answers, and not at
+// all if it disconnects first.
+func (p *Player) RequestCookie(key string, h CookieHandler) error {
+	if !p.conn.Version().Has(protocol.FeatureTransfer) {
+		return ErrTransferUnsupported
+	}
+	if !channelPattern.MatchString(key) {
+		return fmt.Errorf("invalid cookie key %q", key)
+	}
+	p.mu.Lock()
+	if p.cookieRequests == nil {
+		p.cookieRequests = make(map[string][]CookieHandler)
+	}
+	p.cookieRequests[key] = append(p.cookieRequests[key], h)
+	p.mu.Unlock()
+	packet := p.statePacket(protocol.ClientboundConfigCookieRequest, protocol.ClientboundPlayCookieRequest)
+	return p.conn.WritePacket(packet, protocol.WriteString(key))
+}
+
+// cookieResponse hands a Cookie Response to the oldest request for its key.
+// It reports false if there was no such request.
+func (p *Player) cookieResponse(resp *protocol.CookieResponse) bool {
+	p.mu.Lock()
+	waiting := p.cookieRequests[resp.Key]
+	if len(waiting) == 0 {
+		p.mu.Unlock()
+		return false
+	}
+	h := waiting[0]
+	if len(waiting) == 1 {
+		delete(p.cookieRequests, resp.Key)
+	} else {
+		p.cookieRequests[resp.Key] = waiting[1:]
+	}
+	p.mu.Unlock()
+	h(resp.Payload, resp.Present)
+	return true
+}
+
+// handleCookieResponse handles a serverbound Cookie Response. Vanilla kicks
+// clients that answer a request it never made, and so do we.
+func (s *session) handleCookieResponse(raw *protocol.RawPacket) error {
+	var resp protocol.CookieResponse
+	if err := raw.Decode(&resp); err != nil {
+		return err
+	}
+	if s.player == nil || !s.player.cookieResponse(&resp) {
+		return fmt.Errorf("unexpected cookie response for %s", resp.Key)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"log"
+
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// Player Info Update actions. Clients drop chat from players missing from
+// their player list, so every player is listed with its chat session.
+const (
+	infoAddPlayer byte = 1 << iota
+	infoInitializeChat
+	infoGameMode
+	infoListed
+	infoLatency
+	infoDisplayName
+)
+
+// infoJoin are the actions that list a player that just joined.
+const infoJoin = infoAddPlayer | infoInitializeChat | infoGameMode | infoListed | infoLatency
+
+// playerInfoUpdate encodes a Player Info Update with actions for players.
+func playerInfoUpdate(actions byte, players []*Player) [][]byte {
+	payload := [][]byte{protocol.WriteByte(actions), protocol.WriteVarInt(len(players))}
+	for _, p := range players {
+		payload = append(payload, protocol.WriteUUID(p.UUID))
+		if actions&infoAddPlayer != 0 {
+			payload = append(payload, protocol.WriteString(p.Name), protocol.WriteProperties(p.Properties))
+		}
+		if actions&infoInitializeChat != 0 {
+			p.chat.mu.Lock()
+			session := p.chat.session
+			p.chat.mu.Unlock()
+			payload = append(payload, protocol.WriteBool(session != nil))
+			if session != nil {
+				payload = append(payload,
+					protocol.WriteUUID(session.SessionID),
+					protocol.WriteLong(session.ExpiresAt),
+					protocol.WriteByteArray(session.PublicKey),
+					protocol.WriteByteArray(session.KeySignature),
+				)
+			}
+		}
+		if actions&infoGameMode != 0 {
+			payload = append(payload, protocol.WriteVarInt(0)) // survival
+		}
+		if actions&infoListed != 0 {
+			payload = append(payload, protocol.WriteBool(true))
+		}
+		if actions&infoLatency != 0 {
+			payload = append(payload, protocol.WriteVarInt(0))
+		}
+		if actions&infoDisplayName != 0 {
+			payload = append(payload, protocol.WriteBool(false))
+		}
+	}
+	return payload
+}
+
+// addToPlayerList lists everyone online for p, and p for everyone else.
+func (s *Server) addToPlayerList(p *Player) {
+	players := s.Players()
+	if err := p.conn.WritePacket(protocol.ClientboundPlayPlayerInfoUpdate, playerInfoUpdate(infoJoin, players)...); err != nil {
+		log.Printf("Could not send the player list to %s: %v", p.Name, err)
+	}
+	payload := playerInfoUpdate(infoJoin, []*Player{p})
+	for _, other := range players {
+		if other != p {
+			other.conn.WritePacket(protocol.ClientboundPlayPlayerInfoUpdate, payload...)
+		}
+	}
+}
+
+// broadcastPlayerInfo sends a Player Info Update for p to every player.
+func (s *Server) broadcastPlayerInfo(actions byte, p *Player) {
+	payload := playerInfoUpdate(actions, []*Player{p})
+	for _, other := range s.Players() {
+		other.conn.WritePacket(protocol.ClientboundPlayPlayerInfoUpdate, payload...)
+	}
+}
+
+// removeFromPlayerList unlists a player that left for everyone else.
+func (s *Server) removeFromPlayerList(p *Player) {
+	for _, other := range s.Players() {
+		other.conn.WritePacket(protocol.ClientboundPlayPlayerInfoRemove, protocol.WriteVarInt(1), protocol.WriteUUID(p.UUID))
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/query"
+)
+
+// ServeQuery answers GameSpy4 queries read from pc until it is closed.
+func (s *Server) ServeQuery(pc net.PacketConn) error {
+	return query.NewServer(s.queryStats).Serve(pc)
+}
+
+// queryStats reports the same status as the server list ping, which plugins
+// can change or cancel through the serverListPing event.
+func (s *Server) queryStats(addr net.Addr) *query.Stats {
+	status := s.status.Status(addr, "", protocol.Latest().Protocol)
+	if status == nil {
+		return nil
+	}
+	stats := &query.Stats{
+		MOTD:       status.LegacyMOTD(),
+		GameType:   "SMP",
+		Map:        s.levelName(),
+		Version:    status.VersionName,
+		Plugins:    s.pluginList(),
+		NumPlayers: status.OnlinePlayers,
+		MaxPlayers: status.MaxPlayers,
+		HostIP:     "0.0.0.0",
+	}
+	if status.HidePlayerCount {
+		stats.NumPlayers = 0
+	}
+	if host, port, err := net.SplitHostPort(s.cfg.Server.Address); err == nil {
+		if host != "" {
+			stats.HostIP = host
+		}
+		stats.HostPort, _ = strconv.Atoi(port)
+	}
+	if !s.cfg.Status.HideOnlinePlayers && !status.HidePlayerCount {
+		for _, p := range s.Players() {
+			stats.Players = append(stats.Players, p.Name)
+		}
+	}
+	return stats
+}
+
+// pluginList names the server and its plugins the way Bukkit does:
+// "Golem: first 1.0; second 2.1".
+func (s *Server) pluginList() string {
+	if s.plugins == nil || len(s.plugins.Plugins()) == 0 {
+		return "Golem"
+	}
+	var names []string
+	for _, p := range s.plugins.Plugins() {
+		names = append(names, strings.TrimSpace(p.Manifest.Name+" "+p.Manifest.Version))
+	}
+	return "Golem: " + strings.Join(names, "; ")
+}
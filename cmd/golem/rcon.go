@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/Advik-B/Golem/rcon"
+	"github.com/Advik-B/Golem/text"
+)
+
+// ServeRCON runs the commands of RCON clients connecting to ln until it is
+// closed.
+func (s *Server) ServeRCON(ln net.Listener) error {
+	srv := &rcon.Server{
+		Password:        s.cfg.RCON.Password,
+		Exec:            s.rconExec,
+		MaxAuthFailures: s.cfg.RCON.MaxAuthFailures,
+		BanTime:         s.cfg.RCON.BanTime,
+		ReadTimeout:     s.cfg.Network.ReadTimeout,
+	}
+	return srv.Serve(ln)
+}
+
+// rconExec runs a command line through the same dispatcher as players and
+// returns what it sent back.
+func (s *Server) rconExec(addr net.Addr, line string) string {
+	sender := &rconSender{}
+	s.commands.Dispatch(sender, line)
+	return sender.String()
+}
+
+// rconSender is the CommandSender of an RCON command. It collects the
+// feedback as plain text, one line per message.
+type rconSender struct {
+	mu  sync.Mutex
+	out strings.Builder
+}
+
+// DisplayName is "Rcon", as in vanilla.
+func (r *rconSender) DisplayName() string { return "Rcon" }
+
+func (r *rconSender) SendMessage(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.out.WriteString(text.StripLegacy(msg, text.SectionSign))
+	r.out.WriteByte('\n')
+}
+
+func (r *rconSender) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.TrimSuffix(r.out.String(), "\n")
+}
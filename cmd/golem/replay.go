@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/capture"
+	js "github.com/Advik-B/Golem/javascript"
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// replayCommand implements "golem replay": it feeds the serverbound packets of
+// a capture to a fresh server and reports where the server's answers differ
+// from the ones captured. It returns the process exit code.
+func replayCommand(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	configPath := fs.String("config", "", "server configuration to replay against; the defaults if empty")
+	out := fs.String("o", "", "also write the replayed session to this capture file")
+	idsOnly := fs.Bool("ids", false, "compare only packet IDs, not payloads")
+	withPlugins := fs.Bool("plugins", false, "load the plugins from the configured plugin directory")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: golem replay [flags] capture.jsonl")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg := DefaultConfig()
+	if *configPath != "" {
+		var err error
+		if cfg, err = LoadConfig(*configPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	records, err := capture.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var plugins *js.PluginManager
+	if *withPlugins {
+		plugins = js.NewPluginManager()
+	}
+	replayed, err := Replay(cfg, plugins, records)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *out != "" {
+		if err := writeRecords(*out, replayed); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	diffs := CompareReplay(records, replayed, *idsOnly)
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	if len(diffs) > 0 {
+		fmt.Printf("%d differences\n", len(diffs))
+		return 1
+	}
+	fmt.Println("replay matches the capture")
+	return 0
+}
+
+// Replay sends the serverbound packets of records, in order, to a new server
+// with cfg over an in-memory connection, and returns the capture of that
+// connection. If plugins is not nil, the configured plugins are loaded into
+// it. Keep Alive and the limits are disabled during the replay, and the
+// captured Keep Alive responses are skipped, since their IDs depend on the
+// clock.
+func Replay(cfg *Config, plugins *js.PluginManager, records []capture.Record) ([]capture.Record, error) {
+	replayCfg := *cfg
+	replayCfg.Network.KeepAliveInterval = 24 * time.Hour
+	replayCfg.Debug.Capture = false
+	replayCfg.Limits = LimitsConfig{}
+	srv, err := NewServer(&replayCfg, plugins)
+	if err != nil {
+		return nil, err
+	}
+	if plugins != nil {
+		if err := plugins.LoadAll(replayCfg.Server.PluginDir); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	w := capture.NewWriter(&buf)
+	srv.captureHook = func(net.Conn) *capture.Writer { return w }
+
+	serverSide, client := net.Pipe()
+	conn := &replayConn{Conn: serverSide, idle: make(chan struct{}, 1)}
+	done := make(chan struct{})
+	go func() {
+		srv.handleConnection(conn)
+		close(done)
+	}()
+	go func() { _, _ = io.Copy(io.Discard, client) }()
+
+	// Send one packet at a time, each once the server has handled everything
+	// before it, so the replay does not depend on goroutine scheduling.
+	waitIdle := func() bool {
+		select {
+		case <-conn.idle:
+			return true
+		case <-done:
+			return false
+		}
+	}
+	for _, rec := range records {
+		if !rec.Serverbound() || isKeepAlive(rec) {
+			continue
+		}
+		payload, err := rec.Payload()
+		if err != nil {
+			client.Close()
+			<-done
+			return nil, fmt.Errorf("replay: record at %dms: %w", rec.Time, err)
+		}
+		pkt := append(protocol.WriteVarInt(int(rec.ID)), payload...)
+		frame := append(protocol.WriteVarInt(len(pkt)), pkt...)
+		if !waitIdle() {
+			break // the server closed the connection
+		}
+		conn.sent(len(frame))
+		if _, err := client.Write(frame); err != nil {
+			break
+		}
+	}
+	waitIdle()
+	client.Close()
+	<-done
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return capture.Read(&buf)
+}
+
+// replayConn is the server side of a replay. It signals idle whenever the
+// server asks for more input than the replay has sent, which means it has
+// handled every packet so far.
+type replayConn struct {
+	net.Conn
+	idle chan struct{}
+
+	mu      sync.Mutex
+	written int
+	read    int
+}
+
+func (c *replayConn) sent(n int) {
+	c.mu.Lock()
+	c.written += n
+	c.mu.Unlock()
+}
+
+func (c *replayConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	if c.read == c.written {
+		select {
+		case c.idle <- struct{}{}:
+		default:
+		}
+	}
+	c.mu.Unlock()
+	n, err := c.Conn.Read(b)
+	c.mu.Lock()
+	c.read += n
+	c.mu.Unlock()
+	return n, err
+}
+
+// CompareReplay lists the differences between the clientbound packets of a
+// capture and of its replay. Keep Alive is ignored.
+func CompareReplay(captured, replayed []capture.Record, idsOnly bool) []string {
+	want, got := clientbound(captured), clientbound(replayed)
+	var diffs []string
+	for i := 0; i < len(want) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			diffs = append(diffs, fmt.Sprintf("#%d: missing %s", i, describe(want[i])))
+		case i >= len(want):
+			diffs = append(diffs, fmt.Sprintf("#%d: unexpected %s", i, describe(got[i])))
+		case want[i].State != got[i].State || want[i].ID != got[i].ID:
+			diffs = append(diffs, fmt.Sprintf("#%d: want %s, got %s", i, describe(want[i]), describe(got[i])))
+		case !idsOnly && want[i].Data != got[i].Data:
+			diffs = append(diffs, fmt.Sprintf("#%d: %s payload differs", i, describe(want[i])))
+		}
+	}
+	return diffs
+}
+
+func clientbound(records []capture.Record) []capture.Record {
+	var out []capture.Record
+	for _, rec := range records {
+		if !rec.Serverbound() && !isKeepAlive(rec) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+func isKeepAlive(rec capture.Record) bool {
+	// Both directions share the packet names.
+	switch rec.Packet {
+	case protocol.ServerboundConfigKeepAlive.String(), protocol.ServerboundPlayKeepAlive.String():
+		return true
+	}
+	return false
+}
+
+func describe(rec capture.Record) string {
+	name := rec.Packet
+	if name == "" {
+		name = "unknown packet"
+	}
+	return fmt.Sprintf("%s 0x%02x (%s)", rec.State, rec.ID, name)
+}
+
+// writeRecords saves records as a capture file.
+func writeRecords(path string, records []capture.Record) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/dop251/goja"
+
+	js "github.com/Advik-B/Golem/javascript"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/text"
+)
+
+// exposeScripting adds the server's globals to every plugin loaded after it.
+//
+//	channels.register("example:hello", function (player, data) {
+//	    player.sendPluginMessage("example:hello", data);
+//	});
+//
+//	cookies.store(player, "example:visits", "3");
+//	cookies.request(player, "example:visits", function (value) {
+//	    // value is null if the client has no such cookie.
+//	});
+//
+//	commands.register("hello", "[name]", "Says hello", function (sender, args) {
+//	    sender.sendMessage("Hello, " + (args[0] || sender.name) + "!");
+//	});
+//	chat.broadcast("Plugins loaded");
+func (s *Server) exposeScripting() {
+	s.plugins.Expose("channels", func(p *js.Plugin) interface{} {
+		return map[string]interface{}{
+			"register": func(channel string, callback goja.Value) error {
+				cb := p.Callback(callback)
+				return s.channels.Register(channel, func(player *Player, channel string, data []byte) {
+					cb(jsPlayer(player), data)
+				})
+			},
+			"registered": s.channels.Registered,
+		}
+	})
+	// Cookies are strings in JavaScript, stored as UTF-8.
+	s.plugins.Expose("cookies", func(p *js.Plugin) interface{} {
+		return map[string]interface{}{
+			"store": func(player map[string]interface{}, key, value string) error {
+				target, err := s.scriptPlayer(player)
+				if err != nil {
+					return err
+				}
+				return target.StoreCookie(key, []byte(value))
+			},
+			"request": func(player map[string]interface{}, key string, callback goja.Value) error {
+				target, err := s.scriptPlayer(player)
+				if err != nil {
+					return err
+				}
+				cb := p.Callback(callback)
+				return target.RequestCookie(key, func(payload []byte, ok bool) {
+					if !ok {
+						cb(nil)
+						return
+					}
+					cb(string(payload))
+				})
+			},
+		}
+	})
+	s.plugins.Expose("commands", func(p *js.Plugin) interface{} {
+		return map[string]interface{}{
+			"register": func(name, usage, description string, callback goja.Value) error {
+				cb := p.Callback(callback)
+				return s.commands.Register(&Command{
+					Name:        name,
+					Usage:       usage,
+					Description: description,
+					Run: func(sender CommandSender, args []string) error {
+						cb(jsSender(sender), args)
+						return nil
+					},
+				})
+			},
+		}
+	})
+	s.plugins.Expose("chat", func(p *js.Plugin) interface{} {
+		return map[string]interface{}{
+			"broadcast": func(text string) { s.Broadcast(text, nil) },
+		}
+	})
+}
+
+// jsSender is the view of a command sender given to plugins. player is null
+// unless a player ran the command.
+func jsSender(sender CommandSender) map[string]interface{} {
+	view := map[string]interface{}{
+		"name":        sender.DisplayName(),
+		"sendMessage": sender.SendMessage,
+		"player":      nil,
+	}
+	if p, ok := sender.(*Player); ok {
+		view["player"] = jsPlayer(p)
+	}
+	return view
+}
+
+// scriptPlayer finds the online player a plugin passed in as a jsPlayer.
+func (s *Server) scriptPlayer(v map[string]interface{}) (*Player, error) {
+	id, _ := v["uuid"].(string)
+	uuid, err := protocol.ParseUUID(id)
+	if err != nil {
+		return nil, fmt.Errorf("not a player: %v", v["name"])
+	}
+	p := s.Player(uuid)
+	if p == nil {
+		return nil, fmt.Errorf("player %v is not online", v["name"])
+	}
+	return p, nil
+}
+
+// jsPlayer is the view of a player given to plugins.
+func jsPlayer(p *Player) map[string]interface{} {
+	return map[string]interface{}{
+		"name":              p.Name,
+		"uuid":              p.UUID.String(),
+		"clientBrand":       p.ClientBrand,
+		"listens":           p.Listens,
+		"channels":          p.Channels,
+		"sendPluginMessage": p.SendPluginMessage,
+		"sendMessage":       p.SendMessage,
+		"sendMarkup":        func(markup string) { p.SendText(text.ParseMarkup(markup)) },
+		"transferred":       p.Transferred,
+		"transfer":          p.Transfer,
+		"disconnect":        p.Disconnect,
+		"location": func() map[string]interface{} {
+			loc := p.Location()
+			return map[string]interface{}{"x": loc.X, "y": loc.Y, "z": loc.Z, "yaw": loc.Yaw, "pitch": loc.Pitch}
+		},
+		"teleport": func(x, y, z float64) error {
+			loc := p.Location()
+			loc.X, loc.Y, loc.Z = x, y, z
+			return p.Teleport(loc)
+		},
+		"setAllowFlight": p.SetAllowFlight,
+	}
+}
+
+// firePlayerEvent fires a player lifecycle event such as playerJoin.
+func (s *Server) firePlayerEvent(name string, p *Player) {
+	if s.plugins == nil || !s.plugins.Events.Has(name) {
+		return
+	}
+	s.plugins.Events.Fire(name, js.Event{"player": jsPlayer(p)})
+}
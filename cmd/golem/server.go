@@ -0,0 +1,417 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/capture"
+	js "github.com/Advik-B/Golem/javascript"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/proxy"
+	"github.com/Advik-B/Golem/world"
+)
+
+// Server accepts connections and owns the state shared between them.
+type Server struct {
+	cfg      *Config
+	plugins  *js.PluginManager
+	status   *StatusProvider
+	channels *Channels
+	limits   *limiter
+	commands *Commands
+	registry *serverRegistry
+	// loop runs the game; the world and its chunks belong to its goroutine.
+	loop      *TickLoop
+	entities  *EntityTracker
+	world     *world.World
+	chunks    *world.ChunkManager
+	closeOnce sync.Once
+	closeErr  error
+
+	mu      sync.RWMutex
+	players map[protocol.UUID]*Player
+	// viewers are the players whose clients are sent chunks as they load.
+	viewers     map[*Player]struct{}
+	chatFilters []ChatFilter
+	collide     Collider
+
+	// captureHook, if set, replaces the configured packet capture. Replay
+	// uses it to record the replayed connection in memory.
+	captureHook func(nc net.Conn) *capture.Writer
+}
+
+// NewServer creates a server from cfg. Plugins must be loaded afterwards, so
+// they see the globals the server exposes.
+func NewServer(cfg *Config, plugins *js.PluginManager) (*Server, error) {
+	s := &Server{
+		cfg:      cfg,
+		plugins:  plugins,
+		channels: NewChannels(),
+		limits:   newLimiter(cfg.Limits),
+		commands: NewCommands(),
+		loop:     NewTickLoop(cfg.Server.MaxTickTime),
+		players:  make(map[protocol.UUID]*Player),
+		viewers:  make(map[*Player]struct{}),
+	}
+	registry, err := newServerRegistry()
+	if err != nil {
+		return nil, err
+	}
+	s.registry = registry
+	s.entities = newEntityTracker(s)
+	s.registerBuiltinCommands()
+	if plugins != nil {
+		s.exposeScripting()
+	}
+	status, err := NewStatusProvider(s)
+	if err != nil {
+		return nil, err
+	}
+	s.status = status
+	s.loop.Handle(PhaseEntities, s.entities.tick)
+	s.loop.Handle(PhaseChunks, s.tickChunks)
+	s.loop.Handle(PhaseAutosave, s.autosave)
+	s.loop.Start()
+	return s, nil
+}
+
+// Serve accepts connections on ln until it is closed.
+func (s *Server) Serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			log.Println("Accept error:", err)
+			continue
+		}
+		go s.handleConnection(conn)
+	}
+}
+
+// Players returns the online players sorted by name.
+func (s *Server) Players() []*Player {
+	s.mu.RLock()
+	players := make([]*Player, 0, len(s.players))
+	for _, p := range s.players {
+		players = append(players, p)
+	}
+	s.mu.RUnlock()
+	sort.Slice(players, func(i, j int) bool { return players[i].Name < players[j].Name })
+	return players
+}
+
+// Player returns the online player with the given UUID, or nil.
+func (s *Server) Player(id protocol.UUID) *Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.players[id]
+}
+
+// PlayerCount returns the number of online players.
+func (s *Server) PlayerCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.players)
+}
+
+// Loop returns the tick loop that runs the game.
+func (s *Server) Loop() *TickLoop { return s.loop }
+
+// Entities returns the entity tracker, which belongs to the tick goroutine.
+func (s *Server) Entities() *EntityTracker { return s.entities }
+
+// Commands returns the command registry.
+func (s *Server) Commands() *Commands { return s.commands }
+
+// LimitStats returns how many connections the limits have turned away.
+func (s *Server) LimitStats() LimitStats { return s.limits.stats() }
+
+// duplicateLoginMessage kicks a player who logged in again elsewhere, in
+// vanilla's wording.
+const duplicateLoginMessage = "You logged in from another location"
+
+// addPlayer adds p to the online players. Like vanilla, it first kicks a
+// player online with the same UUID, such as one who logged in with the same
+// name, and waits until that player has left. It reports false if p's own
+// connection closed in the meantime.
+func (s *Server) addPlayer(p *Player) bool {
+	for {
+		s.mu.Lock()
+		old := s.players[p.UUID]
+		if old == nil {
+			s.players[p.UUID] = p
+			s.mu.Unlock()
+			return true
+		}
+		s.mu.Unlock()
+		old.conn.Disconnect(duplicateLoginMessage)
+		select {
+		case <-old.left:
+		case <-p.conn.Done():
+			return false
+		}
+	}
+}
+
+// removePlayer removes p from the player list and reports whether it was there.
+func (s *Server) removePlayer(p *Player) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.players[p.UUID] != p {
+		return false
+	}
+	delete(s.players, p.UUID)
+	return true
+}
+
+func (s *Server) connConfig() protocol.ConnConfig {
+	return protocol.ConnConfig{
+		ReadTimeout:       s.cfg.Network.ReadTimeout,
+		KeepAliveInterval: s.cfg.Network.KeepAliveInterval,
+		KeepAliveTimeout:  s.cfg.Network.KeepAliveTimeout,
+		SendQueue:         s.cfg.Network.SendQueue,
+	}
+}
+
+// session is the per-connection state that lives until the client is in play.
+type session struct {
+	srv  *Server
+	conn *protocol.Conn
+
+	// clientProtocol is the protocol the client asked for in the handshake,
+	// which may be one we do not support.
+	clientProtocol int32
+	// serverAddress is the host name the client used to connect.
+	serverAddress string
+	// forwarded is the player a BungeeCord proxy forwarded in the handshake.
+	forwarded *proxy.Forwarded
+	// loginName is the name from Login Start while a Velocity proxy is asked
+	// for the player.
+	loginName string
+	// transferred is set when the client logs in with the transfer intent.
+	transferred bool
+	// pendingLogin is set while the session holds a pending login slot.
+	pendingLogin bool
+
+	player *Player
+}
+
+// openCapture starts recording nc if packet capture is enabled.
+func (s *Server) openCapture(nc net.Conn) *capture.Writer {
+	if s.captureHook != nil {
+		return s.captureHook(nc)
+	}
+	if !s.cfg.Debug.Capture {
+		return nil
+	}
+	w, err := capture.Create(s.cfg.Debug.CaptureDir, nc.RemoteAddr().String())
+	if err != nil {
+		log.Printf("Could not start packet capture: %v", err)
+		return nil
+	}
+	return w
+}
+
+func (s *Server) handleConnection(nc net.Conn) {
+	// Behind a forwarding proxy every connection comes from the proxy.
+	if s.cfg.Proxy.Forwarding == ForwardingNone {
+		release, ok := s.limits.openConn(nc.RemoteAddr())
+		if !ok {
+			nc.Close()
+			return
+		}
+		defer release()
+	}
+
+	cfg := s.connConfig()
+	if w := s.openCapture(nc); w != nil {
+		cfg.Observer = w
+		defer w.Close()
+	}
+	conn := protocol.NewConn(nc, cfg)
+	defer func() {
+		// A disconnecting connection is closed by its writer once the
+		// Disconnect packet is flushed; closing it here would drop it.
+		if conn.Err() == nil {
+			conn.Close()
+		}
+	}()
+
+	sess := &session{srv: s, conn: conn}
+	defer func() {
+		sess.endLogin()
+		p := sess.player
+		if p == nil {
+			return
+		}
+		// Other players forget the entity before the player list entry.
+		s.loop.Call(func() { s.entities.removePlayer(p) })
+		s.closeView(p)
+		if s.removePlayer(p) {
+			log.Printf("%s left the game", p.Name)
+			s.removeFromPlayerList(p)
+			s.firePlayerEvent("playerQuit", p)
+		}
+		close(p.left)
+	}()
+	// A bug in a packet handler must cost one connection, not the server.
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("%s: panic handling packet: %v\n%s", conn.RemoteAddr(), r, debug.Stack())
+			conn.Disconnect("Internal server error")
+		}
+	}()
+
+	ping, err := conn.ReadLegacyPing()
+	if err != nil {
+		return
+	}
+	if ping != nil {
+		s.status.answerLegacy(conn, ping)
+		return
+	}
+
+	rate := s.limits.packetRate()
+	for {
+		pkt, err := conn.ReadPacket()
+		if err != nil {
+			if conn.Err() == nil && !errors.Is(err, io.EOF) {
+				log.Printf("%s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if !rate.allow(time.Now()) {
+			s.limits.packetFlood.Add(1)
+			log.Printf("%s: sending packets too fast", conn.RemoteAddr())
+			conn.Disconnect(packetFloodMessage)
+			return
+		}
+		if err := sess.handlePacket(pkt); err != nil {
+			if conn.Err() != nil {
+				return
+			}
+			log.Printf("%s: %v", conn.RemoteAddr(), err)
+			conn.Disconnect("Invalid packet received")
+			return
+		}
+		if conn.Err() != nil {
+			return
+		}
+	}
+}
+
+// handlePacket dispatches a single packet for the connection's current state.
+// A returned error means the packet was malformed.
+func (s *session) handlePacket(raw *protocol.RawPacket) error {
+	switch s.conn.State() {
+	case protocol.Handshaking:
+		if raw.Packet == protocol.ServerboundHandshakeIntention {
+			return s.handleHandshake(raw)
+		}
+	case protocol.Status:
+		return s.handleStatus(raw)
+	case protocol.Login, protocol.Configuration:
+		return s.handleLogin(raw)
+	case protocol.Play:
+		return s.handlePlay(raw)
+	}
+	return nil
+}
+
+func (s *session) handleHandshake(raw *protocol.RawPacket) error {
+	var hs protocol.Handshake
+	if err := raw.Decode(&hs); err != nil {
+		return err
+	}
+	s.clientProtocol = hs.ProtocolVersion
+	s.serverAddress = hs.ServerAddress
+	if s.srv.cfg.Proxy.Forwarding == ForwardingBungeeCord {
+		s.serverAddress = proxy.StripBungeeCord(hs.ServerAddress)
+		if hs.Intent == protocol.IntentLogin || hs.Intent == protocol.IntentTransfer {
+			f, err := proxy.ParseBungeeCord(hs.ServerAddress)
+			if err != nil {
+				s.conn.SetState(protocol.Login)
+				s.conn.Disconnect("If you wish to use IP forwarding, please enable it in your BungeeCord config as well!")
+				return nil
+			}
+			s.forwarded = f
+			s.conn.SetRemoteAddr(f.Addr)
+		}
+	}
+
+	v, supported := protocol.Lookup(hs.ProtocolVersion)
+	if supported {
+		s.conn.SetVersion(v)
+	}
+	switch hs.Intent {
+	case protocol.IntentStatus:
+		s.conn.SetState(protocol.Status)
+	case protocol.IntentLogin, protocol.IntentTransfer:
+		s.conn.SetState(protocol.Login)
+		if !supported {
+			s.conn.Disconnect(outdatedMessage(hs.ProtocolVersion))
+			return nil
+		}
+		if hs.Intent == protocol.IntentTransfer {
+			if !v.Has(protocol.FeatureTransfer) {
+				return fmt.Errorf("unknown intent %d", hs.Intent)
+			}
+			if !s.srv.cfg.Server.AcceptTransfers {
+				s.conn.Disconnect("Server does not accept transfers")
+				return nil
+			}
+			s.transferred = true
+		}
+		// With Velocity the client address is only known once the proxy
+		// answers, so the throttle is checked then.
+		if s.srv.cfg.Proxy.Forwarding != ForwardingVelocity && s.throttled() {
+			return nil
+		}
+		if !s.srv.limits.beginLogin() {
+			s.conn.Disconnect(busyMessage)
+			return nil
+		}
+		s.pendingLogin = true
+	default:
+		return fmt.Errorf("unknown intent %d", hs.Intent)
+	}
+	return nil
+}
+
+// throttled applies the connection throttle to the client's address and
+// disconnects it if it logged in too recently.
+func (s *session) throttled() bool {
+	if s.srv.limits.throttleLogin(s.conn.RemoteAddr(), time.Now()) {
+		s.conn.Disconnect(throttledMessage)
+		return true
+	}
+	return false
+}
+
+// endLogin gives back the session's pending login slot, if it holds one.
+func (s *session) endLogin() {
+	if s.pendingLogin {
+		s.pendingLogin = false
+		s.srv.limits.endLogin()
+	}
+}
+
+// outdatedMessage builds the login disconnect reason for an unsupported protocol.
+func outdatedMessage(clientProtocol int32) string {
+	var text string
+	if clientProtocol > protocol.Latest().Protocol {
+		text = fmt.Sprintf("Outdated server! I'm still on %s", protocol.SupportedRange())
+	} else {
+		text = fmt.Sprintf("Outdated client! Please use %s", protocol.SupportedRange())
+	}
+	return text
+}
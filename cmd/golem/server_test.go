@@ -0,0 +1,1539 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Advik-B/Golem/block"
+	"github.com/Advik-B/Golem/capture"
+	js "github.com/Advik-B/Golem/javascript"
+	"github.com/Advik-B/Golem/nbt"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/proxy"
+	"github.com/Advik-B/Golem/rcon"
+	"github.com/Advik-B/Golem/world"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer creates a server with the default configuration, changed by
+// configure if it is not nil. No server icon is loaded.
+func newTestServer(t *testing.T, configure func(cfg *Config)) *Server {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.Status.Icon = ""
+	// Players joining side by side would see each other spawn at times the
+	// tests cannot predict; TestEntityTracking turns entities back on.
+	cfg.World.EntityBroadcastRange = 0
+	if configure != nil {
+		configure(cfg)
+	}
+	srv, err := NewServer(cfg, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.CloseWorld() })
+	return srv
+}
+
+// testClient speaks the protocol to a server over an in-memory connection.
+type testClient struct {
+	t       *testing.T
+	conn    net.Conn
+	r       *bufio.Reader
+	version *protocol.Version
+	state   protocol.State
+}
+
+// connect opens a connection to srv for a client of version v.
+func connect(t *testing.T, srv *Server, v *protocol.Version) *testClient {
+	t.Helper()
+	return connectFrom(t, srv, v, nil)
+}
+
+// addrConn is a connection that appears to come from addr.
+type addrConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c addrConn) RemoteAddr() net.Addr { return c.addr }
+
+// connectFrom is connect for a client at addr. A nil addr leaves the
+// connection without an IP address, which the per-address limits ignore.
+func connectFrom(t *testing.T, srv *Server, v *protocol.Version, addr net.Addr) *testClient {
+	t.Helper()
+	server, client := net.Pipe()
+	if addr != nil {
+		server = addrConn{Conn: server, addr: addr}
+	}
+	go srv.handleConnection(server)
+	t.Cleanup(func() { client.Close() })
+	require.NoError(t, client.SetDeadline(time.Now().Add(10*time.Second)))
+	return &testClient{t: t, conn: client, r: bufio.NewReader(client), version: v, state: protocol.Handshaking}
+}
+
+// send writes a packet the client's version knows.
+func (c *testClient) send(p protocol.Packet, payload ...[]byte) {
+	c.t.Helper()
+	_, err := c.conn.Write(c.frame(p, payload...))
+	require.NoError(c.t, err)
+}
+
+// frame encodes a packet the client's version knows.
+func (c *testClient) frame(p protocol.Packet, payload ...[]byte) []byte {
+	c.t.Helper()
+	id, ok := c.version.ID(p)
+	require.True(c.t, ok, "%s has no ID in %s", p, c.version)
+	pkt := protocol.WriteVarInt(int(id))
+	for _, b := range payload {
+		pkt = append(pkt, b...)
+	}
+	return append(protocol.WriteVarInt(len(pkt)), pkt...)
+}
+
+// next reads the next packet from the server.
+func (c *testClient) next() (protocol.Packet, *bytes.Reader) {
+	c.t.Helper()
+	id, payload, err := protocol.ReadFrame(c.r)
+	require.NoError(c.t, err, "reading a packet in %s", c.state)
+	p, ok := c.version.Packet(c.state, protocol.Clientbound, id)
+	require.True(c.t, ok, "unknown packet 0x%02x in %s", id, c.state)
+	return p, bytes.NewReader(payload)
+}
+
+// expect reads the next packet and fails unless it is p.
+func (c *testClient) expect(p protocol.Packet) *bytes.Reader {
+	c.t.Helper()
+	got, r := c.next()
+	require.Equal(c.t, p, got, "in %s", c.state)
+	return r
+}
+
+// expectClosed fails unless the server closes the connection without
+// sending anything more.
+func (c *testClient) expectClosed() {
+	c.t.Helper()
+	_, err := c.r.ReadByte()
+	require.ErrorIs(c.t, err, io.EOF)
+}
+
+// handshake sends the handshake with the given protocol and intent.
+func (c *testClient) handshake(version int32, address string, intent int32) {
+	c.t.Helper()
+	c.send(protocol.ServerboundHandshakeIntention,
+		protocol.WriteVarInt(int(version)),
+		protocol.WriteString(address),
+		protocol.WriteUnsignedShort(25565),
+		protocol.WriteVarInt(int(intent)),
+	)
+	switch intent {
+	case protocol.IntentStatus:
+		c.state = protocol.Status
+	case protocol.IntentLogin, protocol.IntentTransfer:
+		c.state = protocol.Login
+	}
+}
+
+// startLogin handshakes and sends Login Start.
+func (c *testClient) startLogin(name string) {
+	c.t.Helper()
+	c.handshake(c.version.Protocol, "localhost", protocol.IntentLogin)
+	c.send(protocol.ServerboundLoginStart, protocol.WriteString(name), protocol.WriteUUID(protocol.OfflineUUID(name)))
+}
+
+// configure acknowledges Login Success and runs the configuration state up
+// to and including Finish Configuration.
+func (c *testClient) configure() {
+	c.t.Helper()
+	c.send(protocol.ServerboundLoginAcknowledged)
+	c.state = protocol.Configuration
+
+	r := c.expect(protocol.ClientboundConfigPluginMessage)
+	channel, err := protocol.ReadString(r, protocol.DefaultStringLength)
+	require.NoError(c.t, err)
+	require.Equal(c.t, ChannelBrand, channel)
+	brand, err := protocol.ReadString(r, protocol.DefaultStringLength)
+	require.NoError(c.t, err)
+	require.Equal(c.t, ServerBrand, brand)
+
+	if c.version.Has(protocol.FeatureKnownPacks) {
+		c.expect(protocol.ClientboundConfigKnownPacks)
+		c.send(protocol.ServerboundConfigKnownPacks,
+			protocol.WriteVarInt(1),
+			protocol.WriteString("minecraft"), protocol.WriteString("core"), protocol.WriteString(c.version.Name()),
+		)
+	}
+	regs, err := c.version.Registries()
+	require.NoError(c.t, err)
+	want := 1
+	if c.version.Has(protocol.FeatureKnownPacks) {
+		want = len(regs.List())
+	}
+	for i := 0; i < want; i++ {
+		c.expect(protocol.ClientboundConfigRegistryData)
+	}
+	c.expect(protocol.ClientboundConfigFinish)
+}
+
+// join finishes the configuration and reads the packets that put the player
+// in the world.
+func (c *testClient) join() {
+	c.t.Helper()
+	c.send(protocol.ServerboundConfigAcknowledgeFinish)
+	c.state = protocol.Play
+	for _, p := range []protocol.Packet{
+		protocol.ClientboundPlayLogin,
+		protocol.ClientboundPlayGameEvent,
+		protocol.ClientboundPlaySetCenterChunk,
+		protocol.ClientboundPlayChunkDataAndUpdateLight,
+		protocol.ClientboundPlaySynchronizePlayerPosition,
+		protocol.ClientboundPlayCommands,
+		protocol.ClientboundPlayPlayerInfoUpdate,
+	} {
+		c.expect(p)
+	}
+}
+
+// login takes the client all the way into the play state.
+func (c *testClient) login(name string) {
+	c.t.Helper()
+	c.startLogin(name)
+	c.expectLoginSuccess(name, protocol.OfflineUUID(name))
+	c.configure()
+	c.join()
+}
+
+func (c *testClient) expectLoginSuccess(name string, uuid protocol.UUID) {
+	c.t.Helper()
+	r := c.expect(protocol.ClientboundLoginSuccess)
+	gotUUID, err := protocol.ReadUUID(r)
+	require.NoError(c.t, err)
+	assert.Equal(c.t, uuid, gotUUID)
+	gotName, err := protocol.ReadString(r, 16)
+	require.NoError(c.t, err)
+	assert.Equal(c.t, name, gotName)
+}
+
+// waitForPlayer waits until the server lists a player with the given name.
+func waitForPlayer(t *testing.T, srv *Server, name string) *Player {
+	t.Helper()
+	var found *Player
+	require.Eventually(t, func() bool {
+		for _, p := range srv.Players() {
+			if p.Name == name {
+				found = p
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 5*time.Millisecond)
+	return found
+}
+
+// statusResponse is the part of the status JSON the tests look at.
+type statusResponse struct {
+	Version struct {
+		Name     string
+		Protocol int32
+	}
+	Players     struct{ Max, Online int }
+	Description struct{ Text string }
+}
+
+// readStatus reads a Status Response.
+func (c *testClient) readStatus() statusResponse {
+	c.t.Helper()
+	body, err := protocol.ReadString(c.expect(protocol.ClientboundStatusResponse), protocol.DefaultStringLength)
+	require.NoError(c.t, err)
+	var status statusResponse
+	require.NoError(c.t, json.Unmarshal([]byte(body), &status))
+	return status
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golem.yml")
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+	_, err = LoadConfig(path)
+	require.NoError(t, err)
+
+	for _, bad := range []string{
+		"world: {view-distance: 1}",
+		"world: {view-distance: 33}",
+		"world: {chunk-workers: -1}",
+		"world: {cached-chunks: -1}",
+		"world: {level-type: minecraft:amplified}",
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(bad), 0o644))
+		_, err := LoadConfig(path)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) { cfg.Status.MOTD = "Hello" })
+	v := protocol.Latest()
+	c := connect(t, srv, v)
+	c.handshake(v.Protocol, "localhost", protocol.IntentStatus)
+	c.send(protocol.ServerboundStatusRequest)
+
+	status := c.readStatus()
+	assert.Equal(t, v.Protocol, status.Version.Protocol)
+	assert.Equal(t, 20, status.Players.Max)
+	assert.Equal(t, "Hello", status.Description.Text)
+
+	c.send(protocol.ServerboundStatusPing, protocol.WriteLong(1234))
+	pong, err := protocol.ReadLong(c.expect(protocol.ClientboundStatusPong))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), pong)
+	c.expectClosed()
+}
+
+// TestStatusUnsupportedVersion checks that clients we cannot serve still see
+// the server in their list, with our version.
+func TestStatusUnsupportedVersion(t *testing.T) {
+	srv := newTestServer(t, nil)
+	c := connect(t, srv, protocol.Latest())
+	c.handshake(47, "localhost", protocol.IntentStatus)
+	c.send(protocol.ServerboundStatusRequest)
+	assert.Equal(t, protocol.Latest().Protocol, c.readStatus().Version.Protocol)
+}
+
+func TestLegacyStatus(t *testing.T) {
+	srv := newTestServer(t, nil)
+	c := connect(t, srv, protocol.Latest())
+	_, err := c.conn.Write([]byte{0xFE, 0x01})
+	require.NoError(t, err)
+	b, err := c.r.ReadByte()
+	require.NoError(t, err)
+	assert.Equal(t, byte(0xFF), b)
+	rest, err := io.ReadAll(c.r)
+	require.NoError(t, err)
+	assert.NotEmpty(t, rest)
+}
+
+// TestLogin drives every supported version through login, configuration and
+// into play.
+func TestLogin(t *testing.T) {
+	for _, v := range protocol.Supported() {
+		t.Run(v.Name(), func(t *testing.T) {
+			srv := newTestServer(t, nil)
+			c := connect(t, srv, v)
+			c.login("Steve")
+			p := waitForPlayer(t, srv, "Steve")
+			assert.Equal(t, protocol.OfflineUUID("Steve"), p.UUID)
+
+			c.conn.Close()
+			require.Eventually(t, func() bool { return srv.PlayerCount() == 0 }, 5*time.Second, 5*time.Millisecond)
+		})
+	}
+}
+
+func TestLoginOutdated(t *testing.T) {
+	srv := newTestServer(t, nil)
+	c := connect(t, srv, protocol.Latest())
+	c.handshake(47, "localhost", protocol.IntentLogin)
+	reason, err := protocol.ReadString(c.expect(protocol.ClientboundLoginDisconnect), protocol.DefaultStringLength)
+	require.NoError(t, err)
+	assert.Contains(t, reason, "Outdated client!")
+	c.expectClosed()
+}
+
+// TestMalformedPacket checks that a packet that does not decode disconnects
+// the client instead of taking the server down.
+func TestMalformedPacket(t *testing.T) {
+	srv := newTestServer(t, nil)
+	c := connect(t, srv, protocol.Latest())
+	c.handshake(c.version.Protocol, "localhost", protocol.IntentLogin)
+	c.send(protocol.ServerboundLoginStart, protocol.WriteVarInt(1000))
+	reason, err := protocol.ReadString(c.expect(protocol.ClientboundLoginDisconnect), protocol.DefaultStringLength)
+	require.NoError(t, err)
+	assert.Contains(t, reason, "Invalid packet received")
+	c.expectClosed()
+}
+
+func TestPluginChannels(t *testing.T) {
+	srv := newTestServer(t, nil)
+	received := make(chan string, 1)
+	require.NoError(t, srv.channels.Register("golem:test", func(p *Player, channel string, data []byte) {
+		received <- p.Name + " " + string(data)
+	}))
+	assert.Error(t, srv.channels.Register("minecraft:test", func(*Player, string, []byte) {}))
+
+	v := protocol.Latest()
+	c := connect(t, srv, v)
+	c.startLogin("Alex")
+	c.expectLoginSuccess("Alex", protocol.OfflineUUID("Alex"))
+	c.send(protocol.ServerboundLoginAcknowledged)
+	c.state = protocol.Configuration
+
+	c.expect(protocol.ClientboundConfigPluginMessage) // brand
+	r := c.expect(protocol.ClientboundConfigPluginMessage)
+	channel, err := protocol.ReadString(r, protocol.DefaultStringLength)
+	require.NoError(t, err)
+	assert.Equal(t, ChannelRegister, channel)
+	announced, _ := io.ReadAll(r)
+	assert.Equal(t, "golem:test", string(announced))
+
+	c.send(protocol.ServerboundConfigPluginMessage, protocol.WriteString(ChannelBrand), protocol.WriteString("vanilla"))
+	c.send(protocol.ServerboundConfigPluginMessage, protocol.WriteString(ChannelRegister), []byte("golem:test\x00bad name"))
+	c.expect(protocol.ClientboundConfigKnownPacks)
+	c.send(protocol.ServerboundConfigKnownPacks, protocol.WriteVarInt(0))
+	for {
+		p, _ := c.next()
+		if p == protocol.ClientboundConfigFinish {
+			break
+		}
+		require.Equal(t, protocol.ClientboundConfigRegistryData, p)
+	}
+	c.join()
+
+	player := waitForPlayer(t, srv, "Alex")
+	assert.Equal(t, "vanilla", player.ClientBrand())
+	assert.Equal(t, []string{"golem:test"}, player.Channels())
+
+	c.send(protocol.ServerboundPlayPluginMessage, protocol.WriteString("golem:test"), []byte("hi"))
+	select {
+	case got := <-received:
+		assert.Equal(t, "Alex hi", got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("plugin message was not dispatched")
+	}
+
+	require.NoError(t, player.SendPluginMessage("golem:test", []byte("back")))
+	r = c.expect(protocol.ClientboundPlayPluginMessage)
+	channel, _ = protocol.ReadString(r, protocol.DefaultStringLength)
+	assert.Equal(t, "golem:test", channel)
+}
+
+func TestVelocityForwarding(t *testing.T) {
+	secret := "s3cr3t"
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.Proxy.Forwarding = ForwardingVelocity
+		cfg.Proxy.VelocitySecret = secret
+	})
+	notch := protocol.OfflineUUID("Notch")
+	forward := func(key string) []byte {
+		payload := protocol.WriteVarInt(proxy.VelocityDefault)
+		payload = append(payload, protocol.WriteString("203.0.113.7")...)
+		payload = append(payload, protocol.WriteUUID(notch)...)
+		payload = append(payload, protocol.WriteString("Notch")...)
+		payload = append(payload, protocol.WriteProperties(nil)...)
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write(payload)
+		return append(mac.Sum(nil), payload...)
+	}
+	request := func(c *testClient) int32 {
+		c.startLogin("Player")
+		r := c.expect(protocol.ClientboundLoginPluginRequest)
+		id, err := protocol.ReadVarInt(r)
+		require.NoError(t, err)
+		channel, err := protocol.ReadString(r, protocol.DefaultStringLength)
+		require.NoError(t, err)
+		assert.Equal(t, proxy.VelocityChannel, channel)
+		return id
+	}
+
+	c := connect(t, srv, protocol.Latest())
+	id := request(c)
+	c.send(protocol.ServerboundLoginPluginResponse, protocol.WriteVarInt(int(id)), protocol.WriteBool(true), forward(secret))
+	c.expectLoginSuccess("Notch", notch)
+
+	c = connect(t, srv, protocol.Latest())
+	id = request(c)
+	c.send(protocol.ServerboundLoginPluginResponse, protocol.WriteVarInt(int(id)), protocol.WriteBool(true), forward("wrong"))
+	reason, err := protocol.ReadString(c.expect(protocol.ClientboundLoginDisconnect), protocol.DefaultStringLength)
+	require.NoError(t, err)
+	assert.Contains(t, reason, "Unable to verify player details")
+}
+
+// TestReplay captures a login and checks that replaying it gives the same
+// packets.
+func TestReplay(t *testing.T) {
+	srv := newTestServer(t, nil)
+	var buf bytes.Buffer
+	w := capture.NewWriter(&buf)
+	srv.captureHook = func(net.Conn) *capture.Writer { return w }
+
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	c.conn.Close()
+	require.Eventually(t, func() bool { return srv.PlayerCount() == 0 }, 5*time.Second, 5*time.Millisecond)
+	require.NoError(t, w.Flush())
+
+	records, err := capture.Read(&buf)
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	replayed, err := Replay(srv.cfg, nil, records)
+	require.NoError(t, err)
+	assert.Empty(t, CompareReplay(records, replayed, false))
+}
+
+// expectLoginDisconnect reads a Login Disconnect and checks its reason.
+func (c *testClient) expectLoginDisconnect(reason string) {
+	c.t.Helper()
+	got, err := protocol.ReadString(c.expect(protocol.ClientboundLoginDisconnect), protocol.DefaultStringLength)
+	require.NoError(c.t, err)
+	assert.Contains(c.t, got, reason)
+	c.expectClosed()
+}
+
+func TestConnectionThrottle(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) { cfg.Limits.ConnectionThrottle = time.Minute })
+	v := protocol.Latest()
+	addr := &net.TCPAddr{IP: net.ParseIP("203.0.113.5"), Port: 50000}
+
+	c := connectFrom(t, srv, v, addr)
+	c.startLogin("Steve")
+	c.expectLoginSuccess("Steve", protocol.OfflineUUID("Steve"))
+
+	c = connectFrom(t, srv, v, addr)
+	// The server refuses right after the handshake.
+	c.handshake(v.Protocol, "localhost", protocol.IntentLogin)
+	c.expectLoginDisconnect(throttledMessage)
+
+	// Status pings, other addresses and loopback are not throttled.
+	c = connectFrom(t, srv, v, addr)
+	c.handshake(v.Protocol, "localhost", protocol.IntentStatus)
+	c.send(protocol.ServerboundStatusRequest)
+	c.readStatus()
+	c = connectFrom(t, srv, v, &net.TCPAddr{IP: net.ParseIP("203.0.113.6"), Port: 50000})
+	c.startLogin("Alex")
+	c.expectLoginSuccess("Alex", protocol.OfflineUUID("Alex"))
+	for i := 0; i < 2; i++ {
+		c = connectFrom(t, srv, v, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000 + i})
+		c.startLogin("Local")
+		c.expectLoginSuccess("Local", protocol.OfflineUUID("Local"))
+	}
+	assert.Equal(t, int64(1), srv.LimitStats().Throttled)
+	sender := &rconSender{}
+	srv.Commands().Dispatch(sender, "limits")
+	assert.Equal(t, "Turned away since start: 1 throttled, 0 over the per-address limit, 0 while busy, 0 kicked for packet floods", sender.String())
+}
+
+func TestMaxConnectionsPerIP(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) { cfg.Limits.MaxConnectionsPerIP = 1 })
+	v := protocol.Latest()
+	addr := &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 50000}
+
+	first := connectFrom(t, srv, v, addr)
+	first.handshake(v.Protocol, "localhost", protocol.IntentStatus)
+	second := connectFrom(t, srv, v, addr)
+	second.expectClosed()
+	assert.Equal(t, int64(1), srv.LimitStats().TooManyConnections)
+
+	// The slot is free again once the first connection is gone.
+	first.conn.Close()
+	require.Eventually(t, func() bool {
+		srv.limits.mu.Lock()
+		defer srv.limits.mu.Unlock()
+		return len(srv.limits.open) == 0
+	}, 5*time.Second, 5*time.Millisecond)
+	third := connectFrom(t, srv, v, addr)
+	third.handshake(v.Protocol, "localhost", protocol.IntentStatus)
+	third.send(protocol.ServerboundStatusRequest)
+	third.readStatus()
+}
+
+func TestMaxPendingLogins(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) { cfg.Limits.MaxPendingLogins = 1 })
+	v := protocol.Latest()
+
+	first := connect(t, srv, v)
+	first.startLogin("Steve")
+	first.expectLoginSuccess("Steve", protocol.OfflineUUID("Steve"))
+
+	second := connect(t, srv, v)
+	// The server refuses right after the handshake.
+	second.handshake(v.Protocol, "localhost", protocol.IntentLogin)
+	second.expectLoginDisconnect(busyMessage)
+	assert.Equal(t, int64(1), srv.LimitStats().Busy)
+
+	// Joining the game frees the slot.
+	first.configure()
+	first.join()
+	waitForPlayer(t, srv, "Steve")
+	third := connect(t, srv, v)
+	third.login("Alex")
+}
+
+func TestDuplicateLogin(t *testing.T) {
+	srv := newTestServer(t, nil)
+	first := connect(t, srv, protocol.Latest())
+	first.login("Steve")
+	old := waitForPlayer(t, srv, "Steve")
+
+	// Logging in with the same name kicks the player already online, which
+	// has left before the new one is listed.
+	second := connect(t, srv, protocol.Latest())
+	second.startLogin("Steve")
+	second.expectLoginSuccess("Steve", protocol.OfflineUUID("Steve"))
+	second.configure()
+	second.send(protocol.ServerboundConfigAcknowledgeFinish)
+	second.state = protocol.Play
+	second.expectAfter(protocol.ClientboundPlayCommands,
+		protocol.ClientboundPlayLogin, protocol.ClientboundPlayGameEvent, protocol.ClientboundPlaySetCenterChunk,
+		protocol.ClientboundPlayChunkDataAndUpdateLight, protocol.ClientboundPlaySynchronizePlayerPosition)
+	assert.Equal(t, duplicateLoginMessage, first.expectText(protocol.ClientboundPlayDisconnect))
+	first.expectClosed()
+	second.expect(protocol.ClientboundPlayPlayerInfoUpdate)
+
+	p := waitForPlayer(t, srv, "Steve")
+	assert.NotSame(t, old, p)
+	assert.Same(t, p, srv.Player(protocol.OfflineUUID("Steve")))
+	assert.Equal(t, 1, srv.PlayerCount())
+	var entities []*world.Entity
+	srv.loop.Call(func() { entities = srv.Entities().Entities() })
+	require.Len(t, entities, 1)
+	assert.Equal(t, p.EntityID(), entities[0].ID)
+}
+
+func TestPacketRateLimit(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.Limits.PacketLimit = 50
+		cfg.Limits.PacketInterval = time.Hour
+	})
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	spam := c.frame(protocol.ServerboundPlayPluginMessage, protocol.WriteString("golem:spam"))
+	go func() {
+		// Writes fail once the server stops reading and closes the pipe.
+		for i := 0; i < 100; i++ {
+			if _, err := c.conn.Write(spam); err != nil {
+				return
+			}
+		}
+	}()
+	c.expect(protocol.ClientboundPlayDisconnect)
+	c.expectClosed()
+	assert.Equal(t, int64(1), srv.LimitStats().PacketFlood)
+}
+
+func TestTransfer(t *testing.T) {
+	srv := newTestServer(t, nil)
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	player := waitForPlayer(t, srv, "Steve")
+	assert.False(t, player.Transferred)
+
+	require.NoError(t, player.Transfer("lobby.example.com", 25566))
+	r := c.expect(protocol.ClientboundPlayTransfer)
+	host, err := protocol.ReadString(r, protocol.DefaultStringLength)
+	require.NoError(t, err)
+	port, err := protocol.ReadVarInt(r)
+	require.NoError(t, err)
+	assert.Equal(t, "lobby.example.com", host)
+	assert.Equal(t, int32(25566), port)
+	assert.Error(t, player.Transfer("lobby.example.com", 0))
+
+	old, _ := protocol.Lookup(765)
+	c = connect(t, srv, old)
+	c.login("Alex")
+	assert.ErrorIs(t, waitForPlayer(t, srv, "Alex").Transfer("lobby.example.com", 25565), ErrTransferUnsupported)
+}
+
+func TestTransferIntent(t *testing.T) {
+	v := protocol.Latest()
+	srv := newTestServer(t, nil)
+	c := connect(t, srv, v)
+	c.handshake(v.Protocol, "localhost", protocol.IntentTransfer)
+	c.expectLoginDisconnect("Server does not accept transfers")
+
+	srv = newTestServer(t, func(cfg *Config) { cfg.Server.AcceptTransfers = true })
+	c = connect(t, srv, v)
+	c.handshake(v.Protocol, "localhost", protocol.IntentTransfer)
+	c.send(protocol.ServerboundLoginStart, protocol.WriteString("Steve"), protocol.WriteUUID(protocol.OfflineUUID("Steve")))
+	c.expectLoginSuccess("Steve", protocol.OfflineUUID("Steve"))
+	c.configure()
+	c.join()
+	assert.True(t, waitForPlayer(t, srv, "Steve").Transferred)
+}
+
+// cookieResponse encodes a Cookie Response payload.
+func cookieResponse(key string, payload []byte) [][]byte {
+	if payload == nil {
+		return [][]byte{protocol.WriteString(key), protocol.WriteBool(false)}
+	}
+	return [][]byte{protocol.WriteString(key), protocol.WriteBool(true), protocol.WriteByteArray(payload)}
+}
+
+func TestCookies(t *testing.T) {
+	srv := newTestServer(t, nil)
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	player := waitForPlayer(t, srv, "Steve")
+
+	require.NoError(t, player.StoreCookie("golem:session", []byte("abc")))
+	r := c.expect(protocol.ClientboundPlayStoreCookie)
+	key, _ := protocol.ReadString(r, protocol.DefaultStringLength)
+	payload, err := protocol.ReadByteArray(r, protocol.MaxCookieLength)
+	require.NoError(t, err)
+	assert.Equal(t, "golem:session", key)
+	assert.Equal(t, []byte("abc"), payload)
+	assert.Error(t, player.StoreCookie("golem:session", make([]byte, protocol.MaxCookieLength+1)))
+	assert.Error(t, player.StoreCookie("Not A Key", nil))
+
+	type answer struct {
+		payload []byte
+		ok      bool
+	}
+	answers := make(chan answer, 2)
+	handler := func(payload []byte, ok bool) { answers <- answer{payload, ok} }
+	require.NoError(t, player.RequestCookie("golem:session", handler))
+	require.NoError(t, player.RequestCookie("golem:missing", handler))
+	for _, want := range []string{"golem:session", "golem:missing"} {
+		key, _ := protocol.ReadString(c.expect(protocol.ClientboundPlayCookieRequest), protocol.DefaultStringLength)
+		assert.Equal(t, want, key)
+	}
+	c.send(protocol.ServerboundPlayCookieResponse, cookieResponse("golem:session", []byte("abc"))...)
+	c.send(protocol.ServerboundPlayCookieResponse, cookieResponse("golem:missing", nil)...)
+	assert.Equal(t, answer{[]byte("abc"), true}, <-answers)
+	assert.Equal(t, answer{nil, false}, <-answers)
+
+	// An answer nobody asked for gets the client kicked.
+	c.send(protocol.ServerboundPlayCookieResponse, cookieResponse("golem:session", []byte("abc"))...)
+	c.expect(protocol.ClientboundPlayDisconnect)
+}
+
+// TestScriptCookies checks the cookies global with a plugin that counts
+// visits in a cookie.
+func TestScriptCookies(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "visits")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(`{"name": "visits"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.js"), []byte(`
+events.on("playerJoin", function (e) {
+    cookies.request(e.player, "golem:visits", function (value) {
+        cookies.store(e.player, "golem:visits", String(value === null ? 1 : Number(value) + 1));
+    });
+});
+`), 0o644))
+
+	cfg := DefaultConfig()
+	cfg.Status.Icon = ""
+	plugins := js.NewPluginManager()
+	srv, err := NewServer(cfg, plugins)
+	require.NoError(t, err)
+	_, err = plugins.Load(dir)
+	require.NoError(t, err)
+
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	key, _ := protocol.ReadString(c.expect(protocol.ClientboundPlayCookieRequest), protocol.DefaultStringLength)
+	assert.Equal(t, "golem:visits", key)
+	c.send(protocol.ServerboundPlayCookieResponse, cookieResponse("golem:visits", []byte("41"))...)
+	r := c.expect(protocol.ClientboundPlayStoreCookie)
+	key, _ = protocol.ReadString(r, protocol.DefaultStringLength)
+	payload, err := protocol.ReadByteArray(r, protocol.MaxCookieLength)
+	require.NoError(t, err)
+	assert.Equal(t, "golem:visits", key)
+	assert.Equal(t, "42", string(payload))
+}
+
+// chatKey is a client's chat signing key and the session it announced.
+type chatKey struct {
+	key     *rsa.PrivateKey
+	session protocol.UUID
+}
+
+// startSession generates a key and sends it in a Player Session, as a client
+// does with the key Mojang issued it.
+func (c *testClient) startSession() *chatKey {
+	c.t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(c.t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(c.t, err)
+	k := &chatKey{key: key}
+	_, err = rand.Read(k.session[:])
+	require.NoError(c.t, err)
+	c.send(protocol.ServerboundPlayPlayerSession,
+		protocol.WriteUUID(k.session),
+		protocol.WriteLong(time.Now().Add(time.Hour).UnixMilli()),
+		protocol.WriteByteArray(der),
+		protocol.WriteByteArray(make([]byte, 512)), // not checked offline
+	)
+	return k
+}
+
+// sign signs a message for sender, like the client does.
+func (k *chatKey) sign(t *testing.T, sender protocol.UUID, index int32, salt, timestamp int64, message string, lastSeen [][]byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(protocol.SignedMessagePayload(sender, k.session, index, salt, timestamp, message, lastSeen))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, k.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return sig
+}
+
+// chat sends a Chat Message. signature may be nil; acked is the acknowledged
+// bitset of the last seen update.
+func (c *testClient) chat(message string, timestamp, salt int64, signature []byte, offset int, acked [3]byte) {
+	c.t.Helper()
+	payload := [][]byte{protocol.WriteString(message), protocol.WriteLong(timestamp), protocol.WriteLong(salt), protocol.WriteBool(signature != nil)}
+	if signature != nil {
+		payload = append(payload, signature)
+	}
+	c.send(protocol.ServerboundPlayChatMessage, append(payload, protocol.WriteVarInt(offset), acked[:])...)
+}
+
+// playerChat is a decoded Player Chat Message.
+type playerChat struct {
+	sender    protocol.UUID
+	index     int32
+	signature []byte
+	message   string
+	lastSeen  [][]byte
+}
+
+func (c *testClient) expectPlayerChat() playerChat {
+	c.t.Helper()
+	r := c.expect(protocol.ClientboundPlayPlayerChatMessage)
+	var m playerChat
+	var err error
+	m.sender, err = protocol.ReadUUID(r)
+	require.NoError(c.t, err)
+	m.index, err = protocol.ReadVarInt(r)
+	require.NoError(c.t, err)
+	if signed, _ := protocol.ReadBool(r); signed {
+		m.signature, err = protocol.ReadBytes(r, protocol.MessageSignatureLength)
+		require.NoError(c.t, err)
+	}
+	m.message, err = protocol.ReadString(r, protocol.MaxChatLength)
+	require.NoError(c.t, err)
+	_, _ = protocol.ReadLong(r) // timestamp
+	_, _ = protocol.ReadLong(r) // salt
+	count, err := protocol.ReadVarInt(r)
+	require.NoError(c.t, err)
+	for i := int32(0); i < count; i++ {
+		id, _ := protocol.ReadVarInt(r)
+		require.Zero(c.t, id, "previous messages are sent in full")
+		sig, err := protocol.ReadBytes(r, protocol.MessageSignatureLength)
+		require.NoError(c.t, err)
+		m.lastSeen = append(m.lastSeen, sig)
+	}
+	return m
+}
+
+// expectText reads a packet whose content is a plain string text component,
+// such as a System Chat Message or a Disconnect.
+func (c *testClient) expectText(p protocol.Packet) string {
+	c.t.Helper()
+	tag, err := protocol.ReadNBT(c.expect(p))
+	require.NoError(c.t, err)
+	s, ok := tag.(*nbt.StringTag)
+	require.True(c.t, ok, "%s is not a plain string", tag)
+	return s.Value
+}
+
+func TestSignedChat(t *testing.T) {
+	for _, v := range protocol.Supported() {
+		t.Run(v.Name(), func(t *testing.T) {
+			srv := newTestServer(t, nil)
+			alice := connect(t, srv, v)
+			alice.login("Alice")
+			bob := connect(t, srv, v)
+			bob.login("Bob")
+			alice.expect(protocol.ClientboundPlayPlayerInfoUpdate) // Bob joined
+			aliceID, bobID := protocol.OfflineUUID("Alice"), protocol.OfflineUUID("Bob")
+
+			aliceKey := alice.startSession()
+			alice.expect(protocol.ClientboundPlayPlayerInfoUpdate)
+			bob.expect(protocol.ClientboundPlayPlayerInfoUpdate)
+			bobKey := bob.startSession()
+			alice.expect(protocol.ClientboundPlayPlayerInfoUpdate)
+			bob.expect(protocol.ClientboundPlayPlayerInfoUpdate)
+
+			now := time.Now().UnixMilli()
+			sig := aliceKey.sign(t, aliceID, 0, 1, now, "hello", nil)
+			alice.chat("hello", now, 1, sig, 0, [3]byte{})
+			for _, c := range []*testClient{alice, bob} {
+				m := c.expectPlayerChat()
+				assert.Equal(t, aliceID, m.sender)
+				assert.Equal(t, "hello", m.message)
+				assert.Equal(t, sig, m.signature)
+			}
+
+			// Bob has received one message, which is now the newest of his
+			// window, and signs over it.
+			lastSeen := [][]byte{sig}
+			reply := bobKey.sign(t, bobID, 0, 2, now+1, "hi", lastSeen)
+			bob.chat("hi", now+1, 2, reply, 1, [3]byte{0, 0, 1 << 3})
+			for _, c := range []*testClient{alice, bob} {
+				m := c.expectPlayerChat()
+				assert.Equal(t, bobID, m.sender)
+				assert.Equal(t, int32(0), m.index)
+				assert.Equal(t, lastSeen, m.lastSeen)
+			}
+
+			// A signature that does not match breaks the chain.
+			forged := aliceKey.sign(t, aliceID, 1, 3, now+2, "something else", nil)
+			alice.chat("forged", now+2, 3, forged, 0, [3]byte{})
+			assert.Equal(t, invalidSignatureMessage, alice.expectText(protocol.ClientboundPlayDisconnect))
+			alice.expectClosed()
+		})
+	}
+}
+
+func TestUnsignedChat(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) { cfg.Server.EnforceSecureChat = false })
+	srv.AddChatFilter(func(from, to *Player, message string) bool { return to.Name != "Bob" })
+	alice := connect(t, srv, protocol.Latest())
+	alice.login("Alice")
+	bob := connect(t, srv, protocol.Latest())
+	bob.login("Bob")
+	alice.expect(protocol.ClientboundPlayPlayerInfoUpdate)
+
+	alice.chat("hello", time.Now().UnixMilli(), 0, nil, 0, [3]byte{})
+	m := alice.expectPlayerChat()
+	assert.Equal(t, "hello", m.message)
+	assert.Nil(t, m.signature)
+
+	// The filter hid the message from Bob, so his next packet is the reply
+	// to his own message.
+	bob.chat("hi", time.Now().UnixMilli(), 0, nil, 0, [3]byte{})
+	assert.Equal(t, "hi", alice.expectPlayerChat().message)
+
+	// With secure chat enforced, unsigned messages are refused.
+	srv.cfg.Server.EnforceSecureChat = true
+	alice.chat("insecure", time.Now().UnixMilli(), 0, nil, 0, [3]byte{})
+	assert.Equal(t, missingProfileKeyMessage, alice.expectText(protocol.ClientboundPlaySystemChatMessage))
+
+	alice.chat("§4red", time.Now().UnixMilli(), 0, nil, 0, [3]byte{})
+	assert.Equal(t, illegalCharactersMessage, alice.expectText(protocol.ClientboundPlayDisconnect))
+}
+
+func TestCommands(t *testing.T) {
+	for _, v := range protocol.Supported() {
+		t.Run(v.Name(), func(t *testing.T) {
+			srv := newTestServer(t, nil)
+			c := connect(t, srv, v)
+			c.login("Steve")
+			command := func(line string) {
+				payload := [][]byte{protocol.WriteString(line)}
+				if !v.Has(protocol.FeatureTransfer) {
+					// 1.20.3 always sends the signing fields.
+					payload = append(payload, protocol.WriteLong(time.Now().UnixMilli()), protocol.WriteLong(0),
+						protocol.WriteVarInt(0), protocol.WriteVarInt(0), []byte{0, 0, 0})
+				}
+				c.send(protocol.ServerboundPlayChatCommand, payload...)
+			}
+
+			command("list")
+			assert.Equal(t, "There are 1 of a max of 20 players online: Steve", c.expectText(protocol.ClientboundPlaySystemChatMessage))
+			command("tps")
+			assert.Regexp(t, `^TPS from last 1m, 5m, 15m: [\d.]+, [\d.]+, [\d.]+$`, c.expectText(protocol.ClientboundPlaySystemChatMessage))
+			assert.Regexp(t, `^Tick time from last 5s, 1m: `, c.expectText(protocol.ClientboundPlaySystemChatMessage))
+			assert.Regexp(t, `^Phases from last 5s: network [\d.]+ms, tasks`, c.expectText(protocol.ClientboundPlaySystemChatMessage))
+			command("chunks")
+			assert.Equal(t, "No world is loaded", c.expectText(protocol.ClientboundPlaySystemChatMessage))
+			command("say hello world")
+			assert.Equal(t, "[Steve] hello world", c.expectText(protocol.ClientboundPlaySystemChatMessage))
+			command("say")
+			assert.Equal(t, "Usage: /say <message>", c.expectText(protocol.ClientboundPlaySystemChatMessage))
+			command("nope")
+			assert.Equal(t, `Unknown command "nope". Type "/help" for help.`, c.expectText(protocol.ClientboundPlaySystemChatMessage))
+
+			require.NoError(t, srv.Commands().Register(&Command{
+				Name: "ping",
+				Run: func(sender CommandSender, args []string) error {
+					sender.SendMessage("pong")
+					return nil
+				},
+			}))
+			command("PING")
+			assert.Equal(t, "pong", c.expectText(protocol.ClientboundPlaySystemChatMessage))
+		})
+	}
+}
+
+func TestQueryStats(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.Server.Address = "127.0.0.1:25570"
+		cfg.Status.MOTD = `{"text":"Hi","color":"gold"}`
+	})
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	waitForPlayer(t, srv, "Steve")
+
+	stats := srv.queryStats(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000})
+	require.NotNil(t, stats)
+	assert.Equal(t, "§6Hi", stats.MOTD)
+	assert.Equal(t, protocol.Latest().Name(), stats.Version)
+	assert.Equal(t, "Golem", stats.Plugins)
+	assert.Equal(t, 1, stats.NumPlayers)
+	assert.Equal(t, 20, stats.MaxPlayers)
+	assert.Equal(t, "127.0.0.1", stats.HostIP)
+	assert.Equal(t, 25570, stats.HostPort)
+	assert.Equal(t, []string{"Steve"}, stats.Players)
+	assert.Equal(t, "world", stats.Map)
+
+	srv.cfg.Status.HideOnlinePlayers = true
+	assert.Empty(t, srv.queryStats(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}).Players)
+}
+
+func TestRCON(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.RCON.Password = "secret"
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go srv.ServeRCON(ln)
+
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	waitForPlayer(t, srv, "Steve")
+
+	_, err = rcon.Dial(ln.Addr().String(), "wrong")
+	require.ErrorIs(t, err, rcon.ErrAuthFailed)
+	client, err := rcon.Dial(ln.Addr().String(), "secret")
+	require.NoError(t, err)
+	defer client.Close()
+
+	out, err := client.Exec("list")
+	require.NoError(t, err)
+	assert.Equal(t, "There are 1 of a max of 20 players online: Steve", out)
+	out, err = client.Exec("say hello")
+	require.NoError(t, err)
+	assert.Empty(t, out)
+	assert.Equal(t, "[Rcon] hello", c.expectText(protocol.ClientboundPlaySystemChatMessage))
+	out, err = client.Exec("nope")
+	require.NoError(t, err)
+	assert.Equal(t, `Unknown command "nope". Type "/help" for help.`, out)
+
+	require.NoError(t, srv.Commands().Register(&Command{
+		Name: "colors",
+		Run: func(sender CommandSender, args []string) error {
+			sender.SendMessage("§cred")
+			sender.SendMessage("§aand green")
+			return nil
+		},
+	}))
+	out, err = client.Exec("colors")
+	require.NoError(t, err)
+	assert.Equal(t, "red\nand green", out)
+}
+
+// move sends Set Player Position.
+func (c *testClient) move(x, y, z float64, onGround bool) {
+	c.t.Helper()
+	c.send(protocol.ServerboundPlaySetPlayerPosition,
+		protocol.WriteDouble(x), protocol.WriteDouble(y), protocol.WriteDouble(z), protocol.WriteBool(onGround))
+}
+
+// expectTeleport reads a Synchronize Player Position and returns its position
+// and teleport ID.
+func (c *testClient) expectTeleport() (Location, int32) {
+	c.t.Helper()
+	r := c.expect(protocol.ClientboundPlaySynchronizePlayerPosition)
+	var loc Location
+	var err error
+	for _, f := range []*float64{&loc.X, &loc.Y, &loc.Z} {
+		*f, err = protocol.ReadDouble(r)
+		require.NoError(c.t, err)
+	}
+	for _, f := range []*float32{&loc.Yaw, &loc.Pitch} {
+		*f, err = protocol.ReadFloat(r)
+		require.NoError(c.t, err)
+	}
+	_, err = r.ReadByte()
+	require.NoError(c.t, err)
+	id, err := protocol.ReadVarInt(r)
+	require.NoError(c.t, err)
+	return loc, id
+}
+
+func TestMovement(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.Movement.FlyingKickTime = 0
+	})
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	p := waitForPlayer(t, srv, "Steve")
+	at := func(want Location) {
+		t.Helper()
+		require.Eventually(t, func() bool { return p.Location() == want }, time.Second, time.Millisecond)
+	}
+
+	// Moves before the join teleport is confirmed are ignored.
+	c.move(5, 64, 5, true)
+	c.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(1))
+	c.move(1, 64, 1, true)
+	at(Location{X: 1, Y: 64, Z: 1})
+	c.send(protocol.ServerboundPlaySetPlayerPositionAndRotation,
+		protocol.WriteDouble(2), protocol.WriteDouble(64), protocol.WriteDouble(2),
+		protocol.WriteFloat(270), protocol.WriteFloat(120), protocol.WriteBool(true))
+	at(Location{X: 2, Y: 64, Z: 2, Yaw: -90, Pitch: 90})
+
+	// Moving too quickly sends the player back.
+	c.move(50, 64, 2, true)
+	loc, id := c.expectTeleport()
+	assert.Equal(t, Location{X: 2, Y: 64, Z: 2, Yaw: -90, Pitch: 90}, loc)
+	assert.Equal(t, int32(2), id)
+	c.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(2))
+
+	// So does a move the world does not allow.
+	srv.SetCollider(func(p *Player, from, to Location) Location {
+		if to.Y < 60 {
+			to.Y = 60
+		}
+		return to
+	})
+	c.move(2, 59, 2, false)
+	loc, id = c.expectTeleport()
+	assert.Equal(t, 64.0, loc.Y)
+	assert.Equal(t, int32(3), id)
+	c.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(3))
+	c.move(2, 61, 2, false)
+	at(Location{X: 2, Y: 61, Z: 2, Yaw: -90, Pitch: 90})
+	assert.False(t, p.OnGround())
+
+	// Confirming a teleport twice is invalid.
+	c.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(3))
+	assert.Equal(t, invalidMovementMessage, c.expectText(protocol.ClientboundPlayDisconnect))
+	c.expectClosed()
+}
+
+func TestInvalidMovement(t *testing.T) {
+	srv := newTestServer(t, nil)
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	c.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(1))
+	c.move(math.NaN(), 64, 0, true)
+	assert.Equal(t, invalidMovementMessage, c.expectText(protocol.ClientboundPlayDisconnect))
+	c.expectClosed()
+}
+
+func TestFlyingKick(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.Movement.FlyingKickTime = 50 * time.Millisecond
+	})
+	c := connect(t, srv, protocol.Latest())
+	c.login("Steve")
+	p := waitForPlayer(t, srv, "Steve")
+	c.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(1))
+
+	// Players who may fly are left alone.
+	require.NoError(t, p.SetAllowFlight(true))
+	c.expect(protocol.ClientboundPlayPlayerAbilities)
+	c.send(protocol.ServerboundPlayPlayerAbilities, protocol.WriteByte(protocol.AbilityFlying))
+	c.move(0, 70, 0, false)
+	time.Sleep(60 * time.Millisecond)
+	c.move(0, 70, 0, false)
+	require.Eventually(t, func() bool { return p.Location().Y == 70 }, time.Second, time.Millisecond)
+	assert.True(t, p.Flying())
+
+	require.NoError(t, p.SetAllowFlight(false))
+	assert.False(t, p.Flying())
+	c.expect(protocol.ClientboundPlayPlayerAbilities)
+	// Moves are handled on the next tick, so they must be more than a tick
+	// further apart than the kick time.
+	c.move(0, 70, 0, false)
+	time.Sleep(150 * time.Millisecond)
+	c.move(0, 70, 0, false)
+	assert.Equal(t, flyingMessage, c.expectText(protocol.ClientboundPlayDisconnect))
+	c.expectClosed()
+}
+
+func TestWorldSpawn(t *testing.T) {
+	dir := t.TempDir()
+	reg, err := newServerRegistry()
+	require.NoError(t, err)
+	w, err := world.Open(dir, reg)
+	require.NoError(t, err)
+	w.UpdateLevel(func(l *world.LevelData) { l.Spawn = world.BlockPos{X: 100, Y: 80, Z: -20} })
+	overworld, _ := w.Dimension(world.Overworld)
+	chunk := overworld.NewChunk(world.ChunkPosOf(100, -20))
+	stone, _ := block.Parse("stone")
+	chunk.SetBlock(100, 79, -20, stone)
+	require.NoError(t, overworld.SaveChunk(chunk))
+	// The chunk the player walks into is empty too, so no terrain stops it.
+	require.NoError(t, overworld.SaveChunk(overworld.NewChunk(world.ChunkPosOf(113, -20))))
+	require.NoError(t, w.Close())
+
+	// Players see only the chunk they stand in.
+	srv := newTestServer(t, func(cfg *Config) { cfg.World.ViewDistance = 0 })
+	require.NoError(t, srv.OpenWorld(dir))
+	// The spawn chunks are loaded before players join.
+	assert.Len(t, srv.Chunks().Loaded(), 25)
+
+	c := connect(t, srv, protocol.Latest())
+	c.startLogin("Steve")
+	c.expectLoginSuccess("Steve", protocol.OfflineUUID("Steve"))
+	c.configure()
+	c.send(protocol.ServerboundConfigAcknowledgeFinish)
+	c.state = protocol.Play
+	c.expect(protocol.ClientboundPlayLogin)
+	c.expect(protocol.ClientboundPlayGameEvent)
+	r := c.expect(protocol.ClientboundPlaySetCenterChunk)
+	x, _ := protocol.ReadVarInt(r)
+	z, _ := protocol.ReadVarInt(r)
+	assert.Equal(t, []int32{6, -2}, []int32{x, z})
+	r = c.expect(protocol.ClientboundPlayChunkDataAndUpdateLight)
+	x, _ = protocol.ReadInt(r)
+	z, _ = protocol.ReadInt(r)
+	assert.Equal(t, []int32{6, -2}, []int32{x, z})
+	_, err = protocol.ReadNBT(r)
+	require.NoError(t, err)
+	sections, err := protocol.ReadByteArray(r, 1<<20)
+	require.NoError(t, err)
+	// The saved stone is in the ninth section from the bottom.
+	sr := bytes.NewReader(sections)
+	for i := 0; i < 8; i++ {
+		count, _ := protocol.ReadShort(sr)
+		require.Zero(t, count)
+		sr.Seek(6, io.SeekCurrent) // single-value block states and biomes
+	}
+	count, _ := protocol.ReadShort(sr)
+	assert.Equal(t, int16(1), count)
+	loc, id := c.expectTeleport()
+	assert.Equal(t, Location{X: 100.5, Y: 80, Z: -19.5}, loc)
+	c.expect(protocol.ClientboundPlayCommands)
+	c.expect(protocol.ClientboundPlayPlayerInfoUpdate)
+	c.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(int(id)))
+	waitForPlayer(t, srv, "Steve")
+
+	// Walking into the next chunk moves the view there.
+	c.move(108, 80, -19.5, true)
+	c.move(113, 80, -19.5, true)
+	r = c.expect(protocol.ClientboundPlaySetCenterChunk)
+	x, _ = protocol.ReadVarInt(r)
+	z, _ = protocol.ReadVarInt(r)
+	assert.Equal(t, []int32{7, -2}, []int32{x, z})
+	r = c.expect(protocol.ClientboundPlayUnloadChunk)
+	z, _ = protocol.ReadInt(r)
+	x, _ = protocol.ReadInt(r)
+	assert.Equal(t, []int32{6, -2}, []int32{x, z})
+	r = c.expect(protocol.ClientboundPlayChunkDataAndUpdateLight)
+	x, _ = protocol.ReadInt(r)
+	z, _ = protocol.ReadInt(r)
+	assert.Equal(t, []int32{7, -2}, []int32{x, z})
+
+	require.NoError(t, srv.CloseWorld())
+	assert.FileExists(t, filepath.Join(dir, "level.dat_old"))
+	// The generated spawn chunks were saved.
+	w, err = world.Open(dir, reg)
+	require.NoError(t, err)
+	defer w.Close()
+	overworld, _ = w.Dimension(world.Overworld)
+	_, err = overworld.LoadChunk(world.ChunkPos{X: 8, Z: -4})
+	assert.NoError(t, err)
+}
+
+func TestFlatWorld(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.World.Seed = "golem"
+		cfg.World.LevelType = LevelTypeFlat
+		cfg.World.GeneratorSettings = "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains"
+	})
+	require.NoError(t, srv.OpenWorld(t.TempDir()))
+	defer srv.CloseWorld()
+	sender := &rconSender{}
+	require.True(t, srv.Commands().Dispatch(sender, "chunks"))
+	lines := strings.Split(strings.TrimSuffix(sender.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "Chunks: 25 loaded, 0 cached", lines[0])
+	assert.Regexp(t, `^Queue: \d+ waiting \(peak \d+\), \d+ loading, \d+ saves pending$`, lines[1])
+	assert.Regexp(t, `^Since start: 0 read, 25 generated, 0 saved, 0 failed, [\d.]+ms per chunk$`, lines[2])
+	level := srv.World().Level()
+	assert.Equal(t, int64(98536492), level.WorldGen.Seed)
+	// The query reports the world's name.
+	srv.world.UpdateLevel(func(l *world.LevelData) { l.Name = "Flatland" })
+	assert.Equal(t, "Flatland", srv.queryStats(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}).Map)
+	// New players stand on the grass.
+	assert.Equal(t, Location{X: 0.5, Y: -60, Z: 0.5}, srv.spawn())
+	chunk := srv.Chunks().Chunk(world.ChunkPos{})
+	require.NotNil(t, chunk)
+	grass, _ := block.Parse("grass_block")
+	assert.Equal(t, grass, chunk.Block(0, -61, 0))
+}
+
+func TestWorldCollisions(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.World.LevelType = LevelTypeFlat
+		cfg.World.GeneratorSettings = "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains"
+	})
+	require.NoError(t, srv.OpenWorld(t.TempDir()))
+	defer srv.CloseWorld()
+	stone, _ := block.Parse("stone")
+	water, _ := block.Parse("water")
+	srv.loop.Call(func() {
+		// A wall two blocks tall at x = 2, and water hanging in the air.
+		srv.Chunks().SetBlock(2, -60, 0, stone)
+		srv.Chunks().SetBlock(2, -59, 0, stone)
+		srv.Chunks().SetBlock(0, -55, 4, water)
+	})
+
+	ground := Location{X: 0.5, Y: -60, Z: 0.5}
+	moves := []struct {
+		name     string
+		from, to Location
+		want     Location
+	}{
+		{"walk", ground, Location{X: 1.2, Y: -60, Z: 1.5}, Location{X: 1.2, Y: -60, Z: 1.5}},
+		{"into the ground", ground, Location{X: 0.5, Y: -61, Z: 0.5}, ground},
+		{"fall", Location{X: 0.5, Y: -50, Z: 0.5}, Location{X: 0.5, Y: -70, Z: 0.5}, ground},
+		{"into the wall", ground, Location{X: 3.5, Y: -60, Z: 0.5}, Location{X: 1.7, Y: -60, Z: 0.5}},
+		{"along the wall", Location{X: 1.7, Y: -60, Z: 0.5}, Location{X: 1.7, Y: -60, Z: 3}, Location{X: 1.7, Y: -60, Z: 3}},
+		{"over the wall", Location{X: 0.5, Y: -58, Z: 0.5}, Location{X: 3.5, Y: -58, Z: 0.5}, Location{X: 3.5, Y: -58, Z: 0.5}},
+	}
+	for _, m := range moves {
+		var got Location
+		srv.loop.Call(func() { got = srv.collider()(nil, m.from, m.to) })
+		assert.InDelta(t, m.want.X, got.X, 1e-6, m.name)
+		assert.InDelta(t, m.want.Y, got.Y, 1e-6, m.name)
+		assert.InDelta(t, m.want.Z, got.Z, 1e-6, m.name)
+	}
+
+	// Players standing on, in or next to a block are not floating.
+	for loc, want := range map[Location]bool{
+		ground:                           true,
+		{X: 0.5, Y: -59.5, Z: 0.5}:       true,
+		{X: 0.5, Y: -55, Z: 0.5}:         false,
+		{X: 0.5, Y: -55.5, Z: 4.5}:       true,
+		{X: 1.5, Y: -55, Z: 4.5}:         false,
+		{X: 10000.5, Y: -55, Z: 10000.5}: true,
+	} {
+		var got bool
+		srv.loop.Call(func() { got = srv.blocksAround(loc) })
+		assert.Equal(t, want, got, "%+v", loc)
+	}
+}
+
+func TestTickLoop(t *testing.T) {
+	l := NewTickLoop(100 * time.Millisecond)
+	watchdog, out := io.Pipe()
+	l.watchdogOut = out
+	// order is only touched on the tick goroutine.
+	var order []string
+	for p := TickPhase(0); p < numPhases; p++ {
+		l.Handle(p, func() { order = append(order, p.String()) })
+	}
+	l.Start()
+	defer l.Stop()
+
+	// Submitted work runs first, then the phases in order.
+	l.Call(func() { order = nil })
+	var phases []string
+	l.Call(func() { phases = order })
+	assert.Equal(t, []string{"network", "tasks", "entities", "blocks", "chunks", "autosave"}, phases)
+
+	ran := make(chan int64, 10)
+	var start int64
+	var repeating *Task
+	l.Call(func() {
+		start = l.Tick()
+		l.Schedule(2, 0, func() { ran <- l.Tick() - start })
+		repeating = l.Schedule(0, 3, func() { ran <- l.Tick() - start })
+		// A panicking task does not stop the loop.
+		l.Schedule(0, 0, func() { panic("boom") })
+	})
+	var ticks []int64
+	for len(ticks) < 4 {
+		ticks = append(ticks, <-ran)
+	}
+	assert.Equal(t, []int64{1, 3, 4, 7}, ticks)
+	l.Cancel(repeating)
+	for i := 0; i < 4; i++ {
+		l.Call(func() {})
+	}
+	assert.LessOrEqual(t, len(ran), 1)
+
+	stats := l.Stats()
+	assert.Greater(t, stats.Ticks, int64(10))
+	assert.InDelta(t, TicksPerSecond, stats.TPS1m, 4)
+	assert.Equal(t, stats.TPS1m, stats.TPS15m)
+	assert.Greater(t, stats.MSPT5s, time.Duration(0))
+	assert.GreaterOrEqual(t, stats.MSPTMax, stats.MSPT1m)
+
+	// A hung tick makes the watchdog dump the goroutines.
+	release := make(chan struct{})
+	l.Submit(func() { <-release })
+	line, err := bufio.NewReader(watchdog).ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, line, "longer than the max tick time of 100ms")
+	watchdog.Close()
+	close(release)
+
+	// A max tick time of a few nanoseconds still starts.
+	tiny := NewTickLoop(3 * time.Nanosecond)
+	tiny.watchdogOut = io.Discard
+	tiny.Start()
+	tiny.Call(func() {})
+	tiny.Stop()
+}
+
+// entityMoves are the packets that move and turn entities.
+var entityMoves = []protocol.Packet{
+	protocol.ClientboundPlayUpdateEntityPosition,
+	protocol.ClientboundPlayUpdateEntityPositionAndRotation,
+	protocol.ClientboundPlayUpdateEntityRotation,
+	protocol.ClientboundPlayTeleportEntity,
+	protocol.ClientboundPlaySetHeadRotation,
+}
+
+// expectAfter reads packets until p, failing on any packet but p and skip.
+func (c *testClient) expectAfter(p protocol.Packet, skip ...protocol.Packet) *bytes.Reader {
+	c.t.Helper()
+	for {
+		got, r := c.next()
+		if got == p {
+			return r
+		}
+		require.Contains(c.t, skip, got, "waiting for %s", p)
+	}
+}
+
+// expectSpawn reads a Spawn Entity and the metadata that follows it, and
+// returns the entity's ID, UUID, type and position.
+func (c *testClient) expectSpawn() (int32, protocol.UUID, int32, world.Vec3) {
+	c.t.Helper()
+	r := c.expect(protocol.ClientboundPlaySpawnEntity)
+	id, err := protocol.ReadVarInt(r)
+	require.NoError(c.t, err)
+	uuid, err := protocol.ReadUUID(r)
+	require.NoError(c.t, err)
+	typ, err := protocol.ReadVarInt(r)
+	require.NoError(c.t, err)
+	var pos world.Vec3
+	for _, f := range []*float64{&pos.X, &pos.Y, &pos.Z} {
+		*f, err = protocol.ReadDouble(r)
+		require.NoError(c.t, err)
+	}
+	r = c.expect(protocol.ClientboundPlaySetEntityMetadata)
+	metaID, err := protocol.ReadVarInt(r)
+	require.NoError(c.t, err)
+	assert.Equal(c.t, id, metaID)
+	return id, uuid, typ, pos
+}
+
+// expectRemove reads a Remove Entities, skipping moves, and returns the IDs.
+func (c *testClient) expectRemove() []int32 {
+	c.t.Helper()
+	r := c.expectAfter(protocol.ClientboundPlayRemoveEntities, entityMoves...)
+	n, err := protocol.ReadVarInt(r)
+	require.NoError(c.t, err)
+	ids := make([]int32, n)
+	for i := range ids {
+		ids[i], err = protocol.ReadVarInt(r)
+		require.NoError(c.t, err)
+	}
+	return ids
+}
+
+func TestEntityTracking(t *testing.T) {
+	srv := newTestServer(t, func(cfg *Config) {
+		cfg.World.EntityBroadcastRange = 100
+		cfg.World.ViewDistance = 2 // players see entities 32 blocks away
+		cfg.Movement.FlyingKickTime = 0
+	})
+	alice := connect(t, srv, protocol.Latest())
+	alice.login("Alice")
+	alice.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(1))
+	bob := connect(t, srv, protocol.Oldest())
+	bob.login("Bob")
+	bob.send(protocol.ServerboundPlayConfirmTeleportation, protocol.WriteVarInt(1))
+	alice.expect(protocol.ClientboundPlayPlayerInfoUpdate) // Bob joined
+	aliceID := waitForPlayer(t, srv, "Alice").EntityID()
+	bobID := waitForPlayer(t, srv, "Bob").EntityID()
+	assert.NotEqual(t, aliceID, bobID)
+	playerType := func(c *testClient) int32 {
+		typ, ok := c.version.EntityType(PlayerEntityType)
+		require.True(t, ok)
+		return typ
+	}
+
+	// The players see each other.
+	id, uuid, typ, pos := alice.expectSpawn()
+	assert.Equal(t, []any{bobID, protocol.OfflineUUID("Bob"), playerType(alice)}, []any{id, uuid, typ})
+	assert.Equal(t, world.Vec3{Y: 64}, pos)
+	id, uuid, typ, _ = bob.expectSpawn()
+	assert.Equal(t, []any{aliceID, protocol.OfflineUUID("Alice"), playerType(bob)}, []any{id, uuid, typ})
+
+	// Landing is sent as an absolute position, steps as relative moves.
+	bob.move(1, 64, 0, true)
+	r := alice.expect(protocol.ClientboundPlayTeleportEntity)
+	id, _ = protocol.ReadVarInt(r)
+	x, _ := protocol.ReadDouble(r)
+	assert.Equal(t, []any{bobID, 1.0}, []any{id, x})
+	bob.move(2, 64, 0, true)
+	r = alice.expect(protocol.ClientboundPlayUpdateEntityPosition)
+	id, _ = protocol.ReadVarInt(r)
+	dx, _ := protocol.ReadShort(r)
+	assert.Equal(t, []any{bobID, int16(4096)}, []any{id, dx})
+
+	// Entities spawned on the tick goroutine are sent to both, with the
+	// type IDs of each version.
+	pig := world.NewEntity("minecraft:pig", world.Vec3{X: 3, Y: 64, Z: 3})
+	pig.CustomName = `{"text":"Wilbur"}`
+	srv.Loop().Call(func() { require.NoError(t, srv.Entities().Spawn(pig)) })
+	assert.Greater(t, pig.ID, bobID)
+	for _, c := range []*testClient{alice, bob} {
+		id, uuid, typ, pos := c.expectSpawn()
+		want, _ := c.version.EntityType("minecraft:pig")
+		assert.Equal(t, []any{pig.ID, pig.UUID, want, pig.Pos}, []any{id, uuid, typ, pos})
+	}
+	pigType765, _ := protocol.Oldest().EntityType("minecraft:pig")
+	pigType767, _ := protocol.Latest().EntityType("minecraft:pig")
+	assert.NotEqual(t, pigType765, pigType767)
+
+	// Changed metadata is sent alone, at once.
+	srv.Loop().Call(func() { pig.Glowing = true })
+	for _, c := range []*testClient{alice, bob} {
+		r := c.expect(protocol.ClientboundPlaySetEntityMetadata)
+		id, _ := protocol.ReadVarInt(r)
+		rest, _ := io.ReadAll(r)
+		assert.Equal(t, pig.ID, id)
+		assert.Equal(t, []byte{0, 0, world.EntityGlowing, 0xFF}, rest)
+	}
+
+	srv.Loop().Call(func() { pig.Pos.X, pig.HeadYaw = 3.5, 90 })
+	for _, c := range []*testClient{alice, bob} {
+		r := c.expect(protocol.ClientboundPlayUpdateEntityPosition)
+		id, _ := protocol.ReadVarInt(r)
+		dx, _ := protocol.ReadShort(r)
+		assert.Equal(t, []any{pig.ID, int16(2048)}, []any{id, dx})
+		r = c.expect(protocol.ClientboundPlaySetHeadRotation)
+		id, _ = protocol.ReadVarInt(r)
+		head, _ := r.ReadByte()
+		assert.Equal(t, []any{pig.ID, byte(64)}, []any{id, head})
+	}
+
+	var removed bool
+	srv.Loop().Call(func() { removed = srv.Entities().Remove(pig) })
+	assert.True(t, removed)
+	for _, c := range []*testClient{alice, bob} {
+		assert.Equal(t, []int32{pig.ID}, c.expectRemove())
+	}
+
+	// Walking out of range removes the players for each other, walking
+	// back spawns them again.
+	for _, x := range []float64{9, 18, 27, 36} {
+		bob.move(x, 64, 0, true)
+	}
+	assert.Equal(t, []int32{bobID}, alice.expectRemove())
+	assert.Equal(t, []int32{aliceID}, bob.expectRemove())
+	bob.move(30, 64, 0, true)
+	alice.expectAfter(protocol.ClientboundPlaySpawnEntity, entityMoves...)
+	alice.expect(protocol.ClientboundPlaySetEntityMetadata)
+
+	// A player who leaves is removed before the player list entry.
+	bob.conn.Close()
+	assert.Equal(t, []int32{bobID}, alice.expectRemove())
+	alice.expect(protocol.ClientboundPlayPlayerInfoRemove)
+}
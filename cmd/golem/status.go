@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"io/fs"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+
+	js "github.com/Advik-B/Golem/javascript"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/text"
+)
+
+// Status is the data behind a server list entry. The modern JSON ping renders
+// all of it; older ping formats use what they can.
+type Status struct {
+	VersionName string
+	Protocol    int32
+	// MOTD is either plain text, which may use § formatting codes, or a JSON
+	// chat component.
+	MOTD          string
+	MaxPlayers    int
+	OnlinePlayers int
+	// Sample is the list shown when hovering the player count.
+	Sample []SamplePlayer
+	// HidePlayerCount leaves the players object out, so clients show "???".
+	HidePlayerCount bool
+	// Favicon is a data URL of a 64x64 PNG, or empty.
+	Favicon            string
+	EnforcesSecureChat bool
+}
+
+// SamplePlayer is one entry of the player sample.
+type SamplePlayer struct {
+	Name string
+	ID   protocol.UUID
+}
+
+// MarshalJSON encodes the entry with its UUID in the dashed form clients expect.
+func (p SamplePlayer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"name": p.Name, "id": p.ID.String()})
+}
+
+// Description returns the MOTD as a chat component.
+func (s *Status) Description() json.RawMessage {
+	b, _ := json.Marshal(s.component())
+	return b
+}
+
+// component parses the MOTD. Text that is not a valid JSON component is
+// shown as it is.
+func (s *Status) component() text.Component {
+	motd := strings.TrimSpace(s.MOTD)
+	if strings.HasPrefix(motd, "{") || strings.HasPrefix(motd, "[") {
+		var c text.Component
+		if err := json.Unmarshal([]byte(motd), &c); err == nil {
+			return c
+		}
+	}
+	return text.Plain(s.MOTD)
+}
+
+// LegacyMOTD returns the MOTD for the legacy ping, which only understands §
+// codes. A JSON component keeps its colors and formatting but loses its
+// events.
+func (s *Status) LegacyMOTD() string {
+	c := s.component()
+	return c.Legacy(text.SectionSign)
+}
+
+// MarshalJSON encodes the Status Response payload.
+func (s *Status) MarshalJSON() ([]byte, error) {
+	type version struct {
+		Name     string `json:"name"`
+		Protocol int32  `json:"protocol"`
+	}
+	type players struct {
+		Max    int            `json:"max"`
+		Online int            `json:"online"`
+		Sample []SamplePlayer `json:"sample,omitempty"`
+	}
+	resp := struct {
+		Version            version         `json:"version"`
+		Players            *players        `json:"players,omitempty"`
+		Description        json.RawMessage `json:"description"`
+		Favicon            string          `json:"favicon,omitempty"`
+		EnforcesSecureChat bool            `json:"enforcesSecureChat"`
+	}{
+		Version:            version{s.VersionName, s.Protocol},
+		Description:        s.Description(),
+		Favicon:            s.Favicon,
+		EnforcesSecureChat: s.EnforcesSecureChat,
+	}
+	if !s.HidePlayerCount {
+		resp.Players = &players{Max: s.MaxPlayers, Online: s.OnlinePlayers, Sample: s.Sample}
+	}
+	return json.Marshal(resp)
+}
+
+// StatusProvider builds server list responses from the configuration and the
+// live player list, and lets plugins change them through the serverListPing
+// event.
+type StatusProvider struct {
+	srv     *Server
+	favicon string
+}
+
+// NewStatusProvider loads the configured server icon. A missing icon is not an
+// error, but one that is not a 64x64 PNG is.
+func NewStatusProvider(srv *Server) (*StatusProvider, error) {
+	p := &StatusProvider{srv: srv}
+	if path := srv.cfg.Status.Icon; path != "" {
+		favicon, err := loadFavicon(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		p.favicon = favicon
+	}
+	return p, nil
+}
+
+// loadFavicon reads a PNG and returns it as a data URL.
+func loadFavicon(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	img, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if img.Width != 64 || img.Height != 64 {
+		return "", fmt.Errorf("%s: server icon must be 64x64, not %dx%d", path, img.Width, img.Height)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Status builds the status for a client connecting from addr to hostname.
+// The version echoes the client's protocol when it is supported, so the
+// client does not flag the server as incompatible. It returns nil if a plugin
+// cancelled the ping.
+func (p *StatusProvider) Status(addr net.Addr, hostname string, clientProtocol int32) *Status {
+	cfg := p.srv.cfg
+	version, ok := protocol.Lookup(clientProtocol)
+	if !ok {
+		version = protocol.Latest()
+	}
+	players := p.srv.Players()
+	status := &Status{
+		VersionName:        version.Name(),
+		Protocol:           version.Protocol,
+		MOTD:               cfg.Status.MOTD,
+		MaxPlayers:         cfg.Server.MaxPlayers,
+		OnlinePlayers:      len(players),
+		HidePlayerCount:    cfg.Status.HidePlayerCount,
+		Favicon:            p.favicon,
+		EnforcesSecureChat: cfg.Server.EnforceSecureChat,
+	}
+	if !cfg.Status.HideOnlinePlayers {
+		rand.Shuffle(len(players), func(i, j int) { players[i], players[j] = players[j], players[i] })
+		for _, player := range players {
+			if len(status.Sample) == cfg.Status.SampleSize {
+				break
+			}
+			status.Sample = append(status.Sample, SamplePlayer{Name: player.Name, ID: player.UUID})
+		}
+	}
+
+	if p.srv.plugins == nil || !p.srv.plugins.Events.Has("serverListPing") {
+		return status
+	}
+	if !p.fireEvent(status, addr, hostname) {
+		return nil
+	}
+	return status
+}
+
+// fireEvent lets plugins edit status through the serverListPing event. It
+// returns false if the ping was cancelled.
+func (p *StatusProvider) fireEvent(status *Status, addr net.Addr, hostname string) bool {
+	sample := make([]interface{}, len(status.Sample))
+	for i, s := range status.Sample {
+		sample[i] = s.Name
+	}
+	event := js.Event{
+		"address":         addr.String(),
+		"hostname":        hostname,
+		"protocol":        int(status.Protocol),
+		"versionName":     status.VersionName,
+		"motd":            status.MOTD,
+		"maxPlayers":      status.MaxPlayers,
+		"onlinePlayers":   status.OnlinePlayers,
+		"hidePlayerCount": status.HidePlayerCount,
+		"sample":          &sample, // a pointer so that push() is seen from Go
+		"favicon":         status.Favicon,
+	}
+	p.srv.plugins.Events.Fire("serverListPing", event)
+	if event.Cancelled() {
+		return false
+	}
+
+	status.VersionName = event.String("versionName", status.VersionName)
+	status.Protocol = int32(event.Int("protocol", int(status.Protocol)))
+	status.MOTD = event.String("motd", status.MOTD)
+	status.MaxPlayers = event.Int("maxPlayers", status.MaxPlayers)
+	status.OnlinePlayers = event.Int("onlinePlayers", status.OnlinePlayers)
+	status.HidePlayerCount = event.Bool("hidePlayerCount", status.HidePlayerCount)
+	status.Favicon = event.String("favicon", status.Favicon)
+
+	// Keep the real UUIDs of players that are still in the sample; names a
+	// plugin added get the nil UUID, as vanilla does for its anonymous entries.
+	ids := make(map[string]protocol.UUID, len(status.Sample))
+	for _, s := range status.Sample {
+		ids[s.Name] = s.ID
+	}
+	status.Sample = status.Sample[:0]
+	for _, name := range event.Strings("sample") {
+		status.Sample = append(status.Sample, SamplePlayer{Name: name, ID: ids[name]})
+	}
+	return true
+}
+
+// handleStatus answers the server list ping: a Status Request is answered with
+// the status JSON and a Ping with a Pong carrying the same payload, after which
+// the connection is closed.
+func (s *session) handleStatus(raw *protocol.RawPacket) error {
+	switch raw.Packet {
+	case protocol.ServerboundStatusRequest:
+		status := s.srv.status.Status(s.conn.RemoteAddr(), s.serverAddress, s.clientProtocol)
+		if status == nil {
+			return s.conn.Close()
+		}
+		resp, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		return s.conn.WritePacket(protocol.ClientboundStatusResponse, protocol.WriteString(string(resp)))
+
+	case protocol.ServerboundStatusPing:
+		var ping protocol.StatusPing
+		if err := raw.Decode(&ping); err != nil {
+			return err
+		}
+		if err := s.conn.WritePacket(protocol.ClientboundStatusPong, protocol.WriteLong(ping.Payload)); err != nil {
+			return err
+		}
+		s.conn.Disconnect("")
+	}
+	return nil
+}
+
+// answerLegacy answers a pre-Netty server list ping from the same status data
+// as the modern ping, then closes the connection.
+func (p *StatusProvider) answerLegacy(conn *protocol.Conn, ping *protocol.LegacyPing) {
+	status := p.Status(conn.RemoteAddr(), ping.Host, int32(ping.Protocol))
+	if status == nil {
+		conn.Close()
+		return
+	}
+	online := status.OnlinePlayers
+	if status.HidePlayerCount {
+		// The legacy formats have no way to hide the counts, and clients
+		// reject anything that is not a number.
+		online = 0
+	}
+	conn.DisconnectLegacy(protocol.LegacyPingResponse(ping.Format, status.VersionName, status.LegacyMOTD(), online, status.MaxPlayers))
+}
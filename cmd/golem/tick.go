@@ -0,0 +1,469 @@
+package main
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"io"
+	"log"
+	"runtime/debug"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// TicksPerSecond is the game's tick rate.
+	TicksPerSecond = 20
+	// tickInterval is the time a tick is given.
+	tickInterval = time.Second / TicksPerSecond
+	// maxTickLag is how far the loop falls behind before it gives up
+	// catching up on the missed ticks, like vanilla's "Can't keep up!".
+	maxTickLag = 2 * time.Second
+)
+
+// TickPhase is a step of every tick. Phases run in the order they are
+// declared.
+type TickPhase int
+
+const (
+	// PhaseNetwork runs the work connections submitted, such as moving a
+	// player's view, on the tick goroutine.
+	PhaseNetwork TickPhase = iota
+	// PhaseTasks runs the scheduled tasks that are due.
+	PhaseTasks
+	// PhaseEntities moves the entities.
+	PhaseEntities
+	// PhaseBlocks ticks blocks.
+	PhaseBlocks
+	// PhaseChunks hands over loaded chunks and sends them and their light.
+	PhaseChunks
+	// PhaseAutosave saves the world every autosave interval.
+	PhaseAutosave
+	numPhases
+)
+
+func (p TickPhase) String() string {
+	switch p {
+	case PhaseNetwork:
+		return "network"
+	case PhaseTasks:
+		return "tasks"
+	case PhaseEntities:
+		return "entities"
+	case PhaseBlocks:
+		return "blocks"
+	case PhaseChunks:
+		return "chunks"
+	case PhaseAutosave:
+		return "autosave"
+	}
+	return fmt.Sprintf("TickPhase(%d)", int(p))
+}
+
+// Task is a function scheduled on the tick loop.
+type Task struct {
+	fn        func()
+	due       int64
+	period    int64
+	seq       int64
+	index     int
+	cancelled bool
+}
+
+// taskQueue orders tasks by the tick they are due, then by when they were
+// scheduled.
+type taskQueue []*Task
+
+func (q taskQueue) Len() int { return len(q) }
+func (q taskQueue) Less(i, j int) bool {
+	if q[i].due != q[j].due {
+		return q[i].due < q[j].due
+	}
+	return q[i].seq < q[j].seq
+}
+func (q taskQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+func (q *taskQueue) Push(x any) {
+	t := x.(*Task)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+func (q *taskQueue) Pop() any {
+	old := *q
+	t := old[len(old)-1]
+	*q = old[:len(old)-1]
+	t.index = -1
+	return t
+}
+
+// TickStats are the rolling averages of the tick loop, as /tps shows them.
+type TickStats struct {
+	// Ticks is the number of ticks run since the loop started.
+	Ticks int64
+	// TPS1m, TPS5m and TPS15m are the ticks per second over the last one,
+	// five and fifteen minutes, at most TicksPerSecond.
+	TPS1m, TPS5m, TPS15m float64
+	// MSPT5s and MSPT1m are the average time a tick took over the last five
+	// seconds and minute, and MSPTMax the longest tick of the last minute.
+	MSPT5s, MSPT1m, MSPTMax time.Duration
+	// Phases is the average time each phase took over the last five
+	// seconds.
+	Phases [numPhases]time.Duration
+}
+
+// tickRecord is what the loop remembers of a tick.
+type tickRecord struct {
+	start  time.Time
+	took   time.Duration
+	phases [numPhases]time.Duration
+}
+
+// tickHistory is how many ticks the rolling averages reach back: fifteen
+// minutes.
+const tickHistory = 15 * 60 * TicksPerSecond
+
+// TickLoop runs the game at TicksPerSecond. Every tick runs the phases in
+// order on one goroutine, which owns the game state: connections hand it
+// work with Submit or Call rather than changing the state themselves. When
+// a tick runs long the next ones start at once to catch up, unless the
+// loop is more than two seconds behind.
+type TickLoop struct {
+	// maxTickTime is how long a tick may run before the watchdog dumps
+	// the goroutine stacks; 0 turns the watchdog off.
+	maxTickTime time.Duration
+	// watchdogOut receives the stacks; it is the log's output by default.
+	watchdogOut io.Writer
+
+	mu       sync.Mutex
+	handlers [numPhases][]func()
+	input    []func()
+	tasks    taskQueue
+	seq      int64
+	history  []tickRecord
+	next     int // index of the next record in history
+	ticks    int64
+
+	// tickStart is the start of the running tick in Unix nanoseconds, or
+	// 0 between ticks; the watchdog reads it.
+	tickStart atomic.Int64
+	tick      atomic.Int64
+	// running is set from Start until Stop ran the last submitted work;
+	// work submitted while it is not runs at once, one at a time under
+	// inline.
+	running  atomic.Bool
+	stopping bool
+	inline   sync.Mutex
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewTickLoop returns a tick loop whose watchdog reports ticks running
+// longer than maxTickTime. Start runs it.
+func NewTickLoop(maxTickTime time.Duration) *TickLoop {
+	return &TickLoop{
+		maxTickTime: maxTickTime,
+		history:     make([]tickRecord, 0, tickHistory),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Handle adds a function to a phase. Functions of a phase run in the order
+// they were added.
+func (l *TickLoop) Handle(phase TickPhase, fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[phase] = append(l.handlers[phase], fn)
+}
+
+// Submit queues fn to run in the network phase of the next tick. Once the
+// loop stopped, fn runs at once, though never alongside other work
+// submitted after the stop, so it must not submit more itself.
+func (l *TickLoop) Submit(fn func()) {
+	l.mu.Lock()
+	if l.running.Load() {
+		l.input = append(l.input, fn)
+		l.mu.Unlock()
+		return
+	}
+	l.mu.Unlock()
+	l.inline.Lock()
+	defer l.inline.Unlock()
+	fn()
+}
+
+// Call runs fn in the network phase of the next tick and waits for it;
+// once the loop stopped, fn runs at once. It must not be called from the
+// tick goroutine, which would wait for itself.
+func (l *TickLoop) Call(fn func()) {
+	finished := make(chan struct{})
+	l.Submit(func() {
+		defer close(finished)
+		fn()
+	})
+	<-finished
+}
+
+// Schedule runs fn after delay ticks, 0 meaning the next tick, and then
+// every period ticks if period is positive, until the task is cancelled.
+func (l *TickLoop) Schedule(delay, period int64, fn func()) *Task {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	t := &Task{fn: fn, due: l.tick.Load() + 1 + max(delay, 0), period: period, seq: l.seq}
+	heap.Push(&l.tasks, t)
+	return t
+}
+
+// Cancel stops a scheduled task from running again.
+func (l *TickLoop) Cancel(t *Task) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t.cancelled = true
+	if t.index >= 0 && t.index < len(l.tasks) && l.tasks[t.index] == t {
+		heap.Remove(&l.tasks, t.index)
+	}
+}
+
+// Tick returns the number of the running or last tick.
+func (l *TickLoop) Tick() int64 { return l.tick.Load() }
+
+// Start runs the loop on a new goroutine until Stop.
+func (l *TickLoop) Start() {
+	l.running.Store(true)
+	go l.run()
+	if l.maxTickTime > 0 {
+		go l.watchdog()
+	}
+}
+
+// Stop waits for the running tick to finish and stops the loop. The work
+// submitted for the next tick, and while it stops, runs before Stop
+// returns.
+func (l *TickLoop) Stop() {
+	l.mu.Lock()
+	if !l.running.Load() || l.stopping {
+		l.mu.Unlock()
+		return
+	}
+	l.stopping = true
+	l.mu.Unlock()
+	close(l.stop)
+	<-l.done
+	for {
+		l.mu.Lock()
+		work := l.input
+		l.input = nil
+		if len(work) == 0 {
+			l.running.Store(false)
+			l.mu.Unlock()
+			return
+		}
+		l.mu.Unlock()
+		for _, fn := range work {
+			l.runSafe(PhaseNetwork, fn)
+		}
+	}
+}
+
+func (l *TickLoop) run() {
+	defer close(l.done)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	next := time.Now()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-timer.C:
+		}
+		start := time.Now()
+		if behind := start.Sub(next); behind > maxTickLag {
+			log.Printf("Can't keep up! Is the server overloaded? Running %dms or %d ticks behind",
+				behind.Milliseconds(), behind/tickInterval)
+			next = start
+		}
+		l.runTick(start)
+		next = next.Add(tickInterval)
+		timer.Reset(time.Until(next))
+	}
+}
+
+// runTick runs the phases of one tick and records how long they took.
+func (l *TickLoop) runTick(start time.Time) {
+	l.tick.Add(1)
+	l.tickStart.Store(start.UnixNano())
+	rec := tickRecord{start: start}
+	phaseStart := start
+	for phase := TickPhase(0); phase < numPhases; phase++ {
+		l.runPhase(phase)
+		now := time.Now()
+		rec.phases[phase] = now.Sub(phaseStart)
+		phaseStart = now
+	}
+	rec.took = phaseStart.Sub(start)
+	l.tickStart.Store(0)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.history) < tickHistory {
+		l.history = append(l.history, rec)
+	} else {
+		l.history[l.next] = rec
+	}
+	l.next = (l.next + 1) % tickHistory
+	l.ticks++
+}
+
+func (l *TickLoop) runPhase(phase TickPhase) {
+	l.mu.Lock()
+	var work []func()
+	switch phase {
+	case PhaseNetwork:
+		work, l.input = l.input, nil
+	case PhaseTasks:
+		tick := l.tick.Load()
+		for len(l.tasks) > 0 && l.tasks[0].due <= tick {
+			t := heap.Pop(&l.tasks).(*Task)
+			work = append(work, func() {
+				// An earlier task may have cancelled it.
+				l.mu.Lock()
+				cancelled := t.cancelled
+				l.mu.Unlock()
+				if !cancelled {
+					t.fn()
+				}
+			})
+			if t.period > 0 {
+				t.due = tick + t.period
+				heap.Push(&l.tasks, t)
+			}
+		}
+	}
+	work = append(work, l.handlers[phase]...)
+	l.mu.Unlock()
+	for _, fn := range work {
+		l.runSafe(phase, fn)
+	}
+}
+
+// runSafe runs fn, so a panic in one task or handler, such as a plugin's,
+// costs that function and not the loop.
+func (l *TickLoop) runSafe(phase TickPhase, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in the %s phase of tick %d: %v\n%s", phase, l.tick.Load(), r, debug.Stack())
+		}
+	}()
+	fn()
+}
+
+// watchdog dumps the goroutine stacks once when a tick runs longer than
+// maxTickTime, to show where it hangs.
+func (l *TickLoop) watchdog() {
+	// A max tick time of a few nanoseconds would make the interval zero,
+	// on which NewTicker panics.
+	check := time.NewTicker(max(min(l.maxTickTime/4, time.Second), time.Millisecond))
+	defer check.Stop()
+	var reported int64
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-check.C:
+		}
+		start := l.tickStart.Load()
+		if start == 0 || start == reported {
+			continue
+		}
+		if took := time.Since(time.Unix(0, start)); took > l.maxTickTime {
+			reported = start
+			out := l.watchdogOut
+			if out == nil {
+				out = log.Writer()
+			}
+			var stacks bytes.Buffer
+			pprof.Lookup("goroutine").WriteTo(&stacks, 2)
+			fmt.Fprintf(out, "Tick %d has been running for %s, longer than the max tick time of %s. Goroutines:\n%s",
+				l.tick.Load(), took.Round(time.Millisecond), l.maxTickTime, stacks.Bytes())
+		}
+	}
+}
+
+// Stats returns the rolling averages of the loop.
+func (l *TickLoop) Stats() TickStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	s := TickStats{
+		Ticks:  l.ticks,
+		TPS1m:  l.tps(now, time.Minute),
+		TPS5m:  l.tps(now, 5*time.Minute),
+		TPS15m: l.tps(now, 15*time.Minute),
+	}
+	var sum5s, sum1m time.Duration
+	var n5s, n1m int
+	l.eachRecent(now, time.Minute, func(r *tickRecord) {
+		sum1m += r.took
+		n1m++
+		s.MSPTMax = max(s.MSPTMax, r.took)
+		if now.Sub(r.start) <= 5*time.Second {
+			sum5s += r.took
+			n5s++
+			for p, d := range r.phases {
+				s.Phases[p] += d
+			}
+		}
+	})
+	if n1m > 0 {
+		s.MSPT1m = sum1m / time.Duration(n1m)
+	}
+	if n5s > 0 {
+		s.MSPT5s = sum5s / time.Duration(n5s)
+		for p := range s.Phases {
+			s.Phases[p] /= time.Duration(n5s)
+		}
+	}
+	return s
+}
+
+// tps returns the ticks per second over the window before now. Before the
+// loop ran that long it is measured from the first tick.
+func (l *TickLoop) tps(now time.Time, window time.Duration) float64 {
+	n := 0
+	var first time.Time
+	l.eachRecent(now, window, func(r *tickRecord) {
+		n++
+		if first.IsZero() || r.start.Before(first) {
+			first = r.start
+		}
+	})
+	if n == 0 {
+		return 0
+	}
+	span := now.Sub(first)
+	if span < window {
+		// The window is not full yet: n ticks started in span, plus the
+		// time until the next.
+		span += tickInterval
+	} else {
+		span = window
+	}
+	return min(float64(n)/span.Seconds(), TicksPerSecond)
+}
+
+// eachRecent calls fn with the ticks that started within window before now.
+// l.mu must be held.
+func (l *TickLoop) eachRecent(now time.Time, window time.Duration, fn func(r *tickRecord)) {
+	for i := 1; i <= len(l.history); i++ {
+		r := &l.history[(l.next-i+len(l.history))%len(l.history)]
+		if now.Sub(r.start) > window {
+			return
+		}
+		fn(r)
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/bits"
+	"path/filepath"
+
+	"github.com/Advik-B/Golem/block"
+	"github.com/Advik-B/Golem/nbt"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/world"
+)
+
+// OpenWorld opens or creates the world in dir, which players then join, and
+// loads the spawn chunks. Without a world they join an empty one that is not
+// saved.
+func (s *Server) OpenWorld(dir string) error {
+	cfg := s.cfg.World
+	level := world.NewLevelData(filepath.Base(dir), world.ParseSeed(cfg.Seed))
+	if cfg.LevelType == LevelTypeFlat {
+		flat, err := world.ParseFlatPreset(s.registry, cfg.GeneratorSettings)
+		if err != nil {
+			return fmt.Errorf("world.generator-settings: %w", err)
+		}
+		settings := level.WorldGen.Dimensions[world.Overworld]
+		settings.Generator = flat.ToNBT()
+		level.WorldGen.Dimensions[world.Overworld] = settings
+	}
+	w, err := world.OpenWith(dir, s.registry, level)
+	if err != nil {
+		return err
+	}
+	overworld, ok := w.Dimension(world.Overworld)
+	if !ok {
+		w.Close()
+		return fmt.Errorf("the world has no overworld")
+	}
+	// The autosave phase saves the chunks, with the level data.
+	chunks := world.NewChunkManager(overworld, world.ManagerConfig{
+		Workers:   cfg.ChunkWorkers,
+		MaxCached: cfg.CachedChunks,
+	})
+	chunks.OnLoad(s.chunkLoaded)
+	chunks.OnLightChange(s.chunkRelit)
+	level = w.Level()
+	spawn := world.ChunkPosOf(level.Spawn.X, level.Spawn.Z)
+	chunks.AddTicket(world.TicketSpawn, spawn, level.GameRuleInt("spawnChunkRadius"))
+	log.Printf("Preparing spawn area")
+	chunks.TickUntilLoaded()
+
+	// From here on the chunks belong to the tick goroutine.
+	s.loop.Call(func() { s.world, s.chunks = w, chunks })
+	return nil
+}
+
+// tickChunks is the chunk phase: it hands over the chunks that finished
+// loading, which sends them to the players who see them.
+func (s *Server) tickChunks() {
+	if s.chunks != nil {
+		s.chunks.Tick()
+	}
+}
+
+// autosave is the autosave phase: every world.autosave-interval it hands
+// the changed chunks to be saved and writes the level data.
+func (s *Server) autosave() {
+	every := int64(s.cfg.World.AutosaveInterval / tickInterval)
+	if s.world == nil || every <= 0 || s.loop.Tick()%every != 0 {
+		return
+	}
+	s.chunks.SaveAll()
+	if err := s.world.Save(); err != nil {
+		log.Printf("Could not save the world: %v", err)
+	}
+}
+
+// CloseWorld stops the tick loop, saves every changed chunk and the level
+// data and closes the world. Calling it again does nothing.
+func (s *Server) CloseWorld() error {
+	s.closeOnce.Do(func() {
+		s.loop.Stop()
+		if s.world == nil {
+			return
+		}
+		s.closeErr = s.chunks.Close()
+		if err := s.world.Close(); s.closeErr == nil {
+			s.closeErr = err
+		}
+	})
+	return s.closeErr
+}
+
+// World returns the world players join, or nil.
+func (s *Server) World() *world.World { return s.world }
+
+// Chunks returns the chunk manager of the overworld, or nil without a world.
+func (s *Server) Chunks() *world.ChunkManager { return s.chunks }
+
+// levelName names the world, as the query map does: the name in level.dat,
+// or the name of the world directory before a world is open.
+func (s *Server) levelName() string {
+	if s.world != nil {
+		if name := s.world.Level().Name; name != "" {
+			return name
+		}
+	}
+	return filepath.Base(s.cfg.World.Directory)
+}
+
+// spawn returns where players join.
+func (s *Server) spawn() Location {
+	if s.world == nil {
+		return spawnLocation
+	}
+	level := s.world.Level()
+	return Location{
+		X:   float64(level.Spawn.X) + 0.5,
+		Y:   float64(level.Spawn.Y),
+		Z:   float64(level.Spawn.Z) + 0.5,
+		Yaw: level.SpawnAngle,
+	}
+}
+
+// serverRegistry names the IDs of the server's chunks. Block states are
+// those of the block package and biome IDs the positions in the newest
+// version's biome registry.
+type serverRegistry struct {
+	biomes []string
+}
+
+func newServerRegistry() (*serverRegistry, error) {
+	regs, err := protocol.Latest().Registries()
+	if err != nil {
+		return nil, err
+	}
+	biomes, ok := regs.Get("minecraft:worldgen/biome")
+	if !ok {
+		return nil, fmt.Errorf("no biome registry")
+	}
+	r := &serverRegistry{}
+	for _, e := range biomes.Entries {
+		r.biomes = append(r.biomes, e.Name)
+	}
+	return r, nil
+}
+
+func (r *serverRegistry) BlockState(id uint32) (*nbt.CompoundTag, bool) { return block.ToNBT(id) }
+
+func (r *serverRegistry) BlockStateID(state *nbt.CompoundTag) (uint32, bool) {
+	id, err := block.FromNBT(state)
+	return id, err == nil
+}
+
+func (r *serverRegistry) IsAir(id uint32) bool { return block.IsAir(id) }
+
+func (r *serverRegistry) LightEmission(id uint32) int { return block.LightEmission(id) }
+
+func (r *serverRegistry) LightOpacity(id uint32) int { return block.LightOpacity(id) }
+
+func (r *serverRegistry) Biome(id uint32) (string, bool) {
+	if int(id) >= len(r.biomes) {
+		return "", false
+	}
+	return r.biomes[id], true
+}
+
+func (r *serverRegistry) BiomeID(name string) (uint32, bool) {
+	for i, b := range r.biomes {
+		if b == name {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+// networkIDs returns how the server's chunks are sent to clients of v.
+func (r *serverRegistry) networkIDs(v *protocol.Version) (*world.NetworkIDs, error) {
+	regs, err := v.Registries()
+	if err != nil {
+		return nil, err
+	}
+	biomes, ok := regs.Get("minecraft:worldgen/biome")
+	if !ok {
+		return nil, fmt.Errorf("no biome registry for %s", v)
+	}
+	states, toNetworkState, ok := block.ProtocolStates(v.Protocol)
+	if !ok {
+		return nil, fmt.Errorf("no block states for %s", v)
+	}
+	// Biomes the client does not know show as its first one.
+	toNetwork := make([]uint32, len(r.biomes))
+	for i, name := range r.biomes {
+		id, _ := biomes.Index(name)
+		toNetwork[i] = uint32(id)
+	}
+	return &world.NetworkIDs{
+		BlockState: toNetworkState,
+		Biome: func(id uint32) uint32 {
+			if int(id) < len(toNetwork) {
+				return toNetwork[id]
+			}
+			return 0
+		},
+		BlockStateBits:  bits.Len(uint(states - 1)),
+		BiomeBits:       bits.Len(uint(len(biomes.Entries) - 1)),
+		BlockEntityType: v.BlockEntityType,
+	}, nil
+}
+
+// dimensionHeight returns the lowest block Y and the height of a dimension
+// type, which decide how many sections the client expects in a chunk.
+func dimensionHeight(regs *protocol.Registries, dimension string) (minY, height int, err error) {
+	types, ok := regs.Get("minecraft:dimension_type")
+	if !ok {
+		return 0, 0, fmt.Errorf("no dimension type registry")
+	}
+	i, ok := types.Index(dimension)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown dimension type %s", dimension)
+	}
+	element := types.Entries[i].Element
+	y, okY := element.GetInt("min_y")
+	h, okH := element.GetInt("height")
+	if !okY || !okH {
+		return 0, 0, fmt.Errorf("dimension type %s has no height", dimension)
+	}
+	return int(y), int(h), nil
+}
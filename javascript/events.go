@@ -0,0 +1,121 @@
+package js
+
+import (
+	"log"
+	"sync"
+)
+
+// Event is the payload handed to event listeners. JavaScript listeners see it
+// as a plain object, and any field they assign is visible to the server once
+// Fire returns, which is how plugins override server behaviour.
+type Event map[string]interface{}
+
+// Cancel marks the event as cancelled.
+func (e Event) Cancel() { e["cancelled"] = true }
+
+// Cancelled reports whether a listener cancelled the event.
+func (e Event) Cancelled() bool {
+	c, _ := e["cancelled"].(bool)
+	return c
+}
+
+// Listener handles a fired event.
+type Listener func(event Event)
+
+// EventManager dispatches named server events to the listeners registered by
+// Go subsystems and plugins.
+type EventManager struct {
+	mu        sync.RWMutex
+	listeners map[string][]Listener
+}
+
+// NewEventManager creates an empty event manager.
+func NewEventManager() *EventManager {
+	return &EventManager{listeners: make(map[string][]Listener)}
+}
+
+// On registers a listener for the named event.
+func (m *EventManager) On(name string, l Listener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners[name] = append(m.listeners[name], l)
+}
+
+// Has reports whether anything listens for the named event, so callers can
+// skip building payloads nobody will see.
+func (m *EventManager) Has(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.listeners[name]) > 0
+}
+
+// Fire calls every listener of the named event in registration order. A
+// panicking listener is logged and does not stop the others.
+func (m *EventManager) Fire(name string, event Event) {
+	m.mu.RLock()
+	listeners := m.listeners[name]
+	m.mu.RUnlock()
+
+	for _, l := range listeners {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Event listener for %s panicked: %v", name, r)
+				}
+			}()
+			l(event)
+		}()
+	}
+}
+
+// String returns the string field key, or def if it is missing or not a string.
+func (e Event) String(key, def string) string {
+	if s, ok := e[key].(string); ok {
+		return s
+	}
+	return def
+}
+
+// Bool returns the boolean field key, or def if it is missing or not a bool.
+func (e Event) Bool(key string, def bool) bool {
+	if b, ok := e[key].(bool); ok {
+		return b
+	}
+	return def
+}
+
+// Int returns the numeric field key as an int. JavaScript numbers come back as
+// int64 or float64 depending on their value, so both are accepted.
+func (e Event) Int(key string, def int) int {
+	switch n := e[key].(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return def
+}
+
+// Strings returns the list field key as strings, skipping other elements.
+// Lists that JavaScript should be able to grow must be stored as a pointer to
+// a slice; both forms are accepted.
+func (e Event) Strings(key string) []string {
+	value := e[key]
+	if p, ok := value.(*[]interface{}); ok {
+		value = *p
+	}
+	var out []string
+	switch list := value.(type) {
+	case []string:
+		out = append(out, list...)
+	case []interface{}:
+		for _, v := range list {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
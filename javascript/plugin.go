@@ -0,0 +1,208 @@
+package js
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja"
+)
+
+// Manifest is the content of a plugin's plugin.json.
+type Manifest struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Authors     []string `json:"authors"`
+	// Main is the script to run, relative to the plugin directory.
+	Main string `json:"main"`
+}
+
+// Plugin is a loaded plugin. Every plugin runs in its own goja.Runtime so one
+// plugin cannot interfere with another; the runtime is guarded by a mutex
+// because events are fired from many goroutines.
+type Plugin struct {
+	Manifest Manifest
+	Dir      string
+
+	mu      sync.Mutex
+	runtime *goja.Runtime
+}
+
+// Callback wraps a JavaScript function argument so Go can call it later with
+// the plugin's runtime locked. It panics with a TypeError, which goja turns into
+// a JavaScript exception, when v is not a function.
+func (p *Plugin) Callback(v goja.Value) func(args ...interface{}) {
+	fn, ok := goja.AssertFunction(v)
+	if !ok {
+		panic(p.runtime.NewTypeError("expected a function"))
+	}
+	return func(args ...interface{}) {
+		if _, err := p.Call(fn, args...); err != nil {
+			log.Printf("[%s] %v", p.Manifest.Name, err)
+		}
+	}
+}
+
+// Call invokes a JavaScript function with the plugin's runtime locked. Go
+// values are converted with the runtime's ToValue; JavaScript exceptions are
+// returned as errors.
+func (p *Plugin) Call(fn goja.Callable, args ...interface{}) (result goja.Value, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("plugin %s: %v", p.Manifest.Name, r)
+		}
+	}()
+
+	values := make([]goja.Value, len(args))
+	for i, arg := range args {
+		values[i] = p.runtime.ToValue(arg)
+	}
+	return fn(goja.Undefined(), values...)
+}
+
+// PluginManager loads plugins and owns the event bus they share with the server.
+type PluginManager struct {
+	Events *EventManager
+
+	mu      sync.RWMutex
+	plugins []*Plugin
+	globals []global
+}
+
+// global is a value exposed to every plugin by Expose.
+type global struct {
+	name    string
+	factory func(p *Plugin) interface{}
+}
+
+// NewPluginManager creates a manager with an empty event bus.
+func NewPluginManager() *PluginManager {
+	return &PluginManager{Events: NewEventManager()}
+}
+
+// Plugins returns the loaded plugins in load order.
+func (m *PluginManager) Plugins() []*Plugin {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*Plugin(nil), m.plugins...)
+}
+
+// Expose adds a global to every plugin loaded afterwards. factory is called
+// once per plugin, so the value can capture the plugin, for example to invoke
+// its callbacks with Call.
+func (m *PluginManager) Expose(name string, factory func(p *Plugin) interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.globals = append(m.globals, global{name, factory})
+}
+
+// LoadAll loads every plugin directory under dir. A missing directory is not
+// an error; a broken plugin is logged and skipped.
+func (m *PluginManager) LoadAll(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		p, err := m.Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			log.Printf("Could not load plugin %s: %v", entry.Name(), err)
+			continue
+		}
+		log.Printf("Loaded plugin %s %s", p.Manifest.Name, p.Manifest.Version)
+	}
+	return nil
+}
+
+// Load reads the manifest in dir, creates a runtime for the plugin and runs
+// its main script.
+func (m *PluginManager) Load(dir string) (*Plugin, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "plugin.json"))
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid plugin.json: %w", err)
+	}
+	if manifest.Name == "" {
+		manifest.Name = filepath.Base(dir)
+	}
+	if manifest.Main == "" {
+		manifest.Main = "main.js"
+	}
+	script, err := os.ReadFile(filepath.Join(dir, manifest.Main))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{Manifest: manifest, Dir: dir, runtime: goja.New()}
+	p.runtime.SetFieldNameMapper(goja.UncapFieldNameMapper())
+	p.runtime.Set("nbt", NewNbtModule(p.runtime))
+	p.runtime.Set("console", m.newConsole(p))
+	p.runtime.Set("events", m.newEventsModule(p))
+	m.mu.RLock()
+	globals := append([]global(nil), m.globals...)
+	m.mu.RUnlock()
+	for _, g := range globals {
+		p.runtime.Set(g.name, g.factory(p))
+	}
+
+	p.mu.Lock()
+	_, err = p.runtime.RunScript(filepath.Join(dir, manifest.Main), string(script))
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.plugins = append(m.plugins, p)
+	m.mu.Unlock()
+	return p, nil
+}
+
+// newConsole gives a plugin a console.log that prefixes its name.
+func (m *PluginManager) newConsole(p *Plugin) *goja.Object {
+	obj := p.runtime.NewObject()
+	obj.Set("log", func(call goja.FunctionCall) goja.Value {
+		args := make([]string, len(call.Arguments))
+		for i, a := range call.Arguments {
+			args[i] = a.String()
+		}
+		log.Printf("[%s] %s", p.Manifest.Name, strings.Join(args, " "))
+		return goja.Undefined()
+	})
+	return obj
+}
+
+// newEventsModule exposes events.on(name, callback) to a plugin.
+func (m *PluginManager) newEventsModule(p *Plugin) *goja.Object {
+	obj := p.runtime.NewObject()
+	obj.Set("on", func(name string, callback goja.Value) {
+		fn, ok := goja.AssertFunction(callback)
+		if !ok {
+			panic(p.runtime.NewTypeError("events.on expects a function"))
+		}
+		m.Events.On(name, func(event Event) {
+			if _, err := p.Call(fn, map[string]interface{}(event)); err != nil {
+				log.Printf("[%s] error in %s listener: %v", p.Manifest.Name, name, err)
+			}
+		})
+	})
+	return obj
+}
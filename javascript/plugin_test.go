@@ -0,0 +1,55 @@
+package js
+
+import (
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPluginEvents loads the plugins in testdata/plugins and checks that their
+// listeners can change and cancel events.
+func TestPluginEvents(t *testing.T) {
+	m := NewPluginManager()
+	require.NoError(t, m.LoadAll("testdata/plugins"))
+	require.Len(t, m.Plugins(), 1)
+	require.Equal(t, "ping", m.Plugins()[0].Manifest.Name)
+	require.True(t, m.Events.Has("serverListPing"))
+
+	sample := []interface{}{"Notch"}
+	event := Event{"hostname": "play.example", "motd": "A Golem Server", "maxPlayers": 20, "sample": &sample}
+	m.Events.Fire("serverListPing", event)
+	require.False(t, event.Cancelled())
+	require.Equal(t, "Hello from play.example", event.String("motd", ""))
+	require.Equal(t, 21, event.Int("maxPlayers", 0))
+	require.Equal(t, []string{"Notch", "Herobrine"}, event.Strings("sample"))
+
+	event = Event{"hostname": "blocked.example", "maxPlayers": 20, "sample": &[]interface{}{}}
+	m.Events.Fire("serverListPing", event)
+	require.True(t, event.Cancelled())
+}
+
+func TestLoadAllMissingDir(t *testing.T) {
+	m := NewPluginManager()
+	require.NoError(t, m.LoadAll("testdata/does-not-exist"))
+	require.Empty(t, m.Plugins())
+}
+
+// TestExpose checks that exposed globals reach plugins and that callbacks
+// registered through them can be called from Go.
+func TestExpose(t *testing.T) {
+	m := NewPluginManager()
+	var greet func(args ...interface{})
+	var said []string
+	m.Expose("greetings", func(p *Plugin) interface{} {
+		return map[string]interface{}{
+			"register": func(callback goja.Value) { greet = p.Callback(callback) },
+			"say":      func(s string) { said = append(said, s) },
+		}
+	})
+	_, err := m.Load("testdata/expose/greeter")
+	require.NoError(t, err)
+	require.NotNil(t, greet)
+	greet("Steve")
+	require.Equal(t, []string{"Hello, Steve"}, said)
+}
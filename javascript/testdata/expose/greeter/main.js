@@ -0,0 +1,3 @@
+greetings.register(function (name) {
+    greetings.say("Hello, " + name);
+});
@@ -0,0 +1,8 @@
+events.on("serverListPing", function (event) {
+    event.motd = "Hello from " + event.hostname;
+    event.maxPlayers = event.maxPlayers + 1;
+    event.sample.push("Herobrine");
+    if (event.hostname === "blocked.example") {
+        event.cancelled = true;
+    }
+});
@@ -83,3 +83,34 @@ func WriteCompressed(w io.Writer, nbt NamedTag) error {
 
 	return Write(gzw, nbt)
 }
+
+// ReadNetwork reads a single nameless tag, as sent over the network protocol
+// since Minecraft 1.20.2. Unlike Read, it never reads past the end of the tag,
+// so it can be used in the middle of a packet.
+func ReadNetwork(r io.Reader) (Tag, error) {
+	var id [1]byte
+	if _, err := io.ReadFull(r, id[:]); err != nil {
+		return nil, fmt.Errorf("unexpected EOF while reading tag ID")
+	}
+	tag, err := newTag(id[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := tag.read(r); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// WriteNetwork writes a single tag without a root name, as sent over the
+// network protocol since Minecraft 1.20.2.
+func WriteNetwork(w io.Writer, tag Tag) error {
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte(tag.ID()); err != nil {
+		return err
+	}
+	if err := tag.write(bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,64 @@
+package protocol
+
+// blockEntityTypes765 is the block entity type registry of 1.20.3 and
+// 1.20.4. Unlike the registries in the registries directory it is built into
+// the client, so only the order matters.
+var blockEntityTypes765 = []string{
+	"minecraft:furnace",
+	"minecraft:chest",
+	"minecraft:trapped_chest",
+	"minecraft:ender_chest",
+	"minecraft:jukebox",
+	"minecraft:dispenser",
+	"minecraft:dropper",
+	"minecraft:sign",
+	"minecraft:hanging_sign",
+	"minecraft:mob_spawner",
+	"minecraft:piston",
+	"minecraft:brewing_stand",
+	"minecraft:enchanting_table",
+	"minecraft:end_portal",
+	"minecraft:beacon",
+	"minecraft:skull",
+	"minecraft:daylight_detector",
+	"minecraft:hopper",
+	"minecraft:comparator",
+	"minecraft:banner",
+	"minecraft:structure_block",
+	"minecraft:end_gateway",
+	"minecraft:command_block",
+	"minecraft:shulker_box",
+	"minecraft:bed",
+	"minecraft:conduit",
+	"minecraft:barrel",
+	"minecraft:smoker",
+	"minecraft:blast_furnace",
+	"minecraft:lectern",
+	"minecraft:bell",
+	"minecraft:jigsaw",
+	"minecraft:campfire",
+	"minecraft:beehive",
+	"minecraft:sculk_sensor",
+	"minecraft:calibrated_sculk_sensor",
+	"minecraft:sculk_catalyst",
+	"minecraft:sculk_shrieker",
+	"minecraft:chiseled_bookshelf",
+	"minecraft:brushable_block",
+	"minecraft:decorated_pot",
+	"minecraft:crafter",
+	"minecraft:trial_spawner",
+}
+
+// blockEntityTypes766 adds the vault of 1.20.5, which 1.21 kept.
+var blockEntityTypes766 = append(append([]string(nil), blockEntityTypes765...), "minecraft:vault")
+
+// BlockEntityType returns the network ID of a block entity type, such as
+// "minecraft:chest".
+func (v *Version) BlockEntityType(name string) (int32, bool) {
+	for i, t := range v.blockEntityTypes {
+		if t == name {
+			return int32(i), true
+		}
+	}
+	return 0, false
+}
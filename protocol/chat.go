@@ -0,0 +1,218 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Chat limits shared by the chat packets.
+const (
+	// MaxChatLength is the longest chat message or command a client may send.
+	MaxChatLength = 256
+	// MessageSignatureLength is the size of a chat signature, an RSA-2048
+	// SHA256withRSA signature.
+	MessageSignatureLength = 256
+	// LastSeenWindow is the number of messages a client acknowledges in a
+	// LastSeenUpdate.
+	LastSeenWindow = 20
+	// maxArgumentSignatures is the number of signed command arguments vanilla
+	// accepts.
+	maxArgumentSignatures = 8
+)
+
+// LastSeenUpdate is how a client tells the server which of the last
+// LastSeenWindow signed messages it has seen. Offset is the number of
+// messages received since the previous update; bit i of Acknowledged is set
+// if the i-th message of the window, oldest first, was seen.
+type LastSeenUpdate struct {
+	Offset       int32   `json:"offset"`
+	Acknowledged [3]byte `json:"acknowledged"`
+}
+
+// Seen reports whether the i-th message of the window was acknowledged.
+func (u *LastSeenUpdate) Seen(i int) bool { return u.Acknowledged[i/8]&(1<<(i%8)) != 0 }
+
+// Count returns the number of acknowledged messages.
+func (u *LastSeenUpdate) Count() int {
+	n := 0
+	for i := 0; i < LastSeenWindow; i++ {
+		if u.Seen(i) {
+			n++
+		}
+	}
+	return n
+}
+
+func (u *LastSeenUpdate) decode(r *bytes.Reader) (err error) {
+	if u.Offset, err = ReadVarInt(r); err != nil {
+		return err
+	}
+	_, err = io.ReadFull(r, u.Acknowledged[:])
+	return err
+}
+
+// ChatMessage is a chat line typed by the player.
+type ChatMessage struct {
+	Message string `json:"message"`
+	// Timestamp is when the message was sent, in milliseconds since the Unix
+	// epoch.
+	Timestamp int64 `json:"timestamp"`
+	Salt      int64 `json:"salt"`
+	// Signature is nil for unsigned messages.
+	Signature []byte         `json:"signature,omitempty"`
+	LastSeen  LastSeenUpdate `json:"lastSeen"`
+}
+
+func (p *ChatMessage) Decode(r *bytes.Reader) (err error) {
+	if p.Message, err = ReadString(r, MaxChatLength); err != nil {
+		return err
+	}
+	if p.Timestamp, err = ReadLong(r); err != nil {
+		return err
+	}
+	if p.Salt, err = ReadLong(r); err != nil {
+		return err
+	}
+	signed, err := ReadBool(r)
+	if err != nil {
+		return err
+	}
+	if signed {
+		if p.Signature, err = ReadBytes(r, MessageSignatureLength); err != nil {
+			return err
+		}
+	}
+	return p.LastSeen.decode(r)
+}
+
+// ArgumentSignature signs one argument of a command, such as the message of
+// /say, like a chat message.
+type ArgumentSignature struct {
+	Name      string `json:"name"`
+	Signature []byte `json:"signature"`
+}
+
+// ChatCommand is a command typed by the player, without the leading slash.
+// Clients before 1.20.5 always send the signing fields; later clients send
+// them only in Signed Chat Command, and only for commands with arguments they
+// can sign.
+type ChatCommand struct {
+	Command string `json:"command"`
+	// Signed is set when the fields below were sent.
+	Signed             bool                `json:"signed"`
+	Timestamp          int64               `json:"timestamp,omitempty"`
+	Salt               int64               `json:"salt,omitempty"`
+	ArgumentSignatures []ArgumentSignature `json:"argumentSignatures,omitempty"`
+	LastSeen           LastSeenUpdate      `json:"lastSeen"`
+}
+
+func (p *ChatCommand) Decode(r *bytes.Reader) (err error) {
+	if p.Command, err = ReadString(r, MaxChatLength); err != nil {
+		return err
+	}
+	if r.Len() == 0 {
+		return nil
+	}
+	return p.decodeSigned(r)
+}
+
+func (p *ChatCommand) decodeSigned(r *bytes.Reader) (err error) {
+	p.Signed = true
+	if p.Timestamp, err = ReadLong(r); err != nil {
+		return err
+	}
+	if p.Salt, err = ReadLong(r); err != nil {
+		return err
+	}
+	count, err := ReadVarInt(r)
+	if err != nil {
+		return err
+	}
+	if count < 0 || count > maxArgumentSignatures {
+		return fmt.Errorf("invalid argument signature count %d", count)
+	}
+	p.ArgumentSignatures = make([]ArgumentSignature, count)
+	for i := range p.ArgumentSignatures {
+		arg := &p.ArgumentSignatures[i]
+		if arg.Name, err = ReadString(r, 16); err != nil {
+			return err
+		}
+		if arg.Signature, err = ReadBytes(r, MessageSignatureLength); err != nil {
+			return err
+		}
+	}
+	return p.LastSeen.decode(r)
+}
+
+// SignedChatCommand is the Signed Chat Command packet of 1.20.5 and later. It
+// decodes into the same form as ChatCommand.
+type SignedChatCommand struct {
+	ChatCommand
+}
+
+func (p *SignedChatCommand) Decode(r *bytes.Reader) (err error) {
+	if p.Command, err = ReadString(r, MaxChatLength); err != nil {
+		return err
+	}
+	return p.decodeSigned(r)
+}
+
+// PlayerSession starts a chat session: the profile public key the client
+// signs its messages with, as issued and signed by Mojang.
+type PlayerSession struct {
+	SessionID UUID `json:"sessionId"`
+	// ExpiresAt is when the key expires, in milliseconds since the Unix epoch.
+	ExpiresAt int64 `json:"expiresAt"`
+	// PublicKey is the RSA key in X.509 DER form.
+	PublicKey    []byte `json:"publicKey"`
+	KeySignature []byte `json:"keySignature"`
+}
+
+func (p *PlayerSession) Decode(r *bytes.Reader) (err error) {
+	if p.SessionID, err = ReadUUID(r); err != nil {
+		return err
+	}
+	if p.ExpiresAt, err = ReadLong(r); err != nil {
+		return err
+	}
+	if p.PublicKey, err = ReadByteArray(r, 512); err != nil {
+		return err
+	}
+	p.KeySignature, err = ReadByteArray(r, 4096)
+	return err
+}
+
+// AcknowledgeMessage tells the server how many signed messages the client
+// has received since it last said so, when it has not sent a message.
+type AcknowledgeMessage struct {
+	Offset int32 `json:"offset"`
+}
+
+func (p *AcknowledgeMessage) Decode(r *bytes.Reader) (err error) {
+	p.Offset, err = ReadVarInt(r)
+	return err
+}
+
+// SignedMessagePayload returns the bytes a chat signature covers: the link
+// in the sender's message chain followed by the message body. timestamp is
+// in milliseconds since the Unix epoch; lastSeen are the signatures of the
+// acknowledged messages, oldest first.
+func SignedMessagePayload(sender, session UUID, index int32, salt, timestamp int64, message string, lastSeen [][]byte) []byte {
+	var buf bytes.Buffer
+	write := func(v interface{}) { _ = binary.Write(&buf, binary.BigEndian, v) }
+	write(int32(1)) // signature format version
+	buf.Write(sender[:])
+	buf.Write(session[:])
+	write(index)
+	write(salt)
+	write(timestamp / 1000)
+	write(int32(len(message)))
+	buf.WriteString(message)
+	write(int32(len(lastSeen)))
+	for _, sig := range lastSeen {
+		buf.Write(sig)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,400 @@
+package protocol
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf8"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// Reader is the input the Read helpers consume. Both *bufio.Reader and
+// *bytes.Reader satisfy it.
+type Reader interface {
+	io.Reader
+	io.ByteReader
+}
+
+var (
+	ErrVarIntTooBig  = errors.New("protocol: VarInt is too big")
+	ErrVarLongTooBig = errors.New("protocol: VarLong is too big")
+)
+
+// DefaultStringLength is the maximum length, in characters, of a String field
+// that does not specify a tighter bound.
+const DefaultStringLength = 32767
+
+// ReadVarInt reads a VarInt of at most five bytes.
+func ReadVarInt(r io.ByteReader) (int32, error) {
+	var num uint32
+	for i := 0; ; i++ {
+		if i == 5 {
+			return 0, ErrVarIntTooBig
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		num |= uint32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(num), nil
+		}
+	}
+}
+
+// ReadVarLong reads a VarLong of at most ten bytes.
+func ReadVarLong(r io.ByteReader) (int64, error) {
+	var num uint64
+	for i := 0; ; i++ {
+		if i == 10 {
+			return 0, ErrVarLongTooBig
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		num |= uint64(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int64(num), nil
+		}
+	}
+}
+
+// ReadString reads a VarInt-prefixed UTF-8 string of at most maxLen characters.
+func ReadString(r Reader, maxLen int) (string, error) {
+	length, err := ReadVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > maxLen*3 {
+		return "", fmt.Errorf("protocol: string length %d exceeds limit of %d", length, maxLen)
+	}
+	buf, err := ReadBytes(r, int(length))
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(buf) {
+		return "", fmt.Errorf("protocol: string is not valid UTF-8")
+	}
+	if utf8.RuneCount(buf) > maxLen {
+		return "", fmt.Errorf("protocol: string length %d exceeds limit of %d", utf8.RuneCount(buf), maxLen)
+	}
+	return string(buf), nil
+}
+
+// ReadBytes reads exactly n bytes.
+func ReadBytes(r io.Reader, n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// ReadByteArray reads a VarInt-prefixed byte array of at most maxLen bytes.
+func ReadByteArray(r Reader, maxLen int) ([]byte, error) {
+	length, err := ReadVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 || int(length) > maxLen {
+		return nil, fmt.Errorf("protocol: byte array length %d exceeds limit of %d", length, maxLen)
+	}
+	return ReadBytes(r, int(length))
+}
+
+func ReadBool(r io.ByteReader) (bool, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, fmt.Errorf("protocol: invalid boolean %#x", b)
+	}
+}
+
+func ReadUnsignedByte(r io.ByteReader) (byte, error) { return r.ReadByte() }
+
+func ReadUnsignedShort(r io.Reader) (uint16, error) {
+	var v uint16
+	err := binary.Read(r, binary.BigEndian, &v)
+	return v, err
+}
+
+func ReadShort(r io.Reader) (int16, error) {
+	var v int16
+	err := binary.Read(r, binary.BigEndian, &v)
+	return v, err
+}
+
+func ReadInt(r io.Reader) (int32, error) {
+	var v int32
+	err := binary.Read(r, binary.BigEndian, &v)
+	return v, err
+}
+
+func ReadLong(r io.Reader) (int64, error) {
+	var v int64
+	err := binary.Read(r, binary.BigEndian, &v)
+	return v, err
+}
+
+func ReadFloat(r io.Reader) (float32, error) {
+	var v float32
+	err := binary.Read(r, binary.BigEndian, &v)
+	return v, err
+}
+
+func ReadDouble(r io.Reader) (float64, error) {
+	var v float64
+	err := binary.Read(r, binary.BigEndian, &v)
+	return v, err
+}
+
+func ReadUUID(r io.Reader) (UUID, error) {
+	var u UUID
+	_, err := io.ReadFull(r, u[:])
+	return u, err
+}
+
+// ReadNBT reads a nameless network NBT tag.
+func ReadNBT(r io.Reader) (nbt.Tag, error) {
+	return nbt.ReadNetwork(r)
+}
+
+// WriteVarInt encodes n as a VarInt. Negative numbers always take five bytes.
+func WriteVarInt(n int) []byte {
+	u := uint32(n)
+	var out []byte
+	for {
+		b := byte(u & 0x7F)
+		u >>= 7
+		if u != 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if u == 0 {
+			return out
+		}
+	}
+}
+
+// WriteVarLong encodes n as a VarLong.
+func WriteVarLong(n int64) []byte {
+	u := uint64(n)
+	var out []byte
+	for {
+		b := byte(u & 0x7F)
+		u >>= 7
+		if u != 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if u == 0 {
+			return out
+		}
+	}
+}
+
+func WriteString(s string) []byte {
+	out := WriteVarInt(len(s))
+	return append(out, s...)
+}
+
+// WriteByteArray encodes b prefixed with its length as a VarInt.
+func WriteByteArray(b []byte) []byte {
+	out := WriteVarInt(len(b))
+	return append(out, b...)
+}
+
+func WriteByte(b byte) []byte { return []byte{b} }
+
+func WriteBool(b bool) []byte {
+	if b {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+func WriteShort(i int16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], uint16(i))
+	return buf[:]
+}
+
+func WriteUnsignedShort(i uint16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], i)
+	return buf[:]
+}
+
+func WriteInt(i int) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(i))
+	return buf[:]
+}
+
+func WriteLong(i int64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(i))
+	return buf[:]
+}
+
+func WriteFloat(f float32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], math.Float32bits(f))
+	return buf[:]
+}
+
+func WriteDouble(f float64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], math.Float64bits(f))
+	return buf[:]
+}
+
+func WriteUUID(u UUID) []byte { return u[:] }
+
+// WriteNBT encodes tag as nameless network NBT.
+func WriteNBT(tag nbt.Tag) []byte {
+	var buf bytes.Buffer
+	// Writing to a bytes.Buffer cannot fail.
+	_ = nbt.WriteNetwork(&buf, tag)
+	return buf.Bytes()
+}
+
+// UUID is a 128-bit identifier as sent on the wire: two big-endian longs.
+type UUID [16]byte
+
+// String formats the UUID in the canonical dashed form.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// MarshalText encodes the UUID in the dashed form, which is also how it
+// appears in JSON.
+func (u UUID) MarshalText() ([]byte, error) { return []byte(u.String()), nil }
+
+// UnmarshalText parses a UUID with or without dashes.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// ParseUUID parses a UUID with or without dashes.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	clean := make([]byte, 0, 32)
+	for i := 0; i < len(s); i++ {
+		if s[i] != '-' {
+			clean = append(clean, s[i])
+		}
+	}
+	if len(clean) != 32 {
+		return u, fmt.Errorf("protocol: invalid UUID %q", s)
+	}
+	if _, err := hex.Decode(u[:], clean); err != nil {
+		return u, fmt.Errorf("protocol: invalid UUID %q: %w", s, err)
+	}
+	return u, nil
+}
+
+// OfflineUUID returns the UUID vanilla assigns to a player in offline mode: a
+// version 3 UUID of "OfflinePlayer:<name>".
+func OfflineUUID(name string) UUID {
+	u := UUID(md5.Sum([]byte("OfflinePlayer:" + name)))
+	u[6] = u[6]&0x0f | 0x30
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// RandomUUID returns a random version 4 UUID, as vanilla gives new
+// entities.
+func RandomUUID() UUID {
+	var u UUID
+	// crypto/rand.Read never fails.
+	_, _ = rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// Property is a signed game profile property, such as the "textures" property
+// that carries a player's skin.
+type Property struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// maxProperties bounds the property array, matching vanilla's limit of 16.
+const maxProperties = 16
+
+// ReadProperties reads a VarInt-prefixed array of profile properties.
+func ReadProperties(r Reader) ([]Property, error) {
+	count, err := ReadVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 || count > maxProperties {
+		return nil, fmt.Errorf("protocol: invalid property count %d", count)
+	}
+	props := make([]Property, count)
+	for i := range props {
+		if props[i].Name, err = ReadString(r, 64); err != nil {
+			return nil, err
+		}
+		if props[i].Value, err = ReadString(r, DefaultStringLength); err != nil {
+			return nil, err
+		}
+		signed, err := ReadBool(r)
+		if err != nil {
+			return nil, err
+		}
+		if signed {
+			if props[i].Signature, err = ReadString(r, 1024); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return props, nil
+}
+
+// WriteProperties encodes profile properties as sent in Login Success.
+func WriteProperties(props []Property) []byte {
+	out := WriteVarInt(len(props))
+	for _, p := range props {
+		out = append(out, WriteString(p.Name)...)
+		out = append(out, WriteString(p.Value)...)
+		out = append(out, WriteBool(p.Signature != "")...)
+		if p.Signature != "" {
+			out = append(out, WriteString(p.Signature)...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,454 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// MaxPacketLength is the largest frame the protocol allows: the length prefix
+// may be at most a three byte VarInt, i.e. 21 bits.
+const MaxPacketLength = 1<<21 - 1
+
+var (
+	ErrPacketTooLarge = errors.New("protocol: packet length exceeds 21 bits")
+	ErrEmptyPacket    = errors.New("protocol: packet has no ID")
+	ErrConnClosed     = errors.New("protocol: connection closed")
+	ErrSendQueueFull  = errors.New("protocol: send queue is full")
+)
+
+// ConnConfig tunes a Conn. Zero fields take the vanilla defaults.
+type ConnConfig struct {
+	// ReadTimeout closes the connection when the client sends nothing for this
+	// long. Vanilla uses 30 seconds.
+	ReadTimeout time.Duration
+	// WriteTimeout bounds a single write to the socket.
+	WriteTimeout time.Duration
+	// KeepAliveInterval is how often Keep Alive is sent in the configuration and
+	// play states. Vanilla uses 15 seconds.
+	KeepAliveInterval time.Duration
+	// KeepAliveTimeout is how long the client has to answer a Keep Alive.
+	KeepAliveTimeout time.Duration
+	// SendQueue is the number of packets that may wait for the writer. A client
+	// that falls further behind is disconnected instead of blocking the sender.
+	SendQueue int
+	// Observer, if set, sees every packet read or written.
+	Observer Observer
+}
+
+// Observer is told about every packet a Conn reads or queues for writing, for
+// tools such as packet capture. It is called from the reading goroutine and
+// from any goroutine that writes, so it must be safe for concurrent use, and
+// must not keep payload.
+type Observer interface {
+	ObservePacket(dir Direction, state State, id int32, p Packet, payload []byte)
+}
+
+func (c *ConnConfig) setDefaults() {
+	if c.ReadTimeout == 0 {
+		c.ReadTimeout = 30 * time.Second
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = 10 * time.Second
+	}
+	if c.KeepAliveInterval == 0 {
+		c.KeepAliveInterval = 15 * time.Second
+	}
+	if c.KeepAliveTimeout == 0 {
+		c.KeepAliveTimeout = 15 * time.Second
+	}
+	if c.SendQueue == 0 {
+		c.SendQueue = 1024
+	}
+}
+
+// RawPacket is a frame read from the client: its numeric ID, the packet that ID
+// maps to in the connection's version and state, and the remaining payload.
+type RawPacket struct {
+	ID     int32
+	Packet Packet
+	Data   []byte
+}
+
+// Reader returns a reader over the packet payload.
+func (p *RawPacket) Reader() *bytes.Reader { return bytes.NewReader(p.Data) }
+
+// Conn is a client connection speaking the framed Minecraft protocol. Reads
+// happen on the caller's goroutine; writes are queued and performed by a
+// dedicated goroutine so a slow client never blocks the sender.
+type Conn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	cfg  ConnConfig
+
+	mu         sync.RWMutex
+	state      State
+	version    *Version
+	remoteAddr net.Addr
+
+	out       chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+
+	keepAliveOnce    sync.Once
+	keepAliveMu      sync.Mutex
+	keepAlivePending bool
+	keepAliveID      int64
+	keepAliveSent    time.Time
+	latency          time.Duration
+}
+
+// NewConn wraps c and starts its writer goroutine. The connection starts in
+// the handshaking state using the latest version's mapping.
+func NewConn(c net.Conn, cfg ConnConfig) *Conn {
+	cfg.setDefaults()
+	conn := &Conn{
+		conn:    c,
+		r:       bufio.NewReader(c),
+		cfg:     cfg,
+		state:   Handshaking,
+		version: Latest(),
+		out:     make(chan []byte, cfg.SendQueue),
+		closed:  make(chan struct{}),
+	}
+	go conn.writeLoop()
+	return conn
+}
+
+// State returns the current connection state.
+func (c *Conn) State() State {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+// SetState switches the connection state. Entering configuration or play
+// starts the keep-alive loop.
+func (c *Conn) SetState(s State) {
+	c.mu.Lock()
+	c.state = s
+	c.mu.Unlock()
+	if s == Configuration || s == Play {
+		c.keepAliveOnce.Do(func() { go c.keepAliveLoop() })
+	}
+}
+
+// Version returns the protocol version packets are mapped with.
+func (c *Conn) Version() *Version {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.version
+}
+
+// SetVersion selects the protocol version negotiated in the handshake.
+func (c *Conn) SetVersion(v *Version) {
+	c.mu.Lock()
+	c.version = v
+	c.mu.Unlock()
+}
+
+// RemoteAddr returns the address of the client: the one forwarded by a proxy
+// if SetRemoteAddr was called, otherwise the socket's peer.
+func (c *Conn) RemoteAddr() net.Addr {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.conn.RemoteAddr()
+}
+
+// SetRemoteAddr overrides the client address, for connections forwarded by a
+// proxy.
+func (c *Conn) SetRemoteAddr(addr net.Addr) {
+	c.mu.Lock()
+	c.remoteAddr = addr
+	c.mu.Unlock()
+}
+
+// Latency returns the smoothed round trip time measured with Keep Alive.
+func (c *Conn) Latency() time.Duration {
+	c.keepAliveMu.Lock()
+	defer c.keepAliveMu.Unlock()
+	return c.latency
+}
+
+// Done is closed once the connection is closed.
+func (c *Conn) Done() <-chan struct{} { return c.closed }
+
+// Err returns why the connection was closed or disconnected, or nil while it
+// is open.
+func (c *Conn) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closeErr
+}
+
+// ReadPacket reads the next frame. Keep Alive responses are consumed here and
+// never returned.
+func (c *Conn) ReadPacket() (*RawPacket, error) {
+	for {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		if err := c.conn.SetReadDeadline(time.Now().Add(c.cfg.ReadTimeout)); err != nil {
+			return nil, err
+		}
+		id, data, err := ReadFrame(c.r)
+		if err != nil {
+			return nil, err
+		}
+		pkt := &RawPacket{ID: id, Data: data}
+		state := c.State()
+		pkt.Packet, _ = c.Version().Packet(state, Serverbound, id)
+		if c.cfg.Observer != nil {
+			c.cfg.Observer.ObservePacket(Serverbound, state, id, pkt.Packet, pkt.Data)
+		}
+
+		if pkt.Packet == ServerboundConfigKeepAlive || pkt.Packet == ServerboundPlayKeepAlive {
+			if err := c.handleKeepAlive(pkt); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return pkt, nil
+	}
+}
+
+// ReadFrame reads one length-prefixed frame and splits it into the packet ID
+// and the payload after it.
+func ReadFrame(r *bufio.Reader) (id int32, payload []byte, err error) {
+	length, err := readFrameLength(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if length == 0 {
+		return 0, nil, ErrEmptyPacket
+	}
+	data, err := ReadBytes(r, int(length))
+	if err != nil {
+		return 0, nil, err
+	}
+	body := bytes.NewReader(data)
+	if id, err = ReadVarInt(body); err != nil {
+		return 0, nil, err
+	}
+	return id, data[len(data)-body.Len():], nil
+}
+
+// readFrameLength reads the VarInt length prefix, which may be at most three bytes.
+func readFrameLength(r *bufio.Reader) (int32, error) {
+	var length int32
+	for i := 0; i < 3; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length |= int32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return length, nil
+		}
+	}
+	return 0, ErrPacketTooLarge
+}
+
+// WritePacket queues p using the ID the connection's version assigns to it.
+func (c *Conn) WritePacket(p Packet, payload ...[]byte) error {
+	v := c.Version()
+	id, ok := v.ID(p)
+	if !ok {
+		return fmt.Errorf("protocol: Minecraft %s has no %s packet", v, p)
+	}
+	return c.WriteRaw(id, payload...)
+}
+
+// WriteRaw queues a packet with an explicit numeric ID.
+func (c *Conn) WriteRaw(id int32, payload ...[]byte) error {
+	header := WriteVarInt(int(id))
+	pkt := header
+	for _, part := range payload {
+		pkt = append(pkt, part...)
+	}
+	if len(pkt) > MaxPacketLength {
+		return ErrPacketTooLarge
+	}
+	frame := append(WriteVarInt(len(pkt)), pkt...)
+
+	if c.Err() != nil {
+		return ErrConnClosed
+	}
+	if c.cfg.Observer != nil {
+		state := c.State()
+		p, _ := c.Version().Packet(state, Clientbound, id)
+		c.cfg.Observer.ObservePacket(Clientbound, state, id, p, pkt[len(header):])
+	}
+	select {
+	case c.out <- frame:
+		return nil
+	default:
+		c.closeWithError(ErrSendQueueFull)
+		return ErrSendQueueFull
+	}
+}
+
+// writeLoop drains the send queue, batching whatever is queued into a single
+// flush. A nil frame asks it to close the connection once everything before it
+// has been written.
+func (c *Conn) writeLoop() {
+	w := bufio.NewWriter(c.conn)
+	for {
+		select {
+		case <-c.closed:
+			return
+		case frame := <-c.out:
+			if err := c.conn.SetWriteDeadline(time.Now().Add(c.cfg.WriteTimeout)); err != nil {
+				c.closeWithError(err)
+				return
+			}
+		batch:
+			for {
+				if frame == nil {
+					if err := w.Flush(); err != nil {
+						c.closeWithError(err)
+					} else {
+						c.Close()
+					}
+					return
+				}
+				if _, err := w.Write(frame); err != nil {
+					c.closeWithError(err)
+					return
+				}
+				select {
+				case frame = <-c.out:
+				default:
+					break batch
+				}
+			}
+			if err := w.Flush(); err != nil {
+				c.closeWithError(err)
+				return
+			}
+		}
+	}
+}
+
+// Disconnect sends the state's Disconnect packet with the given reason, if it
+// has one, then closes the connection once the send queue is flushed.
+func (c *Conn) Disconnect(reason string) {
+	switch c.State() {
+	case Login:
+		_ = c.WritePacket(ClientboundLoginDisconnect, WriteString(jsonText(reason)))
+	case Configuration:
+		_ = c.WritePacket(ClientboundConfigDisconnect, WriteNBT(&nbt.StringTag{Value: reason}))
+	case Play:
+		_ = c.WritePacket(ClientboundPlayDisconnect, WriteNBT(&nbt.StringTag{Value: reason}))
+	}
+	c.flushAndClose(fmt.Errorf("disconnected: %s", reason))
+}
+
+// flushAndClose closes the connection once the queued packets are written.
+// It does not wait: a client that stops reading is cut off by the write
+// deadline instead.
+func (c *Conn) flushAndClose(reason error) {
+	c.mu.Lock()
+	if c.closeErr == nil {
+		c.closeErr = reason
+	}
+	c.mu.Unlock()
+
+	select {
+	case c.out <- nil:
+	case <-c.closed:
+	default:
+		c.Close()
+	}
+}
+
+// Close closes the connection immediately, dropping queued packets.
+func (c *Conn) Close() error {
+	return c.closeWithError(ErrConnClosed)
+}
+
+func (c *Conn) closeWithError(err error) error {
+	var closeErr error
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		if c.closeErr == nil {
+			c.closeErr = err
+		}
+		c.mu.Unlock()
+		close(c.closed)
+		closeErr = c.conn.Close()
+	})
+	return closeErr
+}
+
+// keepAliveLoop sends Keep Alive at the configured interval and disconnects
+// clients that leave one unanswered for too long.
+func (c *Conn) keepAliveLoop() {
+	ticker := time.NewTicker(c.cfg.KeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.closed:
+			return
+		case now := <-ticker.C:
+			c.keepAliveMu.Lock()
+			if c.keepAlivePending {
+				timedOut := now.Sub(c.keepAliveSent) >= c.cfg.KeepAliveTimeout
+				c.keepAliveMu.Unlock()
+				if timedOut {
+					c.Disconnect("Timed out")
+					return
+				}
+				continue
+			}
+			c.keepAlivePending = true
+			c.keepAliveID = now.UnixMilli()
+			c.keepAliveSent = now
+			id := c.keepAliveID
+			c.keepAliveMu.Unlock()
+
+			packet := ClientboundPlayKeepAlive
+			if c.State() == Configuration {
+				packet = ClientboundConfigKeepAlive
+			}
+			_ = c.WritePacket(packet, WriteLong(id))
+		}
+	}
+}
+
+// handleKeepAlive checks a Keep Alive response and updates the latency the
+// same way vanilla does: a 3:1 weighted average with the previous value.
+func (c *Conn) handleKeepAlive(pkt *RawPacket) error {
+	id, err := ReadLong(pkt.Reader())
+	if err != nil {
+		return err
+	}
+	c.keepAliveMu.Lock()
+	if !c.keepAlivePending || id != c.keepAliveID {
+		c.keepAliveMu.Unlock()
+		c.Disconnect("Timed out")
+		return fmt.Errorf("protocol: unexpected keep alive %d", id)
+	}
+	c.keepAlivePending = false
+	sample := time.Since(c.keepAliveSent)
+	c.latency = (c.latency*3 + sample) / 4
+	c.keepAliveMu.Unlock()
+	return nil
+}
+
+// jsonText encodes s as a JSON text component.
+func jsonText(s string) string {
+	b, _ := json.Marshal(map[string]string{"text": s})
+	return string(b)
+}
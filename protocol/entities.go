@@ -0,0 +1,426 @@
+package protocol
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// entityTypes765 is the entity type registry of 1.20.3 and 1.20.4. Like the
+// block entity types it is built into the client.
+var entityTypes765 = []string{
+	"minecraft:allay",
+	"minecraft:area_effect_cloud",
+	"minecraft:armor_stand",
+	"minecraft:arrow",
+	"minecraft:axolotl",
+	"minecraft:bat",
+	"minecraft:bee",
+	"minecraft:blaze",
+	"minecraft:block_display",
+	"minecraft:boat",
+	"minecraft:breeze",
+	"minecraft:camel",
+	"minecraft:cat",
+	"minecraft:cave_spider",
+	"minecraft:chest_boat",
+	"minecraft:chest_minecart",
+	"minecraft:chicken",
+	"minecraft:cod",
+	"minecraft:command_block_minecart",
+	"minecraft:cow",
+	"minecraft:creeper",
+	"minecraft:dolphin",
+	"minecraft:donkey",
+	"minecraft:dragon_fireball",
+	"minecraft:drowned",
+	"minecraft:egg",
+	"minecraft:elder_guardian",
+	"minecraft:end_crystal",
+	"minecraft:ender_dragon",
+	"minecraft:ender_pearl",
+	"minecraft:enderman",
+	"minecraft:endermite",
+	"minecraft:evoker",
+	"minecraft:evoker_fangs",
+	"minecraft:experience_bottle",
+	"minecraft:experience_orb",
+	"minecraft:eye_of_ender",
+	"minecraft:falling_block",
+	"minecraft:firework_rocket",
+	"minecraft:fox",
+	"minecraft:frog",
+	"minecraft:furnace_minecart",
+	"minecraft:ghast",
+	"minecraft:giant",
+	"minecraft:glow_item_frame",
+	"minecraft:glow_squid",
+	"minecraft:goat",
+	"minecraft:guardian",
+	"minecraft:hoglin",
+	"minecraft:hopper_minecart",
+	"minecraft:horse",
+	"minecraft:husk",
+	"minecraft:illusioner",
+	"minecraft:interaction",
+	"minecraft:iron_golem",
+	"minecraft:item",
+	"minecraft:item_display",
+	"minecraft:item_frame",
+	"minecraft:fireball",
+	"minecraft:leash_knot",
+	"minecraft:lightning_bolt",
+	"minecraft:llama",
+	"minecraft:llama_spit",
+	"minecraft:magma_cube",
+	"minecraft:marker",
+	"minecraft:minecart",
+	"minecraft:mooshroom",
+	"minecraft:mule",
+	"minecraft:ocelot",
+	"minecraft:painting",
+	"minecraft:panda",
+	"minecraft:parrot",
+	"minecraft:phantom",
+	"minecraft:pig",
+	"minecraft:piglin",
+	"minecraft:piglin_brute",
+	"minecraft:pillager",
+	"minecraft:polar_bear",
+	"minecraft:potion",
+	"minecraft:pufferfish",
+	"minecraft:rabbit",
+	"minecraft:ravager",
+	"minecraft:salmon",
+	"minecraft:sheep",
+	"minecraft:shulker",
+	"minecraft:shulker_bullet",
+	"minecraft:silverfish",
+	"minecraft:skeleton",
+	"minecraft:skeleton_horse",
+	"minecraft:slime",
+	"minecraft:small_fireball",
+	"minecraft:sniffer",
+	"minecraft:snow_golem",
+	"minecraft:snowball",
+	"minecraft:spawner_minecart",
+	"minecraft:spectral_arrow",
+	"minecraft:spider",
+	"minecraft:squid",
+	"minecraft:stray",
+	"minecraft:strider",
+	"minecraft:tadpole",
+	"minecraft:text_display",
+	"minecraft:tnt",
+	"minecraft:tnt_minecart",
+	"minecraft:trader_llama",
+	"minecraft:trident",
+	"minecraft:tropical_fish",
+	"minecraft:turtle",
+	"minecraft:vex",
+	"minecraft:villager",
+	"minecraft:vindicator",
+	"minecraft:wandering_trader",
+	"minecraft:warden",
+	"minecraft:wind_charge",
+	"minecraft:witch",
+	"minecraft:wither",
+	"minecraft:wither_skeleton",
+	"minecraft:wither_skull",
+	"minecraft:wolf",
+	"minecraft:zoglin",
+	"minecraft:zombie",
+	"minecraft:zombie_horse",
+	"minecraft:zombie_villager",
+	"minecraft:zombified_piglin",
+	"minecraft:player",
+	"minecraft:fishing_bobber",
+}
+
+// entityTypes766 is the entity type registry of 1.20.5, which 1.21 kept. The
+// armadillo, the bogged, the breeze's wind charge and the ominous item
+// spawner were added in name order, moving the IDs after them.
+var entityTypes766 = []string{
+	"minecraft:allay",
+	"minecraft:area_effect_cloud",
+	"minecraft:armadillo",
+	"minecraft:armor_stand",
+	"minecraft:arrow",
+	"minecraft:axolotl",
+	"minecraft:bat",
+	"minecraft:bee",
+	"minecraft:blaze",
+	"minecraft:block_display",
+	"minecraft:boat",
+	"minecraft:bogged",
+	"minecraft:breeze",
+	"minecraft:breeze_wind_charge",
+	"minecraft:camel",
+	"minecraft:cat",
+	"minecraft:cave_spider",
+	"minecraft:chest_boat",
+	"minecraft:chest_minecart",
+	"minecraft:chicken",
+	"minecraft:cod",
+	"minecraft:command_block_minecart",
+	"minecraft:cow",
+	"minecraft:creeper",
+	"minecraft:dolphin",
+	"minecraft:donkey",
+	"minecraft:dragon_fireball",
+	"minecraft:drowned",
+	"minecraft:egg",
+	"minecraft:elder_guardian",
+	"minecraft:end_crystal",
+	"minecraft:ender_dragon",
+	"minecraft:ender_pearl",
+	"minecraft:enderman",
+	"minecraft:endermite",
+	"minecraft:evoker",
+	"minecraft:evoker_fangs",
+	"minecraft:experience_bottle",
+	"minecraft:experience_orb",
+	"minecraft:eye_of_ender",
+	"minecraft:falling_block",
+	"minecraft:firework_rocket",
+	"minecraft:fox",
+	"minecraft:frog",
+	"minecraft:furnace_minecart",
+	"minecraft:ghast",
+	"minecraft:giant",
+	"minecraft:glow_item_frame",
+	"minecraft:glow_squid",
+	"minecraft:goat",
+	"minecraft:guardian",
+	"minecraft:hoglin",
+	"minecraft:hopper_minecart",
+	"minecraft:horse",
+	"minecraft:husk",
+	"minecraft:illusioner",
+	"minecraft:interaction",
+	"minecraft:iron_golem",
+	"minecraft:item",
+	"minecraft:item_display",
+	"minecraft:item_frame",
+	"minecraft:ominous_item_spawner",
+	"minecraft:fireball",
+	"minecraft:leash_knot",
+	"minecraft:lightning_bolt",
+	"minecraft:llama",
+	"minecraft:llama_spit",
+	"minecraft:magma_cube",
+	"minecraft:marker",
+	"minecraft:minecart",
+	"minecraft:mooshroom",
+	"minecraft:mule",
+	"minecraft:ocelot",
+	"minecraft:painting",
+	"minecraft:panda",
+	"minecraft:parrot",
+	"minecraft:phantom",
+	"minecraft:pig",
+	"minecraft:piglin",
+	"minecraft:piglin_brute",
+	"minecraft:pillager",
+	"minecraft:polar_bear",
+	"minecraft:potion",
+	"minecraft:pufferfish",
+	"minecraft:rabbit",
+	"minecraft:ravager",
+	"minecraft:salmon",
+	"minecraft:sheep",
+	"minecraft:shulker",
+	"minecraft:shulker_bullet",
+	"minecraft:silverfish",
+	"minecraft:skeleton",
+	"minecraft:skeleton_horse",
+	"minecraft:slime",
+	"minecraft:small_fireball",
+	"minecraft:sniffer",
+	"minecraft:snow_golem",
+	"minecraft:snowball",
+	"minecraft:spawner_minecart",
+	"minecraft:spectral_arrow",
+	"minecraft:spider",
+	"minecraft:squid",
+	"minecraft:stray",
+	"minecraft:strider",
+	"minecraft:tadpole",
+	"minecraft:text_display",
+	"minecraft:tnt",
+	"minecraft:tnt_minecart",
+	"minecraft:trader_llama",
+	"minecraft:trident",
+	"minecraft:tropical_fish",
+	"minecraft:turtle",
+	"minecraft:vex",
+	"minecraft:villager",
+	"minecraft:vindicator",
+	"minecraft:wandering_trader",
+	"minecraft:warden",
+	"minecraft:wind_charge",
+	"minecraft:witch",
+	"minecraft:wither",
+	"minecraft:wither_skeleton",
+	"minecraft:wither_skull",
+	"minecraft:wolf",
+	"minecraft:zoglin",
+	"minecraft:zombie",
+	"minecraft:zombie_horse",
+	"minecraft:zombie_villager",
+	"minecraft:zombified_piglin",
+	"minecraft:player",
+	"minecraft:fishing_bobber",
+}
+
+// EntityType returns the network ID of an entity type, such as
+// "minecraft:pig".
+func (v *Version) EntityType(name string) (int32, bool) {
+	for i, t := range v.entityTypes {
+		if t == name {
+			return int32(i), true
+		}
+	}
+	return 0, false
+}
+
+// WriteAngle encodes an angle in degrees as the fraction of a turn in one
+// byte, as entity packets send rotations.
+func WriteAngle(degrees float32) []byte {
+	return []byte{byte(int32(math.Floor(float64(degrees) * 256 / 360)))}
+}
+
+// MetadataType is the kind of value an entity metadata entry holds. The
+// versions number the kinds differently; Version.WriteMetadata sends each
+// with the client's number.
+type MetadataType int
+
+const (
+	MetadataByte MetadataType = iota
+	MetadataVarInt
+	MetadataVarLong
+	MetadataFloat
+	MetadataString
+	MetadataText
+	MetadataOptionalText
+	MetadataSlot
+	MetadataBool
+	MetadataRotations
+	MetadataPosition
+	MetadataOptionalPosition
+	MetadataDirection
+	MetadataOptionalUUID
+	MetadataBlockState
+	MetadataOptionalBlockState
+	MetadataNBT
+	MetadataParticle
+	MetadataParticles
+	MetadataVillagerData
+	MetadataOptionalVarInt
+	MetadataPose
+	MetadataCatVariant
+	MetadataWolfVariant
+	MetadataFrogVariant
+	MetadataOptionalGlobalPos
+	MetadataPaintingVariant
+	MetadataSnifferState
+	MetadataArmadilloState
+	MetadataVector3
+	MetadataQuaternion
+)
+
+// metadataTypes765 lists the metadata types of 1.20.3 and 1.20.4 in
+// network ID order.
+var metadataTypes765 = []MetadataType{
+	MetadataByte, MetadataVarInt, MetadataVarLong, MetadataFloat, MetadataString,
+	MetadataText, MetadataOptionalText, MetadataSlot, MetadataBool, MetadataRotations,
+	MetadataPosition, MetadataOptionalPosition, MetadataDirection, MetadataOptionalUUID,
+	MetadataBlockState, MetadataOptionalBlockState, MetadataNBT, MetadataParticle,
+	MetadataVillagerData, MetadataOptionalVarInt, MetadataPose, MetadataCatVariant,
+	MetadataFrogVariant, MetadataOptionalGlobalPos, MetadataPaintingVariant,
+	MetadataSnifferState, MetadataVector3, MetadataQuaternion,
+}
+
+// metadataTypes766 adds the particle lists, wolf variants and armadillo
+// states of 1.20.5, which 1.21 kept. MetadataType follows its order.
+var metadataTypes766 = []MetadataType{
+	MetadataByte, MetadataVarInt, MetadataVarLong, MetadataFloat, MetadataString,
+	MetadataText, MetadataOptionalText, MetadataSlot, MetadataBool, MetadataRotations,
+	MetadataPosition, MetadataOptionalPosition, MetadataDirection, MetadataOptionalUUID,
+	MetadataBlockState, MetadataOptionalBlockState, MetadataNBT, MetadataParticle,
+	MetadataParticles, MetadataVillagerData, MetadataOptionalVarInt, MetadataPose,
+	MetadataCatVariant, MetadataWolfVariant, MetadataFrogVariant, MetadataOptionalGlobalPos,
+	MetadataPaintingVariant, MetadataSnifferState, MetadataArmadilloState, MetadataVector3,
+	MetadataQuaternion,
+}
+
+// MetadataEntry is one entry of an entity's metadata: the value at an index
+// of the entity's class hierarchy, already encoded.
+type MetadataEntry struct {
+	Index uint8
+	Type  MetadataType
+	Value []byte
+}
+
+// Equal reports whether e and o hold the same value at the same index.
+func (e MetadataEntry) Equal(o MetadataEntry) bool {
+	return e.Index == o.Index && e.Type == o.Type && bytes.Equal(e.Value, o.Value)
+}
+
+// MetadataByteEntry holds a byte, such as the flags every entity has.
+func MetadataByteEntry(index uint8, b byte) MetadataEntry {
+	return MetadataEntry{index, MetadataByte, []byte{b}}
+}
+
+// MetadataVarIntEntry holds a VarInt.
+func MetadataVarIntEntry(index uint8, n int) MetadataEntry {
+	return MetadataEntry{index, MetadataVarInt, WriteVarInt(n)}
+}
+
+// MetadataFloatEntry holds a float.
+func MetadataFloatEntry(index uint8, f float32) MetadataEntry {
+	return MetadataEntry{index, MetadataFloat, WriteFloat(f)}
+}
+
+// MetadataBoolEntry holds a boolean.
+func MetadataBoolEntry(index uint8, b bool) MetadataEntry {
+	return MetadataEntry{index, MetadataBool, WriteBool(b)}
+}
+
+// MetadataPoseEntry holds a pose, such as 0 for standing.
+func MetadataPoseEntry(index uint8, pose int) MetadataEntry {
+	return MetadataEntry{index, MetadataPose, WriteVarInt(pose)}
+}
+
+// MetadataOptionalTextEntry holds a text component as network NBT, or none
+// if text is nil.
+func MetadataOptionalTextEntry(index uint8, text nbt.Tag) MetadataEntry {
+	if text == nil {
+		return MetadataEntry{index, MetadataOptionalText, WriteBool(false)}
+	}
+	return MetadataEntry{index, MetadataOptionalText, append(WriteBool(true), WriteNBT(text)...)}
+}
+
+// WriteMetadata encodes entries as the Set Entity Metadata packet carries
+// them, ending with the 0xFF terminator. Entries of a type
+// the version does not have are left out.
+func (v *Version) WriteMetadata(entries []MetadataEntry) []byte {
+	var b []byte
+	for _, e := range entries {
+		id := -1
+		for i, t := range v.metadataTypes {
+			if t == e.Type {
+				id = i
+				break
+			}
+		}
+		if id < 0 {
+			continue
+		}
+		b = append(b, e.Index)
+		b = append(b, WriteVarInt(id)...)
+		b = append(b, e.Value...)
+	}
+	return append(b, 0xFF)
+}
@@ -0,0 +1,76 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"testing"
+)
+
+// FuzzReadFrame feeds arbitrary bytes to the frame decoder, which must reject
+// anything malformed with an error rather than panic or over-allocate.
+func FuzzReadFrame(f *testing.F) {
+	f.Add([]byte{0x01, 0x00})
+	f.Add([]byte{0x00})
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0x0F})
+	f.Add([]byte{0x03, 0x80, 0x80, 0x80})
+	f.Add(append(WriteVarInt(3), 0x00, 0x01, 0x02))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bufio.NewReader(bytes.NewReader(data))
+		for {
+			_, payload, err := ReadFrame(r)
+			if err != nil {
+				return
+			}
+			if len(payload) > MaxPacketLength {
+				t.Fatalf("frame payload of %d bytes exceeds the limit", len(payload))
+			}
+		}
+	})
+}
+
+// FuzzDecodeServerbound runs arbitrary payloads through every serverbound
+// decoder. The first byte picks the packet.
+func FuzzDecodeServerbound(f *testing.F) {
+	packets := DecodablePackets()
+	sort.Slice(packets, func(i, j int) bool { return packets[i] < packets[j] })
+	index := make(map[Packet]byte, len(packets))
+	for i, p := range packets {
+		index[p] = byte(i)
+	}
+
+	handshake := append(WriteVarInt(767), WriteString("localhost")...)
+	handshake = append(handshake, WriteUnsignedShort(25565)...)
+	handshake = append(handshake, WriteVarInt(IntentLogin)...)
+	loginStart := append(WriteString("Notch"), WriteUUID(OfflineUUID("Notch"))...)
+	knownPacks := append(WriteVarInt(1), WriteString("minecraft")...)
+	knownPacks = append(knownPacks, WriteString("core")...)
+	knownPacks = append(knownPacks, WriteString("1.21.1")...)
+	seeds := map[Packet][]byte{
+		ServerboundHandshakeIntention:               handshake,
+		ServerboundStatusPing:                       WriteLong(42),
+		ServerboundLoginStart:                       loginStart,
+		ServerboundLoginPluginResponse:              append(WriteVarInt(0), 0x01, 0xAA),
+		ServerboundConfigKnownPacks:                 knownPacks,
+		ServerboundConfigPluginMessage:              append(WriteString("minecraft:brand"), WriteString("vanilla")...),
+		ServerboundPlayKeepAlive:                    WriteLong(1),
+		ServerboundPlayCookieResponse:               append(WriteString("golem:session"), 0x01, 0x02, 0xAA, 0xBB),
+		ServerboundPlayChatMessage:                  append(WriteString("hello"), append(make([]byte, 17), 0x00, 0x00, 0x00, 0x00)...),
+		ServerboundPlayChatCommand:                  WriteString("list"),
+		ServerboundPlayPlayerSession:                append(make([]byte, 24), 0x01, 0xAA, 0x01, 0xBB),
+		ServerboundPlayConfirmTeleportation:         WriteVarInt(1),
+		ServerboundPlaySetPlayerPositionAndRotation: append(make([]byte, 32), 0x01),
+	}
+	for p, payload := range seeds {
+		f.Add(index[p], payload)
+	}
+	f.Add(index[ServerboundConfigKnownPacks], WriteVarInt(1<<30))
+
+	f.Fuzz(func(t *testing.T, which byte, data []byte) {
+		p := packets[int(which)%len(packets)]
+		_, ok, _ := DecodeServerbound(&RawPacket{Packet: p, Data: data})
+		if !ok {
+			t.Fatalf("%s has no decoder", p)
+		}
+	})
+}
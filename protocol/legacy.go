@@ -0,0 +1,162 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// LegacyPingFormat identifies which pre-Netty client sent a legacy ping.
+type LegacyPingFormat int
+
+const (
+	// LegacyPingBeta is a bare 0xFE, sent by Beta 1.8 to 1.3. The answer is
+	// "motd§online§max".
+	LegacyPingBeta LegacyPingFormat = iota
+	// LegacyPing14 is 0xFE 0x01, sent by 1.4 and 1.5. The answer is the
+	// NUL-separated format starting with "§1".
+	LegacyPing14
+	// LegacyPing16 is 0xFE 0x01 0xFA followed by an MC|PingHost plugin
+	// message, sent by 1.6. It is answered like LegacyPing14.
+	LegacyPing16
+)
+
+// LegacyPingProtocol is the protocol number sent in legacy ping responses. It
+// matches no pre-Netty release, so old clients show the server as
+// incompatible, as vanilla does.
+const LegacyPingProtocol = 127
+
+// LegacyPing is a server list ping from a pre-Netty client. Only 1.6 clients
+// send the protocol, host and port.
+type LegacyPing struct {
+	Format   LegacyPingFormat
+	Protocol int
+	Host     string
+	Port     int
+}
+
+// ReadLegacyPing checks whether the connection starts with a legacy ping. If it
+// does, the ping is consumed and returned; otherwise nothing is consumed and it
+// returns nil. It must be called before the first ReadPacket.
+//
+// Like vanilla, only a lone 0xFE or a 0xFE 0x01 prefix is taken for a legacy
+// ping. A modern frame whose length VarInt starts with 0xFE, such as a
+// forwarded handshake of 254 bytes, is left for ReadPacket.
+func (c *Conn) ReadLegacyPing() (*LegacyPing, error) {
+	if err := c.conn.SetReadDeadline(time.Now().Add(c.cfg.ReadTimeout)); err != nil {
+		return nil, err
+	}
+	first, err := c.r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	if first[0] != 0xFE {
+		return nil, nil
+	}
+
+	// The variants are told apart by what arrives together with the 0xFE,
+	// the same way vanilla looks at the readable bytes of the first read.
+	prefix, _ := c.r.Peek(min(c.r.Buffered(), 3))
+	ping := &LegacyPing{Format: LegacyPingBeta}
+	switch {
+	case len(prefix) == 1:
+		_, _ = c.r.Discard(1)
+		return ping, nil
+	case prefix[1] != 0x01:
+		return nil, nil
+	case len(prefix) == 2:
+		_, _ = c.r.Discard(2)
+		ping.Format = LegacyPing14
+		return ping, nil
+	case prefix[2] != 0xFA:
+		// 0xFE 0x01 is also the length 254, followed by a packet ID.
+		return nil, nil
+	}
+	_, _ = c.r.Discard(3)
+	ping.Format = LegacyPing16
+	// MC|PingHost: channel, data length, protocol, host and port. A malformed
+	// message is still answered; only the host is lost.
+	if channel, err := readLegacyString(c.r); err != nil || channel != "MC|PingHost" {
+		return ping, nil
+	}
+	if _, err := ReadUnsignedShort(c.r); err != nil {
+		return ping, nil
+	}
+	proto, err := c.r.ReadByte()
+	if err != nil {
+		return ping, nil
+	}
+	host, err := readLegacyString(c.r)
+	if err != nil {
+		return ping, nil
+	}
+	port, err := ReadInt(c.r)
+	if err != nil {
+		return ping, nil
+	}
+	ping.Protocol, ping.Host, ping.Port = int(proto), host, int(port)
+	return ping, nil
+}
+
+// DisconnectLegacy answers a legacy ping with a Kick packet (0xFF) carrying
+// reason, then closes the connection.
+func (c *Conn) DisconnectLegacy(reason string) {
+	units := utf16.Encode([]rune(reason))
+	frame := make([]byte, 3+2*len(units))
+	frame[0] = 0xFF
+	binary.BigEndian.PutUint16(frame[1:], uint16(len(units)))
+	for i, u := range units {
+		binary.BigEndian.PutUint16(frame[3+2*i:], u)
+	}
+	select {
+	case c.out <- frame:
+	default:
+	}
+	c.flushAndClose(ErrConnClosed)
+}
+
+// LegacyPingResponse formats the kick reason that answers a legacy ping. The
+// Beta format cannot carry formatting codes, so they are stripped from motd.
+func LegacyPingResponse(format LegacyPingFormat, versionName, motd string, online, max int) string {
+	if format == LegacyPingBeta {
+		return strings.Join([]string{stripFormatting(motd), strconv.Itoa(online), strconv.Itoa(max)}, "§")
+	}
+	return strings.Join([]string{"§1", strconv.Itoa(LegacyPingProtocol), versionName, motd, strconv.Itoa(online), strconv.Itoa(max)}, "\x00")
+}
+
+// readLegacyString reads a short-prefixed UTF-16BE string.
+func readLegacyString(r Reader) (string, error) {
+	n, err := ReadUnsignedShort(r)
+	if err != nil {
+		return "", err
+	}
+	data := make([]byte, 2*int(n))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return "", err
+	}
+	units := make([]uint16, n)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// stripFormatting removes § formatting codes.
+func stripFormatting(s string) string {
+	var b strings.Builder
+	skip := false
+	for _, r := range s {
+		switch {
+		case skip:
+			skip = false
+		case r == '§':
+			skip = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
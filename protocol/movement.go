@@ -0,0 +1,116 @@
+package protocol
+
+import "bytes"
+
+// Flags of the Player Abilities packets.
+const (
+	AbilityInvulnerable = 0x01
+	AbilityFlying       = 0x02
+	AbilityAllowFlying  = 0x04
+	AbilityInstantBreak = 0x08
+)
+
+// ConfirmTeleportation acknowledges a Synchronize Player Position with its
+// teleport ID.
+type ConfirmTeleportation struct {
+	TeleportID int32 `json:"teleportId"`
+}
+
+func (p *ConfirmTeleportation) Decode(r *bytes.Reader) (err error) {
+	p.TeleportID, err = ReadVarInt(r)
+	return err
+}
+
+// SetPlayerPosition moves the player. Y is the height of the feet.
+type SetPlayerPosition struct {
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Z        float64 `json:"z"`
+	OnGround bool    `json:"onGround"`
+}
+
+func (p *SetPlayerPosition) Decode(r *bytes.Reader) (err error) {
+	if p.X, err = ReadDouble(r); err != nil {
+		return err
+	}
+	if p.Y, err = ReadDouble(r); err != nil {
+		return err
+	}
+	if p.Z, err = ReadDouble(r); err != nil {
+		return err
+	}
+	p.OnGround, err = ReadBool(r)
+	return err
+}
+
+// SetPlayerRotation turns the player. Angles are in degrees.
+type SetPlayerRotation struct {
+	Yaw      float32 `json:"yaw"`
+	Pitch    float32 `json:"pitch"`
+	OnGround bool    `json:"onGround"`
+}
+
+func (p *SetPlayerRotation) Decode(r *bytes.Reader) (err error) {
+	if p.Yaw, err = ReadFloat(r); err != nil {
+		return err
+	}
+	if p.Pitch, err = ReadFloat(r); err != nil {
+		return err
+	}
+	p.OnGround, err = ReadBool(r)
+	return err
+}
+
+// SetPlayerPositionAndRotation moves and turns the player at once.
+type SetPlayerPositionAndRotation struct {
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Z        float64 `json:"z"`
+	Yaw      float32 `json:"yaw"`
+	Pitch    float32 `json:"pitch"`
+	OnGround bool    `json:"onGround"`
+}
+
+func (p *SetPlayerPositionAndRotation) Decode(r *bytes.Reader) (err error) {
+	if p.X, err = ReadDouble(r); err != nil {
+		return err
+	}
+	if p.Y, err = ReadDouble(r); err != nil {
+		return err
+	}
+	if p.Z, err = ReadDouble(r); err != nil {
+		return err
+	}
+	if p.Yaw, err = ReadFloat(r); err != nil {
+		return err
+	}
+	if p.Pitch, err = ReadFloat(r); err != nil {
+		return err
+	}
+	p.OnGround, err = ReadBool(r)
+	return err
+}
+
+// SetPlayerOnGround is sent when the player neither moved nor turned.
+type SetPlayerOnGround struct {
+	OnGround bool `json:"onGround"`
+}
+
+func (p *SetPlayerOnGround) Decode(r *bytes.Reader) (err error) {
+	p.OnGround, err = ReadBool(r)
+	return err
+}
+
+// PlayerAbilities is sent when the player starts or stops flying. Only
+// AbilityFlying is meaningful from the client.
+type PlayerAbilities struct {
+	Flags byte `json:"flags"`
+}
+
+func (p *PlayerAbilities) Decode(r *bytes.Reader) (err error) {
+	p.Flags, err = r.ReadByte()
+	return err
+}
+
+// Flying reports whether the client says it is flying.
+func (p *PlayerAbilities) Flying() bool { return p.Flags&AbilityFlying != 0 }
@@ -0,0 +1,492 @@
+package protocol
+
+import "fmt"
+
+// Packet identifies a packet independently of the numeric ID a particular
+// protocol version assigns to it. Use Version.ID and Version.Packet to map
+// between the two.
+type Packet int
+
+const (
+	// UnknownPacket is returned for IDs a version does not define.
+	UnknownPacket Packet = iota
+
+	// Handshaking, serverbound.
+	ServerboundHandshakeIntention
+
+	// Status, serverbound.
+	ServerboundStatusRequest
+	ServerboundStatusPing
+
+	// Status, clientbound.
+	ClientboundStatusResponse
+	ClientboundStatusPong
+
+	// Login, serverbound.
+	ServerboundLoginStart
+	ServerboundLoginEncryptionResponse
+	ServerboundLoginPluginResponse
+	ServerboundLoginAcknowledged
+	ServerboundLoginCookieResponse
+
+	// Login, clientbound.
+	ClientboundLoginDisconnect
+	ClientboundLoginEncryptionRequest
+	ClientboundLoginSuccess
+	ClientboundLoginSetCompression
+	ClientboundLoginPluginRequest
+	ClientboundLoginCookieRequest
+
+	// Configuration, serverbound.
+	ServerboundConfigClientInformation
+	ServerboundConfigCookieResponse
+	ServerboundConfigPluginMessage
+	ServerboundConfigAcknowledgeFinish
+	ServerboundConfigKeepAlive
+	ServerboundConfigPong
+	ServerboundConfigResourcePackResponse
+	ServerboundConfigKnownPacks
+	ServerboundConfigCustomReportDetails
+	ServerboundConfigServerLinks
+
+	// Configuration, clientbound.
+	ClientboundConfigCookieRequest
+	ClientboundConfigPluginMessage
+	ClientboundConfigDisconnect
+	ClientboundConfigFinish
+	ClientboundConfigKeepAlive
+	ClientboundConfigPing
+	ClientboundConfigResetChat
+	ClientboundConfigRegistryData
+	ClientboundConfigRemoveResourcePack
+	ClientboundConfigAddResourcePack
+	ClientboundConfigStoreCookie
+	ClientboundConfigTransfer
+	ClientboundConfigFeatureFlags
+	ClientboundConfigUpdateTags
+	ClientboundConfigKnownPacks
+	ClientboundConfigCustomReportDetails
+	ClientboundConfigServerLinks
+
+	// Play, serverbound.
+	ServerboundPlayConfirmTeleportation
+	ServerboundPlayQueryBlockEntityTag
+	ServerboundPlayChangeDifficulty
+	ServerboundPlayAcknowledgeMessage
+	ServerboundPlayChatCommand
+	ServerboundPlaySignedChatCommand
+	ServerboundPlayChatMessage
+	ServerboundPlayPlayerSession
+	ServerboundPlayChunkBatchReceived
+	ServerboundPlayClientStatus
+	ServerboundPlayClientInformation
+	ServerboundPlayCommandSuggestionsRequest
+	ServerboundPlayAcknowledgeConfiguration
+	ServerboundPlayClickContainerButton
+	ServerboundPlayClickContainer
+	ServerboundPlayCloseContainer
+	ServerboundPlayChangeContainerSlotState
+	ServerboundPlayCookieResponse
+	ServerboundPlayPluginMessage
+	ServerboundPlayDebugSampleSubscription
+	ServerboundPlayEditBook
+	ServerboundPlayQueryEntityTag
+	ServerboundPlayInteract
+	ServerboundPlayJigsawGenerate
+	ServerboundPlayKeepAlive
+	ServerboundPlayLockDifficulty
+	ServerboundPlaySetPlayerPosition
+	ServerboundPlaySetPlayerPositionAndRotation
+	ServerboundPlaySetPlayerRotation
+	ServerboundPlaySetPlayerOnGround
+	ServerboundPlayMoveVehicle
+	ServerboundPlayPaddleBoat
+	ServerboundPlayPickItem
+	ServerboundPlayPingRequest
+	ServerboundPlayPlaceRecipe
+	ServerboundPlayPlayerAbilities
+	ServerboundPlayPlayerAction
+	ServerboundPlayPlayerCommand
+	ServerboundPlayPlayerInput
+	ServerboundPlayPong
+	ServerboundPlayChangeRecipeBookSettings
+	ServerboundPlaySetSeenRecipe
+	ServerboundPlayRenameItem
+	ServerboundPlayResourcePackResponse
+	ServerboundPlaySeenAdvancements
+	ServerboundPlaySelectTrade
+	ServerboundPlaySetBeaconEffect
+	ServerboundPlaySetHeldItem
+	ServerboundPlayProgramCommandBlock
+	ServerboundPlayProgramCommandBlockMinecart
+	ServerboundPlaySetCreativeModeSlot
+	ServerboundPlayProgramJigsawBlock
+	ServerboundPlayProgramStructureBlock
+	ServerboundPlayUpdateSign
+	ServerboundPlaySwingArm
+	ServerboundPlayTeleportToEntity
+	ServerboundPlayUseItemOn
+	ServerboundPlayUseItem
+
+	// Play, clientbound.
+	ClientboundPlayBundleDelimiter
+	ClientboundPlaySpawnEntity
+	ClientboundPlaySpawnExperienceOrb
+	ClientboundPlayEntityAnimation
+	ClientboundPlayAwardStatistics
+	ClientboundPlayAcknowledgeBlockChange
+	ClientboundPlaySetBlockDestroyStage
+	ClientboundPlayBlockEntityData
+	ClientboundPlayBlockAction
+	ClientboundPlayBlockUpdate
+	ClientboundPlayBossBar
+	ClientboundPlayChangeDifficulty
+	ClientboundPlayChunkBatchFinished
+	ClientboundPlayChunkBatchStart
+	ClientboundPlayChunkBiomes
+	ClientboundPlayClearTitles
+	ClientboundPlayCommandSuggestionsResponse
+	ClientboundPlayCommands
+	ClientboundPlayCloseContainer
+	ClientboundPlaySetContainerContent
+	ClientboundPlaySetContainerProperty
+	ClientboundPlaySetContainerSlot
+	ClientboundPlayCookieRequest
+	ClientboundPlaySetCooldown
+	ClientboundPlayChatSuggestions
+	ClientboundPlayPluginMessage
+	ClientboundPlayDamageEvent
+	ClientboundPlayDebugSample
+	ClientboundPlayDeleteMessage
+	ClientboundPlayDisconnect
+	ClientboundPlayDisguisedChatMessage
+	ClientboundPlayEntityEvent
+	ClientboundPlayExplosion
+	ClientboundPlayUnloadChunk
+	ClientboundPlayGameEvent
+	ClientboundPlayOpenHorseScreen
+	ClientboundPlayHurtAnimation
+	ClientboundPlayInitializeWorldBorder
+	ClientboundPlayKeepAlive
+	ClientboundPlayChunkDataAndUpdateLight
+	ClientboundPlayWorldEvent
+	ClientboundPlayParticle
+	ClientboundPlayUpdateLight
+	ClientboundPlayLogin
+	ClientboundPlayMapData
+	ClientboundPlayMerchantOffers
+	ClientboundPlayUpdateEntityPosition
+	ClientboundPlayUpdateEntityPositionAndRotation
+	ClientboundPlayUpdateEntityRotation
+	ClientboundPlayMoveVehicle
+	ClientboundPlayOpenBook
+	ClientboundPlayOpenScreen
+	ClientboundPlayOpenSignEditor
+	ClientboundPlayPing
+	ClientboundPlayPingResponse
+	ClientboundPlayPlaceGhostRecipe
+	ClientboundPlayPlayerAbilities
+	ClientboundPlayPlayerChatMessage
+	ClientboundPlayEndCombat
+	ClientboundPlayEnterCombat
+	ClientboundPlayCombatDeath
+	ClientboundPlayPlayerInfoRemove
+	ClientboundPlayPlayerInfoUpdate
+	ClientboundPlayLookAt
+	ClientboundPlaySynchronizePlayerPosition
+	ClientboundPlayUpdateRecipeBook
+	ClientboundPlayRemoveEntities
+	ClientboundPlayRemoveEntityEffect
+	ClientboundPlayResetScore
+	ClientboundPlayRemoveResourcePack
+	ClientboundPlayAddResourcePack
+	ClientboundPlayRespawn
+	ClientboundPlaySetHeadRotation
+	ClientboundPlayUpdateSectionBlocks
+	ClientboundPlaySelectAdvancementsTab
+	ClientboundPlayServerData
+	ClientboundPlaySetActionBarText
+	ClientboundPlaySetBorderCenter
+	ClientboundPlaySetBorderLerpSize
+	ClientboundPlaySetBorderSize
+	ClientboundPlaySetBorderWarningDelay
+	ClientboundPlaySetBorderWarningDistance
+	ClientboundPlaySetCamera
+	ClientboundPlaySetHeldItem
+	ClientboundPlaySetCenterChunk
+	ClientboundPlaySetRenderDistance
+	ClientboundPlaySetDefaultSpawnPosition
+	ClientboundPlayDisplayObjective
+	ClientboundPlaySetEntityMetadata
+	ClientboundPlayLinkEntities
+	ClientboundPlaySetEntityVelocity
+	ClientboundPlaySetEquipment
+	ClientboundPlaySetExperience
+	ClientboundPlaySetHealth
+	ClientboundPlayUpdateObjectives
+	ClientboundPlaySetPassengers
+	ClientboundPlayUpdateTeams
+	ClientboundPlayUpdateScore
+	ClientboundPlaySetSimulationDistance
+	ClientboundPlaySetSubtitleText
+	ClientboundPlayUpdateTime
+	ClientboundPlaySetTitleText
+	ClientboundPlaySetTitleAnimationTimes
+	ClientboundPlayEntitySoundEffect
+	ClientboundPlaySoundEffect
+	ClientboundPlayStartConfiguration
+	ClientboundPlayStopSound
+	ClientboundPlayStoreCookie
+	ClientboundPlaySystemChatMessage
+	ClientboundPlaySetTabListHeaderAndFooter
+	ClientboundPlayTagQueryResponse
+	ClientboundPlayPickupItem
+	ClientboundPlayTeleportEntity
+	ClientboundPlaySetTickingState
+	ClientboundPlayStepTick
+	ClientboundPlayTransfer
+	ClientboundPlayUpdateAdvancements
+	ClientboundPlayUpdateAttributes
+	ClientboundPlayEntityEffect
+	ClientboundPlayUpdateRecipes
+	ClientboundPlayUpdateTags
+	ClientboundPlayProjectilePower
+	ClientboundPlayCustomReportDetails
+	ClientboundPlayServerLinks
+)
+
+// packetNames holds the human readable name of every packet, used in logs and captures.
+var packetNames = map[Packet]string{
+	ServerboundHandshakeIntention:                  "HandshakeIntention",
+	ServerboundStatusRequest:                       "StatusRequest",
+	ServerboundStatusPing:                          "StatusPing",
+	ClientboundStatusResponse:                      "StatusResponse",
+	ClientboundStatusPong:                          "StatusPong",
+	ServerboundLoginStart:                          "LoginStart",
+	ServerboundLoginEncryptionResponse:             "LoginEncryptionResponse",
+	ServerboundLoginPluginResponse:                 "LoginPluginResponse",
+	ServerboundLoginAcknowledged:                   "LoginAcknowledged",
+	ServerboundLoginCookieResponse:                 "LoginCookieResponse",
+	ClientboundLoginDisconnect:                     "LoginDisconnect",
+	ClientboundLoginEncryptionRequest:              "LoginEncryptionRequest",
+	ClientboundLoginSuccess:                        "LoginSuccess",
+	ClientboundLoginSetCompression:                 "LoginSetCompression",
+	ClientboundLoginPluginRequest:                  "LoginPluginRequest",
+	ClientboundLoginCookieRequest:                  "LoginCookieRequest",
+	ServerboundConfigClientInformation:             "ConfigClientInformation",
+	ServerboundConfigCookieResponse:                "ConfigCookieResponse",
+	ServerboundConfigPluginMessage:                 "ConfigPluginMessage",
+	ServerboundConfigAcknowledgeFinish:             "ConfigAcknowledgeFinish",
+	ServerboundConfigKeepAlive:                     "ConfigKeepAlive",
+	ServerboundConfigPong:                          "ConfigPong",
+	ServerboundConfigResourcePackResponse:          "ConfigResourcePackResponse",
+	ServerboundConfigKnownPacks:                    "ConfigKnownPacks",
+	ServerboundConfigCustomReportDetails:           "ConfigCustomReportDetails",
+	ServerboundConfigServerLinks:                   "ConfigServerLinks",
+	ClientboundConfigCookieRequest:                 "ConfigCookieRequest",
+	ClientboundConfigPluginMessage:                 "ConfigPluginMessage",
+	ClientboundConfigDisconnect:                    "ConfigDisconnect",
+	ClientboundConfigFinish:                        "ConfigFinish",
+	ClientboundConfigKeepAlive:                     "ConfigKeepAlive",
+	ClientboundConfigPing:                          "ConfigPing",
+	ClientboundConfigResetChat:                     "ConfigResetChat",
+	ClientboundConfigRegistryData:                  "ConfigRegistryData",
+	ClientboundConfigRemoveResourcePack:            "ConfigRemoveResourcePack",
+	ClientboundConfigAddResourcePack:               "ConfigAddResourcePack",
+	ClientboundConfigStoreCookie:                   "ConfigStoreCookie",
+	ClientboundConfigTransfer:                      "ConfigTransfer",
+	ClientboundConfigFeatureFlags:                  "ConfigFeatureFlags",
+	ClientboundConfigUpdateTags:                    "ConfigUpdateTags",
+	ClientboundConfigKnownPacks:                    "ConfigKnownPacks",
+	ClientboundConfigCustomReportDetails:           "ConfigCustomReportDetails",
+	ClientboundConfigServerLinks:                   "ConfigServerLinks",
+	ServerboundPlayConfirmTeleportation:            "PlayConfirmTeleportation",
+	ServerboundPlayQueryBlockEntityTag:             "PlayQueryBlockEntityTag",
+	ServerboundPlayChangeDifficulty:                "PlayChangeDifficulty",
+	ServerboundPlayAcknowledgeMessage:              "PlayAcknowledgeMessage",
+	ServerboundPlayChatCommand:                     "PlayChatCommand",
+	ServerboundPlaySignedChatCommand:               "PlaySignedChatCommand",
+	ServerboundPlayChatMessage:                     "PlayChatMessage",
+	ServerboundPlayPlayerSession:                   "PlayPlayerSession",
+	ServerboundPlayChunkBatchReceived:              "PlayChunkBatchReceived",
+	ServerboundPlayClientStatus:                    "PlayClientStatus",
+	ServerboundPlayClientInformation:               "PlayClientInformation",
+	ServerboundPlayCommandSuggestionsRequest:       "PlayCommandSuggestionsRequest",
+	ServerboundPlayAcknowledgeConfiguration:        "PlayAcknowledgeConfiguration",
+	ServerboundPlayClickContainerButton:            "PlayClickContainerButton",
+	ServerboundPlayClickContainer:                  "PlayClickContainer",
+	ServerboundPlayCloseContainer:                  "PlayCloseContainer",
+	ServerboundPlayChangeContainerSlotState:        "PlayChangeContainerSlotState",
+	ServerboundPlayCookieResponse:                  "PlayCookieResponse",
+	ServerboundPlayPluginMessage:                   "PlayPluginMessage",
+	ServerboundPlayDebugSampleSubscription:         "PlayDebugSampleSubscription",
+	ServerboundPlayEditBook:                        "PlayEditBook",
+	ServerboundPlayQueryEntityTag:                  "PlayQueryEntityTag",
+	ServerboundPlayInteract:                        "PlayInteract",
+	ServerboundPlayJigsawGenerate:                  "PlayJigsawGenerate",
+	ServerboundPlayKeepAlive:                       "PlayKeepAlive",
+	ServerboundPlayLockDifficulty:                  "PlayLockDifficulty",
+	ServerboundPlaySetPlayerPosition:               "PlaySetPlayerPosition",
+	ServerboundPlaySetPlayerPositionAndRotation:    "PlaySetPlayerPositionAndRotation",
+	ServerboundPlaySetPlayerRotation:               "PlaySetPlayerRotation",
+	ServerboundPlaySetPlayerOnGround:               "PlaySetPlayerOnGround",
+	ServerboundPlayMoveVehicle:                     "PlayMoveVehicle",
+	ServerboundPlayPaddleBoat:                      "PlayPaddleBoat",
+	ServerboundPlayPickItem:                        "PlayPickItem",
+	ServerboundPlayPingRequest:                     "PlayPingRequest",
+	ServerboundPlayPlaceRecipe:                     "PlayPlaceRecipe",
+	ServerboundPlayPlayerAbilities:                 "PlayPlayerAbilities",
+	ServerboundPlayPlayerAction:                    "PlayPlayerAction",
+	ServerboundPlayPlayerCommand:                   "PlayPlayerCommand",
+	ServerboundPlayPlayerInput:                     "PlayPlayerInput",
+	ServerboundPlayPong:                            "PlayPong",
+	ServerboundPlayChangeRecipeBookSettings:        "PlayChangeRecipeBookSettings",
+	ServerboundPlaySetSeenRecipe:                   "PlaySetSeenRecipe",
+	ServerboundPlayRenameItem:                      "PlayRenameItem",
+	ServerboundPlayResourcePackResponse:            "PlayResourcePackResponse",
+	ServerboundPlaySeenAdvancements:                "PlaySeenAdvancements",
+	ServerboundPlaySelectTrade:                     "PlaySelectTrade",
+	ServerboundPlaySetBeaconEffect:                 "PlaySetBeaconEffect",
+	ServerboundPlaySetHeldItem:                     "PlaySetHeldItem",
+	ServerboundPlayProgramCommandBlock:             "PlayProgramCommandBlock",
+	ServerboundPlayProgramCommandBlockMinecart:     "PlayProgramCommandBlockMinecart",
+	ServerboundPlaySetCreativeModeSlot:             "PlaySetCreativeModeSlot",
+	ServerboundPlayProgramJigsawBlock:              "PlayProgramJigsawBlock",
+	ServerboundPlayProgramStructureBlock:           "PlayProgramStructureBlock",
+	ServerboundPlayUpdateSign:                      "PlayUpdateSign",
+	ServerboundPlaySwingArm:                        "PlaySwingArm",
+	ServerboundPlayTeleportToEntity:                "PlayTeleportToEntity",
+	ServerboundPlayUseItemOn:                       "PlayUseItemOn",
+	ServerboundPlayUseItem:                         "PlayUseItem",
+	ClientboundPlayBundleDelimiter:                 "PlayBundleDelimiter",
+	ClientboundPlaySpawnEntity:                     "PlaySpawnEntity",
+	ClientboundPlaySpawnExperienceOrb:              "PlaySpawnExperienceOrb",
+	ClientboundPlayEntityAnimation:                 "PlayEntityAnimation",
+	ClientboundPlayAwardStatistics:                 "PlayAwardStatistics",
+	ClientboundPlayAcknowledgeBlockChange:          "PlayAcknowledgeBlockChange",
+	ClientboundPlaySetBlockDestroyStage:            "PlaySetBlockDestroyStage",
+	ClientboundPlayBlockEntityData:                 "PlayBlockEntityData",
+	ClientboundPlayBlockAction:                     "PlayBlockAction",
+	ClientboundPlayBlockUpdate:                     "PlayBlockUpdate",
+	ClientboundPlayBossBar:                         "PlayBossBar",
+	ClientboundPlayChangeDifficulty:                "PlayChangeDifficulty",
+	ClientboundPlayChunkBatchFinished:              "PlayChunkBatchFinished",
+	ClientboundPlayChunkBatchStart:                 "PlayChunkBatchStart",
+	ClientboundPlayChunkBiomes:                     "PlayChunkBiomes",
+	ClientboundPlayClearTitles:                     "PlayClearTitles",
+	ClientboundPlayCommandSuggestionsResponse:      "PlayCommandSuggestionsResponse",
+	ClientboundPlayCommands:                        "PlayCommands",
+	ClientboundPlayCloseContainer:                  "PlayCloseContainer",
+	ClientboundPlaySetContainerContent:             "PlaySetContainerContent",
+	ClientboundPlaySetContainerProperty:            "PlaySetContainerProperty",
+	ClientboundPlaySetContainerSlot:                "PlaySetContainerSlot",
+	ClientboundPlayCookieRequest:                   "PlayCookieRequest",
+	ClientboundPlaySetCooldown:                     "PlaySetCooldown",
+	ClientboundPlayChatSuggestions:                 "PlayChatSuggestions",
+	ClientboundPlayPluginMessage:                   "PlayPluginMessage",
+	ClientboundPlayDamageEvent:                     "PlayDamageEvent",
+	ClientboundPlayDebugSample:                     "PlayDebugSample",
+	ClientboundPlayDeleteMessage:                   "PlayDeleteMessage",
+	ClientboundPlayDisconnect:                      "PlayDisconnect",
+	ClientboundPlayDisguisedChatMessage:            "PlayDisguisedChatMessage",
+	ClientboundPlayEntityEvent:                     "PlayEntityEvent",
+	ClientboundPlayExplosion:                       "PlayExplosion",
+	ClientboundPlayUnloadChunk:                     "PlayUnloadChunk",
+	ClientboundPlayGameEvent:                       "PlayGameEvent",
+	ClientboundPlayOpenHorseScreen:                 "PlayOpenHorseScreen",
+	ClientboundPlayHurtAnimation:                   "PlayHurtAnimation",
+	ClientboundPlayInitializeWorldBorder:           "PlayInitializeWorldBorder",
+	ClientboundPlayKeepAlive:                       "PlayKeepAlive",
+	ClientboundPlayChunkDataAndUpdateLight:         "PlayChunkDataAndUpdateLight",
+	ClientboundPlayWorldEvent:                      "PlayWorldEvent",
+	ClientboundPlayParticle:                        "PlayParticle",
+	ClientboundPlayUpdateLight:                     "PlayUpdateLight",
+	ClientboundPlayLogin:                           "PlayLogin",
+	ClientboundPlayMapData:                         "PlayMapData",
+	ClientboundPlayMerchantOffers:                  "PlayMerchantOffers",
+	ClientboundPlayUpdateEntityPosition:            "PlayUpdateEntityPosition",
+	ClientboundPlayUpdateEntityPositionAndRotation: "PlayUpdateEntityPositionAndRotation",
+	ClientboundPlayUpdateEntityRotation:            "PlayUpdateEntityRotation",
+	ClientboundPlayMoveVehicle:                     "PlayMoveVehicle",
+	ClientboundPlayOpenBook:                        "PlayOpenBook",
+	ClientboundPlayOpenScreen:                      "PlayOpenScreen",
+	ClientboundPlayOpenSignEditor:                  "PlayOpenSignEditor",
+	ClientboundPlayPing:                            "PlayPing",
+	ClientboundPlayPingResponse:                    "PlayPingResponse",
+	ClientboundPlayPlaceGhostRecipe:                "PlayPlaceGhostRecipe",
+	ClientboundPlayPlayerAbilities:                 "PlayPlayerAbilities",
+	ClientboundPlayPlayerChatMessage:               "PlayPlayerChatMessage",
+	ClientboundPlayEndCombat:                       "PlayEndCombat",
+	ClientboundPlayEnterCombat:                     "PlayEnterCombat",
+	ClientboundPlayCombatDeath:                     "PlayCombatDeath",
+	ClientboundPlayPlayerInfoRemove:                "PlayPlayerInfoRemove",
+	ClientboundPlayPlayerInfoUpdate:                "PlayPlayerInfoUpdate",
+	ClientboundPlayLookAt:                          "PlayLookAt",
+	ClientboundPlaySynchronizePlayerPosition:       "PlaySynchronizePlayerPosition",
+	ClientboundPlayUpdateRecipeBook:                "PlayUpdateRecipeBook",
+	ClientboundPlayRemoveEntities:                  "PlayRemoveEntities",
+	ClientboundPlayRemoveEntityEffect:              "PlayRemoveEntityEffect",
+	ClientboundPlayResetScore:                      "PlayResetScore",
+	ClientboundPlayRemoveResourcePack:              "PlayRemoveResourcePack",
+	ClientboundPlayAddResourcePack:                 "PlayAddResourcePack",
+	ClientboundPlayRespawn:                         "PlayRespawn",
+	ClientboundPlaySetHeadRotation:                 "PlaySetHeadRotation",
+	ClientboundPlayUpdateSectionBlocks:             "PlayUpdateSectionBlocks",
+	ClientboundPlaySelectAdvancementsTab:           "PlaySelectAdvancementsTab",
+	ClientboundPlayServerData:                      "PlayServerData",
+	ClientboundPlaySetActionBarText:                "PlaySetActionBarText",
+	ClientboundPlaySetBorderCenter:                 "PlaySetBorderCenter",
+	ClientboundPlaySetBorderLerpSize:               "PlaySetBorderLerpSize",
+	ClientboundPlaySetBorderSize:                   "PlaySetBorderSize",
+	ClientboundPlaySetBorderWarningDelay:           "PlaySetBorderWarningDelay",
+	ClientboundPlaySetBorderWarningDistance:        "PlaySetBorderWarningDistance",
+	ClientboundPlaySetCamera:                       "PlaySetCamera",
+	ClientboundPlaySetHeldItem:                     "PlaySetHeldItem",
+	ClientboundPlaySetCenterChunk:                  "PlaySetCenterChunk",
+	ClientboundPlaySetRenderDistance:               "PlaySetRenderDistance",
+	ClientboundPlaySetDefaultSpawnPosition:         "PlaySetDefaultSpawnPosition",
+	ClientboundPlayDisplayObjective:                "PlayDisplayObjective",
+	ClientboundPlaySetEntityMetadata:               "PlaySetEntityMetadata",
+	ClientboundPlayLinkEntities:                    "PlayLinkEntities",
+	ClientboundPlaySetEntityVelocity:               "PlaySetEntityVelocity",
+	ClientboundPlaySetEquipment:                    "PlaySetEquipment",
+	ClientboundPlaySetExperience:                   "PlaySetExperience",
+	ClientboundPlaySetHealth:                       "PlaySetHealth",
+	ClientboundPlayUpdateObjectives:                "PlayUpdateObjectives",
+	ClientboundPlaySetPassengers:                   "PlaySetPassengers",
+	ClientboundPlayUpdateTeams:                     "PlayUpdateTeams",
+	ClientboundPlayUpdateScore:                     "PlayUpdateScore",
+	ClientboundPlaySetSimulationDistance:           "PlaySetSimulationDistance",
+	ClientboundPlaySetSubtitleText:                 "PlaySetSubtitleText",
+	ClientboundPlayUpdateTime:                      "PlayUpdateTime",
+	ClientboundPlaySetTitleText:                    "PlaySetTitleText",
+	ClientboundPlaySetTitleAnimationTimes:          "PlaySetTitleAnimationTimes",
+	ClientboundPlayEntitySoundEffect:               "PlayEntitySoundEffect",
+	ClientboundPlaySoundEffect:                     "PlaySoundEffect",
+	ClientboundPlayStartConfiguration:              "PlayStartConfiguration",
+	ClientboundPlayStopSound:                       "PlayStopSound",
+	ClientboundPlayStoreCookie:                     "PlayStoreCookie",
+	ClientboundPlaySystemChatMessage:               "PlaySystemChatMessage",
+	ClientboundPlaySetTabListHeaderAndFooter:       "PlaySetTabListHeaderAndFooter",
+	ClientboundPlayTagQueryResponse:                "PlayTagQueryResponse",
+	ClientboundPlayPickupItem:                      "PlayPickupItem",
+	ClientboundPlayTeleportEntity:                  "PlayTeleportEntity",
+	ClientboundPlaySetTickingState:                 "PlaySetTickingState",
+	ClientboundPlayStepTick:                        "PlayStepTick",
+	ClientboundPlayTransfer:                        "PlayTransfer",
+	ClientboundPlayUpdateAdvancements:              "PlayUpdateAdvancements",
+	ClientboundPlayUpdateAttributes:                "PlayUpdateAttributes",
+	ClientboundPlayEntityEffect:                    "PlayEntityEffect",
+	ClientboundPlayUpdateRecipes:                   "PlayUpdateRecipes",
+	ClientboundPlayUpdateTags:                      "PlayUpdateTags",
+	ClientboundPlayProjectilePower:                 "PlayProjectilePower",
+	ClientboundPlayCustomReportDetails:             "PlayCustomReportDetails",
+	ClientboundPlayServerLinks:                     "PlayServerLinks",
+}
+
+func (p Packet) String() string {
+	if name, ok := packetNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Packet(%d)", int(p))
+}
@@ -0,0 +1,60 @@
+// Package protocol implements the pieces of the Minecraft: Java Edition network
+// protocol that are shared by every connection: connection states, the
+// per-version packet ID tables and the primitive wire types.
+package protocol
+
+import "fmt"
+
+// State is the connection state a packet belongs to.
+type State int
+
+const (
+	Handshaking State = iota
+	Status
+	Login
+	Configuration
+	Play
+
+	numStates
+)
+
+func (s State) String() string {
+	switch s {
+	case Handshaking:
+		return "handshaking"
+	case Status:
+		return "status"
+	case Login:
+		return "login"
+	case Configuration:
+		return "configuration"
+	case Play:
+		return "play"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Direction tells whether a packet is sent by the client or by the server.
+type Direction int
+
+const (
+	Serverbound Direction = iota
+	Clientbound
+)
+
+func (d Direction) String() string {
+	if d == Serverbound {
+		return "serverbound"
+	}
+	return "clientbound"
+}
+
+// Intent values a client may request in the Handshake packet's next state field.
+const (
+	IntentStatus = 1
+	IntentLogin  = 2
+	// IntentTransfer is a login by a client another server sent here with
+	// Transfer.
+	IntentTransfer = 3
+)
@@ -0,0 +1,301 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVarInt checks the encoding against the examples from the protocol documentation.
+func TestVarInt(t *testing.T) {
+	cases := map[int][]byte{
+		0:           {0x00},
+		1:           {0x01},
+		127:         {0x7f},
+		128:         {0x80, 0x01},
+		255:         {0xff, 0x01},
+		25565:       {0xdd, 0xc7, 0x01},
+		2097151:     {0xff, 0xff, 0x7f},
+		2147483647:  {0xff, 0xff, 0xff, 0xff, 0x07},
+		-1:          {0xff, 0xff, 0xff, 0xff, 0x0f},
+		-2147483648: {0x80, 0x80, 0x80, 0x80, 0x08},
+	}
+	for value, encoded := range cases {
+		assert.Equal(t, encoded, WriteVarInt(value), "encoding %d", value)
+		decoded, err := ReadVarInt(bytes.NewReader(encoded))
+		require.NoError(t, err)
+		assert.Equal(t, int32(value), decoded, "decoding %d", value)
+	}
+
+	_, err := ReadVarInt(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}))
+	assert.ErrorIs(t, err, ErrVarIntTooBig)
+}
+
+func TestStringLimits(t *testing.T) {
+	s, err := ReadString(bytes.NewReader(WriteString("Notch")), 16)
+	require.NoError(t, err)
+	assert.Equal(t, "Notch", s)
+
+	_, err = ReadString(bytes.NewReader(WriteString("ThisNameIsFarTooLong")), 16)
+	assert.Error(t, err, "strings over the limit should be rejected")
+}
+
+func TestOfflineUUID(t *testing.T) {
+	// Matches UUID.nameUUIDFromBytes("OfflinePlayer:Notch") in Java.
+	assert.Equal(t, "b50ad385-829d-3141-a216-7e7d7539ba7f", OfflineUUID("Notch").String())
+
+	parsed, err := ParseUUID("b50ad385829d3141a2167e7d7539ba7f")
+	require.NoError(t, err)
+	assert.Equal(t, OfflineUUID("Notch"), parsed)
+}
+
+// TestVersionMappings spot-checks packet IDs that moved between versions.
+func TestVersionMappings(t *testing.T) {
+	cases := []struct {
+		protocol int32
+		packet   Packet
+		id       int32
+	}{
+		{765, ClientboundPlayLogin, 0x29},
+		{766, ClientboundPlayLogin, 0x2B},
+		{767, ClientboundPlayLogin, 0x2B},
+		{765, ClientboundPlayKeepAlive, 0x24},
+		{767, ClientboundPlayKeepAlive, 0x26},
+		{765, ClientboundConfigFinish, 0x02},
+		{766, ClientboundConfigFinish, 0x03},
+		{765, ServerboundPlayChatMessage, 0x05},
+		{766, ServerboundPlayChatMessage, 0x06},
+		{767, ClientboundPlayServerLinks, 0x7B},
+	}
+	for _, c := range cases {
+		v, ok := Lookup(c.protocol)
+		require.True(t, ok, "protocol %d should be supported", c.protocol)
+
+		id, ok := v.ID(c.packet)
+		require.True(t, ok, "%d should define %s", c.protocol, c.packet)
+		assert.Equal(t, c.id, id, "%s in %d", c.packet, c.protocol)
+	}
+
+	v765, _ := Lookup(765)
+	_, ok := v765.ID(ClientboundConfigKnownPacks)
+	assert.False(t, ok, "1.20.4 has no known packs negotiation")
+
+	p, ok := v765.Packet(Play, Serverbound, 0x17)
+	require.True(t, ok)
+	assert.Equal(t, ServerboundPlaySetPlayerPosition, p)
+
+	_, ok = Lookup(47)
+	assert.False(t, ok, "1.8 is not supported")
+	assert.Equal(t, "1.20.3-1.21.1", SupportedRange())
+}
+
+func TestEntities(t *testing.T) {
+	v765, _ := Lookup(765)
+	v767, _ := Lookup(767)
+	for _, c := range []struct {
+		v    *Version
+		name string
+		id   int32
+	}{
+		{v765, "minecraft:allay", 0},
+		{v765, "minecraft:pig", 73},
+		{v765, "minecraft:player", 124},
+		{v767, "minecraft:pig", 77},
+		{v767, "minecraft:player", 128},
+		{v767, "minecraft:armadillo", 2},
+	} {
+		id, ok := c.v.EntityType(c.name)
+		require.True(t, ok, "%s in %s", c.name, c.v)
+		assert.Equal(t, c.id, id, "%s in %s", c.name, c.v)
+	}
+	_, ok := v765.EntityType("minecraft:armadillo")
+	assert.False(t, ok, "1.20.4 has no armadillos")
+
+	assert.Equal(t, []byte{0}, WriteAngle(0))
+	assert.Equal(t, []byte{64}, WriteAngle(90))
+	assert.Equal(t, []byte{192}, WriteAngle(-90))
+
+	// Poses moved up by one when 1.20.5 added particle lists; entries of
+	// types a version lacks are left out.
+	entries := []MetadataEntry{
+		MetadataByteEntry(0, 0x40),
+		MetadataPoseEntry(6, 5),
+		{Index: 17, Type: MetadataWolfVariant, Value: WriteVarInt(1)},
+	}
+	assert.Equal(t, []byte{0, 0, 0x40, 6, 20, 5, 0xFF}, v765.WriteMetadata(entries))
+	assert.Equal(t, []byte{0, 0, 0x40, 6, 21, 5, 17, 23, 1, 0xFF}, v767.WriteMetadata(entries))
+}
+
+func TestRegistries(t *testing.T) {
+	for _, v := range Supported() {
+		regs, err := v.Registries()
+		require.NoError(t, err, "registries for %s", v)
+
+		dimensionTypes, ok := regs.Get("minecraft:dimension_type")
+		require.True(t, ok, "%s should have dimension types", v)
+		_, ok = dimensionTypes.Index("minecraft:overworld")
+		assert.True(t, ok, "%s should have the overworld dimension type", v)
+
+		biomes, ok := regs.Get("minecraft:worldgen/biome")
+		require.True(t, ok)
+		_, ok = biomes.Index("minecraft:plains")
+		assert.True(t, ok, "%s should have the plains biome", v)
+	}
+}
+
+// readFrame reads one frame written by a Conn from the client side of a pipe.
+func readFrame(t *testing.T, r *bufio.Reader) (int32, *bytes.Reader) {
+	t.Helper()
+	length, err := ReadVarInt(r)
+	require.NoError(t, err)
+	data, err := ReadBytes(r, int(length))
+	require.NoError(t, err)
+	pkt := bytes.NewReader(data)
+	id, err := ReadVarInt(pkt)
+	require.NoError(t, err)
+	return id, pkt
+}
+
+func TestConnRejectsOversizedFrames(t *testing.T) {
+	server, client := net.Pipe()
+	conn := NewConn(server, ConnConfig{})
+	defer conn.Close()
+
+	go client.Write([]byte{0xff, 0xff, 0xff, 0x01})
+	_, err := conn.ReadPacket()
+	assert.ErrorIs(t, err, ErrPacketTooLarge)
+}
+
+func TestConnKeepAlive(t *testing.T) {
+	server, client := net.Pipe()
+	conn := NewConn(server, ConnConfig{KeepAliveInterval: 10 * time.Millisecond, KeepAliveTimeout: time.Second})
+	defer conn.Close()
+	conn.SetVersion(v767)
+	conn.SetState(Play)
+
+	// Answer the first Keep Alive and then send a real packet; only the latter
+	// should surface from ReadPacket.
+	go func() {
+		r := bufio.NewReader(client)
+		id, pkt := readFrame(t, r)
+		assert.Equal(t, int32(0x26), id)
+		challenge, _ := ReadLong(pkt)
+
+		response := append(WriteVarInt(0x18), WriteLong(challenge)...)
+		client.Write(append(WriteVarInt(len(response)), response...))
+		swing := append(WriteVarInt(0x36), WriteVarInt(0)...)
+		client.Write(append(WriteVarInt(len(swing)), swing...))
+	}()
+
+	pkt, err := conn.ReadPacket()
+	require.NoError(t, err)
+	assert.Equal(t, ServerboundPlaySwingArm, pkt.Packet)
+	assert.Greater(t, conn.Latency(), time.Duration(0))
+}
+
+func TestConnDisconnectInLogin(t *testing.T) {
+	server, client := net.Pipe()
+	conn := NewConn(server, ConnConfig{})
+	conn.SetVersion(v765)
+	conn.SetState(Login)
+
+	go conn.Disconnect("Server closed")
+
+	r := bufio.NewReader(client)
+	id, pkt := readFrame(t, r)
+	assert.Equal(t, int32(0x00), id)
+	reason, err := ReadString(pkt, DefaultStringLength)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text":"Server closed"}`, reason)
+
+	// The connection is closed once the disconnect has been flushed.
+	_, err = r.ReadByte()
+	assert.Error(t, err)
+	<-conn.Done()
+}
+
+func TestLegacyPing(t *testing.T) {
+	utf16be := func(s string) []byte {
+		var b []byte
+		for _, u := range utf16.Encode([]rune(s)) {
+			b = append(b, byte(u>>8), byte(u))
+		}
+		return b
+	}
+	legacyString := func(s string) []byte {
+		return append(WriteUnsignedShort(uint16(len(utf16.Encode([]rune(s))))), utf16be(s)...)
+	}
+	pingHost := append(WriteByte(78), legacyString("mc.example")...)
+	pingHost = append(pingHost, WriteInt(25565)...)
+	v16 := append([]byte{0xFE, 0x01, 0xFA}, legacyString("MC|PingHost")...)
+	v16 = append(v16, WriteUnsignedShort(uint16(len(pingHost)))...)
+	v16 = append(v16, pingHost...)
+
+	tests := []struct {
+		name   string
+		sent   []byte
+		want   LegacyPing
+		answer string
+	}{
+		{"beta", []byte{0xFE}, LegacyPing{Format: LegacyPingBeta}, "A Golem Server§3§20"},
+		{"1.4", []byte{0xFE, 0x01}, LegacyPing{Format: LegacyPing14}, "§1\x00127\x001.21.1\x00A §aGolem§r Server\x003\x0020"},
+		{"1.6", v16, LegacyPing{Format: LegacyPing16, Protocol: 78, Host: "mc.example", Port: 25565}, "§1\x00127\x001.21.1\x00A §aGolem§r Server\x003\x0020"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			conn := NewConn(server, ConnConfig{})
+			go client.Write(tt.sent)
+
+			ping, err := conn.ReadLegacyPing()
+			require.NoError(t, err)
+			require.NotNil(t, ping)
+			assert.Equal(t, tt.want, *ping)
+
+			go conn.DisconnectLegacy(LegacyPingResponse(ping.Format, "1.21.1", "A §aGolem§r Server", 3, 20))
+			resp, err := io.ReadAll(client)
+			require.NoError(t, err)
+			want := append([]byte{0xFF}, legacyString(tt.answer)...)
+			assert.Equal(t, want, resp)
+		})
+	}
+
+	// A modern handshake is left untouched.
+	server, client := net.Pipe()
+	conn := NewConn(server, ConnConfig{})
+	go client.Write(append(WriteVarInt(1), 0x00))
+	ping, err := conn.ReadLegacyPing()
+	require.NoError(t, err)
+	assert.Nil(t, ping)
+	pkt, err := conn.ReadPacket()
+	require.NoError(t, err)
+	assert.Equal(t, ServerboundHandshakeIntention, pkt.Packet)
+	conn.Close()
+
+	// So is a handshake of 254 bytes, whose length VarInt is 0xFE 0x01, like
+	// those of proxies that forward in the address.
+	handshake := append([]byte{0x00}, WriteVarInt(767)...)
+	handshake = append(handshake, WriteString(strings.Repeat("a", 246))...)
+	handshake = append(handshake, WriteUnsignedShort(25565)...)
+	handshake = append(handshake, WriteVarInt(1)...)
+	require.Len(t, handshake, 254)
+	server, client = net.Pipe()
+	conn = NewConn(server, ConnConfig{})
+	go client.Write(append(WriteVarInt(len(handshake)), handshake...))
+	ping, err = conn.ReadLegacyPing()
+	require.NoError(t, err)
+	assert.Nil(t, ping)
+	pkt, err = conn.ReadPacket()
+	require.NoError(t, err)
+	assert.Equal(t, ServerboundHandshakeIntention, pkt.Packet)
+	assert.Equal(t, handshake[1:], pkt.Data)
+	conn.Close()
+}
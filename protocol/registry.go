@@ -0,0 +1,111 @@
+package protocol
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// The registries directory holds the vanilla synchronized registries for each
+// protocol version, dumped from the game's built-in data pack. Every file is a
+// gzipped compound in the registry codec layout used by 1.20.2-1.20.4:
+// {<registry>: {type: <registry>, value: [{name, id, element}, ...]}}.
+//
+//go:embed registries/*.nbt
+var registryFiles embed.FS
+
+// RegistryEntry is one element of a synchronized registry.
+type RegistryEntry struct {
+	Name    string
+	Element *nbt.CompoundTag
+}
+
+// Registry is a synchronized registry with its entries in network ID order.
+type Registry struct {
+	Name    string
+	Entries []RegistryEntry
+}
+
+// Index returns the network ID of the named entry.
+func (r *Registry) Index(name string) (int, bool) {
+	for i, e := range r.Entries {
+		if e.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Registries holds the synchronized registries a version sends during configuration.
+type Registries struct {
+	codec *nbt.CompoundTag
+	list  []*Registry
+}
+
+// Codec returns every registry as the single compound sent by versions
+// without FeatureKnownPacks.
+func (r *Registries) Codec() *nbt.CompoundTag { return r.codec }
+
+// List returns the registries sorted by name.
+func (r *Registries) List() []*Registry { return r.list }
+
+// Get returns the named registry, e.g. "minecraft:dimension_type".
+func (r *Registries) Get(name string) (*Registry, bool) {
+	for _, reg := range r.list {
+		if reg.Name == name {
+			return reg, true
+		}
+	}
+	return nil, false
+}
+
+var registryCache sync.Map // map[int32]*Registries
+
+// Registries loads the vanilla registries for the version.
+func (v *Version) Registries() (*Registries, error) {
+	if cached, ok := registryCache.Load(v.Protocol); ok {
+		return cached.(*Registries), nil
+	}
+	data, err := registryFiles.ReadFile(fmt.Sprintf("registries/%d.nbt", v.Protocol))
+	if err != nil {
+		return nil, fmt.Errorf("protocol: no registry data for %d: %w", v.Protocol, err)
+	}
+	root, err := nbt.ReadCompressed(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	codec, ok := root.Tag.(*nbt.CompoundTag)
+	if !ok {
+		return nil, fmt.Errorf("protocol: registry data for %d is not a compound", v.Protocol)
+	}
+
+	regs := &Registries{codec: codec}
+	for name, tag := range codec.Value {
+		compound, ok := tag.(*nbt.CompoundTag)
+		if !ok {
+			return nil, fmt.Errorf("protocol: registry %s is not a compound", name)
+		}
+		entries, _ := compound.GetList("value")
+		reg := &Registry{Name: name}
+		if entries != nil {
+			for _, e := range entries.Value {
+				entry, ok := e.(*nbt.CompoundTag)
+				if !ok {
+					return nil, fmt.Errorf("protocol: malformed entry in registry %s", name)
+				}
+				entryName, _ := entry.GetString("name")
+				element, _ := entry.GetCompound("element")
+				reg.Entries = append(reg.Entries, RegistryEntry{Name: entryName, Element: element})
+			}
+		}
+		regs.list = append(regs.list, reg)
+	}
+	sort.Slice(regs.list, func(i, j int) bool { return regs.list[i].Name < regs.list[j].Name })
+
+	actual, _ := registryCache.LoadOrStore(v.Protocol, regs)
+	return actual.(*Registries), nil
+}
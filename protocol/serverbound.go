@@ -0,0 +1,273 @@
+package protocol
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Decoder is implemented by the typed form of each serverbound packet the
+// server understands.
+type Decoder interface {
+	Decode(r *bytes.Reader) error
+}
+
+// serverboundDecoders maps packets to constructors of their typed form.
+var serverboundDecoders = map[Packet]func() Decoder{
+	ServerboundHandshakeIntention:               func() Decoder { return new(Handshake) },
+	ServerboundStatusRequest:                    func() Decoder { return new(StatusRequest) },
+	ServerboundStatusPing:                       func() Decoder { return new(StatusPing) },
+	ServerboundLoginStart:                       func() Decoder { return new(LoginStart) },
+	ServerboundLoginPluginResponse:              func() Decoder { return new(LoginPluginResponse) },
+	ServerboundLoginAcknowledged:                func() Decoder { return new(LoginAcknowledged) },
+	ServerboundLoginCookieResponse:              func() Decoder { return new(CookieResponse) },
+	ServerboundConfigCookieResponse:             func() Decoder { return new(CookieResponse) },
+	ServerboundConfigKnownPacks:                 func() Decoder { return new(KnownPacks) },
+	ServerboundConfigPluginMessage:              func() Decoder { return new(PluginMessage) },
+	ServerboundConfigKeepAlive:                  func() Decoder { return new(KeepAlive) },
+	ServerboundConfigAcknowledgeFinish:          func() Decoder { return new(AcknowledgeFinish) },
+	ServerboundPlayPluginMessage:                func() Decoder { return new(PluginMessage) },
+	ServerboundPlayKeepAlive:                    func() Decoder { return new(KeepAlive) },
+	ServerboundPlayCookieResponse:               func() Decoder { return new(CookieResponse) },
+	ServerboundPlayChatMessage:                  func() Decoder { return new(ChatMessage) },
+	ServerboundPlayChatCommand:                  func() Decoder { return new(ChatCommand) },
+	ServerboundPlaySignedChatCommand:            func() Decoder { return new(SignedChatCommand) },
+	ServerboundPlayPlayerSession:                func() Decoder { return new(PlayerSession) },
+	ServerboundPlayAcknowledgeMessage:           func() Decoder { return new(AcknowledgeMessage) },
+	ServerboundPlayConfirmTeleportation:         func() Decoder { return new(ConfirmTeleportation) },
+	ServerboundPlaySetPlayerPosition:            func() Decoder { return new(SetPlayerPosition) },
+	ServerboundPlaySetPlayerPositionAndRotation: func() Decoder { return new(SetPlayerPositionAndRotation) },
+	ServerboundPlaySetPlayerRotation:            func() Decoder { return new(SetPlayerRotation) },
+	ServerboundPlaySetPlayerOnGround:            func() Decoder { return new(SetPlayerOnGround) },
+	ServerboundPlayPlayerAbilities:              func() Decoder { return new(PlayerAbilities) },
+}
+
+// DecodeServerbound decodes raw into its typed form. The second result is
+// false for packets that have no typed form. Trailing bytes are an error, as
+// they are in vanilla.
+func DecodeServerbound(raw *RawPacket) (Decoder, bool, error) {
+	newDecoder, ok := serverboundDecoders[raw.Packet]
+	if !ok {
+		return nil, false, nil
+	}
+	d := newDecoder()
+	return d, true, raw.Decode(d)
+}
+
+// DecodablePackets returns every serverbound packet with a typed form.
+func DecodablePackets() []Packet {
+	packets := make([]Packet, 0, len(serverboundDecoders))
+	for p := range serverboundDecoders {
+		packets = append(packets, p)
+	}
+	return packets
+}
+
+// Decode decodes the packet's payload into d and checks that all of it was used.
+func (p *RawPacket) Decode(d Decoder) error {
+	r := p.Reader()
+	if err := d.Decode(r); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("%s: %w", p.Packet, err)
+	}
+	if r.Len() > 0 {
+		return fmt.Errorf("%s: %d bytes extra", p.Packet, r.Len())
+	}
+	return nil
+}
+
+// rest returns the unread bytes of r, for packets that end in raw data.
+func rest(r *bytes.Reader) []byte {
+	data := make([]byte, r.Len())
+	_, _ = r.Read(data)
+	return data
+}
+
+// Handshake is the first packet of every connection.
+type Handshake struct {
+	ProtocolVersion int32  `json:"protocolVersion"`
+	ServerAddress   string `json:"serverAddress"`
+	ServerPort      uint16 `json:"serverPort"`
+	Intent          int32  `json:"intent"`
+}
+
+func (p *Handshake) Decode(r *bytes.Reader) (err error) {
+	if p.ProtocolVersion, err = ReadVarInt(r); err != nil {
+		return err
+	}
+	// BungeeCord forwarding packs the player into the address, so allow more
+	// than the 255 characters vanilla does.
+	if p.ServerAddress, err = ReadString(r, DefaultStringLength); err != nil {
+		return err
+	}
+	if p.ServerPort, err = ReadUnsignedShort(r); err != nil {
+		return err
+	}
+	p.Intent, err = ReadVarInt(r)
+	return err
+}
+
+// StatusRequest asks for the server list status.
+type StatusRequest struct{}
+
+func (p *StatusRequest) Decode(r *bytes.Reader) error { return nil }
+
+// StatusPing carries a payload the server echoes in Pong.
+type StatusPing struct {
+	Payload int64 `json:"payload"`
+}
+
+func (p *StatusPing) Decode(r *bytes.Reader) (err error) {
+	p.Payload, err = ReadLong(r)
+	return err
+}
+
+// LoginStart starts the login with the player's name and, since 1.20.2, the
+// UUID the client believes it has.
+type LoginStart struct {
+	Name string `json:"name"`
+	UUID UUID   `json:"uuid"`
+}
+
+func (p *LoginStart) Decode(r *bytes.Reader) (err error) {
+	if p.Name, err = ReadString(r, 16); err != nil {
+		return err
+	}
+	p.UUID, err = ReadUUID(r)
+	return err
+}
+
+// LoginPluginResponse answers a Login Plugin Request.
+type LoginPluginResponse struct {
+	MessageID  int32  `json:"messageId"`
+	Successful bool   `json:"successful"`
+	Data       []byte `json:"data,omitempty"`
+}
+
+func (p *LoginPluginResponse) Decode(r *bytes.Reader) (err error) {
+	if p.MessageID, err = ReadVarInt(r); err != nil {
+		return err
+	}
+	if p.Successful, err = ReadBool(r); err != nil {
+		return err
+	}
+	if p.Successful {
+		p.Data = rest(r)
+	}
+	return nil
+}
+
+// LoginAcknowledged moves the connection to the configuration state.
+type LoginAcknowledged struct{}
+
+func (p *LoginAcknowledged) Decode(r *bytes.Reader) error { return nil }
+
+// KnownPack is a data pack both sides may already have.
+type KnownPack struct {
+	Namespace string `json:"namespace"`
+	ID        string `json:"id"`
+	Version   string `json:"version"`
+}
+
+// maxKnownPacks is the number of known packs vanilla accepts from a client.
+const maxKnownPacks = 64
+
+// KnownPacks lists the packs the client has, in reply to the server's offer.
+type KnownPacks struct {
+	Packs []KnownPack `json:"packs"`
+}
+
+func (p *KnownPacks) Decode(r *bytes.Reader) error {
+	count, err := ReadVarInt(r)
+	if err != nil {
+		return err
+	}
+	if count < 0 || count > maxKnownPacks {
+		return fmt.Errorf("invalid known pack count %d", count)
+	}
+	p.Packs = make([]KnownPack, count)
+	for i := range p.Packs {
+		pack := &p.Packs[i]
+		if pack.Namespace, err = ReadString(r, DefaultStringLength); err != nil {
+			return err
+		}
+		if pack.ID, err = ReadString(r, DefaultStringLength); err != nil {
+			return err
+		}
+		if pack.Version, err = ReadString(r, DefaultStringLength); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Has reports whether the client listed the given pack, in any version.
+func (p *KnownPacks) Has(namespace, id string) bool {
+	for _, pack := range p.Packs {
+		if pack.Namespace == namespace && pack.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxPluginMessage is the largest serverbound plugin message payload vanilla
+// accepts.
+const MaxPluginMessage = 32767
+
+// PluginMessage is a custom payload on a named channel, in the configuration
+// or play state.
+type PluginMessage struct {
+	Channel string `json:"channel"`
+	Data    []byte `json:"data,omitempty"`
+}
+
+func (p *PluginMessage) Decode(r *bytes.Reader) (err error) {
+	if p.Channel, err = ReadString(r, DefaultStringLength); err != nil {
+		return err
+	}
+	if r.Len() > MaxPluginMessage {
+		return fmt.Errorf("payload on %s is too large: %d bytes", p.Channel, r.Len())
+	}
+	p.Data = rest(r)
+	return nil
+}
+
+// KeepAlive answers a Keep Alive with the same ID.
+type KeepAlive struct {
+	ID int64 `json:"id"`
+}
+
+func (p *KeepAlive) Decode(r *bytes.Reader) (err error) {
+	p.ID, err = ReadLong(r)
+	return err
+}
+
+// AcknowledgeFinish moves the connection to the play state.
+type AcknowledgeFinish struct{}
+
+func (p *AcknowledgeFinish) Decode(r *bytes.Reader) error { return nil }
+
+// MaxCookieLength is the largest cookie payload vanilla stores or accepts.
+const MaxCookieLength = 5120
+
+// CookieResponse answers a Cookie Request, in the login, configuration or
+// play state. Present is false if the client has no cookie with the key.
+type CookieResponse struct {
+	Key     string `json:"key"`
+	Present bool   `json:"present"`
+	Payload []byte `json:"payload,omitempty"`
+}
+
+func (p *CookieResponse) Decode(r *bytes.Reader) (err error) {
+	if p.Key, err = ReadString(r, DefaultStringLength); err != nil {
+		return err
+	}
+	if p.Present, err = ReadBool(r); err != nil || !p.Present {
+		return err
+	}
+	p.Payload, err = ReadByteArray(r, MaxCookieLength)
+	return err
+}
@@ -0,0 +1,913 @@
+package protocol
+
+import (
+	"sort"
+	"strings"
+)
+
+// Feature marks a protocol behaviour that only some versions have. Packet
+// encoders consult it through Version.Has wherever the field layout differs.
+type Feature uint32
+
+const (
+	// FeatureKnownPacks means the client negotiates data packs with Select Known
+	// Packs and receives one Registry Data packet per registry.
+	FeatureKnownPacks Feature = 1 << iota
+	// FeatureStrictErrorHandling means Login Success ends with the strict error
+	// handling flag.
+	FeatureStrictErrorHandling
+	// FeatureDimensionTypeID means Login (play) and Respawn refer to the
+	// dimension type by registry ID instead of by name, and Login (play) carries
+	// the enforces secure chat flag.
+	FeatureDimensionTypeID
+	// FeatureTransfer means the client can be sent to another server with
+	// Transfer, connects with the transfer intent when it is, and keeps
+	// cookies set with Store Cookie across the move.
+	FeatureTransfer
+	// FeatureRegistryHolders means references to registry entries that may
+	// also be given inline, such as the chat type of Player Chat Message, are
+	// sent as the network ID plus one, with zero introducing an inline value.
+	FeatureRegistryHolders
+)
+
+// Version describes one protocol version: the game releases that speak it, the
+// packet ID assignment for every state, and which optional features it has.
+type Version struct {
+	// Protocol is the number sent by the client in the Handshake packet.
+	Protocol int32
+	// Names lists the game releases using this protocol, oldest first.
+	Names    []string
+	Features Feature
+
+	// blockEntityTypes lists the block entity types in network ID order.
+	blockEntityTypes []string
+	// entityTypes and metadataTypes list the entity types and entity
+	// metadata types in network ID order.
+	entityTypes   []string
+	metadataTypes []MetadataType
+
+	packets [numStates][2][]Packet
+	ids     map[Packet]int32
+}
+
+// Name returns the newest game release using this protocol.
+func (v *Version) Name() string { return v.Names[len(v.Names)-1] }
+
+// Has reports whether the version supports the given feature.
+func (v *Version) Has(f Feature) bool { return v.Features&f == f }
+
+// ID returns the numeric packet ID this version uses for p.
+func (v *Version) ID(p Packet) (int32, bool) {
+	id, ok := v.ids[p]
+	return id, ok
+}
+
+// Packet returns the packet with the given ID in the given state and direction.
+func (v *Version) Packet(state State, dir Direction, id int32) (Packet, bool) {
+	if state < 0 || state >= numStates {
+		return UnknownPacket, false
+	}
+	table := v.packets[state][dir]
+	if id < 0 || int(id) >= len(table) {
+		return UnknownPacket, false
+	}
+	return table[id], true
+}
+
+func (v *Version) String() string {
+	return v.Name()
+}
+
+var versions = map[int32]*Version{}
+
+// register indexes the packet tables of v and adds it to the registry.
+func register(v *Version) {
+	v.ids = make(map[Packet]int32)
+	for _, dirs := range v.packets {
+		for _, table := range dirs {
+			for id, p := range table {
+				v.ids[p] = int32(id)
+			}
+		}
+	}
+	versions[v.Protocol] = v
+}
+
+// Lookup returns the version registered for the given protocol number.
+func Lookup(protocol int32) (*Version, bool) {
+	v, ok := versions[protocol]
+	return v, ok
+}
+
+// Supported returns every registered version, oldest first.
+func Supported() []*Version {
+	list := make([]*Version, 0, len(versions))
+	for _, v := range versions {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Protocol < list[j].Protocol })
+	return list
+}
+
+// Latest returns the newest registered version.
+func Latest() *Version {
+	list := Supported()
+	return list[len(list)-1]
+}
+
+// Oldest returns the oldest registered version.
+func Oldest() *Version {
+	return Supported()[0]
+}
+
+// SupportedRange describes the supported releases for humans, e.g. "1.20.3-1.21.1".
+func SupportedRange() string {
+	oldest, latest := Oldest(), Latest()
+	if oldest == latest && len(latest.Names) == 1 {
+		return latest.Name()
+	}
+	return oldest.Names[0] + "-" + latest.Name()
+}
+
+// SupportedNames lists every supported release, e.g. "1.20.3, 1.20.4, 1.20.5".
+func SupportedNames() string {
+	var names []string
+	for _, v := range Supported() {
+		names = append(names, v.Names...)
+	}
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	register(v765)
+	register(v766)
+	register(v767)
+}
+
+// v765 is Minecraft 1.20.3 and 1.20.4.
+var v765 = &Version{
+	Protocol: 765,
+	Names:    []string{"1.20.3", "1.20.4"},
+
+	blockEntityTypes: blockEntityTypes765,
+	entityTypes:      entityTypes765,
+	metadataTypes:    metadataTypes765,
+	packets: [numStates][2][]Packet{
+		Handshaking: {
+			Serverbound: {
+				ServerboundHandshakeIntention,
+			},
+		},
+		Status: {
+			Serverbound: {
+				ServerboundStatusRequest,
+				ServerboundStatusPing,
+			},
+			Clientbound: {
+				ClientboundStatusResponse,
+				ClientboundStatusPong,
+			},
+		},
+		Login: {
+			Serverbound: {
+				ServerboundLoginStart,
+				ServerboundLoginEncryptionResponse,
+				ServerboundLoginPluginResponse,
+				ServerboundLoginAcknowledged,
+			},
+			Clientbound: {
+				ClientboundLoginDisconnect,
+				ClientboundLoginEncryptionRequest,
+				ClientboundLoginSuccess,
+				ClientboundLoginSetCompression,
+				ClientboundLoginPluginRequest,
+			},
+		},
+		Configuration: {
+			Serverbound: {
+				ServerboundConfigClientInformation,
+				ServerboundConfigPluginMessage,
+				ServerboundConfigAcknowledgeFinish,
+				ServerboundConfigKeepAlive,
+				ServerboundConfigPong,
+				ServerboundConfigResourcePackResponse,
+			},
+			Clientbound: {
+				ClientboundConfigPluginMessage,
+				ClientboundConfigDisconnect,
+				ClientboundConfigFinish,
+				ClientboundConfigKeepAlive,
+				ClientboundConfigPing,
+				ClientboundConfigRegistryData,
+				ClientboundConfigRemoveResourcePack,
+				ClientboundConfigAddResourcePack,
+				ClientboundConfigFeatureFlags,
+				ClientboundConfigUpdateTags,
+			},
+		},
+		Play: {
+			Serverbound: {
+				ServerboundPlayConfirmTeleportation,
+				ServerboundPlayQueryBlockEntityTag,
+				ServerboundPlayChangeDifficulty,
+				ServerboundPlayAcknowledgeMessage,
+				ServerboundPlayChatCommand,
+				ServerboundPlayChatMessage,
+				ServerboundPlayPlayerSession,
+				ServerboundPlayChunkBatchReceived,
+				ServerboundPlayClientStatus,
+				ServerboundPlayClientInformation,
+				ServerboundPlayCommandSuggestionsRequest,
+				ServerboundPlayAcknowledgeConfiguration,
+				ServerboundPlayClickContainerButton,
+				ServerboundPlayClickContainer,
+				ServerboundPlayCloseContainer,
+				ServerboundPlayChangeContainerSlotState,
+				ServerboundPlayPluginMessage,
+				ServerboundPlayEditBook,
+				ServerboundPlayQueryEntityTag,
+				ServerboundPlayInteract,
+				ServerboundPlayJigsawGenerate,
+				ServerboundPlayKeepAlive,
+				ServerboundPlayLockDifficulty,
+				ServerboundPlaySetPlayerPosition,
+				ServerboundPlaySetPlayerPositionAndRotation,
+				ServerboundPlaySetPlayerRotation,
+				ServerboundPlaySetPlayerOnGround,
+				ServerboundPlayMoveVehicle,
+				ServerboundPlayPaddleBoat,
+				ServerboundPlayPickItem,
+				ServerboundPlayPingRequest,
+				ServerboundPlayPlaceRecipe,
+				ServerboundPlayPlayerAbilities,
+				ServerboundPlayPlayerAction,
+				ServerboundPlayPlayerCommand,
+				ServerboundPlayPlayerInput,
+				ServerboundPlayPong,
+				ServerboundPlayChangeRecipeBookSettings,
+				ServerboundPlaySetSeenRecipe,
+				ServerboundPlayRenameItem,
+				ServerboundPlayResourcePackResponse,
+				ServerboundPlaySeenAdvancements,
+				ServerboundPlaySelectTrade,
+				ServerboundPlaySetBeaconEffect,
+				ServerboundPlaySetHeldItem,
+				ServerboundPlayProgramCommandBlock,
+				ServerboundPlayProgramCommandBlockMinecart,
+				ServerboundPlaySetCreativeModeSlot,
+				ServerboundPlayProgramJigsawBlock,
+				ServerboundPlayProgramStructureBlock,
+				ServerboundPlayUpdateSign,
+				ServerboundPlaySwingArm,
+				ServerboundPlayTeleportToEntity,
+				ServerboundPlayUseItemOn,
+				ServerboundPlayUseItem,
+			},
+			Clientbound: {
+				ClientboundPlayBundleDelimiter,
+				ClientboundPlaySpawnEntity,
+				ClientboundPlaySpawnExperienceOrb,
+				ClientboundPlayEntityAnimation,
+				ClientboundPlayAwardStatistics,
+				ClientboundPlayAcknowledgeBlockChange,
+				ClientboundPlaySetBlockDestroyStage,
+				ClientboundPlayBlockEntityData,
+				ClientboundPlayBlockAction,
+				ClientboundPlayBlockUpdate,
+				ClientboundPlayBossBar,
+				ClientboundPlayChangeDifficulty,
+				ClientboundPlayChunkBatchFinished,
+				ClientboundPlayChunkBatchStart,
+				ClientboundPlayChunkBiomes,
+				ClientboundPlayClearTitles,
+				ClientboundPlayCommandSuggestionsResponse,
+				ClientboundPlayCommands,
+				ClientboundPlayCloseContainer,
+				ClientboundPlaySetContainerContent,
+				ClientboundPlaySetContainerProperty,
+				ClientboundPlaySetContainerSlot,
+				ClientboundPlaySetCooldown,
+				ClientboundPlayChatSuggestions,
+				ClientboundPlayPluginMessage,
+				ClientboundPlayDamageEvent,
+				ClientboundPlayDeleteMessage,
+				ClientboundPlayDisconnect,
+				ClientboundPlayDisguisedChatMessage,
+				ClientboundPlayEntityEvent,
+				ClientboundPlayExplosion,
+				ClientboundPlayUnloadChunk,
+				ClientboundPlayGameEvent,
+				ClientboundPlayOpenHorseScreen,
+				ClientboundPlayHurtAnimation,
+				ClientboundPlayInitializeWorldBorder,
+				ClientboundPlayKeepAlive,
+				ClientboundPlayChunkDataAndUpdateLight,
+				ClientboundPlayWorldEvent,
+				ClientboundPlayParticle,
+				ClientboundPlayUpdateLight,
+				ClientboundPlayLogin,
+				ClientboundPlayMapData,
+				ClientboundPlayMerchantOffers,
+				ClientboundPlayUpdateEntityPosition,
+				ClientboundPlayUpdateEntityPositionAndRotation,
+				ClientboundPlayUpdateEntityRotation,
+				ClientboundPlayMoveVehicle,
+				ClientboundPlayOpenBook,
+				ClientboundPlayOpenScreen,
+				ClientboundPlayOpenSignEditor,
+				ClientboundPlayPing,
+				ClientboundPlayPingResponse,
+				ClientboundPlayPlaceGhostRecipe,
+				ClientboundPlayPlayerAbilities,
+				ClientboundPlayPlayerChatMessage,
+				ClientboundPlayEndCombat,
+				ClientboundPlayEnterCombat,
+				ClientboundPlayCombatDeath,
+				ClientboundPlayPlayerInfoRemove,
+				ClientboundPlayPlayerInfoUpdate,
+				ClientboundPlayLookAt,
+				ClientboundPlaySynchronizePlayerPosition,
+				ClientboundPlayUpdateRecipeBook,
+				ClientboundPlayRemoveEntities,
+				ClientboundPlayRemoveEntityEffect,
+				ClientboundPlayResetScore,
+				ClientboundPlayRemoveResourcePack,
+				ClientboundPlayAddResourcePack,
+				ClientboundPlayRespawn,
+				ClientboundPlaySetHeadRotation,
+				ClientboundPlayUpdateSectionBlocks,
+				ClientboundPlaySelectAdvancementsTab,
+				ClientboundPlayServerData,
+				ClientboundPlaySetActionBarText,
+				ClientboundPlaySetBorderCenter,
+				ClientboundPlaySetBorderLerpSize,
+				ClientboundPlaySetBorderSize,
+				ClientboundPlaySetBorderWarningDelay,
+				ClientboundPlaySetBorderWarningDistance,
+				ClientboundPlaySetCamera,
+				ClientboundPlaySetHeldItem,
+				ClientboundPlaySetCenterChunk,
+				ClientboundPlaySetRenderDistance,
+				ClientboundPlaySetDefaultSpawnPosition,
+				ClientboundPlayDisplayObjective,
+				ClientboundPlaySetEntityMetadata,
+				ClientboundPlayLinkEntities,
+				ClientboundPlaySetEntityVelocity,
+				ClientboundPlaySetEquipment,
+				ClientboundPlaySetExperience,
+				ClientboundPlaySetHealth,
+				ClientboundPlayUpdateObjectives,
+				ClientboundPlaySetPassengers,
+				ClientboundPlayUpdateTeams,
+				ClientboundPlayUpdateScore,
+				ClientboundPlaySetSimulationDistance,
+				ClientboundPlaySetSubtitleText,
+				ClientboundPlayUpdateTime,
+				ClientboundPlaySetTitleText,
+				ClientboundPlaySetTitleAnimationTimes,
+				ClientboundPlayEntitySoundEffect,
+				ClientboundPlaySoundEffect,
+				ClientboundPlayStartConfiguration,
+				ClientboundPlayStopSound,
+				ClientboundPlaySystemChatMessage,
+				ClientboundPlaySetTabListHeaderAndFooter,
+				ClientboundPlayTagQueryResponse,
+				ClientboundPlayPickupItem,
+				ClientboundPlayTeleportEntity,
+				ClientboundPlaySetTickingState,
+				ClientboundPlayStepTick,
+				ClientboundPlayUpdateAdvancements,
+				ClientboundPlayUpdateAttributes,
+				ClientboundPlayEntityEffect,
+				ClientboundPlayUpdateRecipes,
+				ClientboundPlayUpdateTags,
+			},
+		},
+	},
+}
+
+// v766 is Minecraft 1.20.5 and 1.20.6.
+var v766 = &Version{
+	Protocol: 766,
+	Names:    []string{"1.20.5", "1.20.6"},
+	Features: FeatureKnownPacks | FeatureStrictErrorHandling | FeatureDimensionTypeID | FeatureTransfer,
+
+	blockEntityTypes: blockEntityTypes766,
+	entityTypes:      entityTypes766,
+	metadataTypes:    metadataTypes766,
+	packets: [numStates][2][]Packet{
+		Handshaking: {
+			Serverbound: {
+				ServerboundHandshakeIntention,
+			},
+		},
+		Status: {
+			Serverbound: {
+				ServerboundStatusRequest,
+				ServerboundStatusPing,
+			},
+			Clientbound: {
+				ClientboundStatusResponse,
+				ClientboundStatusPong,
+			},
+		},
+		Login: {
+			Serverbound: {
+				ServerboundLoginStart,
+				ServerboundLoginEncryptionResponse,
+				ServerboundLoginPluginResponse,
+				ServerboundLoginAcknowledged,
+				ServerboundLoginCookieResponse,
+			},
+			Clientbound: {
+				ClientboundLoginDisconnect,
+				ClientboundLoginEncryptionRequest,
+				ClientboundLoginSuccess,
+				ClientboundLoginSetCompression,
+				ClientboundLoginPluginRequest,
+				ClientboundLoginCookieRequest,
+			},
+		},
+		Configuration: {
+			Serverbound: {
+				ServerboundConfigClientInformation,
+				ServerboundConfigCookieResponse,
+				ServerboundConfigPluginMessage,
+				ServerboundConfigAcknowledgeFinish,
+				ServerboundConfigKeepAlive,
+				ServerboundConfigPong,
+				ServerboundConfigResourcePackResponse,
+				ServerboundConfigKnownPacks,
+			},
+			Clientbound: {
+				ClientboundConfigCookieRequest,
+				ClientboundConfigPluginMessage,
+				ClientboundConfigDisconnect,
+				ClientboundConfigFinish,
+				ClientboundConfigKeepAlive,
+				ClientboundConfigPing,
+				ClientboundConfigResetChat,
+				ClientboundConfigRegistryData,
+				ClientboundConfigRemoveResourcePack,
+				ClientboundConfigAddResourcePack,
+				ClientboundConfigStoreCookie,
+				ClientboundConfigTransfer,
+				ClientboundConfigFeatureFlags,
+				ClientboundConfigUpdateTags,
+				ClientboundConfigKnownPacks,
+			},
+		},
+		Play: {
+			Serverbound: {
+				ServerboundPlayConfirmTeleportation,
+				ServerboundPlayQueryBlockEntityTag,
+				ServerboundPlayChangeDifficulty,
+				ServerboundPlayAcknowledgeMessage,
+				ServerboundPlayChatCommand,
+				ServerboundPlaySignedChatCommand,
+				ServerboundPlayChatMessage,
+				ServerboundPlayPlayerSession,
+				ServerboundPlayChunkBatchReceived,
+				ServerboundPlayClientStatus,
+				ServerboundPlayClientInformation,
+				ServerboundPlayCommandSuggestionsRequest,
+				ServerboundPlayAcknowledgeConfiguration,
+				ServerboundPlayClickContainerButton,
+				ServerboundPlayClickContainer,
+				ServerboundPlayCloseContainer,
+				ServerboundPlayChangeContainerSlotState,
+				ServerboundPlayCookieResponse,
+				ServerboundPlayPluginMessage,
+				ServerboundPlayDebugSampleSubscription,
+				ServerboundPlayEditBook,
+				ServerboundPlayQueryEntityTag,
+				ServerboundPlayInteract,
+				ServerboundPlayJigsawGenerate,
+				ServerboundPlayKeepAlive,
+				ServerboundPlayLockDifficulty,
+				ServerboundPlaySetPlayerPosition,
+				ServerboundPlaySetPlayerPositionAndRotation,
+				ServerboundPlaySetPlayerRotation,
+				ServerboundPlaySetPlayerOnGround,
+				ServerboundPlayMoveVehicle,
+				ServerboundPlayPaddleBoat,
+				ServerboundPlayPickItem,
+				ServerboundPlayPingRequest,
+				ServerboundPlayPlaceRecipe,
+				ServerboundPlayPlayerAbilities,
+				ServerboundPlayPlayerAction,
+				ServerboundPlayPlayerCommand,
+				ServerboundPlayPlayerInput,
+				ServerboundPlayPong,
+				ServerboundPlayChangeRecipeBookSettings,
+				ServerboundPlaySetSeenRecipe,
+				ServerboundPlayRenameItem,
+				ServerboundPlayResourcePackResponse,
+				ServerboundPlaySeenAdvancements,
+				ServerboundPlaySelectTrade,
+				ServerboundPlaySetBeaconEffect,
+				ServerboundPlaySetHeldItem,
+				ServerboundPlayProgramCommandBlock,
+				ServerboundPlayProgramCommandBlockMinecart,
+				ServerboundPlaySetCreativeModeSlot,
+				ServerboundPlayProgramJigsawBlock,
+				ServerboundPlayProgramStructureBlock,
+				ServerboundPlayUpdateSign,
+				ServerboundPlaySwingArm,
+				ServerboundPlayTeleportToEntity,
+				ServerboundPlayUseItemOn,
+				ServerboundPlayUseItem,
+			},
+			Clientbound: {
+				ClientboundPlayBundleDelimiter,
+				ClientboundPlaySpawnEntity,
+				ClientboundPlaySpawnExperienceOrb,
+				ClientboundPlayEntityAnimation,
+				ClientboundPlayAwardStatistics,
+				ClientboundPlayAcknowledgeBlockChange,
+				ClientboundPlaySetBlockDestroyStage,
+				ClientboundPlayBlockEntityData,
+				ClientboundPlayBlockAction,
+				ClientboundPlayBlockUpdate,
+				ClientboundPlayBossBar,
+				ClientboundPlayChangeDifficulty,
+				ClientboundPlayChunkBatchFinished,
+				ClientboundPlayChunkBatchStart,
+				ClientboundPlayChunkBiomes,
+				ClientboundPlayClearTitles,
+				ClientboundPlayCommandSuggestionsResponse,
+				ClientboundPlayCommands,
+				ClientboundPlayCloseContainer,
+				ClientboundPlaySetContainerContent,
+				ClientboundPlaySetContainerProperty,
+				ClientboundPlaySetContainerSlot,
+				ClientboundPlayCookieRequest,
+				ClientboundPlaySetCooldown,
+				ClientboundPlayChatSuggestions,
+				ClientboundPlayPluginMessage,
+				ClientboundPlayDamageEvent,
+				ClientboundPlayDebugSample,
+				ClientboundPlayDeleteMessage,
+				ClientboundPlayDisconnect,
+				ClientboundPlayDisguisedChatMessage,
+				ClientboundPlayEntityEvent,
+				ClientboundPlayExplosion,
+				ClientboundPlayUnloadChunk,
+				ClientboundPlayGameEvent,
+				ClientboundPlayOpenHorseScreen,
+				ClientboundPlayHurtAnimation,
+				ClientboundPlayInitializeWorldBorder,
+				ClientboundPlayKeepAlive,
+				ClientboundPlayChunkDataAndUpdateLight,
+				ClientboundPlayWorldEvent,
+				ClientboundPlayParticle,
+				ClientboundPlayUpdateLight,
+				ClientboundPlayLogin,
+				ClientboundPlayMapData,
+				ClientboundPlayMerchantOffers,
+				ClientboundPlayUpdateEntityPosition,
+				ClientboundPlayUpdateEntityPositionAndRotation,
+				ClientboundPlayUpdateEntityRotation,
+				ClientboundPlayMoveVehicle,
+				ClientboundPlayOpenBook,
+				ClientboundPlayOpenScreen,
+				ClientboundPlayOpenSignEditor,
+				ClientboundPlayPing,
+				ClientboundPlayPingResponse,
+				ClientboundPlayPlaceGhostRecipe,
+				ClientboundPlayPlayerAbilities,
+				ClientboundPlayPlayerChatMessage,
+				ClientboundPlayEndCombat,
+				ClientboundPlayEnterCombat,
+				ClientboundPlayCombatDeath,
+				ClientboundPlayPlayerInfoRemove,
+				ClientboundPlayPlayerInfoUpdate,
+				ClientboundPlayLookAt,
+				ClientboundPlaySynchronizePlayerPosition,
+				ClientboundPlayUpdateRecipeBook,
+				ClientboundPlayRemoveEntities,
+				ClientboundPlayRemoveEntityEffect,
+				ClientboundPlayResetScore,
+				ClientboundPlayRemoveResourcePack,
+				ClientboundPlayAddResourcePack,
+				ClientboundPlayRespawn,
+				ClientboundPlaySetHeadRotation,
+				ClientboundPlayUpdateSectionBlocks,
+				ClientboundPlaySelectAdvancementsTab,
+				ClientboundPlayServerData,
+				ClientboundPlaySetActionBarText,
+				ClientboundPlaySetBorderCenter,
+				ClientboundPlaySetBorderLerpSize,
+				ClientboundPlaySetBorderSize,
+				ClientboundPlaySetBorderWarningDelay,
+				ClientboundPlaySetBorderWarningDistance,
+				ClientboundPlaySetCamera,
+				ClientboundPlaySetHeldItem,
+				ClientboundPlaySetCenterChunk,
+				ClientboundPlaySetRenderDistance,
+				ClientboundPlaySetDefaultSpawnPosition,
+				ClientboundPlayDisplayObjective,
+				ClientboundPlaySetEntityMetadata,
+				ClientboundPlayLinkEntities,
+				ClientboundPlaySetEntityVelocity,
+				ClientboundPlaySetEquipment,
+				ClientboundPlaySetExperience,
+				ClientboundPlaySetHealth,
+				ClientboundPlayUpdateObjectives,
+				ClientboundPlaySetPassengers,
+				ClientboundPlayUpdateTeams,
+				ClientboundPlayUpdateScore,
+				ClientboundPlaySetSimulationDistance,
+				ClientboundPlaySetSubtitleText,
+				ClientboundPlayUpdateTime,
+				ClientboundPlaySetTitleText,
+				ClientboundPlaySetTitleAnimationTimes,
+				ClientboundPlayEntitySoundEffect,
+				ClientboundPlaySoundEffect,
+				ClientboundPlayStartConfiguration,
+				ClientboundPlayStopSound,
+				ClientboundPlayStoreCookie,
+				ClientboundPlaySystemChatMessage,
+				ClientboundPlaySetTabListHeaderAndFooter,
+				ClientboundPlayTagQueryResponse,
+				ClientboundPlayPickupItem,
+				ClientboundPlayTeleportEntity,
+				ClientboundPlaySetTickingState,
+				ClientboundPlayStepTick,
+				ClientboundPlayTransfer,
+				ClientboundPlayUpdateAdvancements,
+				ClientboundPlayUpdateAttributes,
+				ClientboundPlayEntityEffect,
+				ClientboundPlayUpdateRecipes,
+				ClientboundPlayUpdateTags,
+				ClientboundPlayProjectilePower,
+			},
+		},
+	},
+}
+
+// v767 is Minecraft 1.21 and 1.21.1.
+var v767 = &Version{
+	Protocol: 767,
+	Names:    []string{"1.21", "1.21.1"},
+	Features: FeatureKnownPacks | FeatureStrictErrorHandling | FeatureDimensionTypeID | FeatureTransfer | FeatureRegistryHolders,
+
+	blockEntityTypes: blockEntityTypes766,
+	entityTypes:      entityTypes766,
+	metadataTypes:    metadataTypes766,
+	packets: [numStates][2][]Packet{
+		Handshaking: {
+			Serverbound: {
+				ServerboundHandshakeIntention,
+			},
+		},
+		Status: {
+			Serverbound: {
+				ServerboundStatusRequest,
+				ServerboundStatusPing,
+			},
+			Clientbound: {
+				ClientboundStatusResponse,
+				ClientboundStatusPong,
+			},
+		},
+		Login: {
+			Serverbound: {
+				ServerboundLoginStart,
+				ServerboundLoginEncryptionResponse,
+				ServerboundLoginPluginResponse,
+				ServerboundLoginAcknowledged,
+				ServerboundLoginCookieResponse,
+			},
+			Clientbound: {
+				ClientboundLoginDisconnect,
+				ClientboundLoginEncryptionRequest,
+				ClientboundLoginSuccess,
+				ClientboundLoginSetCompression,
+				ClientboundLoginPluginRequest,
+				ClientboundLoginCookieRequest,
+			},
+		},
+		Configuration: {
+			Serverbound: {
+				ServerboundConfigClientInformation,
+				ServerboundConfigCookieResponse,
+				ServerboundConfigPluginMessage,
+				ServerboundConfigAcknowledgeFinish,
+				ServerboundConfigKeepAlive,
+				ServerboundConfigPong,
+				ServerboundConfigResourcePackResponse,
+				ServerboundConfigKnownPacks,
+				ServerboundConfigCustomReportDetails,
+				ServerboundConfigServerLinks,
+			},
+			Clientbound: {
+				ClientboundConfigCookieRequest,
+				ClientboundConfigPluginMessage,
+				ClientboundConfigDisconnect,
+				ClientboundConfigFinish,
+				ClientboundConfigKeepAlive,
+				ClientboundConfigPing,
+				ClientboundConfigResetChat,
+				ClientboundConfigRegistryData,
+				ClientboundConfigRemoveResourcePack,
+				ClientboundConfigAddResourcePack,
+				ClientboundConfigStoreCookie,
+				ClientboundConfigTransfer,
+				ClientboundConfigFeatureFlags,
+				ClientboundConfigUpdateTags,
+				ClientboundConfigKnownPacks,
+				ClientboundConfigCustomReportDetails,
+				ClientboundConfigServerLinks,
+			},
+		},
+		Play: {
+			Serverbound: {
+				ServerboundPlayConfirmTeleportation,
+				ServerboundPlayQueryBlockEntityTag,
+				ServerboundPlayChangeDifficulty,
+				ServerboundPlayAcknowledgeMessage,
+				ServerboundPlayChatCommand,
+				ServerboundPlaySignedChatCommand,
+				ServerboundPlayChatMessage,
+				ServerboundPlayPlayerSession,
+				ServerboundPlayChunkBatchReceived,
+				ServerboundPlayClientStatus,
+				ServerboundPlayClientInformation,
+				ServerboundPlayCommandSuggestionsRequest,
+				ServerboundPlayAcknowledgeConfiguration,
+				ServerboundPlayClickContainerButton,
+				ServerboundPlayClickContainer,
+				ServerboundPlayCloseContainer,
+				ServerboundPlayChangeContainerSlotState,
+				ServerboundPlayCookieResponse,
+				ServerboundPlayPluginMessage,
+				ServerboundPlayDebugSampleSubscription,
+				ServerboundPlayEditBook,
+				ServerboundPlayQueryEntityTag,
+				ServerboundPlayInteract,
+				ServerboundPlayJigsawGenerate,
+				ServerboundPlayKeepAlive,
+				ServerboundPlayLockDifficulty,
+				ServerboundPlaySetPlayerPosition,
+				ServerboundPlaySetPlayerPositionAndRotation,
+				ServerboundPlaySetPlayerRotation,
+				ServerboundPlaySetPlayerOnGround,
+				ServerboundPlayMoveVehicle,
+				ServerboundPlayPaddleBoat,
+				ServerboundPlayPickItem,
+				ServerboundPlayPingRequest,
+				ServerboundPlayPlaceRecipe,
+				ServerboundPlayPlayerAbilities,
+				ServerboundPlayPlayerAction,
+				ServerboundPlayPlayerCommand,
+				ServerboundPlayPlayerInput,
+				ServerboundPlayPong,
+				ServerboundPlayChangeRecipeBookSettings,
+				ServerboundPlaySetSeenRecipe,
+				ServerboundPlayRenameItem,
+				ServerboundPlayResourcePackResponse,
+				ServerboundPlaySeenAdvancements,
+				ServerboundPlaySelectTrade,
+				ServerboundPlaySetBeaconEffect,
+				ServerboundPlaySetHeldItem,
+				ServerboundPlayProgramCommandBlock,
+				ServerboundPlayProgramCommandBlockMinecart,
+				ServerboundPlaySetCreativeModeSlot,
+				ServerboundPlayProgramJigsawBlock,
+				ServerboundPlayProgramStructureBlock,
+				ServerboundPlayUpdateSign,
+				ServerboundPlaySwingArm,
+				ServerboundPlayTeleportToEntity,
+				ServerboundPlayUseItemOn,
+				ServerboundPlayUseItem,
+			},
+			Clientbound: {
+				ClientboundPlayBundleDelimiter,
+				ClientboundPlaySpawnEntity,
+				ClientboundPlaySpawnExperienceOrb,
+				ClientboundPlayEntityAnimation,
+				ClientboundPlayAwardStatistics,
+				ClientboundPlayAcknowledgeBlockChange,
+				ClientboundPlaySetBlockDestroyStage,
+				ClientboundPlayBlockEntityData,
+				ClientboundPlayBlockAction,
+				ClientboundPlayBlockUpdate,
+				ClientboundPlayBossBar,
+				ClientboundPlayChangeDifficulty,
+				ClientboundPlayChunkBatchFinished,
+				ClientboundPlayChunkBatchStart,
+				ClientboundPlayChunkBiomes,
+				ClientboundPlayClearTitles,
+				ClientboundPlayCommandSuggestionsResponse,
+				ClientboundPlayCommands,
+				ClientboundPlayCloseContainer,
+				ClientboundPlaySetContainerContent,
+				ClientboundPlaySetContainerProperty,
+				ClientboundPlaySetContainerSlot,
+				ClientboundPlayCookieRequest,
+				ClientboundPlaySetCooldown,
+				ClientboundPlayChatSuggestions,
+				ClientboundPlayPluginMessage,
+				ClientboundPlayDamageEvent,
+				ClientboundPlayDebugSample,
+				ClientboundPlayDeleteMessage,
+				ClientboundPlayDisconnect,
+				ClientboundPlayDisguisedChatMessage,
+				ClientboundPlayEntityEvent,
+				ClientboundPlayExplosion,
+				ClientboundPlayUnloadChunk,
+				ClientboundPlayGameEvent,
+				ClientboundPlayOpenHorseScreen,
+				ClientboundPlayHurtAnimation,
+				ClientboundPlayInitializeWorldBorder,
+				ClientboundPlayKeepAlive,
+				ClientboundPlayChunkDataAndUpdateLight,
+				ClientboundPlayWorldEvent,
+				ClientboundPlayParticle,
+				ClientboundPlayUpdateLight,
+				ClientboundPlayLogin,
+				ClientboundPlayMapData,
+				ClientboundPlayMerchantOffers,
+				ClientboundPlayUpdateEntityPosition,
+				ClientboundPlayUpdateEntityPositionAndRotation,
+				ClientboundPlayUpdateEntityRotation,
+				ClientboundPlayMoveVehicle,
+				ClientboundPlayOpenBook,
+				ClientboundPlayOpenScreen,
+				ClientboundPlayOpenSignEditor,
+				ClientboundPlayPing,
+				ClientboundPlayPingResponse,
+				ClientboundPlayPlaceGhostRecipe,
+				ClientboundPlayPlayerAbilities,
+				ClientboundPlayPlayerChatMessage,
+				ClientboundPlayEndCombat,
+				ClientboundPlayEnterCombat,
+				ClientboundPlayCombatDeath,
+				ClientboundPlayPlayerInfoRemove,
+				ClientboundPlayPlayerInfoUpdate,
+				ClientboundPlayLookAt,
+				ClientboundPlaySynchronizePlayerPosition,
+				ClientboundPlayUpdateRecipeBook,
+				ClientboundPlayRemoveEntities,
+				ClientboundPlayRemoveEntityEffect,
+				ClientboundPlayResetScore,
+				ClientboundPlayRemoveResourcePack,
+				ClientboundPlayAddResourcePack,
+				ClientboundPlayRespawn,
+				ClientboundPlaySetHeadRotation,
+				ClientboundPlayUpdateSectionBlocks,
+				ClientboundPlaySelectAdvancementsTab,
+				ClientboundPlayServerData,
+				ClientboundPlaySetActionBarText,
+				ClientboundPlaySetBorderCenter,
+				ClientboundPlaySetBorderLerpSize,
+				ClientboundPlaySetBorderSize,
+				ClientboundPlaySetBorderWarningDelay,
+				ClientboundPlaySetBorderWarningDistance,
+				ClientboundPlaySetCamera,
+				ClientboundPlaySetHeldItem,
+				ClientboundPlaySetCenterChunk,
+				ClientboundPlaySetRenderDistance,
+				ClientboundPlaySetDefaultSpawnPosition,
+				ClientboundPlayDisplayObjective,
+				ClientboundPlaySetEntityMetadata,
+				ClientboundPlayLinkEntities,
+				ClientboundPlaySetEntityVelocity,
+				ClientboundPlaySetEquipment,
+				ClientboundPlaySetExperience,
+				ClientboundPlaySetHealth,
+				ClientboundPlayUpdateObjectives,
+				ClientboundPlaySetPassengers,
+				ClientboundPlayUpdateTeams,
+				ClientboundPlayUpdateScore,
+				ClientboundPlaySetSimulationDistance,
+				ClientboundPlaySetSubtitleText,
+				ClientboundPlayUpdateTime,
+				ClientboundPlaySetTitleText,
+				ClientboundPlaySetTitleAnimationTimes,
+				ClientboundPlayEntitySoundEffect,
+				ClientboundPlaySoundEffect,
+				ClientboundPlayStartConfiguration,
+				ClientboundPlayStopSound,
+				ClientboundPlayStoreCookie,
+				ClientboundPlaySystemChatMessage,
+				ClientboundPlaySetTabListHeaderAndFooter,
+				ClientboundPlayTagQueryResponse,
+				ClientboundPlayPickupItem,
+				ClientboundPlayTeleportEntity,
+				ClientboundPlaySetTickingState,
+				ClientboundPlayStepTick,
+				ClientboundPlayTransfer,
+				ClientboundPlayUpdateAdvancements,
+				ClientboundPlayUpdateAttributes,
+				ClientboundPlayEntityEffect,
+				ClientboundPlayUpdateRecipes,
+				ClientboundPlayUpdateTags,
+				ClientboundPlayProjectilePower,
+				ClientboundPlayCustomReportDetails,
+				ClientboundPlayServerLinks,
+			},
+		},
+	},
+}
@@ -0,0 +1,42 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// ParseBungeeCord reads the player BungeeCord forwards in the handshake's
+// server address field: the host, client IP, undashed UUID and, optionally, a
+// JSON array of profile properties, separated by NUL characters.
+func ParseBungeeCord(address string) (*Forwarded, error) {
+	parts := strings.Split(address, "\x00")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, ErrNotForwarded
+	}
+	addr, err := clientAddr(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("proxy: BungeeCord forwarding: %w", err)
+	}
+	uuid, err := protocol.ParseUUID(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("proxy: BungeeCord forwarding: %w", err)
+	}
+	f := &Forwarded{Host: parts[0], Addr: addr, UUID: uuid}
+	if len(parts) == 4 {
+		if err := json.Unmarshal([]byte(parts[3]), &f.Properties); err != nil {
+			return nil, fmt.Errorf("proxy: BungeeCord forwarding properties: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// StripBungeeCord returns the host part of a handshake address, dropping any
+// forwarded data, for uses such as the server list ping that only need the
+// host.
+func StripBungeeCord(address string) string {
+	host, _, _ := strings.Cut(address, "\x00")
+	return host
+}
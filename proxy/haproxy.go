@@ -0,0 +1,229 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoProxyHeader means a connection on a PROXY protocol listener did not
+// start with a PROXY header. Such connections are refused, since trusting
+// them would let clients bypass the proxy and pick their own address.
+var ErrNoProxyHeader = errors.New("proxy: connection did not send a PROXY header")
+
+// proxyV2Signature starts every PROXY protocol v2 header.
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// maxV1Header is the longest header v1 allows, including the CRLF.
+const maxV1Header = 107
+
+// Listener accepts connections that start with a HAProxy PROXY protocol
+// header, version 1 or 2. The header is read on the connection's first Read or
+// RemoteAddr call, so a slow client never holds up Accept.
+type Listener struct {
+	net.Listener
+	// HeaderTimeout bounds how long a connection may take to send its header.
+	HeaderTimeout time.Duration
+}
+
+// NewListener wraps ln with a five second header timeout.
+func NewListener(ln net.Listener) *Listener {
+	return &Listener{Listener: ln, HeaderTimeout: 5 * time.Second}
+}
+
+// Accept waits for the next connection.
+func (l *Listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &proxyConn{Conn: c, r: bufio.NewReader(c), timeout: l.HeaderTimeout}, nil
+}
+
+// proxyConn is a connection whose addresses come from its PROXY header.
+type proxyConn struct {
+	net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+
+	once   sync.Once
+	err    error
+	remote net.Addr
+	local  net.Addr
+
+	mu sync.Mutex
+	// readDeadline is the read deadline the caller set, which is put back
+	// once the header has been read under the header timeout.
+	readDeadline time.Time
+}
+
+// SetDeadline sets the read and write deadlines of the connection.
+func (c *proxyConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	return c.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the connection. A deadline set
+// before the header is read bounds the header too, and still applies after.
+func (c *proxyConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	return c.Conn.SetReadDeadline(t)
+}
+
+func (c *proxyConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.r.Read(b)
+}
+
+// RemoteAddr returns the client address from the header, or the address of the
+// proxy itself for LOCAL and UNKNOWN headers.
+func (c *proxyConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr returns the address the client connected to, as the proxy saw it.
+func (c *proxyConn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.local != nil {
+		return c.local
+	}
+	return c.Conn.LocalAddr()
+}
+
+func (c *proxyConn) readHeader() {
+	if c.timeout > 0 {
+		deadline := time.Now().Add(c.timeout)
+		c.mu.Lock()
+		if !c.readDeadline.IsZero() && c.readDeadline.Before(deadline) {
+			deadline = c.readDeadline
+		}
+		err := c.Conn.SetReadDeadline(deadline)
+		c.mu.Unlock()
+		if err != nil {
+			c.err = err
+			return
+		}
+		defer func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.Conn.SetReadDeadline(c.readDeadline)
+		}()
+	}
+
+	if sig, err := c.r.Peek(len(proxyV2Signature)); err == nil && bytes.Equal(sig, proxyV2Signature) {
+		c.remote, c.local, c.err = readV2(c.r)
+	} else if prefix, err := c.r.Peek(6); err == nil && string(prefix) == "PROXY " {
+		c.remote, c.local, c.err = readV1(c.r)
+	} else {
+		c.err = ErrNoProxyHeader
+	}
+	if c.err != nil {
+		c.Conn.Close()
+	}
+}
+
+// readV1 parses a text header such as "PROXY TCP4 1.2.3.4 5.6.7.8 1234 25565\r\n".
+func readV1(r *bufio.Reader) (remote, local net.Addr, err error) {
+	var line []byte
+	for len(line) < maxV1Header {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	header, ok := strings.CutSuffix(string(line), "\r\n")
+	if !ok {
+		return nil, nil, fmt.Errorf("proxy: PROXY v1 header too long or not CRLF terminated")
+	}
+	fields := strings.Split(header, " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, nil, fmt.Errorf("proxy: invalid PROXY v1 header %q", header)
+	}
+	src, err := v1Addr(fields[2], fields[4])
+	if err != nil {
+		return nil, nil, err
+	}
+	dst, err := v1Addr(fields[3], fields[5])
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, dst, nil
+}
+
+func v1Addr(ip, port string) (net.Addr, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("proxy: invalid PROXY v1 address %q", ip)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("proxy: invalid PROXY v1 port %q", port)
+	}
+	return &net.TCPAddr{IP: parsed, Port: int(p)}, nil
+}
+
+// readV2 parses a binary header. TLVs after the addresses are skipped.
+func readV2(r *bufio.Reader) (remote, local net.Addr, err error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return nil, nil, err
+	}
+	verCmd, family := fixed[12], fixed[13]
+	length := binary.BigEndian.Uint16(fixed[14:])
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, nil, err
+	}
+	if verCmd>>4 != 2 {
+		return nil, nil, fmt.Errorf("proxy: unsupported PROXY version %d", verCmd>>4)
+	}
+	switch verCmd & 0x0F {
+	case 0x0: // LOCAL: a health check from the proxy itself
+		return nil, nil, nil
+	case 0x1: // PROXY
+	default:
+		return nil, nil, fmt.Errorf("proxy: unsupported PROXY command %d", verCmd&0x0F)
+	}
+
+	var ipLen int
+	switch family >> 4 {
+	case 0x1:
+		ipLen = net.IPv4len
+	case 0x2:
+		ipLen = net.IPv6len
+	default: // UNSPEC or UNIX: nothing usable
+		return nil, nil, nil
+	}
+	if len(body) < 2*ipLen+4 {
+		return nil, nil, fmt.Errorf("proxy: PROXY v2 address block too short")
+	}
+	src := &net.TCPAddr{IP: net.IP(body[:ipLen]), Port: int(binary.BigEndian.Uint16(body[2*ipLen:]))}
+	dst := &net.TCPAddr{IP: net.IP(body[ipLen : 2*ipLen]), Port: int(binary.BigEndian.Uint16(body[2*ipLen+2:]))}
+	return src, dst, nil
+}
@@ -0,0 +1,44 @@
+// Package proxy implements the ways a proxy in front of the server passes on
+// the real client: BungeeCord's legacy forwarding in the handshake address,
+// Velocity's modern forwarding through a login plugin message, and the HAProxy
+// PROXY protocol on the listener.
+package proxy
+
+import (
+	"errors"
+	"net"
+
+	"github.com/Advik-B/Golem/protocol"
+)
+
+var (
+	// ErrNotForwarded means the proxy did not forward the player, usually
+	// because forwarding is not enabled on the proxy side.
+	ErrNotForwarded = errors.New("proxy: connection was not forwarded")
+	// ErrInvalidSignature means a Velocity forwarding payload was not signed
+	// with the configured secret.
+	ErrInvalidSignature = errors.New("proxy: invalid forwarding signature")
+)
+
+// Forwarded is what a proxy tells the server about the player behind it.
+type Forwarded struct {
+	// Host is the address the client connected to. Only BungeeCord sends it.
+	Host string
+	// Addr is the client's own address.
+	Addr net.Addr
+	UUID protocol.UUID
+	// Name is the player's name. Only Velocity sends it; with BungeeCord it
+	// comes from Login Start as usual.
+	Name       string
+	Properties []protocol.Property
+}
+
+// clientAddr builds a net.Addr for a forwarded IP. Proxies do not forward the
+// client's port, so it is left zero.
+func clientAddr(ip string) (net.Addr, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: ip}
+	}
+	return &net.TCPAddr{IP: parsed}, nil
+}
@@ -0,0 +1,150 @@
+package proxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var notch = protocol.OfflineUUID("Notch")
+
+var textures = protocol.Property{Name: "textures", Value: "ewogICJ0aW1lc3RhbXAiIDogMAp9", Signature: "c2lnbmF0dXJl"}
+
+func TestBungeeCord(t *testing.T) {
+	address := "mc.example\x00203.0.113.7\x00b50ad385829d3141a2167e7d7539ba7f\x00" +
+		`[{"name":"textures","value":"ewogICJ0aW1lc3RhbXAiIDogMAp9","signature":"c2lnbmF0dXJl"}]`
+	f, err := ParseBungeeCord(address)
+	require.NoError(t, err)
+	assert.Equal(t, "mc.example", f.Host)
+	assert.Equal(t, "203.0.113.7", f.Addr.(*net.TCPAddr).IP.String())
+	assert.Equal(t, notch, f.UUID)
+	assert.Equal(t, []protocol.Property{textures}, f.Properties)
+	assert.Equal(t, "mc.example", StripBungeeCord(address))
+
+	_, err = ParseBungeeCord("mc.example")
+	assert.ErrorIs(t, err, ErrNotForwarded)
+	_, err = ParseBungeeCord("mc.example\x00not-an-ip\x00b50ad385829d3141a2167e7d7539ba7f")
+	assert.Error(t, err)
+}
+
+// velocityResponse builds the forwarding data a Velocity proxy would send.
+func velocityResponse(secret []byte, version int) []byte {
+	payload := protocol.WriteVarInt(version)
+	payload = append(payload, protocol.WriteString("2001:db8::1")...)
+	payload = append(payload, protocol.WriteUUID(notch)...)
+	payload = append(payload, protocol.WriteString("Notch")...)
+	payload = append(payload, protocol.WriteProperties([]protocol.Property{textures})...)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return append(mac.Sum(nil), payload...)
+}
+
+func TestVelocity(t *testing.T) {
+	secret := []byte("s3cr3t")
+	assert.Equal(t, []byte{VelocityLazySession}, VelocityRequest())
+
+	f, err := ParseVelocity(secret, velocityResponse(secret, VelocityLazySession))
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::1", f.Addr.(*net.TCPAddr).IP.String())
+	assert.Equal(t, notch, f.UUID)
+	assert.Equal(t, "Notch", f.Name)
+	assert.Equal(t, []protocol.Property{textures}, f.Properties)
+
+	_, err = ParseVelocity([]byte("wrong"), velocityResponse(secret, VelocityLazySession))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = ParseVelocity(secret, []byte{1, 2, 3})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = ParseVelocity(secret, velocityResponse(secret, VelocityWithKeyV2))
+	assert.Error(t, err)
+}
+
+// dialProxy connects to ln as a proxy would, sends header and then "hello",
+// and returns what the server side sees.
+func dialProxy(t *testing.T, ln *Listener, header []byte) (net.Conn, []byte, error) {
+	t.Helper()
+	client, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	_, err = client.Write(append(header, "hello"...))
+	require.NoError(t, err)
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	return conn, buf, err
+}
+
+func TestProxyProtocol(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ln := NewListener(inner)
+	defer ln.Close()
+
+	t.Run("v1", func(t *testing.T) {
+		conn, data, err := dialProxy(t, ln, []byte("PROXY TCP4 198.51.100.2 192.0.2.1 51234 25565\r\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+		assert.Equal(t, "198.51.100.2:51234", conn.RemoteAddr().String())
+		assert.Equal(t, "192.0.2.1:25565", conn.LocalAddr().String())
+	})
+
+	t.Run("v1 unknown", func(t *testing.T) {
+		conn, data, err := dialProxy(t, ln, []byte("PROXY UNKNOWN\r\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+		assert.Equal(t, "127.0.0.1", conn.RemoteAddr().(*net.TCPAddr).IP.String())
+	})
+
+	t.Run("v2", func(t *testing.T) {
+		header := append([]byte(nil), proxyV2Signature...)
+		header = append(header, 0x21, 0x21) // v2 PROXY, TCP over IPv6
+		header = binary.BigEndian.AppendUint16(header, 36+3)
+		header = append(header, net.ParseIP("2001:db8::2")...)
+		header = append(header, net.ParseIP("2001:db8::1")...)
+		header = binary.BigEndian.AppendUint16(header, 40000)
+		header = binary.BigEndian.AppendUint16(header, 25565)
+		header = append(header, 0x04, 0x00, 0x00) // an empty NOOP TLV
+		conn, data, err := dialProxy(t, ln, header)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+		assert.Equal(t, "[2001:db8::2]:40000", conn.RemoteAddr().String())
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		_, _, err := dialProxy(t, ln, []byte("\x10\x00\xff\x05\x09localhost"))
+		assert.ErrorIs(t, err, ErrNoProxyHeader)
+	})
+
+	t.Run("deadline kept", func(t *testing.T) {
+		client, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer client.Close()
+		_, err = client.Write([]byte("PROXY UNKNOWN\r\nhi"))
+		require.NoError(t, err)
+		// Without the deadline the read would end only here, with EOF.
+		time.AfterFunc(2*time.Second, func() { client.Close() })
+
+		// The deadline set before the header is read still holds for the
+		// reads after it, so a client that goes quiet is cut off.
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+		buf := make([]byte, 2)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		_, err = conn.Read(buf)
+		var netErr net.Error
+		require.ErrorAs(t, err, &netErr)
+		assert.True(t, netErr.Timeout())
+	})
+}
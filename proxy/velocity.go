@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// VelocityChannel is the login plugin channel Velocity answers with the
+// forwarded player.
+const VelocityChannel = "velocity:player_info"
+
+// Velocity forwarding versions. Golem only speaks 1.20.3 and newer, where
+// Velocity never sends the chat session key in the forwarding data, so only
+// the versions without a key are accepted.
+const (
+	VelocityDefault     = 1
+	VelocityWithKey     = 2
+	VelocityWithKeyV2   = 3
+	VelocityLazySession = 4
+)
+
+// VelocityRequest returns the payload of the Login Plugin Request that asks
+// Velocity for the player. It is the highest forwarding version we accept.
+func VelocityRequest() []byte {
+	return []byte{VelocityLazySession}
+}
+
+// ParseVelocity verifies and decodes the Login Plugin Response data Velocity
+// sends on VelocityChannel. The data is an HMAC-SHA256 of the rest, keyed with
+// the forwarding secret, followed by the version, client address, UUID, name
+// and properties.
+func ParseVelocity(secret, data []byte) (*Forwarded, error) {
+	if len(data) < sha256.Size {
+		return nil, ErrInvalidSignature
+	}
+	signature, payload := data[:sha256.Size], data[sha256.Size:]
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrInvalidSignature
+	}
+
+	r := bytes.NewReader(payload)
+	version, err := protocol.ReadVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if version != VelocityDefault && version != VelocityLazySession {
+		return nil, fmt.Errorf("proxy: unsupported Velocity forwarding version %d", version)
+	}
+	ip, err := protocol.ReadString(r, 255)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := clientAddr(ip)
+	if err != nil {
+		return nil, fmt.Errorf("proxy: Velocity forwarding: %w", err)
+	}
+	f := &Forwarded{Addr: addr}
+	if f.UUID, err = protocol.ReadUUID(r); err != nil {
+		return nil, err
+	}
+	if f.Name, err = protocol.ReadString(r, 16); err != nil {
+		return nil, err
+	}
+	if f.Properties, err = protocol.ReadProperties(r); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
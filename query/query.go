@@ -0,0 +1,194 @@
+// Package query implements the GameSpy4 query protocol that server browsers
+// and monitoring scripts use over UDP, as enabled by enable-query in vanilla.
+// A client first asks for a challenge token, then sends it back with a basic
+// or a full stat request.
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Packet types.
+const (
+	typeStat      = 0x00
+	typeHandshake = 0x09
+)
+
+// magic starts every request.
+var magic = []byte{0xFE, 0xFD}
+
+// TokenLifetime is how long a challenge token stays valid. Vanilla forgets
+// tokens after 30 seconds as well.
+const TokenLifetime = 30 * time.Second
+
+// maxTokens caps the outstanding challenge tokens, so a flood of handshakes
+// from spoofed addresses cannot grow the table without limit.
+const maxTokens = 8192
+
+// Stats is what a query reports about the server.
+type Stats struct {
+	MOTD string
+	// GameType is "SMP" for vanilla.
+	GameType string
+	Map      string
+	Version  string
+	// Plugins is a free-form plugin list. Bukkit sends
+	// "<server>: <plugin> <version>; ...".
+	Plugins    string
+	NumPlayers int
+	MaxPlayers int
+	// HostIP and HostPort are where players connect to the game.
+	HostIP   string
+	HostPort int
+	// Players are the names in the full stat.
+	Players []string
+}
+
+// Server answers queries. It is safe for concurrent use.
+type Server struct {
+	// Stats returns the stats reported to addr, or nil to ignore the request.
+	Stats func(addr net.Addr) *Stats
+
+	mu     sync.Mutex
+	tokens map[string]challenge
+}
+
+type challenge struct {
+	token   int32
+	expires time.Time
+}
+
+// NewServer creates a server reporting the stats from stats.
+func NewServer(stats func(addr net.Addr) *Stats) *Server {
+	return &Server{Stats: stats, tokens: make(map[string]challenge)}
+}
+
+// Serve answers the requests read from pc until it is closed.
+func (s *Server) Serve(pc net.PacketConn) error {
+	buf := make([]byte, 1460)
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			continue
+		}
+		if resp := s.Handle(buf[:n], addr, time.Now()); resp != nil {
+			pc.WriteTo(resp, addr)
+		}
+	}
+}
+
+// Handle answers one request from addr. It returns nil for requests that
+// are malformed or carry no valid token, which get no answer.
+func (s *Server) Handle(req []byte, addr net.Addr, now time.Time) []byte {
+	if len(req) < 7 || !bytes.Equal(req[:2], magic) {
+		return nil
+	}
+	kind, session := req[2], req[3:7]
+	switch kind {
+	case typeHandshake:
+		token, ok := s.newToken(addr, now)
+		if !ok {
+			return nil
+		}
+		resp := append([]byte{typeHandshake}, session...)
+		resp = append(resp, strconv.Itoa(int(token))...)
+		return append(resp, 0)
+	case typeStat:
+		if len(req) < 11 || !s.validToken(addr, int32(binary.BigEndian.Uint32(req[7:11])), now) {
+			return nil
+		}
+		stats := s.Stats(addr)
+		if stats == nil {
+			return nil
+		}
+		resp := append([]byte{typeStat}, session...)
+		// A full stat request pads the token with four bytes.
+		if len(req) >= 15 {
+			return stats.appendFull(resp)
+		}
+		return stats.appendBasic(resp)
+	}
+	return nil
+}
+
+// newToken issues addr a challenge token, replacing any it had.
+func (s *Server) newToken(addr net.Addr, now time.Time) (int32, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.tokens) >= maxTokens {
+		for key, c := range s.tokens {
+			if now.After(c.expires) {
+				delete(s.tokens, key)
+			}
+		}
+		if len(s.tokens) >= maxTokens {
+			return 0, false
+		}
+	}
+	token := rand.Int31()
+	s.tokens[addr.String()] = challenge{token: token, expires: now.Add(TokenLifetime)}
+	return token, true
+}
+
+func (s *Server) validToken(addr net.Addr, token int32, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.tokens[addr.String()]
+	if ok && now.After(c.expires) {
+		delete(s.tokens, addr.String())
+		return false
+	}
+	return ok && c.token == token
+}
+
+// appendBasic appends the basic stat: a fixed list of strings and the port.
+func (st *Stats) appendBasic(b []byte) []byte {
+	for _, v := range []string{st.MOTD, st.GameType, st.Map, strconv.Itoa(st.NumPlayers), strconv.Itoa(st.MaxPlayers)} {
+		b = appendString(b, v)
+	}
+	b = binary.LittleEndian.AppendUint16(b, uint16(st.HostPort))
+	return appendString(b, st.HostIP)
+}
+
+// appendFull appends the full stat: the key-value section, then the player
+// names.
+func (st *Stats) appendFull(b []byte) []byte {
+	b = append(b, "splitnum\x00\x80\x00"...)
+	for _, kv := range [][2]string{
+		{"hostname", st.MOTD},
+		{"gametype", st.GameType},
+		{"game_id", "MINECRAFT"},
+		{"version", st.Version},
+		{"plugins", st.Plugins},
+		{"map", st.Map},
+		{"numplayers", strconv.Itoa(st.NumPlayers)},
+		{"maxplayers", strconv.Itoa(st.MaxPlayers)},
+		{"hostport", strconv.Itoa(st.HostPort)},
+		{"hostip", st.HostIP},
+	} {
+		b = appendString(appendString(b, kv[0]), kv[1])
+	}
+	b = append(b, 0)
+	b = append(b, "\x01player_\x00\x00"...)
+	for _, name := range st.Players {
+		b = appendString(b, name)
+	}
+	return append(b, 0)
+}
+
+// appendString appends s NUL-terminated. The protocol has no escaping, so
+// NULs inside s are dropped.
+func appendString(b []byte, s string) []byte {
+	b = append(b, bytes.ReplaceAll([]byte(s), []byte{0}, nil)...)
+	return append(b, 0)
+}
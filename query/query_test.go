@@ -0,0 +1,138 @@
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStats() *Stats {
+	return &Stats{
+		MOTD:       "A Golem Server",
+		GameType:   "SMP",
+		Map:        "world",
+		Version:    "1.21.1",
+		Plugins:    "Golem",
+		NumPlayers: 2,
+		MaxPlayers: 20,
+		HostIP:     "127.0.0.1",
+		HostPort:   25565,
+		Players:    []string{"Alex", "Steve"},
+	}
+}
+
+var session = []byte{0x00, 0x00, 0x00, 0x01}
+
+func request(kind byte, extra ...byte) []byte {
+	return append(append([]byte{0xFE, 0xFD, kind}, session...), extra...)
+}
+
+// handshake asks for a token and returns it as the stat requests send it.
+func handshake(t *testing.T, s *Server, addr net.Addr, now time.Time) []byte {
+	t.Helper()
+	resp := s.Handle(request(typeHandshake), addr, now)
+	require.NotNil(t, resp)
+	require.Equal(t, append([]byte{typeHandshake}, session...), resp[:5])
+	require.Equal(t, byte(0), resp[len(resp)-1])
+	token, err := strconv.ParseInt(string(resp[5:len(resp)-1]), 10, 32)
+	require.NoError(t, err)
+	return binary.BigEndian.AppendUint32(nil, uint32(token))
+}
+
+func TestBasicStat(t *testing.T) {
+	s := NewServer(func(net.Addr) *Stats { return testStats() })
+	addr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}
+	now := time.Now()
+	token := handshake(t, s, addr, now)
+
+	resp := s.Handle(request(typeStat, token...), addr, now)
+	want := append([]byte{typeStat}, session...)
+	want = append(want, "A Golem Server\x00SMP\x00world\x002\x0020\x00"...)
+	want = append(want, 0xDD, 0x63) // 25565, little-endian
+	want = append(want, "127.0.0.1\x00"...)
+	assert.Equal(t, want, resp)
+}
+
+func TestFullStat(t *testing.T) {
+	s := NewServer(func(net.Addr) *Stats { return testStats() })
+	addr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}
+	now := time.Now()
+	token := handshake(t, s, addr, now)
+
+	resp := s.Handle(request(typeStat, append(token, 0, 0, 0, 0)...), addr, now)
+	require.NotNil(t, resp)
+	body := bytes.TrimPrefix(resp, append(append([]byte{typeStat}, session...), "splitnum\x00\x80\x00"...))
+	require.NotEqual(t, len(resp), len(body), "missing header")
+	kv, players, ok := bytes.Cut(body, []byte("\x00\x00\x01player_\x00\x00"))
+	require.True(t, ok)
+
+	fields := bytes.Split(kv, []byte{0})
+	values := make(map[string]string)
+	for i := 0; i+1 < len(fields); i += 2 {
+		values[string(fields[i])] = string(fields[i+1])
+	}
+	assert.Equal(t, map[string]string{
+		"hostname":   "A Golem Server",
+		"gametype":   "SMP",
+		"game_id":    "MINECRAFT",
+		"version":    "1.21.1",
+		"plugins":    "Golem",
+		"map":        "world",
+		"numplayers": "2",
+		"maxplayers": "20",
+		"hostport":   "25565",
+		"hostip":     "127.0.0.1",
+	}, values)
+	assert.Equal(t, "Alex\x00Steve\x00\x00", string(players))
+}
+
+func TestTokens(t *testing.T) {
+	s := NewServer(func(net.Addr) *Stats { return testStats() })
+	addr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}
+	other := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50001}
+	now := time.Now()
+	token := handshake(t, s, addr, now)
+
+	assert.Nil(t, s.Handle(request(typeStat, 0, 0, 0, 0), addr, now), "wrong token")
+	assert.Nil(t, s.Handle(request(typeStat, token...), other, now), "token of another address")
+	assert.Nil(t, s.Handle(request(typeStat, token...), addr, now.Add(TokenLifetime+time.Second)), "expired token")
+	assert.Nil(t, s.Handle(request(typeStat, token...), addr, now), "token forgotten once expired")
+	assert.Nil(t, s.Handle([]byte{0xFE, 0xFD, typeStat}, addr, now), "truncated")
+	assert.Nil(t, s.Handle(request(0x42), addr, now), "unknown type")
+
+	dropped := NewServer(func(net.Addr) *Stats { return nil })
+	token = handshake(t, dropped, addr, now)
+	assert.Nil(t, dropped.Handle(request(typeStat, token...), addr, now))
+}
+
+func TestServe(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := NewServer(func(net.Addr) *Stats { return testStats() })
+	go s.Serve(pc)
+	defer pc.Close()
+
+	conn, err := net.Dial("udp", pc.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Write(request(typeHandshake))
+	require.NoError(t, err)
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	token, err := strconv.ParseInt(string(buf[5:n-1]), 10, 32)
+	require.NoError(t, err)
+
+	_, err = conn.Write(request(typeStat, binary.BigEndian.AppendUint32(nil, uint32(token))...))
+	require.NoError(t, err)
+	n, err = conn.Read(buf)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf[:n]), "A Golem Server\x00SMP\x00world\x00")
+}
@@ -0,0 +1,92 @@
+package rcon
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+)
+
+// ErrAuthFailed means the server rejected the password.
+var ErrAuthFailed = errors.New("rcon: authentication failed")
+
+// Client is a logged-in RCON connection. It is not safe for concurrent use.
+type Client struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	nextID int32
+}
+
+// Dial connects to the RCON server at addr and logs in with password.
+func Dial(addr, password string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewClient(conn, password)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewClient logs in with password over conn.
+func NewClient(conn net.Conn, password string) (*Client, error) {
+	c := &Client{conn: conn, r: bufio.NewReader(conn), nextID: 1}
+	id := c.id()
+	if err := WritePacket(conn, &Packet{ID: id, Type: TypeAuth, Body: password}); err != nil {
+		return nil, err
+	}
+	for {
+		p, err := ReadPacket(c.r, 1<<20)
+		if err != nil {
+			return nil, err
+		}
+		// Some servers send an empty response before the auth response.
+		if p.Type != TypeAuthResponse {
+			continue
+		}
+		if p.ID != id {
+			return nil, ErrAuthFailed
+		}
+		return c, nil
+	}
+}
+
+func (c *Client) id() int32 {
+	id := c.nextID
+	c.nextID++
+	return id
+}
+
+// Exec runs command and returns its output. Output split over several
+// packets is joined: an unknown request sent after the command marks where
+// the output ends.
+func (c *Client) Exec(command string) (string, error) {
+	id, marker := c.id(), c.id()
+	if err := WritePacket(c.conn, &Packet{ID: id, Type: TypeCommand, Body: command}); err != nil {
+		return "", err
+	}
+	if err := WritePacket(c.conn, &Packet{ID: marker, Type: TypeResponse}); err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	for {
+		p, err := ReadPacket(c.r, 1<<20)
+		if err != nil {
+			return "", err
+		}
+		switch p.ID {
+		case id:
+			out.WriteString(p.Body)
+		case marker:
+			return out.String(), nil
+		case AuthFailedID:
+			return "", ErrAuthFailed
+		}
+	}
+}
+
+// Close closes the connection.
+func (c *Client) Close() error { return c.conn.Close() }
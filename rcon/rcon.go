@@ -0,0 +1,102 @@
+// Package rcon implements the Source RCON protocol that Minecraft servers use
+// for remote administration: a TCP connection that logs in with a password
+// and then runs console commands, receiving their output as text.
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Packet types. Commands and authentication responses share a number.
+const (
+	TypeResponse     int32 = 0
+	TypeCommand      int32 = 2
+	TypeAuthResponse int32 = 2
+	TypeAuth         int32 = 3
+)
+
+// AuthFailedID is the request ID of the response to a failed login.
+const AuthFailedID int32 = -1
+
+const (
+	// MaxRequestLength is the largest packet a client may send, as in
+	// vanilla.
+	MaxRequestLength = 1460
+	// MaxResponseBody is the most output sent in one packet. Longer output
+	// is split over several packets with the same ID.
+	MaxResponseBody = 4096
+	// headerLength is the ID, the type and the two NULs after the body.
+	headerLength = 10
+)
+
+// ErrPacketLength means a packet declared a length out of range.
+var ErrPacketLength = errors.New("rcon: invalid packet length")
+
+// Packet is one RCON packet.
+type Packet struct {
+	ID   int32
+	Type int32
+	Body string
+}
+
+// ReadPacket reads a packet of at most maxLength bytes after the length
+// field.
+func ReadPacket(r io.Reader, maxLength int) (*Packet, error) {
+	var length int32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < headerLength || int(length) > maxLength {
+		return nil, fmt.Errorf("%w: %d", ErrPacketLength, length)
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	p := &Packet{
+		ID:   int32(binary.LittleEndian.Uint32(buf[0:4])),
+		Type: int32(binary.LittleEndian.Uint32(buf[4:8])),
+	}
+	// The body ends at the first NUL; some clients leave out the padding.
+	body := buf[8:]
+	if i := bytes.IndexByte(body, 0); i >= 0 {
+		body = body[:i]
+	}
+	p.Body = string(body)
+	return p, nil
+}
+
+// WritePacket writes p in one Write call.
+func WritePacket(w io.Writer, p *Packet) error {
+	buf := make([]byte, 0, 4+headerLength+len(p.Body))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(headerLength+len(p.Body)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(p.ID))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(p.Type))
+	buf = append(buf, p.Body...)
+	buf = append(buf, 0, 0)
+	_, err := w.Write(buf)
+	return err
+}
+
+// splitBody cuts output into chunks of at most MaxResponseBody bytes without
+// splitting a UTF-8 sequence. Empty output is one empty chunk.
+func splitBody(s string) []string {
+	if len(s) <= MaxResponseBody {
+		return []string{s}
+	}
+	var chunks []string
+	for len(s) > MaxResponseBody {
+		cut := MaxResponseBody
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		chunks = append(chunks, s[:cut])
+		s = s[cut:]
+	}
+	return append(chunks, s)
+}
@@ -0,0 +1,106 @@
+package rcon
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacket(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePacket(&buf, &Packet{ID: 7, Type: TypeCommand, Body: "list"}))
+	assert.Equal(t, []byte{14, 0, 0, 0, 7, 0, 0, 0, 2, 0, 0, 0, 'l', 'i', 's', 't', 0, 0}, buf.Bytes())
+
+	p, err := ReadPacket(&buf, MaxRequestLength)
+	require.NoError(t, err)
+	assert.Equal(t, &Packet{ID: 7, Type: TypeCommand, Body: "list"}, p)
+
+	_, err = ReadPacket(bytes.NewReader([]byte{9, 0, 0, 0}), MaxRequestLength)
+	assert.ErrorIs(t, err, ErrPacketLength)
+	_, err = ReadPacket(bytes.NewReader([]byte{0xFF, 0xFF, 0, 0}), MaxRequestLength)
+	assert.ErrorIs(t, err, ErrPacketLength)
+}
+
+func TestSplitBody(t *testing.T) {
+	assert.Equal(t, []string{""}, splitBody(""))
+	long := strings.Repeat("a", MaxResponseBody-1) + "é" + "b"
+	chunks := splitBody(long)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, strings.Repeat("a", MaxResponseBody-1), chunks[0])
+	assert.Equal(t, "éb", chunks[1])
+}
+
+func serve(t *testing.T, s *Server) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go s.Serve(ln)
+	return ln.Addr().String()
+}
+
+func TestExec(t *testing.T) {
+	long := strings.Repeat("x", 3*MaxResponseBody+10)
+	addr := serve(t, &Server{
+		Password: "secret",
+		Exec: func(_ net.Addr, command string) string {
+			if command == "long" {
+				return long
+			}
+			return "ran " + command
+		},
+	})
+
+	c, err := Dial(addr, "secret")
+	require.NoError(t, err)
+	defer c.Close()
+	out, err := c.Exec("time set day")
+	require.NoError(t, err)
+	assert.Equal(t, "ran time set day", out)
+	out, err = c.Exec("long")
+	require.NoError(t, err)
+	assert.Equal(t, long, out)
+}
+
+func TestUnauthenticated(t *testing.T) {
+	addr := serve(t, &Server{Password: "secret", Exec: func(net.Addr, string) string { return "ran" }})
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	require.NoError(t, WritePacket(conn, &Packet{ID: 1, Type: TypeCommand, Body: "stop"}))
+	p, err := ReadPacket(conn, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, &Packet{ID: AuthFailedID, Type: TypeAuthResponse}, p)
+}
+
+func TestAuthBan(t *testing.T) {
+	addr := serve(t, &Server{
+		Password:        "secret",
+		Exec:            func(net.Addr, string) string { return "" },
+		MaxAuthFailures: 3,
+		BanTime:         time.Minute,
+	})
+	for i := 0; i < 2; i++ {
+		_, err := Dial(addr, "wrong")
+		require.ErrorIs(t, err, ErrAuthFailed)
+	}
+	// A login clears the failures.
+	c, err := Dial(addr, "secret")
+	require.NoError(t, err)
+	c.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err := Dial(addr, "wrong")
+		require.ErrorIs(t, err, ErrAuthFailed)
+	}
+	_, err = Dial(addr, "secret")
+	assert.Error(t, err, "banned address logged in")
+	assert.NotErrorIs(t, err, ErrAuthFailed)
+}
@@ -0,0 +1,174 @@
+package rcon
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// Server accepts RCON connections and runs their commands with Exec.
+type Server struct {
+	// Password is the password clients log in with. It must not be empty.
+	Password string
+	// Exec runs a command line, without the leading slash, and returns its
+	// output as plain text.
+	Exec func(addr net.Addr, command string) string
+	// MaxAuthFailures is how many wrong passwords an address may send within
+	// BanTime before it is refused for BanTime. Zero turns this off.
+	MaxAuthFailures int
+	BanTime         time.Duration
+	// ReadTimeout closes connections idle for that long. Zero means never.
+	ReadTimeout time.Duration
+
+	mu       sync.Mutex
+	failures map[netip.Addr]*authFailures
+}
+
+// authFailures tracks the wrong passwords from one address.
+type authFailures struct {
+	count int
+	last  time.Time
+	// bannedUntil is set once the address ran out of attempts.
+	bannedUntil time.Time
+}
+
+// Serve accepts connections on ln until it is closed.
+func (s *Server) Serve(ln net.Listener) error {
+	if s.Password == "" {
+		return errors.New("rcon: no password set")
+	}
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			log.Println("RCON accept error:", err)
+			continue
+		}
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn speaks RCON on conn until the client disconnects.
+func (s *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+	ip, hasIP := addrIP(conn.RemoteAddr())
+	if hasIP && s.banned(ip, time.Now()) {
+		return
+	}
+	r := bufio.NewReader(conn)
+	authed := false
+	for {
+		if s.ReadTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(s.ReadTimeout))
+		}
+		p, err := ReadPacket(r, MaxRequestLength)
+		if err != nil {
+			return
+		}
+		switch {
+		case p.Type == TypeAuth:
+			if subtle.ConstantTimeCompare([]byte(p.Body), []byte(s.Password)) == 1 {
+				authed = true
+				if hasIP {
+					s.forgive(ip)
+				}
+				log.Printf("RCON client %s logged in", conn.RemoteAddr())
+				err = WritePacket(conn, &Packet{ID: p.ID, Type: TypeAuthResponse})
+				break
+			}
+			authed = false
+			log.Printf("RCON client %s sent a wrong password", conn.RemoteAddr())
+			err = WritePacket(conn, &Packet{ID: AuthFailedID, Type: TypeAuthResponse})
+			if hasIP && s.fail(ip, time.Now()) {
+				log.Printf("RCON client %s is banned for %s after too many wrong passwords", ip, s.BanTime)
+				return
+			}
+		case !authed:
+			err = WritePacket(conn, &Packet{ID: AuthFailedID, Type: TypeAuthResponse})
+		case p.Type == TypeCommand:
+			log.Printf("RCON client %s ran: %s", conn.RemoteAddr(), p.Body)
+			err = s.respond(conn, p.ID, s.Exec(conn.RemoteAddr(), p.Body))
+		default:
+			// Clients send an unknown request after a command to learn where
+			// its possibly split output ends.
+			err = s.respond(conn, p.ID, fmt.Sprintf("Unknown request %x", p.Type))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// respond sends output as one or more response packets with the request ID.
+func (s *Server) respond(conn net.Conn, id int32, output string) error {
+	for _, chunk := range splitBody(output) {
+		if err := WritePacket(conn, &Packet{ID: id, Type: TypeResponse, Body: chunk}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addrIP(addr net.Addr) (netip.Addr, bool) {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+	ip, ok := netip.AddrFromSlice(tcp.IP)
+	return ip.Unmap(), ok
+}
+
+// banned reports whether ip is refused for sending too many wrong passwords.
+func (s *Server) banned(ip netip.Addr, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.failures[ip]
+	return f != nil && now.Before(f.bannedUntil)
+}
+
+// fail records a wrong password from ip and reports whether it is now
+// banned.
+func (s *Server) fail(ip netip.Addr, now time.Time) bool {
+	if s.MaxAuthFailures <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures == nil {
+		s.failures = make(map[netip.Addr]*authFailures)
+	}
+	// Forget addresses whose failures and bans have run out.
+	for addr, f := range s.failures {
+		if now.Sub(f.last) > s.BanTime && now.After(f.bannedUntil) {
+			delete(s.failures, addr)
+		}
+	}
+	f := s.failures[ip]
+	if f == nil {
+		f = &authFailures{}
+		s.failures[ip] = f
+	}
+	f.count++
+	f.last = now
+	if f.count >= s.MaxAuthFailures {
+		f.count = 0
+		f.bannedUntil = now.Add(s.BanTime)
+		return true
+	}
+	return false
+}
+
+// forgive clears the failures of an address that logged in.
+func (s *Server) forgive(ip netip.Addr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, ip)
+}
@@ -0,0 +1,268 @@
+package region
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// Minecraft compresses LZ4 chunks with lz4-java's LZ4BlockOutputStream: a
+// series of blocks, each with a header naming its method, sizes and an
+// XXH32 checksum of the uncompressed bytes, ended by an empty raw block.
+
+var lz4Magic = []byte("LZ4Block")
+
+const (
+	lz4MethodRaw = 0x10
+	lz4MethodLZ4 = 0x20
+	// lz4BlockSize is lz4-java's default block size, which level 6 encodes.
+	lz4BlockSize = 1 << 16
+	lz4Level     = 6
+	lz4Seed      = 0x9747b28c
+	// lz4HeaderLength is the magic, the token and three 32-bit fields.
+	lz4HeaderLength = 8 + 1 + 4 + 4 + 4
+)
+
+var errLZ4Corrupt = errors.New("region: corrupt LZ4 data")
+
+// lz4Compress encodes data as an LZ4Block stream.
+func lz4Compress(data []byte) []byte {
+	var out []byte
+	for len(data) > 0 {
+		n := min(len(data), lz4BlockSize)
+		block := data[:n]
+		data = data[n:]
+		method, payload := byte(lz4MethodLZ4), lz4CompressBlock(block)
+		if len(payload) >= len(block) {
+			method, payload = lz4MethodRaw, block
+		}
+		out = appendLZ4Header(out, method, len(payload), len(block), xxh32(block, lz4Seed)&0xFFFFFFF)
+		out = append(out, payload...)
+	}
+	return appendLZ4Header(out, lz4MethodRaw, 0, 0, 0)
+}
+
+func appendLZ4Header(b []byte, method byte, compressed, original int, checksum uint32) []byte {
+	b = append(b, lz4Magic...)
+	b = append(b, method|lz4Level)
+	b = binary.LittleEndian.AppendUint32(b, uint32(compressed))
+	b = binary.LittleEndian.AppendUint32(b, uint32(original))
+	return binary.LittleEndian.AppendUint32(b, checksum)
+}
+
+// lz4Decompress decodes an LZ4Block stream.
+func lz4Decompress(r io.Reader) ([]byte, error) {
+	var out bytes.Buffer
+	header := make([]byte, lz4HeaderLength)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(header[:8], lz4Magic) {
+			return nil, errLZ4Corrupt
+		}
+		method, level := header[8]&0xF0, int(header[8]&0x0F)
+		compressed := int(binary.LittleEndian.Uint32(header[9:]))
+		original := int(binary.LittleEndian.Uint32(header[13:]))
+		checksum := binary.LittleEndian.Uint32(header[17:])
+		maxBlock := 1 << (level + 10)
+		if original < 0 || original > maxBlock || compressed < 0 || compressed > maxBlock ||
+			(method == lz4MethodRaw && compressed != original) ||
+			(method != lz4MethodRaw && method != lz4MethodLZ4) {
+			return nil, errLZ4Corrupt
+		}
+		if original == 0 {
+			if compressed != 0 || checksum != 0 {
+				return nil, errLZ4Corrupt
+			}
+			return out.Bytes(), nil
+		}
+		payload := make([]byte, compressed)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return nil, err
+		}
+		block := payload
+		if method == lz4MethodLZ4 {
+			var err error
+			if block, err = lz4DecompressBlock(payload, original); err != nil {
+				return nil, err
+			}
+		}
+		if xxh32(block, lz4Seed)&0xFFFFFFF != checksum {
+			return nil, fmt.Errorf("%w: checksum mismatch", errLZ4Corrupt)
+		}
+		out.Write(block)
+	}
+}
+
+// Limits of the LZ4 block format.
+const (
+	lz4MinMatch     = 4
+	lz4MFLimit      = 12 // the last match starts at least this far from the end
+	lz4LastLiterals = 5  // the last bytes are always literals
+	lz4HashLog      = 14
+	lz4MaxOffset    = 65535
+)
+
+// lz4CompressBlock compresses src as one LZ4 block with a greedy matcher.
+func lz4CompressBlock(src []byte) []byte {
+	dst := make([]byte, 0, len(src)+len(src)/255+16)
+	anchor := 0
+	if len(src) > lz4MFLimit {
+		// table holds the last position plus one of each hashed sequence.
+		var table [1 << lz4HashLog]int32
+		limit, maxEnd := len(src)-lz4MFLimit, len(src)-lz4LastLiterals
+		for i := 0; i < limit; {
+			seq := binary.LittleEndian.Uint32(src[i:])
+			h := (seq * 2654435761) >> (32 - lz4HashLog)
+			ref := int(table[h]) - 1
+			table[h] = int32(i + 1)
+			if ref < 0 || i-ref > lz4MaxOffset || binary.LittleEndian.Uint32(src[ref:]) != seq {
+				i++
+				continue
+			}
+			end := i + lz4MinMatch
+			for end < maxEnd && src[end] == src[ref+end-i] {
+				end++
+			}
+			dst = appendLZ4Sequence(dst, src[anchor:i], i-ref, end-i)
+			i, anchor = end, end
+		}
+	}
+	return appendLZ4Sequence(dst, src[anchor:], 0, 0)
+}
+
+// appendLZ4Sequence appends literals followed by a match, or by nothing for
+// the last sequence, where matchLength is zero.
+func appendLZ4Sequence(dst, literals []byte, offset, matchLength int) []byte {
+	token := byte(min(len(literals), 15)) << 4
+	if matchLength > 0 {
+		token |= byte(min(matchLength-lz4MinMatch, 15))
+	}
+	dst = append(dst, token)
+	if len(literals) >= 15 {
+		dst = appendLZ4Length(dst, len(literals)-15)
+	}
+	dst = append(dst, literals...)
+	if matchLength == 0 {
+		return dst
+	}
+	dst = binary.LittleEndian.AppendUint16(dst, uint16(offset))
+	if matchLength-lz4MinMatch >= 15 {
+		dst = appendLZ4Length(dst, matchLength-lz4MinMatch-15)
+	}
+	return dst
+}
+
+func appendLZ4Length(dst []byte, n int) []byte {
+	for ; n >= 255; n -= 255 {
+		dst = append(dst, 255)
+	}
+	return append(dst, byte(n))
+}
+
+// lz4DecompressBlock decodes an LZ4 block that expands to exactly size bytes.
+func lz4DecompressBlock(src []byte, size int) ([]byte, error) {
+	dst := make([]byte, 0, size)
+	i := 0
+	readLength := func(n int) (int, bool) {
+		if n != 15 {
+			return n, true
+		}
+		for i < len(src) {
+			b := src[i]
+			i++
+			n += int(b)
+			if n > size {
+				return 0, false
+			}
+			if b != 255 {
+				return n, true
+			}
+		}
+		return 0, false
+	}
+	for {
+		if i >= len(src) {
+			return nil, errLZ4Corrupt
+		}
+		token := src[i]
+		i++
+		literals, ok := readLength(int(token >> 4))
+		if !ok || i+literals > len(src) || len(dst)+literals > size {
+			return nil, errLZ4Corrupt
+		}
+		dst = append(dst, src[i:i+literals]...)
+		i += literals
+		if i == len(src) {
+			break
+		}
+		if i+2 > len(src) {
+			return nil, errLZ4Corrupt
+		}
+		offset := int(binary.LittleEndian.Uint16(src[i:]))
+		i += 2
+		length, ok := readLength(int(token & 0x0F))
+		if !ok || offset == 0 || offset > len(dst) || len(dst)+length+lz4MinMatch > size {
+			return nil, errLZ4Corrupt
+		}
+		// Matches may overlap their own output, so copy byte by byte.
+		start := len(dst) - offset
+		for k := 0; k < length+lz4MinMatch; k++ {
+			dst = append(dst, dst[start+k])
+		}
+	}
+	if len(dst) != size {
+		return nil, errLZ4Corrupt
+	}
+	return dst, nil
+}
+
+// XXH32 primes.
+const (
+	xxPrime1 uint32 = 2654435761
+	xxPrime2 uint32 = 2246822519
+	xxPrime3 uint32 = 3266489917
+	xxPrime4 uint32 = 668265263
+	xxPrime5 uint32 = 374761393
+)
+
+// xxh32 returns the XXH32 hash of b.
+func xxh32(b []byte, seed uint32) uint32 {
+	n := len(b)
+	var h uint32
+	if n >= 16 {
+		v1, v2, v3, v4 := seed+xxPrime1+xxPrime2, seed+xxPrime2, seed, seed-xxPrime1
+		for ; len(b) >= 16; b = b[16:] {
+			v1 = xxRound(v1, binary.LittleEndian.Uint32(b[0:]))
+			v2 = xxRound(v2, binary.LittleEndian.Uint32(b[4:]))
+			v3 = xxRound(v3, binary.LittleEndian.Uint32(b[8:]))
+			v4 = xxRound(v4, binary.LittleEndian.Uint32(b[12:]))
+		}
+		h = bits.RotateLeft32(v1, 1) + bits.RotateLeft32(v2, 7) + bits.RotateLeft32(v3, 12) + bits.RotateLeft32(v4, 18)
+	} else {
+		h = seed + xxPrime5
+	}
+	h += uint32(n)
+	for ; len(b) >= 4; b = b[4:] {
+		h += binary.LittleEndian.Uint32(b) * xxPrime3
+		h = bits.RotateLeft32(h, 17) * xxPrime4
+	}
+	for _, c := range b {
+		h += uint32(c) * xxPrime5
+		h = bits.RotateLeft32(h, 11) * xxPrime1
+	}
+	h ^= h >> 15
+	h *= xxPrime2
+	h ^= h >> 13
+	h *= xxPrime3
+	h ^= h >> 16
+	return h
+}
+
+func xxRound(acc, input uint32) uint32 {
+	return bits.RotateLeft32(acc+input*xxPrime2, 13) * xxPrime1
+}
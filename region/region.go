@@ -0,0 +1,445 @@
+// Package region reads and writes Anvil region files (.mca), which store the
+// chunks of a 32x32 chunk area. The file starts with an 8 KiB header: 1024
+// chunk locations in 4 KiB sectors, then 1024 modification timestamps.
+// Chunks too large for the 255 sectors a location can span are stored next
+// to the region in an external .mcc file.
+package region
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// Compression schemes of a chunk payload.
+const (
+	CompressionGzip byte = 1
+	CompressionZlib byte = 2
+	CompressionNone byte = 3
+	CompressionLZ4  byte = 4
+)
+
+// externalFlag marks a chunk whose payload is in a .mcc file.
+const externalFlag = 0x80
+
+const (
+	// SectorSize is the unit regions are allocated in.
+	SectorSize = 4096
+	// headerSectors are the location and timestamp tables.
+	headerSectors = 2
+	// maxSectors is the most sectors one chunk may span in the region.
+	maxSectors = 255
+	// chunkHeaderLength is the payload length and the compression scheme.
+	chunkHeaderLength = 5
+)
+
+// ErrNotFound is returned for chunks the region does not hold.
+var ErrNotFound = errors.New("region: chunk not found")
+
+// File is an open region file. Chunks are addressed by their absolute chunk
+// coordinates, of which the region uses the low five bits. It is safe for
+// concurrent use.
+type File struct {
+	// Compression is used for chunks written from now on. It defaults to
+	// zlib, as in vanilla.
+	Compression byte
+
+	mu         sync.RWMutex
+	f          *os.File
+	dir        string
+	locations  [1024]uint32
+	timestamps [1024]uint32
+	// used marks the sectors taken by the header and by chunks.
+	used []bool
+}
+
+// Open opens the region file at path, creating it if it does not exist.
+// Locations that point outside the file or at sectors another chunk holds
+// are dropped, as vanilla does.
+func Open(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	r := &File{Compression: CompressionZlib, f: f, dir: filepath.Dir(path)}
+	if err := r.load(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("region %s: %w", path, err)
+	}
+	return r, nil
+}
+
+func (r *File) load() error {
+	info, err := r.f.Stat()
+	if err != nil {
+		return err
+	}
+	header := make([]byte, headerSectors*SectorSize)
+	if info.Size() < int64(len(header)) {
+		// A new or truncated file: start with an empty header.
+		if _, err := r.f.WriteAt(header, 0); err != nil {
+			return err
+		}
+		info, err = r.f.Stat()
+		if err != nil {
+			return err
+		}
+	} else if _, err := r.f.ReadAt(header, 0); err != nil {
+		return err
+	}
+	sectors := int((info.Size() + SectorSize - 1) / SectorSize)
+	r.used = make([]bool, sectors)
+	r.used[0], r.used[1] = true, true
+	for i := range r.locations {
+		loc := binary.BigEndian.Uint32(header[i*4:])
+		r.timestamps[i] = binary.BigEndian.Uint32(header[SectorSize+i*4:])
+		if loc == 0 {
+			continue
+		}
+		offset, count := int(loc>>8), int(loc&0xFF)
+		if offset < headerSectors || count == 0 || offset+count > sectors || r.taken(offset, count) {
+			continue
+		}
+		r.locations[i] = loc
+		r.mark(offset, count, true)
+	}
+	return nil
+}
+
+func (r *File) taken(offset, count int) bool {
+	for s := offset; s < offset+count; s++ {
+		if r.used[s] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *File) mark(offset, count int, used bool) {
+	for s := offset; s < offset+count; s++ {
+		r.used[s] = used
+	}
+}
+
+// Close closes the file.
+func (r *File) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+func index(x, z int) int { return (x & 31) + (z&31)*32 }
+
+// HasChunk reports whether the region holds chunk x, z.
+func (r *File) HasChunk(x, z int) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.locations[index(x, z)] != 0
+}
+
+// Timestamp returns when chunk x, z was last written, or the zero time.
+func (r *File) Timestamp(x, z int) time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ts := r.timestamps[index(x, z)]
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(ts), 0)
+}
+
+// Chunks calls fn with the coordinates, relative to the region, of every
+// chunk it holds.
+func (r *File) Chunks(fn func(x, z int)) {
+	r.mu.RLock()
+	var present []int
+	for i, loc := range r.locations {
+		if loc != 0 {
+			present = append(present, i)
+		}
+	}
+	r.mu.RUnlock()
+	for _, i := range present {
+		fn(i%32, i/32)
+	}
+}
+
+// externalPath is the .mcc file of chunk x, z.
+func (r *File) externalPath(x, z int) string {
+	return filepath.Join(r.dir, fmt.Sprintf("c.%d.%d.mcc", x, z))
+}
+
+// ReadChunkData returns the uncompressed NBT of chunk x, z.
+func (r *File) ReadChunkData(x, z int) ([]byte, error) {
+	scheme, payload, err := r.readPayload(x, z)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(scheme, payload)
+}
+
+// readPayload returns the compression scheme and the compressed payload of
+// chunk x, z, from the region or its .mcc file.
+func (r *File) readPayload(x, z int) (byte, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	loc := r.locations[index(x, z)]
+	if loc == 0 {
+		return 0, nil, ErrNotFound
+	}
+	offset, count := int64(loc>>8), int(loc&0xFF)
+	buf := make([]byte, count*SectorSize)
+	n, err := r.f.ReadAt(buf, offset*SectorSize)
+	if err != nil && !(errors.Is(err, io.EOF) && n >= chunkHeaderLength) {
+		return 0, nil, err
+	}
+	buf = buf[:n]
+	if len(buf) < chunkHeaderLength {
+		return 0, nil, fmt.Errorf("region: chunk %d, %d is truncated", x, z)
+	}
+	length := int(binary.BigEndian.Uint32(buf))
+	scheme := buf[4]
+	if length < 1 || chunkHeaderLength-1+length > len(buf) {
+		return 0, nil, fmt.Errorf("region: chunk %d, %d has invalid length %d", x, z, length)
+	}
+	if scheme&externalFlag != 0 {
+		payload, err := os.ReadFile(r.externalPath(x, z))
+		return scheme &^ externalFlag, payload, err
+	}
+	return scheme, buf[chunkHeaderLength : chunkHeaderLength-1+length], nil
+}
+
+// ReadChunk reads chunk x, z as NBT.
+func (r *File) ReadChunk(x, z int) (*nbt.CompoundTag, error) {
+	data, err := r.ReadChunkData(x, z)
+	if err != nil {
+		return nil, err
+	}
+	named, err := nbt.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("region: chunk %d, %d: %w", x, z, err)
+	}
+	tag, ok := named.Tag.(*nbt.CompoundTag)
+	if !ok {
+		return nil, fmt.Errorf("region: chunk %d, %d is not a compound", x, z)
+	}
+	return tag, nil
+}
+
+// WriteChunk writes chunk x, z as NBT.
+func (r *File) WriteChunk(x, z int, tag *nbt.CompoundTag) error {
+	var buf bytes.Buffer
+	if err := nbt.Write(&buf, nbt.NamedTag{Tag: tag}); err != nil {
+		return err
+	}
+	return r.WriteChunkData(x, z, buf.Bytes())
+}
+
+// WriteChunkData writes the uncompressed NBT of chunk x, z. The new payload
+// goes to free sectors before the header points at it, so a crash leaves
+// either the old chunk or the new one; the old sectors are freed afterwards.
+func (r *File) WriteChunkData(x, z int, data []byte) error {
+	scheme := r.Compression
+	payload, err := compress(scheme, data)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	external := false
+	if sectorsFor(len(payload)) > maxSectors {
+		if err := writeFileAtomic(r.externalPath(x, z), payload); err != nil {
+			return err
+		}
+		external, payload = true, nil
+		scheme |= externalFlag
+	}
+
+	sector := make([]byte, sectorsFor(len(payload))*SectorSize)
+	binary.BigEndian.PutUint32(sector, uint32(len(payload)+1))
+	sector[4] = scheme
+	copy(sector[chunkHeaderLength:], payload)
+	count := len(sector) / SectorSize
+	offset := r.allocate(count)
+	if _, err := r.f.WriteAt(sector, int64(offset)*SectorSize); err != nil {
+		r.mark(offset, count, false)
+		return err
+	}
+
+	i := index(x, z)
+	old := r.locations[i]
+	if err := r.setHeader(i, uint32(offset)<<8|uint32(count), uint32(time.Now().Unix())); err != nil {
+		r.mark(offset, count, false)
+		return err
+	}
+	if old != 0 {
+		r.mark(int(old>>8), int(old&0xFF), false)
+	}
+	if !external {
+		// The chunk may have outgrown the region before.
+		if err := os.Remove(r.externalPath(x, z)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return r.trim()
+}
+
+// DeleteChunk removes chunk x, z and frees its sectors.
+func (r *File) DeleteChunk(x, z int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := index(x, z)
+	old := r.locations[i]
+	if old == 0 {
+		return nil
+	}
+	if err := r.setHeader(i, 0, 0); err != nil {
+		return err
+	}
+	r.mark(int(old>>8), int(old&0xFF), false)
+	if err := os.Remove(r.externalPath(x, z)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return r.trim()
+}
+
+// setHeader writes the location and timestamp of chunk i.
+func (r *File) setHeader(i int, loc, ts uint32) error {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], loc)
+	if _, err := r.f.WriteAt(b[:], int64(i*4)); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(b[:], ts)
+	if _, err := r.f.WriteAt(b[:], int64(SectorSize+i*4)); err != nil {
+		return err
+	}
+	r.locations[i], r.timestamps[i] = loc, ts
+	return nil
+}
+
+// allocate takes the first run of count free sectors, growing the file if
+// there is none.
+func (r *File) allocate(count int) int {
+	run := 0
+	for s := headerSectors; s < len(r.used); s++ {
+		if r.used[s] {
+			run = 0
+			continue
+		}
+		if run++; run == count {
+			offset := s - count + 1
+			r.mark(offset, count, true)
+			return offset
+		}
+	}
+	// Extend the free run at the end of the file, if any.
+	offset := len(r.used) - run
+	for len(r.used) < offset+count {
+		r.used = append(r.used, false)
+	}
+	r.mark(offset, count, true)
+	return offset
+}
+
+// trim truncates the free sectors at the end of the file.
+func (r *File) trim() error {
+	end := len(r.used)
+	for end > headerSectors && !r.used[end-1] {
+		end--
+	}
+	if end == len(r.used) {
+		return nil
+	}
+	if err := r.f.Truncate(int64(end) * SectorSize); err != nil {
+		return err
+	}
+	r.used = r.used[:end]
+	return nil
+}
+
+// Sectors returns the size of the file in sectors and how many of them are
+// in use, header included.
+func (r *File) Sectors() (total, used int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, u := range r.used {
+		if u {
+			used++
+		}
+	}
+	return len(r.used), used
+}
+
+// sectorsFor returns the sectors a payload of n bytes needs with its header.
+func sectorsFor(n int) int {
+	return (n + chunkHeaderLength + SectorSize - 1) / SectorSize
+}
+
+func compress(scheme byte, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	switch scheme {
+	case CompressionGzip:
+		w := gzip.NewWriter(&buf)
+		w.Write(data)
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	case CompressionZlib:
+		w := zlib.NewWriter(&buf)
+		w.Write(data)
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	case CompressionNone:
+		return data, nil
+	case CompressionLZ4:
+		return lz4Compress(data), nil
+	default:
+		return nil, fmt.Errorf("region: unknown compression %d", scheme)
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(scheme byte, payload []byte) ([]byte, error) {
+	switch scheme {
+	case CompressionGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(zr)
+	case CompressionZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(zr)
+	case CompressionNone:
+		return payload, nil
+	case CompressionLZ4:
+		return lz4Decompress(bytes.NewReader(payload))
+	}
+	return nil, fmt.Errorf("region: unknown compression %d", scheme)
+}
+
+// writeFileAtomic replaces the file at path with data through a temporary
+// file, so readers never see half of it.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
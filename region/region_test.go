@@ -0,0 +1,227 @@
+package region
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/Advik-B/Golem/nbt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testChunk(x, z int, filler int) *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	tag.Put("xPos", &nbt.IntTag{Value: int32(x)})
+	tag.Put("zPos", &nbt.IntTag{Value: int32(z)})
+	tag.Put("Status", &nbt.StringTag{Value: "minecraft:full"})
+	data := make([]byte, filler)
+	rand.New(rand.NewSource(int64(x*31 + z))).Read(data)
+	tag.Put("Filler", &nbt.ByteArrayTag{Value: toInt8(data)})
+	return tag
+}
+
+func toInt8(b []byte) []int8 {
+	out := make([]int8, len(b))
+	for i, v := range b {
+		out[i] = int8(v)
+	}
+	return out
+}
+
+func TestXXH32(t *testing.T) {
+	assert.Equal(t, uint32(0x02CC5D05), xxh32(nil, 0))
+	assert.Equal(t, uint32(0x32D153FF), xxh32([]byte("abc"), 0))
+	assert.Equal(t, uint32(0xE2293B2F), xxh32([]byte("Nobody inspects the spammish repetition"), 0))
+}
+
+func TestLZ4(t *testing.T) {
+	random := make([]byte, 200_000)
+	rand.New(rand.NewSource(1)).Read(random)
+	for name, data := range map[string][]byte{
+		"empty":      nil,
+		"short":      []byte("hello"),
+		"repetitive": bytes.Repeat([]byte("minecraft:stone "), 20_000),
+		"random":     random,
+		"runs":       append(bytes.Repeat([]byte{0}, 70_000), bytes.Repeat([]byte{1, 2, 3}, 1000)...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			compressed := lz4Compress(data)
+			got, err := lz4Decompress(bytes.NewReader(compressed))
+			require.NoError(t, err)
+			assert.Equal(t, len(data), len(got))
+			assert.True(t, bytes.Equal(data, got))
+		})
+	}
+	// Corrupting the payload is caught by the block decoder or the checksum.
+	compressed := lz4Compress(bytes.Repeat([]byte("abcd"), 1000))
+	compressed[lz4HeaderLength+3] ^= 0xFF
+	_, err := lz4Decompress(bytes.NewReader(compressed))
+	assert.Error(t, err)
+}
+
+func TestReadWrite(t *testing.T) {
+	for name, scheme := range map[string]byte{
+		"gzip": CompressionGzip,
+		"zlib": CompressionZlib,
+		"none": CompressionNone,
+		"lz4":  CompressionLZ4,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "r.0.0.mca")
+			r, err := Open(path)
+			require.NoError(t, err)
+			r.Compression = scheme
+			assert.False(t, r.HasChunk(3, 4))
+			_, err = r.ReadChunk(3, 4)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			chunk := testChunk(3, 4, 10_000)
+			require.NoError(t, r.WriteChunk(3, 4, chunk))
+			require.NoError(t, r.WriteChunk(-1, -1, testChunk(-1, -1, 100)))
+			assert.True(t, r.HasChunk(3, 4))
+			assert.False(t, r.Timestamp(3, 4).IsZero())
+			require.NoError(t, r.Close())
+
+			// The chunks survive reopening, and absolute coordinates map to
+			// the same slots.
+			r, err = Open(path)
+			require.NoError(t, err)
+			defer r.Close()
+			got, err := r.ReadChunk(3+32, 4-64)
+			require.NoError(t, err)
+			assert.True(t, nbt.CompareTags(chunk, got, false))
+			var present [][2]int
+			r.Chunks(func(x, z int) { present = append(present, [2]int{x, z}) })
+			assert.ElementsMatch(t, [][2]int{{3, 4}, {31, 31}}, present)
+		})
+	}
+}
+
+func TestExternalChunks(t *testing.T) {
+	dir := t.TempDir()
+	r, err := Open(filepath.Join(dir, "r.0.0.mca"))
+	require.NoError(t, err)
+	defer r.Close()
+	r.Compression = CompressionNone
+
+	big := testChunk(1, 2, 2<<20)
+	require.NoError(t, r.WriteChunk(1, 2, big))
+	mcc := filepath.Join(dir, "c.1.2.mcc")
+	assert.FileExists(t, mcc)
+	total, used := r.Sectors()
+	assert.Equal(t, 3, total, "only a stub in the region")
+	assert.Equal(t, 3, used)
+	got, err := r.ReadChunk(1, 2)
+	require.NoError(t, err)
+	assert.True(t, nbt.CompareTags(big, got, false))
+
+	// Shrinking the chunk brings it back into the region.
+	require.NoError(t, r.WriteChunk(1, 2, testChunk(1, 2, 100)))
+	assert.NoFileExists(t, mcc)
+	got, err = r.ReadChunk(1, 2)
+	require.NoError(t, err)
+	assert.True(t, nbt.CompareTags(testChunk(1, 2, 100), got, false))
+}
+
+func TestReclaimSectors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "r.0.0.mca")
+	r, err := Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+	r.Compression = CompressionNone
+
+	require.NoError(t, r.WriteChunk(0, 0, testChunk(0, 0, 3*SectorSize)))
+	require.NoError(t, r.WriteChunk(1, 0, testChunk(1, 0, 100)))
+	total, _ := r.Sectors()
+	assert.Equal(t, 2+4+1, total)
+
+	// The rewrite goes after the other chunk, then its old sectors are free
+	// for the next chunk.
+	require.NoError(t, r.WriteChunk(0, 0, testChunk(0, 0, 100)))
+	total, used := r.Sectors()
+	assert.Equal(t, 2+4+1+1, total)
+	assert.Equal(t, 2+1+1, used)
+	require.NoError(t, r.WriteChunk(2, 0, testChunk(2, 0, 2*SectorSize)))
+	total, used = r.Sectors()
+	assert.Equal(t, 2+4+1+1, total, "reused the freed sectors")
+	assert.Equal(t, 2+1+1+3, used)
+
+	// Freed sectors at the end are cut off.
+	require.NoError(t, r.DeleteChunk(0, 0))
+	total, _ = r.Sectors()
+	assert.Equal(t, 2+4+1, total)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(total*SectorSize), info.Size())
+	assert.False(t, r.HasChunk(0, 0))
+}
+
+func TestCorruptHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "r.0.0.mca")
+	r, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, r.WriteChunk(0, 0, testChunk(0, 0, 10)))
+	require.NoError(t, r.Close())
+
+	// Point chunk 1 past the end of the file and chunk 2 at chunk 0's sector.
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte{0, 0, 100, 1, 0, 0, 2, 1}, 4)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	r, err = Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+	assert.True(t, r.HasChunk(0, 0))
+	assert.False(t, r.HasChunk(1, 0))
+	assert.False(t, r.HasChunk(2, 0))
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	r, err := Open(filepath.Join(t.TempDir(), "r.0.0.mca"))
+	require.NoError(t, err)
+	defer r.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				x, z := g, i
+				chunk := testChunk(x, z, 1000*(i%5+1))
+				if !assert.NoError(t, r.WriteChunk(x, z, chunk)) {
+					return
+				}
+				got, err := r.ReadChunk(x, z)
+				if assert.NoError(t, err) {
+					assert.True(t, nbt.CompareTags(chunk, got, false))
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestStorage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "region")
+	s := NewStorage(dir)
+	_, err := s.ReadChunk(100, -100)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoDirExists(t, dir, "reads create nothing")
+
+	require.NoError(t, s.WriteChunk(100, -100, testChunk(100, -100, 10)))
+	assert.FileExists(t, filepath.Join(dir, "r.3.-4.mca"))
+	got, err := s.ReadChunk(100, -100)
+	require.NoError(t, err)
+	assert.True(t, nbt.CompareTags(testChunk(100, -100, 10), got, false))
+	require.NoError(t, s.DeleteChunk(100, -100))
+	_, err = s.ReadChunk(100, -100)
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, s.Close())
+}
@@ -0,0 +1,102 @@
+package region
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// Storage is a directory of region files, such as a dimension's region
+// folder, addressed by absolute chunk coordinates. Region files are opened
+// on first use and stay open until Close. It is safe for concurrent use.
+type Storage struct {
+	// Compression is used for the region files opened from now on.
+	Compression byte
+
+	dir   string
+	mu    sync.Mutex
+	files map[[2]int]*File
+}
+
+// NewStorage returns the storage in dir, which is created on the first
+// write.
+func NewStorage(dir string) *Storage {
+	return &Storage{Compression: CompressionZlib, dir: dir, files: make(map[[2]int]*File)}
+}
+
+// Path returns the path of the region file holding chunk x, z.
+func (s *Storage) Path(x, z int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("r.%d.%d.mca", x>>5, z>>5))
+}
+
+// region returns the region file of chunk x, z. Unless create is set, a
+// missing file is reported as ErrNotFound rather than created.
+func (s *Storage) region(x, z int, create bool) (*File, error) {
+	key := [2]int{x >> 5, z >> 5}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f := s.files[key]; f != nil {
+		return f, nil
+	}
+	path := s.Path(x, z)
+	if !create {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+	} else if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	f.Compression = s.Compression
+	s.files[key] = f
+	return f, nil
+}
+
+// ReadChunk reads chunk x, z, or returns ErrNotFound.
+func (s *Storage) ReadChunk(x, z int) (*nbt.CompoundTag, error) {
+	f, err := s.region(x, z, false)
+	if err != nil {
+		return nil, err
+	}
+	return f.ReadChunk(x, z)
+}
+
+// WriteChunk writes chunk x, z.
+func (s *Storage) WriteChunk(x, z int, tag *nbt.CompoundTag) error {
+	f, err := s.region(x, z, true)
+	if err != nil {
+		return err
+	}
+	return f.WriteChunk(x, z, tag)
+}
+
+// DeleteChunk removes chunk x, z.
+func (s *Storage) DeleteChunk(x, z int) error {
+	f, err := s.region(x, z, false)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return f.DeleteChunk(x, z)
+}
+
+// Close closes every open region file.
+func (s *Storage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for key, f := range s.files {
+		errs = append(errs, f.Close())
+		delete(s.files, key)
+	}
+	return errors.Join(errs...)
+}
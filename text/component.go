@@ -0,0 +1,168 @@
+// Package text implements Minecraft text components, the rich text shown in
+// chat, disconnect screens, titles and the server list. Components encode to
+// JSON, which the status response uses, and to NBT, which play packets use
+// since 1.20.3. Legacy § codes and a MiniMessage-like markup convert to and
+// from components for configuration strings.
+package text
+
+import (
+	"strings"
+)
+
+// Component is a piece of rich text: its content, the style it is shown in
+// and the components that follow it, which inherit that style.
+type Component struct {
+	// Text is the literal content of a plain text component, which is what a
+	// component is unless one of the other contents below is set.
+	Text string
+	// Translate is a translation key, shown in the client's language.
+	Translate string
+	// Fallback is shown when the client has no translation for Translate.
+	Fallback string
+	// With fills the placeholders of Translate.
+	With []Component
+	// Score shows a scoreboard value.
+	Score *Score
+	// Selector shows the names of the entities a selector such as "@p"
+	// matches.
+	Selector string
+	// Separator joins the values of Selector and NBT. Nil means ", ".
+	Separator *Component
+	// Keybind shows the key bound to a control, such as "key.jump".
+	Keybind string
+	// NBT shows values read from a block, entity or storage.
+	NBT *NBTSource
+
+	Style
+	Extra []Component
+}
+
+// Score is the content of a score component.
+type Score struct {
+	// Name is the score holder: a player name or a selector.
+	Name      string `json:"name"`
+	Objective string `json:"objective"`
+}
+
+// NBTSource is the content of an NBT component: a path resolved against one
+// of a block, an entity or a storage.
+type NBTSource struct {
+	Path string
+	// Interpret parses the values found as components.
+	Interpret bool
+	// Block is the coordinates of a block entity, such as "~ ~-1 ~".
+	Block string
+	// Entity is a selector for the entities to read.
+	Entity string
+	// Storage is the ID of a command storage.
+	Storage string
+}
+
+// Content kinds, as named by the optional "type" field.
+const (
+	TypeText         = "text"
+	TypeTranslatable = "translatable"
+	TypeScore        = "score"
+	TypeSelector     = "selector"
+	TypeKeybind      = "keybind"
+	TypeNBT          = "nbt"
+)
+
+// Type returns the kind of content the component has.
+func (c *Component) Type() string {
+	switch {
+	case c.Translate != "":
+		return TypeTranslatable
+	case c.Score != nil:
+		return TypeScore
+	case c.Selector != "":
+		return TypeSelector
+	case c.Keybind != "":
+		return TypeKeybind
+	case c.NBT != nil:
+		return TypeNBT
+	}
+	return TypeText
+}
+
+// Plain returns a plain text component.
+func Plain(s string) Component { return Component{Text: s} }
+
+// Translatable returns a component for a translation key with arguments.
+func Translatable(key string, with ...Component) Component {
+	return Component{Translate: key, With: with}
+}
+
+// Keybind returns a component showing the key bound to a control.
+func Keybind(key string) Component { return Component{Keybind: key} }
+
+// Join returns a component made of parts, which keep their own styles.
+func Join(parts ...Component) Component { return Component{Extra: parts} }
+
+// Append returns c followed by children.
+func (c Component) Append(children ...Component) Component {
+	c.Extra = append(append([]Component(nil), c.Extra...), children...)
+	return c
+}
+
+// Styled returns c with style s.
+func (c Component) Styled(s Style) Component {
+	c.Style = s
+	return c
+}
+
+// Colored returns c in color.
+func (c Component) Colored(color Color) Component {
+	c.Color = color
+	return c
+}
+
+// IsPlain reports whether c is unstyled plain text without children, which
+// encodes as a bare string.
+func (c *Component) IsPlain() bool {
+	return c.Type() == TypeText && c.Style.IsZero() && len(c.Extra) == 0
+}
+
+// PlainText returns the text of c and its children without styling. Contents
+// only the client can resolve are shown as their translation fallback or key,
+// selector or path.
+func (c *Component) PlainText() string {
+	var b strings.Builder
+	c.walk(Style{}, func(s string, _ Style) { b.WriteString(s) })
+	return b.String()
+}
+
+// content returns the text shown for the component's own content.
+func (c *Component) content() string {
+	switch c.Type() {
+	case TypeTranslatable:
+		if c.Fallback != "" {
+			return c.Fallback
+		}
+		return c.Translate
+	case TypeScore:
+		return ""
+	case TypeSelector:
+		return c.Selector
+	case TypeKeybind:
+		return c.Keybind
+	case TypeNBT:
+		return c.NBT.Path
+	}
+	return c.Text
+}
+
+// walk calls fn for the content of c and its children in order, with the
+// style each is shown in.
+func (c *Component) walk(parent Style, fn func(s string, style Style)) {
+	style := c.Style.Inherit(parent)
+	if s := c.content(); s != "" {
+		fn(s, style)
+	}
+	for i := range c.Extra {
+		c.Extra[i].walk(style, fn)
+	}
+}
+
+// String returns the plain text of c.
+func (c Component) String() string { return c.PlainText() }
@@ -0,0 +1,218 @@
+package text
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonComponent is the JSON form of a component.
+type jsonComponent struct {
+	Type      string      `json:"type,omitempty"`
+	Text      *string     `json:"text,omitempty"`
+	Translate string      `json:"translate,omitempty"`
+	Fallback  string      `json:"fallback,omitempty"`
+	With      []Component `json:"with,omitempty"`
+	Score     *Score      `json:"score,omitempty"`
+	Selector  string      `json:"selector,omitempty"`
+	Separator *Component  `json:"separator,omitempty"`
+	Keybind   string      `json:"keybind,omitempty"`
+	NBT       *string     `json:"nbt,omitempty"`
+	Interpret *bool       `json:"interpret,omitempty"`
+	Block     string      `json:"block,omitempty"`
+	Entity    string      `json:"entity,omitempty"`
+	Storage   string      `json:"storage,omitempty"`
+
+	Color         Color       `json:"color,omitempty"`
+	Bold          *bool       `json:"bold,omitempty"`
+	Italic        *bool       `json:"italic,omitempty"`
+	Underlined    *bool       `json:"underlined,omitempty"`
+	Strikethrough *bool       `json:"strikethrough,omitempty"`
+	Obfuscated    *bool       `json:"obfuscated,omitempty"`
+	Font          string      `json:"font,omitempty"`
+	Insertion     string      `json:"insertion,omitempty"`
+	ClickEvent    *ClickEvent `json:"clickEvent,omitempty"`
+	HoverEvent    *HoverEvent `json:"hoverEvent,omitempty"`
+
+	Extra []Component `json:"extra,omitempty"`
+}
+
+// MarshalJSON encodes c as a JSON object.
+func (c Component) MarshalJSON() ([]byte, error) {
+	j := jsonComponent{
+		Translate: c.Translate,
+		Fallback:  c.Fallback,
+		With:      c.With,
+		Score:     c.Score,
+		Selector:  c.Selector,
+		Separator: c.Separator,
+		Keybind:   c.Keybind,
+
+		Color:         c.Color,
+		Bold:          c.Bold,
+		Italic:        c.Italic,
+		Underlined:    c.Underlined,
+		Strikethrough: c.Strikethrough,
+		Obfuscated:    c.Obfuscated,
+		Font:          c.Font,
+		Insertion:     c.Insertion,
+		ClickEvent:    c.ClickEvent,
+		HoverEvent:    c.HoverEvent,
+
+		Extra: c.Extra,
+	}
+	switch c.Type() {
+	case TypeText:
+		j.Text = &c.Text
+	case TypeNBT:
+		j.NBT = &c.NBT.Path
+		if c.NBT.Interpret {
+			j.Interpret = Bool(true)
+		}
+		j.Block, j.Entity, j.Storage = c.NBT.Block, c.NBT.Entity, c.NBT.Storage
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes any of the JSON forms of a component: an object, a
+// string or other primitive for plain text, or an array whose first element
+// is followed by the rest.
+func (c *Component) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return fmt.Errorf("text: empty component")
+	}
+	switch data[0] {
+	case '"':
+		*c = Component{}
+		return json.Unmarshal(data, &c.Text)
+	case '[':
+		var list []Component
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("text: empty component list")
+		}
+		*c = list[0].Append(list[1:]...)
+		return nil
+	case '{':
+		var j jsonComponent
+		if err := json.Unmarshal(data, &j); err != nil {
+			return err
+		}
+		return c.fromJSON(&j)
+	case 'n':
+		return fmt.Errorf("text: null component")
+	}
+	// Numbers and booleans are shown as they are written.
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = Plain(string(data))
+	return nil
+}
+
+func (c *Component) fromJSON(j *jsonComponent) error {
+	*c = Component{
+		Translate: j.Translate,
+		Fallback:  j.Fallback,
+		With:      j.With,
+		Score:     j.Score,
+		Selector:  j.Selector,
+		Separator: j.Separator,
+		Keybind:   j.Keybind,
+		Style: Style{
+			Color:         j.Color,
+			Bold:          j.Bold,
+			Italic:        j.Italic,
+			Underlined:    j.Underlined,
+			Strikethrough: j.Strikethrough,
+			Obfuscated:    j.Obfuscated,
+			Font:          j.Font,
+			Insertion:     j.Insertion,
+			ClickEvent:    j.ClickEvent,
+			HoverEvent:    j.HoverEvent,
+		},
+		Extra: j.Extra,
+	}
+	if j.Text != nil {
+		c.Text = *j.Text
+	}
+	if j.NBT != nil {
+		c.NBT = &NBTSource{Path: *j.NBT, Interpret: isSet(j.Interpret), Block: j.Block, Entity: j.Entity, Storage: j.Storage}
+	}
+	if j.Color != "" {
+		color, ok := ParseColor(string(j.Color))
+		if !ok {
+			return fmt.Errorf("text: invalid color %q", j.Color)
+		}
+		c.Color = color
+	}
+	if j.Text == nil && c.Type() == TypeText {
+		return fmt.Errorf("text: component has no content")
+	}
+	return nil
+}
+
+// jsonHoverEvent is the JSON form of a hover event. Older writers put the
+// tooltip in value rather than contents.
+type jsonHoverEvent struct {
+	Action   HoverAction     `json:"action"`
+	Contents json.RawMessage `json:"contents,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+}
+
+// MarshalJSON encodes the hover event with its contents.
+func (h HoverEvent) MarshalJSON() ([]byte, error) {
+	var contents interface{}
+	switch h.Action {
+	case ShowText:
+		contents = h.Text
+	case ShowItem:
+		contents = h.Item
+	case ShowEntity:
+		contents = h.Entity
+	default:
+		return nil, fmt.Errorf("text: unknown hover action %q", h.Action)
+	}
+	raw, err := json.Marshal(contents)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonHoverEvent{Action: h.Action, Contents: raw})
+}
+
+// UnmarshalJSON decodes a hover event.
+func (h *HoverEvent) UnmarshalJSON(data []byte) error {
+	var j jsonHoverEvent
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	contents := j.Contents
+	if contents == nil {
+		contents = j.Value
+	}
+	if contents == nil {
+		return fmt.Errorf("text: hover event has no contents")
+	}
+	*h = HoverEvent{Action: j.Action}
+	switch j.Action {
+	case ShowText:
+		h.Text = new(Component)
+		return json.Unmarshal(contents, h.Text)
+	case ShowItem:
+		h.Item = new(HoverItem)
+		// An item may be given by its ID alone.
+		if strings.HasPrefix(string(bytes.TrimSpace(contents)), `"`) {
+			return json.Unmarshal(contents, &h.Item.ID)
+		}
+		return json.Unmarshal(contents, h.Item)
+	case ShowEntity:
+		h.Entity = new(HoverEntity)
+		return json.Unmarshal(contents, h.Entity)
+	}
+	return fmt.Errorf("text: unknown hover action %q", j.Action)
+}
@@ -0,0 +1,155 @@
+package text
+
+import (
+	"strings"
+)
+
+// SectionSign starts a legacy formatting code, such as "§c" for red.
+const SectionSign = '§'
+
+// legacyFormats maps the legacy formatting codes to the style flag they set.
+var legacyFormats = map[rune]func(s *Style) **bool{
+	'k': func(s *Style) **bool { return &s.Obfuscated },
+	'l': func(s *Style) **bool { return &s.Bold },
+	'm': func(s *Style) **bool { return &s.Strikethrough },
+	'n': func(s *Style) **bool { return &s.Underlined },
+	'o': func(s *Style) **bool { return &s.Italic },
+}
+
+const legacyColorCodes = "0123456789abcdef"
+
+// FromLegacy converts text with legacy formatting codes introduced by code,
+// usually SectionSign or '&', to a component. As in the old clients, a color
+// code resets the formatting and "r" resets everything. BungeeCord's hex
+// colors, "§x§R§R§G§G§B§B", are understood too. Unknown codes are dropped.
+func FromLegacy(s string, code rune) Component {
+	var parts []Component
+	var style Style
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			parts = append(parts, Component{Text: b.String(), Style: style})
+			b.Reset()
+		}
+	}
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != code || i+1 >= len(runes) {
+			b.WriteRune(runes[i])
+			continue
+		}
+		c := toLowerASCII(runes[i+1])
+		i++
+		switch {
+		case strings.ContainsRune(legacyColorCodes, c):
+			flush()
+			style = Style{Color: namedColors[strings.IndexRune(legacyColorCodes, c)].name}
+		case c == 'x':
+			if hex, ok := legacyHex(runes[i+1:], code); ok {
+				flush()
+				style = Style{Color: Color("#" + strings.ToUpper(hex))}
+				i += 12
+			}
+		case c == 'r':
+			flush()
+			style = Style{}
+		default:
+			if flag, ok := legacyFormats[c]; ok {
+				flush()
+				*flag(&style) = Bool(true)
+			}
+		}
+	}
+	flush()
+	switch len(parts) {
+	case 0:
+		return Plain("")
+	case 1:
+		return parts[0]
+	}
+	return Join(parts...)
+}
+
+// legacyHex reads the six "§R" pairs after a BungeeCord "§x".
+func legacyHex(runes []rune, code rune) (string, bool) {
+	if len(runes) < 12 {
+		return "", false
+	}
+	var hex strings.Builder
+	for i := 0; i < 12; i += 2 {
+		if runes[i] != code || !strings.ContainsRune("0123456789abcdefABCDEF", runes[i+1]) {
+			return "", false
+		}
+		hex.WriteRune(runes[i+1])
+	}
+	return hex.String(), true
+}
+
+func toLowerASCII(r rune) rune {
+	if r >= 'A' && r <= 'Z' {
+		return r + 'a' - 'A'
+	}
+	return r
+}
+
+// Legacy converts c to text with legacy formatting codes introduced by code,
+// for places such as the legacy server list ping that cannot show
+// components. Hex colors become the nearest named color; events and fonts
+// are lost.
+func (c *Component) Legacy(code rune) string {
+	var b strings.Builder
+	var last Style
+	first := true
+	c.walk(Style{}, func(s string, style Style) {
+		color := style.Color.Named()
+		lastColor := last.Color.Named()
+		formats := legacyFlags(style)
+		lastFormats := legacyFlags(last)
+		// Codes only add formatting, so anything else starts over from a
+		// color code or a reset.
+		if first || color != lastColor || !strings.HasPrefix(formats, lastFormats) {
+			if color != "" {
+				b.WriteRune(code)
+				b.WriteByte(legacyColorCodes[colorIndex(color)])
+			} else if !first {
+				b.WriteRune(code)
+				b.WriteByte('r')
+			}
+			lastFormats = ""
+		}
+		for _, f := range formats[len(lastFormats):] {
+			b.WriteRune(code)
+			b.WriteRune(f)
+		}
+		b.WriteString(s)
+		last, first = style, false
+	})
+	return b.String()
+}
+
+// legacyFlags returns the codes of the formatting flags style sets, in a
+// fixed order.
+func legacyFlags(style Style) string {
+	var codes []byte
+	for _, f := range "klmno" {
+		if isSet(*legacyFormats[f](&style)) {
+			codes = append(codes, byte(f))
+		}
+	}
+	return string(codes)
+}
+
+func colorIndex(c Color) int {
+	for i, n := range namedColors {
+		if n.name == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// StripLegacy removes the legacy formatting codes introduced by code from s.
+func StripLegacy(s string, code rune) string {
+	c := FromLegacy(s, code)
+	return c.PlainText()
+}
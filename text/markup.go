@@ -0,0 +1,298 @@
+package text
+
+import (
+	"strings"
+)
+
+// ParseMarkup converts MiniMessage-like markup, meant for configuration
+// strings, to a component. Style tags apply until they are closed:
+//
+//	<red>, <#FFAA00>, <color:gold>            colors
+//	<bold>, <italic>, <underlined>,           formatting, also as <b>, <i>,
+//	<strikethrough>, <obfuscated>             <u>, <st> and <obf>; <!bold> turns
+//	                                          a flag off
+//	<click:run_command:/help>                 click events, with any ClickAction
+//	<hover:show_text:'<red>tip'>              tooltips, which are markup too
+//	<insert:text>, <font:minecraft:uniform>   insertion and font
+//
+// and <reset> closes them all. Other tags insert content:
+//
+//	<key:key.jump>                            the key bound to a control
+//	<lang:block.minecraft.stone:arg...>       a translation, with markup args
+//	<selector:@p>, <score:name:objective>     entity names and scores
+//	<newline>, <br>                           a line break
+//
+// A closing tag such as </red> closes the innermost open tag of that name and
+// everything opened after it; </color> closes the innermost color. Arguments
+// containing ':' or '>' can be quoted with ' or ". Unknown tags, and '<'
+// escaped as "\<", are kept as text.
+func ParseMarkup(s string) Component {
+	p := &markupParser{in: []rune(s)}
+	p.parse()
+	p.flush()
+	switch len(p.parts) {
+	case 0:
+		return Plain("")
+	case 1:
+		return p.parts[0]
+	}
+	return Join(p.parts...)
+}
+
+// Escape escapes s so that ParseMarkup shows it as it is, for putting player
+// input into markup.
+func Escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `<`, `\<`).Replace(s)
+}
+
+// openTag is a style tag waiting to be closed.
+type openTag struct {
+	name string
+	// color is set for the color tags, which </color> also closes.
+	color bool
+	style Style
+}
+
+type markupParser struct {
+	in    []rune
+	pos   int
+	stack []openTag
+	text  strings.Builder
+	parts []Component
+}
+
+// style returns the style of the open tags combined.
+func (p *markupParser) style() Style {
+	var s Style
+	for _, t := range p.stack {
+		s = t.style.Inherit(s)
+	}
+	return s
+}
+
+// flush ends the current run of text.
+func (p *markupParser) flush() {
+	if p.text.Len() > 0 {
+		p.parts = append(p.parts, Component{Text: p.text.String(), Style: p.style()})
+		p.text.Reset()
+	}
+}
+
+// insert adds a component in the current style.
+func (p *markupParser) insert(c Component) {
+	p.flush()
+	c.Style = c.Style.Inherit(p.style())
+	p.parts = append(p.parts, c)
+}
+
+func (p *markupParser) parse() {
+	for p.pos < len(p.in) {
+		r := p.in[p.pos]
+		switch {
+		case r == '\\' && p.pos+1 < len(p.in) && (p.in[p.pos+1] == '<' || p.in[p.pos+1] == '\\'):
+			p.text.WriteRune(p.in[p.pos+1])
+			p.pos += 2
+		case r == '<':
+			end := p.tagEnd(p.pos + 1)
+			if end < 0 || !p.tag(string(p.in[p.pos+1:end])) {
+				p.text.WriteRune(r)
+				p.pos++
+				continue
+			}
+			p.pos = end + 1
+		default:
+			p.text.WriteRune(r)
+			p.pos++
+		}
+	}
+}
+
+// tagEnd returns the index of the '>' closing a tag that starts at i, or -1.
+func (p *markupParser) tagEnd(i int) int {
+	var quote rune
+	for ; i < len(p.in); i++ {
+		r := p.in[i]
+		switch {
+		case quote != 0:
+			if r == '\\' {
+				i++
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '<':
+			return -1
+		case r == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// splitArgs splits the inside of a tag on ':' outside quotes, unquoting the
+// quoted parts.
+func splitArgs(s string) []string {
+	var args []string
+	var b strings.Builder
+	var quote rune
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0 && r == '\\' && i+1 < len(runes):
+			i++
+			b.WriteRune(runes[i])
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			b.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ':':
+			args = append(args, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return append(args, b.String())
+}
+
+// tagAliases maps short tag names to the names they stand for.
+var tagAliases = map[string]string{
+	"b": "bold", "i": "italic", "em": "italic", "u": "underlined",
+	"st": "strikethrough", "obf": "obfuscated", "colour": "color", "c": "color",
+	"br": "newline", "tr": "lang", "translate": "lang", "insertion": "insert",
+	"sel": "selector",
+}
+
+// tag applies the tag with the given inside and reports whether it was one
+// the parser knows.
+func (p *markupParser) tag(inside string) bool {
+	if strings.HasPrefix(inside, "/") {
+		return p.close(strings.ToLower(splitArgs(inside[1:])[0]))
+	}
+	negate := strings.HasPrefix(inside, "!")
+	args := splitArgs(strings.TrimPrefix(inside, "!"))
+	name := strings.ToLower(args[0])
+	if alias, ok := tagAliases[name]; ok {
+		name = alias
+	}
+	args = args[1:]
+
+	if color, ok := ParseColor(name); ok && !negate && len(args) == 0 {
+		p.openColor(name, color)
+		return true
+	}
+	if flag, ok := map[string]func(s *Style) **bool{
+		"bold":          legacyFormats['l'],
+		"italic":        legacyFormats['o'],
+		"underlined":    legacyFormats['n'],
+		"strikethrough": legacyFormats['m'],
+		"obfuscated":    legacyFormats['k'],
+	}[name]; ok && len(args) == 0 {
+		var s Style
+		*flag(&s) = Bool(!negate)
+		p.open(name, s)
+		return true
+	}
+	if negate {
+		return false
+	}
+
+	switch name {
+	case "reset":
+		p.flush()
+		p.stack = nil
+	case "newline":
+		p.text.WriteByte('\n')
+	case "color":
+		if len(args) != 1 {
+			return false
+		}
+		color, ok := ParseColor(args[0])
+		if !ok {
+			return false
+		}
+		p.openColor(name, color)
+	case "click":
+		if len(args) < 2 {
+			return false
+		}
+		action := ClickAction(strings.ToLower(args[0]))
+		switch action {
+		case OpenURL, RunCommand, SuggestCommand, ChangePage, CopyToClipboard:
+		default:
+			return false
+		}
+		p.open(name, Style{ClickEvent: &ClickEvent{Action: action, Value: strings.Join(args[1:], ":")}})
+	case "hover":
+		if len(args) < 2 || strings.ToLower(args[0]) != string(ShowText) {
+			return false
+		}
+		p.open(name, Style{HoverEvent: ShowTextEvent(ParseMarkup(strings.Join(args[1:], ":")))})
+	case "insert":
+		if len(args) == 0 {
+			return false
+		}
+		p.open(name, Style{Insertion: strings.Join(args, ":")})
+	case "font":
+		if len(args) == 0 {
+			return false
+		}
+		p.open(name, Style{Font: strings.Join(args, ":")})
+	case "key":
+		if len(args) != 1 {
+			return false
+		}
+		p.insert(Keybind(args[0]))
+	case "lang":
+		if len(args) == 0 {
+			return false
+		}
+		with := make([]Component, len(args)-1)
+		for i, arg := range args[1:] {
+			with[i] = ParseMarkup(arg)
+		}
+		p.insert(Translatable(args[0], with...))
+	case "selector":
+		if len(args) != 1 {
+			return false
+		}
+		p.insert(Component{Selector: args[0]})
+	case "score":
+		if len(args) != 2 {
+			return false
+		}
+		p.insert(Component{Score: &Score{Name: args[0], Objective: args[1]}})
+	default:
+		return false
+	}
+	return true
+}
+
+func (p *markupParser) open(name string, s Style) {
+	p.flush()
+	p.stack = append(p.stack, openTag{name: name, style: s})
+}
+
+func (p *markupParser) openColor(name string, color Color) {
+	p.open(name, Style{Color: color})
+	p.stack[len(p.stack)-1].color = true
+}
+
+// close closes the innermost open tag called name and everything after it.
+func (p *markupParser) close(name string) bool {
+	if alias, ok := tagAliases[name]; ok {
+		name = alias
+	}
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if tag := p.stack[i]; tag.name == name || (name == "color" && tag.color) {
+			p.flush()
+			p.stack = p.stack[:i]
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,306 @@
+package text
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// ToNBT encodes c as NBT, the form play packets use since 1.20.3. Unstyled
+// plain text is a bare string tag, as vanilla writes it.
+func (c Component) ToNBT() nbt.Tag {
+	if c.IsPlain() {
+		return &nbt.StringTag{Value: c.Text}
+	}
+	return c.compound()
+}
+
+// compound encodes c as a compound tag, which lists of components need since
+// NBT lists hold one tag type.
+func (c *Component) compound() *nbt.CompoundTag {
+	t := nbt.NewCompoundTag()
+	putString := func(key, value string) {
+		if value != "" {
+			t.Put(key, &nbt.StringTag{Value: value})
+		}
+	}
+	switch c.Type() {
+	case TypeText:
+		t.Put("text", &nbt.StringTag{Value: c.Text})
+	case TypeTranslatable:
+		putString("translate", c.Translate)
+		putString("fallback", c.Fallback)
+		if len(c.With) > 0 {
+			t.Put("with", componentList(c.With))
+		}
+	case TypeScore:
+		score := nbt.NewCompoundTag()
+		score.Put("name", &nbt.StringTag{Value: c.Score.Name})
+		score.Put("objective", &nbt.StringTag{Value: c.Score.Objective})
+		t.Put("score", score)
+	case TypeSelector:
+		putString("selector", c.Selector)
+	case TypeKeybind:
+		putString("keybind", c.Keybind)
+	case TypeNBT:
+		t.Put("nbt", &nbt.StringTag{Value: c.NBT.Path})
+		if c.NBT.Interpret {
+			t.Put("interpret", byteBool(true))
+		}
+		putString("block", c.NBT.Block)
+		putString("entity", c.NBT.Entity)
+		putString("storage", c.NBT.Storage)
+	}
+	if c.Separator != nil {
+		t.Put("separator", c.Separator.ToNBT())
+	}
+
+	putString("color", string(c.Color))
+	for key, flag := range map[string]*bool{
+		"bold":          c.Bold,
+		"italic":        c.Italic,
+		"underlined":    c.Underlined,
+		"strikethrough": c.Strikethrough,
+		"obfuscated":    c.Obfuscated,
+	} {
+		if flag != nil {
+			t.Put(key, byteBool(*flag))
+		}
+	}
+	putString("font", c.Font)
+	putString("insertion", c.Insertion)
+	if e := c.ClickEvent; e != nil {
+		click := nbt.NewCompoundTag()
+		click.Put("action", &nbt.StringTag{Value: string(e.Action)})
+		click.Put("value", &nbt.StringTag{Value: e.Value})
+		t.Put("clickEvent", click)
+	}
+	if e := c.HoverEvent; e != nil {
+		t.Put("hoverEvent", e.nbt())
+	}
+	if len(c.Extra) > 0 {
+		t.Put("extra", componentList(c.Extra))
+	}
+	return t
+}
+
+func (h *HoverEvent) nbt() *nbt.CompoundTag {
+	t := nbt.NewCompoundTag()
+	t.Put("action", &nbt.StringTag{Value: string(h.Action)})
+	switch {
+	case h.Text != nil:
+		t.Put("contents", h.Text.ToNBT())
+	case h.Item != nil:
+		item := nbt.NewCompoundTag()
+		item.Put("id", &nbt.StringTag{Value: h.Item.ID})
+		if h.Item.Count != 0 {
+			item.Put("count", &nbt.IntTag{Value: h.Item.Count})
+		}
+		if h.Item.Tag != "" {
+			item.Put("tag", &nbt.StringTag{Value: h.Item.Tag})
+		}
+		t.Put("contents", item)
+	case h.Entity != nil:
+		entity := nbt.NewCompoundTag()
+		entity.Put("type", &nbt.StringTag{Value: h.Entity.Type})
+		entity.Put("id", &nbt.StringTag{Value: h.Entity.ID})
+		if h.Entity.Name != nil {
+			entity.Put("name", h.Entity.Name.ToNBT())
+		}
+		t.Put("contents", entity)
+	}
+	return t
+}
+
+func componentList(components []Component) *nbt.ListTag {
+	list := &nbt.ListTag{Type: nbt.TagCompound, Value: make([]nbt.Tag, len(components))}
+	for i := range components {
+		list.Value[i] = components[i].compound()
+	}
+	return list
+}
+
+func byteBool(b bool) *nbt.ByteTag {
+	if b {
+		return &nbt.ByteTag{Value: 1}
+	}
+	return &nbt.ByteTag{Value: 0}
+}
+
+// FromNBT decodes a component from any of its NBT forms: a compound, a
+// string or number for plain text, or a list whose first element is followed
+// by the rest.
+func FromNBT(tag nbt.Tag) (Component, error) {
+	switch t := tag.(type) {
+	case *nbt.StringTag:
+		return Plain(t.Value), nil
+	case *nbt.ByteTag:
+		return Plain(strconv.Itoa(int(t.Value))), nil
+	case *nbt.ShortTag:
+		return Plain(strconv.Itoa(int(t.Value))), nil
+	case *nbt.IntTag:
+		return Plain(strconv.Itoa(int(t.Value))), nil
+	case *nbt.LongTag:
+		return Plain(strconv.FormatInt(t.Value, 10)), nil
+	case *nbt.FloatTag:
+		return Plain(strconv.FormatFloat(float64(t.Value), 'g', -1, 32)), nil
+	case *nbt.DoubleTag:
+		return Plain(strconv.FormatFloat(t.Value, 'g', -1, 64)), nil
+	case *nbt.ListTag:
+		list, err := listFromNBT(t)
+		if err != nil {
+			return Component{}, err
+		}
+		if len(list) == 0 {
+			return Component{}, fmt.Errorf("text: empty component list")
+		}
+		return list[0].Append(list[1:]...), nil
+	case *nbt.CompoundTag:
+		return compoundFromNBT(t)
+	}
+	return Component{}, fmt.Errorf("text: a component cannot be a %s", nbt.TagTypeNames[tag.ID()])
+}
+
+func listFromNBT(t *nbt.ListTag) ([]Component, error) {
+	list := make([]Component, len(t.Value))
+	for i, tag := range t.Value {
+		c, err := FromNBT(tag)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = c
+	}
+	return list, nil
+}
+
+func compoundFromNBT(t *nbt.CompoundTag) (Component, error) {
+	// Lists of mixed types wrap their elements in compounds with an empty key.
+	if inner, ok := t.Get(""); ok && len(t.Value) == 1 {
+		return FromNBT(inner)
+	}
+	var c Component
+	str := func(key string) string {
+		s, _ := t.GetString(key)
+		return s
+	}
+	flag := func(key string) *bool {
+		if b, ok := t.Get(key); ok {
+			if v, ok := b.(*nbt.ByteTag); ok {
+				return Bool(v.Value != 0)
+			}
+		}
+		return nil
+	}
+	text, hasText := t.GetString("text")
+	c.Text = text
+	c.Translate = str("translate")
+	c.Fallback = str("fallback")
+	if with, ok := t.GetList("with"); ok {
+		list, err := listFromNBT(with)
+		if err != nil {
+			return c, err
+		}
+		c.With = list
+	}
+	if score, ok := t.GetCompound("score"); ok {
+		name, _ := score.GetString("name")
+		objective, _ := score.GetString("objective")
+		c.Score = &Score{Name: name, Objective: objective}
+	}
+	c.Selector = str("selector")
+	c.Keybind = str("keybind")
+	if path, ok := t.GetString("nbt"); ok {
+		c.NBT = &NBTSource{Path: path, Interpret: isSet(flag("interpret")), Block: str("block"), Entity: str("entity"), Storage: str("storage")}
+	}
+	if sep, ok := t.Get("separator"); ok {
+		s, err := FromNBT(sep)
+		if err != nil {
+			return c, err
+		}
+		c.Separator = &s
+	}
+	if !hasText && c.Type() == TypeText {
+		return c, fmt.Errorf("text: component has no content")
+	}
+
+	if color := str("color"); color != "" {
+		parsed, ok := ParseColor(color)
+		if !ok {
+			return c, fmt.Errorf("text: invalid color %q", color)
+		}
+		c.Color = parsed
+	}
+	c.Bold = flag("bold")
+	c.Italic = flag("italic")
+	c.Underlined = flag("underlined")
+	c.Strikethrough = flag("strikethrough")
+	c.Obfuscated = flag("obfuscated")
+	c.Font = str("font")
+	c.Insertion = str("insertion")
+	if click, ok := t.GetCompound("clickEvent"); ok {
+		action, _ := click.GetString("action")
+		value, _ := click.GetString("value")
+		c.ClickEvent = &ClickEvent{Action: ClickAction(action), Value: value}
+	}
+	if hover, ok := t.GetCompound("hoverEvent"); ok {
+		h, err := hoverFromNBT(hover)
+		if err != nil {
+			return c, err
+		}
+		c.HoverEvent = h
+	}
+	if extra, ok := t.GetList("extra"); ok {
+		list, err := listFromNBT(extra)
+		if err != nil {
+			return c, err
+		}
+		c.Extra = list
+	}
+	return c, nil
+}
+
+func hoverFromNBT(t *nbt.CompoundTag) (*HoverEvent, error) {
+	action, _ := t.GetString("action")
+	h := &HoverEvent{Action: HoverAction(action)}
+	contents, ok := t.Get("contents")
+	if !ok {
+		return nil, fmt.Errorf("text: hover event has no contents")
+	}
+	switch h.Action {
+	case ShowText:
+		c, err := FromNBT(contents)
+		if err != nil {
+			return nil, err
+		}
+		h.Text = &c
+	case ShowItem:
+		h.Item = new(HoverItem)
+		switch v := contents.(type) {
+		case *nbt.StringTag:
+			h.Item.ID = v.Value
+		case *nbt.CompoundTag:
+			h.Item.ID, _ = v.GetString("id")
+			h.Item.Count, _ = v.GetInt("count")
+			h.Item.Tag, _ = v.GetString("tag")
+		}
+	case ShowEntity:
+		v, ok := contents.(*nbt.CompoundTag)
+		if !ok {
+			return nil, fmt.Errorf("text: show_entity contents must be a compound")
+		}
+		h.Entity = new(HoverEntity)
+		h.Entity.Type, _ = v.GetString("type")
+		h.Entity.ID, _ = v.GetString("id")
+		if name, ok := v.Get("name"); ok {
+			c, err := FromNBT(name)
+			if err != nil {
+				return nil, err
+			}
+			h.Entity.Name = &c
+		}
+	default:
+		return nil, fmt.Errorf("text: unknown hover action %q", action)
+	}
+	return h, nil
+}
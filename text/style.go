@@ -0,0 +1,217 @@
+package text
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Style is how a component is shown. Unset fields are inherited from the
+// parent component; the formatting flags are pointers so that a child can
+// turn off what its parent turned on.
+type Style struct {
+	Color         Color
+	Bold          *bool
+	Italic        *bool
+	Underlined    *bool
+	Strikethrough *bool
+	Obfuscated    *bool
+	// Font is a font resource location, such as "minecraft:uniform".
+	Font string
+	// Insertion is inserted into the chat box when the text is shift-clicked.
+	Insertion  string
+	ClickEvent *ClickEvent
+	HoverEvent *HoverEvent
+}
+
+// Bool returns a pointer to b, for the formatting flags of Style.
+func Bool(b bool) *bool { return &b }
+
+// IsZero reports whether s sets nothing.
+func (s *Style) IsZero() bool {
+	return s.Color == "" && s.Bold == nil && s.Italic == nil && s.Underlined == nil &&
+		s.Strikethrough == nil && s.Obfuscated == nil && s.Font == "" && s.Insertion == "" &&
+		s.ClickEvent == nil && s.HoverEvent == nil
+}
+
+// Inherit returns s with its unset fields taken from parent.
+func (s Style) Inherit(parent Style) Style {
+	if s.Color == "" {
+		s.Color = parent.Color
+	}
+	inherit := func(b **bool, p *bool) {
+		if *b == nil {
+			*b = p
+		}
+	}
+	inherit(&s.Bold, parent.Bold)
+	inherit(&s.Italic, parent.Italic)
+	inherit(&s.Underlined, parent.Underlined)
+	inherit(&s.Strikethrough, parent.Strikethrough)
+	inherit(&s.Obfuscated, parent.Obfuscated)
+	if s.Font == "" {
+		s.Font = parent.Font
+	}
+	if s.Insertion == "" {
+		s.Insertion = parent.Insertion
+	}
+	if s.ClickEvent == nil {
+		s.ClickEvent = parent.ClickEvent
+	}
+	if s.HoverEvent == nil {
+		s.HoverEvent = parent.HoverEvent
+	}
+	return s
+}
+
+// isSet reports whether a formatting flag is on.
+func isSet(b *bool) bool { return b != nil && *b }
+
+// Color is a text color: one of the sixteen named colors or "#RRGGBB".
+type Color string
+
+// The named colors, which are also the legacy § colors.
+const (
+	Black       Color = "black"
+	DarkBlue    Color = "dark_blue"
+	DarkGreen   Color = "dark_green"
+	DarkAqua    Color = "dark_aqua"
+	DarkRed     Color = "dark_red"
+	DarkPurple  Color = "dark_purple"
+	Gold        Color = "gold"
+	Gray        Color = "gray"
+	DarkGray    Color = "dark_gray"
+	Blue        Color = "blue"
+	Green       Color = "green"
+	Aqua        Color = "aqua"
+	Red         Color = "red"
+	LightPurple Color = "light_purple"
+	Yellow      Color = "yellow"
+	White       Color = "white"
+)
+
+// namedColors lists the named colors in legacy code order with their RGB
+// values.
+var namedColors = [16]struct {
+	name Color
+	rgb  uint32
+}{
+	{Black, 0x000000}, {DarkBlue, 0x0000AA}, {DarkGreen, 0x00AA00}, {DarkAqua, 0x00AAAA},
+	{DarkRed, 0xAA0000}, {DarkPurple, 0xAA00AA}, {Gold, 0xFFAA00}, {Gray, 0xAAAAAA},
+	{DarkGray, 0x555555}, {Blue, 0x5555FF}, {Green, 0x55FF55}, {Aqua, 0x55FFFF},
+	{Red, 0xFF5555}, {LightPurple, 0xFF55FF}, {Yellow, 0xFFFF55}, {White, 0xFFFFFF},
+}
+
+// RGB returns a hex color.
+func RGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("#%02X%02X%02X", r, g, b))
+}
+
+// ParseColor parses a color name or "#RRGGBB", case-insensitively.
+func ParseColor(s string) (Color, bool) {
+	s = strings.ToLower(s)
+	if strings.HasPrefix(s, "#") {
+		if len(s) != 7 {
+			return "", false
+		}
+		if _, err := strconv.ParseUint(s[1:], 16, 32); err != nil {
+			return "", false
+		}
+		return Color(strings.ToUpper(s)), true
+	}
+	for _, c := range namedColors {
+		if string(c.name) == s {
+			return c.name, true
+		}
+	}
+	return "", false
+}
+
+// Hex returns the color's RGB value as 0xRRGGBB.
+func (c Color) Hex() (uint32, bool) {
+	if strings.HasPrefix(string(c), "#") {
+		v, err := strconv.ParseUint(string(c[1:]), 16, 32)
+		return uint32(v), err == nil && len(c) == 7
+	}
+	for _, n := range namedColors {
+		if n.name == c {
+			return n.rgb, true
+		}
+	}
+	return 0, false
+}
+
+// Named returns the named color closest to c, for places such as legacy text
+// that cannot show hex colors.
+func (c Color) Named() Color {
+	rgb, ok := c.Hex()
+	if !ok {
+		return ""
+	}
+	best, bestDist := Color(""), -1
+	for _, n := range namedColors {
+		dr := int(rgb>>16&0xFF) - int(n.rgb>>16&0xFF)
+		dg := int(rgb>>8&0xFF) - int(n.rgb>>8&0xFF)
+		db := int(rgb&0xFF) - int(n.rgb&0xFF)
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = n.name, d
+		}
+	}
+	return best
+}
+
+// ClickAction is what clicking a component does.
+type ClickAction string
+
+const (
+	OpenURL         ClickAction = "open_url"
+	RunCommand      ClickAction = "run_command"
+	SuggestCommand  ClickAction = "suggest_command"
+	ChangePage      ClickAction = "change_page"
+	CopyToClipboard ClickAction = "copy_to_clipboard"
+)
+
+// ClickEvent runs an action when the component is clicked.
+type ClickEvent struct {
+	Action ClickAction `json:"action"`
+	Value  string      `json:"value"`
+}
+
+// HoverAction is what hovering over a component shows.
+type HoverAction string
+
+const (
+	ShowText   HoverAction = "show_text"
+	ShowItem   HoverAction = "show_item"
+	ShowEntity HoverAction = "show_entity"
+)
+
+// HoverEvent shows a tooltip when the component is hovered over. The field
+// matching Action is set.
+type HoverEvent struct {
+	Action HoverAction
+	Text   *Component
+	Item   *HoverItem
+	Entity *HoverEntity
+}
+
+// HoverItem is the item a show_item tooltip describes.
+type HoverItem struct {
+	ID    string `json:"id"`
+	Count int32  `json:"count,omitempty"`
+	// Tag is the item's NBT as SNBT, for clients before 1.20.5.
+	Tag string `json:"tag,omitempty"`
+}
+
+// HoverEntity is the entity a show_entity tooltip describes.
+type HoverEntity struct {
+	Type string `json:"type"`
+	// ID is the entity's UUID in the dashed form.
+	ID   string     `json:"id"`
+	Name *Component `json:"name,omitempty"`
+}
+
+// ShowTextEvent returns a hover event showing c.
+func ShowTextEvent(c Component) *HoverEvent {
+	return &HoverEvent{Action: ShowText, Text: &c}
+}
@@ -0,0 +1,192 @@
+package text
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// sample exercises every content kind, style field and event.
+func sample() Component {
+	name := Plain("Steve").Colored(Aqua)
+	return Component{
+		Text: "Hello ",
+		Style: Style{
+			Color:      RGB(0x12, 0xAB, 0xEF),
+			Bold:       Bool(true),
+			Italic:     Bool(false),
+			Font:       "minecraft:uniform",
+			Insertion:  "hi",
+			ClickEvent: &ClickEvent{Action: RunCommand, Value: "/help"},
+			HoverEvent: ShowTextEvent(Plain("tip").Colored(Red)),
+		},
+		Extra: []Component{
+			Translatable("chat.type.text", name, Plain("hi")),
+			{Translate: "missing.key", Fallback: "fallback"},
+			{Score: &Score{Name: "@s", Objective: "kills"}},
+			{Selector: "@a", Separator: &Component{Text: " | "}},
+			Keybind("key.jump"),
+			{NBT: &NBTSource{Path: "Items[0]", Interpret: true, Entity: "@s"}},
+			{Text: "item", Style: Style{HoverEvent: &HoverEvent{Action: ShowItem, Item: &HoverItem{ID: "minecraft:stone", Count: 3}}}},
+			{Text: "mob", Style: Style{HoverEvent: &HoverEvent{Action: ShowEntity, Entity: &HoverEntity{
+				Type: "minecraft:pig", ID: "0d1b3a0c-6a4b-4e36-8f16-0c5a9f4f5e31", Name: &name,
+			}}}},
+		},
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	c := sample()
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	var decoded Component
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, c, decoded)
+}
+
+func TestJSONForms(t *testing.T) {
+	data, err := json.Marshal(Plain("hi"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text":"hi"}`, string(data))
+
+	for input, want := range map[string]Component{
+		`"hi"`:                             Plain("hi"),
+		`42`:                               Plain("42"),
+		`true`:                             Plain("true"),
+		`["a",{"text":"b","color":"RED"}]`: Plain("a").Append(Plain("b").Colored(Red)),
+		`{"translate":"x","with":["y"]}`:   Translatable("x", Plain("y")),
+		`{"text":"","hoverEvent":{"action":"show_text","value":"old"}}`: {Style: Style{HoverEvent: ShowTextEvent(Plain("old"))}},
+		`{"text":"","hoverEvent":{"action":"show_item","contents":"minecraft:stone"}}`: {
+			Style: Style{HoverEvent: &HoverEvent{Action: ShowItem, Item: &HoverItem{ID: "minecraft:stone"}}},
+		},
+	} {
+		var c Component
+		require.NoError(t, json.Unmarshal([]byte(input), &c), input)
+		assert.Equal(t, want, c, input)
+	}
+
+	for _, input := range []string{`null`, `[]`, `{}`, `{"text":"a","color":"nope"}`, `{"text":"a","hoverEvent":{"action":"show_text"}}`} {
+		var c Component
+		assert.Error(t, json.Unmarshal([]byte(input), &c), input)
+	}
+}
+
+func TestNBTRoundTrip(t *testing.T) {
+	c := sample()
+	// Through the wire format, to catch lists of mixed tag types.
+	var buf bytes.Buffer
+	require.NoError(t, nbt.WriteNetwork(&buf, c.ToNBT()))
+	tag, err := nbt.ReadNetwork(&buf)
+	require.NoError(t, err)
+	decoded, err := FromNBT(tag)
+	require.NoError(t, err)
+	assert.Equal(t, c, decoded)
+
+	assert.Equal(t, &nbt.StringTag{Value: "plain"}, Plain("plain").ToNBT())
+}
+
+func TestNBTForms(t *testing.T) {
+	wrapped := nbt.NewCompoundTag()
+	wrapped.Put("", &nbt.StringTag{Value: "b"})
+	list := &nbt.ListTag{Type: nbt.TagCompound, Value: []nbt.Tag{Plain("a").Colored(Red).ToNBT(), wrapped}}
+	c, err := FromNBT(list)
+	require.NoError(t, err)
+	assert.Equal(t, Plain("a").Colored(Red).Append(Plain("b")), c)
+
+	c, err = FromNBT(&nbt.IntTag{Value: 7})
+	require.NoError(t, err)
+	assert.Equal(t, Plain("7"), c)
+
+	_, err = FromNBT(nbt.NewCompoundTag())
+	assert.Error(t, err)
+	_, err = FromNBT(&nbt.IntArrayTag{})
+	assert.Error(t, err)
+}
+
+func TestColors(t *testing.T) {
+	color, ok := ParseColor("#a0B1c2")
+	require.True(t, ok)
+	assert.Equal(t, Color("#A0B1C2"), color)
+	_, ok = ParseColor("#12345")
+	assert.False(t, ok)
+	_, ok = ParseColor("pink")
+	assert.False(t, ok)
+
+	assert.Equal(t, Red, RGB(0xF0, 0x50, 0x50).Named())
+	assert.Equal(t, Gold, Gold.Named())
+	assert.Equal(t, Color(""), Color("").Named())
+}
+
+func TestFromLegacy(t *testing.T) {
+	assert.Equal(t, Plain("plain"), FromLegacy("plain", SectionSign))
+	assert.Equal(t, Join(
+		Plain("a"),
+		Plain("red ").Colored(Red),
+		Component{Text: "bold", Style: Style{Color: Red, Bold: Bool(true)}},
+		Plain(" green").Colored(Green),
+		Plain(" reset"),
+		Plain("hex").Colored("#FFAA00"),
+	), FromLegacy("a§cred §lbold§a green§r reset§x§f§f§a§a§0§0hex", SectionSign))
+	assert.Equal(t, Component{Text: "x", Style: Style{Italic: Bool(true)}}, FromLegacy("&ox", '&'))
+	// A trailing code character is text.
+	assert.Equal(t, Plain("50%"), FromLegacy("50%", '%'))
+	assert.Equal(t, "Hello world", StripLegacy("§6Hello §lworld§z", SectionSign))
+}
+
+func TestLegacy(t *testing.T) {
+	for _, s := range []string{
+		"plain",
+		"§cred §lbold§a green§rreset",
+		"§6§ngold underlined§o and italic",
+	} {
+		c := FromLegacy(s, SectionSign)
+		assert.Equal(t, s, c.Legacy(SectionSign), s)
+	}
+	c := Plain("Hi ").Colored(RGB(0x50, 0xFF, 0x50)).Append(Translatable("chat.x"), Keybind("key.jump"))
+	assert.Equal(t, "§aHi chat.xkey.jump", c.Legacy(SectionSign))
+	assert.Equal(t, "Hi chat.xkey.jump", c.PlainText())
+}
+
+func TestParseMarkup(t *testing.T) {
+	for input, want := range map[string]Component{
+		"plain":                Plain("plain"),
+		"<red>red</red> plain": Join(Plain("red").Colored(Red), Plain(" plain")),
+		"<#FFAA00>hex <b>bold</#ffaa00> plain": Join(
+			Plain("hex ").Colored("#FFAA00"),
+			Component{Text: "bold", Style: Style{Color: "#FFAA00", Bold: Bool(true)}},
+			Plain(" plain"),
+		),
+		"<color:gold><bold>a<!bold>b</color>c": Join(
+			Component{Text: "a", Style: Style{Color: Gold, Bold: Bool(true)}},
+			Component{Text: "b", Style: Style{Color: Gold, Bold: Bool(false)}},
+			Plain("c"),
+		),
+		"<i><u>a<reset>b": Join(
+			Component{Text: "a", Style: Style{Italic: Bool(true), Underlined: Bool(true)}},
+			Plain("b"),
+		),
+		"<click:open_url:https://example.com>site": {Text: "site", Style: Style{
+			ClickEvent: &ClickEvent{Action: OpenURL, Value: "https://example.com"},
+		}},
+		"<hover:show_text:'<red>a > b'>x": {Text: "x", Style: Style{HoverEvent: ShowTextEvent(Plain("a > b").Colored(Red))}},
+		"<font:minecraft:uniform>f":       {Text: "f", Style: Style{Font: "minecraft:uniform"}},
+		"<green>Press <key:key.jump><br>": Join(
+			Plain("Press ").Colored(Green),
+			Keybind("key.jump").Colored(Green),
+			Plain("\n").Colored(Green),
+		),
+		"<lang:chat.type.text:'<aqua>Steve':hi>": Translatable("chat.type.text", Plain("Steve").Colored(Aqua), Plain("hi")),
+		"<selector:@p><score:@s:kills>":          Join(Component{Selector: "@p"}, Component{Score: &Score{Name: "@s", Objective: "kills"}}),
+		"a < b <nope> \\<red> </blue> <red":      Plain("a < b <nope> <red> </blue> <red"),
+	} {
+		assert.Equal(t, want, ParseMarkup(input), input)
+	}
+
+	input := "<b>" + Escape(`<red>\o/`)
+	assert.Equal(t, Component{Text: `<red>\o/`, Style: Style{Bold: Bool(true)}}, ParseMarkup(input))
+}
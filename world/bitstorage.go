@@ -0,0 +1,99 @@
+package world
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// BitStorage packs fixed-width unsigned values into 64-bit longs the way
+// chunks store block states, biomes and heightmaps: each long holds as many
+// whole values as fit, lowest bits first, and no value spans two longs.
+type BitStorage struct {
+	bits    int
+	size    int
+	perLong int
+	mask    uint64
+	data    []uint64
+}
+
+// NewBitStorage returns zeroed storage for size values of the given width,
+// which must be between 1 and 32.
+func NewBitStorage(bits, size int) *BitStorage {
+	s, err := NewBitStorageFrom(bits, size, nil)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewBitStorageFrom wraps packed data, as found in a LongArrayTag. A nil
+// data is zeroed; otherwise its length must match the width and size.
+func NewBitStorageFrom(bits, size int, data []uint64) (*BitStorage, error) {
+	if bits < 1 || bits > 32 {
+		return nil, fmt.Errorf("world: invalid bits per value %d", bits)
+	}
+	perLong := 64 / bits
+	longs := (size + perLong - 1) / perLong
+	if data == nil {
+		data = make([]uint64, longs)
+	} else if len(data) != longs {
+		return nil, fmt.Errorf("world: invalid length given for storage, got %d but expected %d", len(data), longs)
+	}
+	return &BitStorage{bits: bits, size: size, perLong: perLong, mask: 1<<bits - 1, data: data}, nil
+}
+
+// Bits returns the width of each value.
+func (s *BitStorage) Bits() int { return s.bits }
+
+// Size returns the number of values.
+func (s *BitStorage) Size() int { return s.size }
+
+// Longs returns the packed data. It is shared with the storage.
+func (s *BitStorage) Longs() []uint64 { return s.data }
+
+// Get returns value i.
+func (s *BitStorage) Get(i int) uint32 {
+	shift := (i % s.perLong) * s.bits
+	return uint32(s.data[i/s.perLong] >> shift & s.mask)
+}
+
+// Set stores value i, which must fit the width, and returns the old value.
+func (s *BitStorage) Set(i int, v uint32) uint32 {
+	long := &s.data[i/s.perLong]
+	shift := (i % s.perLong) * s.bits
+	old := uint32(*long >> shift & s.mask)
+	*long = *long&^(s.mask<<shift) | (uint64(v)&s.mask)<<shift
+	return old
+}
+
+// Clone returns a copy of the storage.
+func (s *BitStorage) Clone() *BitStorage {
+	c := *s
+	c.data = append([]uint64(nil), s.data...)
+	return &c
+}
+
+// ceilLog2 returns the bits needed to tell n values apart: 0 for one value.
+func ceilLog2(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// toInt64s and toUint64s convert packed data to and from a LongArrayTag.
+func toInt64s(data []uint64) []int64 {
+	out := make([]int64, len(data))
+	for i, v := range data {
+		out[i] = int64(v)
+	}
+	return out
+}
+
+func toUint64s(data []int64) []uint64 {
+	out := make([]uint64, len(data))
+	for i, v := range data {
+		out[i] = uint64(v)
+	}
+	return out
+}
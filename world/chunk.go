@@ -0,0 +1,260 @@
+// Package world holds the in-memory form of the world: chunks made of
+// 16x16x16 sections whose block states and biomes live in paletted
+// containers, their heightmaps and light, and their conversion to and from
+// the NBT that region files store.
+package world
+
+import (
+	"fmt"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// BlockPos is the position of a block in the world.
+type BlockPos struct {
+	X, Y, Z int
+}
+
+// ChunkPos is the position of a chunk, in chunks.
+type ChunkPos struct {
+	X, Z int32
+}
+
+// ChunkPosOf returns the chunk holding a block column.
+func ChunkPosOf(x, z int) ChunkPos {
+	return ChunkPos{X: int32(x >> 4), Z: int32(z >> 4)}
+}
+
+// StatusFull is the status of a chunk that finished generating.
+const StatusFull = "minecraft:full"
+
+// Chunk is a 16-block wide column of sections. Block coordinates inside the
+// chunk use the low four bits of X and Z, and Y in world coordinates. A
+// chunk is not safe for concurrent use.
+type Chunk struct {
+	Pos ChunkPos
+	// Sections are ordered bottom up, starting at MinY.
+	Sections   []*Section
+	Heightmaps map[HeightmapType]*Heightmap
+	// SkyLight and BlockLight hold a light array per section, with one more
+	// section below and above the chunk, as light reaches past the blocks.
+	// An entry is nil when that section has no light data.
+	SkyLight   [][]byte
+	BlockLight [][]byte
+	// LightOn is set once the chunk's light has been computed.
+	LightOn bool
+	// BlockEntities are keyed by position, in their saved form.
+	BlockEntities map[BlockPos]*nbt.CompoundTag
+	Status        string
+	// LastUpdate is the game tick of the last save and InhabitedTime the
+	// ticks players have spent in the chunk.
+	LastUpdate    int64
+	InhabitedTime int64
+	// Extra holds the tags of a loaded chunk this package does not model,
+	// such as entities and scheduled ticks, so saving keeps them.
+	Extra *nbt.CompoundTag
+
+	reg  Registry
+	minY int
+}
+
+// NewChunk returns an empty chunk at pos spanning height blocks from minY,
+// which must both be multiples of 16. Its biomes are DefaultBiome.
+func NewChunk(reg Registry, pos ChunkPos, minY, height int) *Chunk {
+	biome, _ := reg.BiomeID(DefaultBiome)
+	sections := height / 16
+	c := &Chunk{
+		Pos:           pos,
+		Sections:      make([]*Section, sections),
+		Heightmaps:    make(map[HeightmapType]*Heightmap),
+		SkyLight:      make([][]byte, sections+2),
+		BlockLight:    make([][]byte, sections+2),
+		BlockEntities: make(map[BlockPos]*nbt.CompoundTag),
+		Status:        StatusFull,
+		reg:           reg,
+		minY:          minY,
+	}
+	for i := range c.Sections {
+		c.Sections[i] = NewSection(biome)
+	}
+	return c
+}
+
+// Registry returns the registry the chunk's IDs belong to.
+func (c *Chunk) Registry() Registry { return c.reg }
+
+// MinY returns the lowest block Y.
+func (c *Chunk) MinY() int { return c.minY }
+
+// MaxY returns the Y just above the highest block.
+func (c *Chunk) MaxY() int { return c.minY + len(c.Sections)*16 }
+
+// MinSection returns the section Y of the lowest section.
+func (c *Chunk) MinSection() int { return c.minY >> 4 }
+
+// Section returns the section holding block Y, or nil outside the chunk.
+func (c *Chunk) Section(y int) *Section {
+	i := (y - c.minY) >> 4
+	if y < c.minY || i >= len(c.Sections) {
+		return nil
+	}
+	return c.Sections[i]
+}
+
+// Block returns the block state at x, y, z; air outside the chunk's height.
+func (c *Chunk) Block(x, y, z int) uint32 {
+	s := c.Section(y)
+	if s == nil {
+		return Air
+	}
+	return s.Blocks.Get(s.Blocks.Index(x&15, y&15, z&15))
+}
+
+// SetBlock sets the block state at x, y, z, updates the tracked heightmaps
+// and returns the old state. Outside the chunk's height it does nothing.
+func (c *Chunk) SetBlock(x, y, z int, state uint32) uint32 {
+	s := c.Section(y)
+	if s == nil {
+		return Air
+	}
+	x, z = x&15, z&15
+	old := s.setBlock(c.reg, x, y&15, z, state)
+	if old != state {
+		for _, h := range c.Heightmaps {
+			h.update(c, x, y, z, state)
+		}
+	}
+	return old
+}
+
+// Biome returns the biome at block x, y, z. Biomes are stored per 4x4x4
+// blocks.
+func (c *Chunk) Biome(x, y, z int) uint32 {
+	s := c.Section(y)
+	if s == nil {
+		return 0
+	}
+	return s.Biomes.Get(s.Biomes.Index(x&15>>2, y&15>>2, z&15>>2))
+}
+
+// SetBiome sets the biome of the 4x4x4 cell holding block x, y, z.
+func (c *Chunk) SetBiome(x, y, z int, biome uint32) {
+	if s := c.Section(y); s != nil {
+		s.Biomes.Set(s.Biomes.Index(x&15>>2, y&15>>2, z&15>>2), biome)
+	}
+}
+
+// Heightmap returns a heightmap, or nil if the chunk has none of that type.
+func (c *Chunk) Heightmap(t HeightmapType) *Heightmap { return c.Heightmaps[t] }
+
+// TrackHeightmap computes heightmap t from the blocks for which counts
+// returns true, and keeps it up to date as blocks change.
+func (c *Chunk) TrackHeightmap(t HeightmapType, counts func(state uint32) bool) *Heightmap {
+	h := c.Heightmaps[t]
+	if h == nil {
+		h = newHeightmap(c.minY, len(c.Sections)*16)
+		c.Heightmaps[t] = h
+	}
+	h.blocks = counts
+	h.recalculate(c)
+	return h
+}
+
+// lightIndex returns the index into SkyLight and BlockLight of block Y, or
+// -1 outside the light sections.
+func (c *Chunk) lightIndex(y int) int {
+	i := (y-c.minY)>>4 + 1
+	if i < 0 || i >= len(c.SkyLight) {
+		return -1
+	}
+	return i
+}
+
+// SkyLightAt returns the sky light at x, y, z.
+func (c *Chunk) SkyLightAt(x, y, z int) int {
+	if i := c.lightIndex(y); i >= 0 {
+		return Light(c.SkyLight[i], x&15, y&15, z&15)
+	}
+	return 0
+}
+
+// BlockLightAt returns the block light at x, y, z.
+func (c *Chunk) BlockLightAt(x, y, z int) int {
+	if i := c.lightIndex(y); i >= 0 {
+		return Light(c.BlockLight[i], x&15, y&15, z&15)
+	}
+	return 0
+}
+
+// SetSkyLight stores the sky light at x, y, z, allocating the section's
+// array if needed.
+func (c *Chunk) SetSkyLight(x, y, z, level int) {
+	c.setLight(c.SkyLight, x, y, z, level)
+}
+
+// SetBlockLight stores the block light at x, y, z.
+func (c *Chunk) SetBlockLight(x, y, z, level int) {
+	c.setLight(c.BlockLight, x, y, z, level)
+}
+
+func (c *Chunk) setLight(layers [][]byte, x, y, z, level int) {
+	i := c.lightIndex(y)
+	if i < 0 {
+		return
+	}
+	if layers[i] == nil {
+		if level == 0 {
+			return
+		}
+		layers[i] = make([]byte, LightLength)
+	}
+	SetLight(layers[i], x&15, y&15, z&15, level)
+}
+
+// SetBlockEntity stores the saved form of a block entity, which must have
+// its x, y and z.
+func (c *Chunk) SetBlockEntity(tag *nbt.CompoundTag) error {
+	x, okX := tag.GetInt("x")
+	y, okY := tag.GetInt("y")
+	z, okZ := tag.GetInt("z")
+	if !okX || !okY || !okZ {
+		return fmt.Errorf("world: block entity without a position")
+	}
+	c.BlockEntities[BlockPos{int(x), int(y), int(z)}] = tag
+	return nil
+}
+
+// Clone returns a deep copy of the chunk, for saving it while it changes.
+func (c *Chunk) Clone() *Chunk {
+	clone := *c
+	clone.Sections = make([]*Section, len(c.Sections))
+	for i, s := range c.Sections {
+		clone.Sections[i] = s.Clone()
+	}
+	clone.Heightmaps = make(map[HeightmapType]*Heightmap, len(c.Heightmaps))
+	for t, h := range c.Heightmaps {
+		hc := *h
+		hc.data = h.data.Clone()
+		clone.Heightmaps[t] = &hc
+	}
+	clone.SkyLight = cloneLayers(c.SkyLight)
+	clone.BlockLight = cloneLayers(c.BlockLight)
+	clone.BlockEntities = make(map[BlockPos]*nbt.CompoundTag, len(c.BlockEntities))
+	for pos, tag := range c.BlockEntities {
+		clone.BlockEntities[pos] = tag.Copy().(*nbt.CompoundTag)
+	}
+	if c.Extra != nil {
+		clone.Extra = c.Extra.Copy().(*nbt.CompoundTag)
+	}
+	return &clone
+}
+
+func cloneLayers(layers [][]byte) [][]byte {
+	out := make([][]byte, len(layers))
+	for i, l := range layers {
+		if l != nil {
+			out[i] = append([]byte(nil), l...)
+		}
+	}
+	return out
+}
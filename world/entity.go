@@ -0,0 +1,323 @@
+package world
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Advik-B/Golem/nbt"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/text"
+)
+
+// Vec3 is a position or velocity in blocks, or blocks per tick.
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+// Entity flags of the first metadata entry.
+const (
+	EntityOnFire    = 0x01
+	EntityCrouching = 0x02
+	EntitySprinting = 0x08
+	EntitySwimming  = 0x10
+	EntityInvisible = 0x20
+	EntityGlowing   = 0x40
+	EntityGliding   = 0x80
+)
+
+// defaultAir is the air supply of an entity that is not under water.
+const defaultAir = 300
+
+// Entity is a mob, item, projectile or other entity, with the fields every
+// entity saves. Tags of a saved entity that Entity does not model, such as
+// a mob's health or an item's stack, are kept in Extra and saved back
+// unchanged.
+type Entity struct {
+	// ID identifies the entity to clients while it is loaded. It is given
+	// out by the server and not saved.
+	ID   int32
+	UUID protocol.UUID
+	// Type is the entity type, such as "minecraft:pig".
+	Type   string
+	Pos    Vec3
+	Motion Vec3
+	// Yaw and Pitch are in degrees. HeadYaw is where a mob looks, which
+	// vanilla does not save; it loads as Yaw.
+	Yaw, Pitch float32
+	HeadYaw    float32
+	OnGround   bool
+
+	FallDistance float32
+	// Fire is the ticks left burning, or a negative number when the
+	// entity does not burn.
+	Fire           int16
+	Air            int16
+	PortalCooldown int32
+	TicksFrozen    int32
+	Invulnerable   bool
+	Silent         bool
+	NoGravity      bool
+	Glowing        bool
+	HasVisualFire  bool
+	// CustomName is a text component as JSON, as vanilla saves it, or "".
+	CustomName        string
+	CustomNameVisible bool
+	Tags              []string
+	Passengers        []*Entity
+
+	// Flags holds the flags of the first metadata entry that are not
+	// saved, such as EntityCrouching. Burning and glowing come from Fire,
+	// HasVisualFire and Glowing.
+	Flags byte
+	// Pose is the pose sent to clients, such as 0 for standing.
+	Pose int
+	// TypeMetadata holds the metadata of the entity's type, from index 8
+	// on, which Metadata sends after the entries every entity has.
+	TypeMetadata []protocol.MetadataEntry
+
+	Extra *nbt.CompoundTag
+}
+
+// NewEntity returns an entity of the given type at pos with a random UUID.
+func NewEntity(typ string, pos Vec3) *Entity {
+	return &Entity{
+		UUID: protocol.RandomUUID(),
+		Type: typ,
+		Pos:  pos,
+		Fire: -1,
+		Air:  defaultAir,
+	}
+}
+
+// Metadata returns the entity's metadata: the entries every entity has,
+// then TypeMetadata.
+func (e *Entity) Metadata() []protocol.MetadataEntry {
+	flags := e.Flags &^ (EntityOnFire | EntityGlowing)
+	if e.Fire > 0 || e.HasVisualFire {
+		flags |= EntityOnFire
+	}
+	if e.Glowing {
+		flags |= EntityGlowing
+	}
+	var name nbt.Tag
+	if e.CustomName != "" {
+		var c text.Component
+		if err := json.Unmarshal([]byte(e.CustomName), &c); err != nil {
+			// Names written by hand are often not JSON.
+			c = text.Plain(e.CustomName)
+		}
+		name = c.ToNBT()
+	}
+	return append([]protocol.MetadataEntry{
+		protocol.MetadataByteEntry(0, flags),
+		protocol.MetadataVarIntEntry(1, int(e.Air)),
+		protocol.MetadataOptionalTextEntry(2, name),
+		protocol.MetadataBoolEntry(3, e.CustomNameVisible),
+		protocol.MetadataBoolEntry(4, e.Silent),
+		protocol.MetadataBoolEntry(5, e.NoGravity),
+		protocol.MetadataPoseEntry(6, e.Pose),
+		protocol.MetadataVarIntEntry(7, int(e.TicksFrozen)),
+	}, e.TypeMetadata...)
+}
+
+// EntityFromNBT reads an entity as vanilla saves it, with its passengers.
+// An entity without a type or a position is an error.
+func EntityFromNBT(tag *nbt.CompoundTag) (*Entity, error) {
+	e := &Entity{Extra: tag.Copy().(*nbt.CompoundTag)}
+	take := func(key string) nbt.Tag {
+		v := e.Extra.Value[key]
+		delete(e.Extra.Value, key)
+		return v
+	}
+	e.Type = stringValue(take("id"))
+	if e.Type == "" {
+		return nil, fmt.Errorf("world: entity without an id")
+	}
+	pos := doubles(take("Pos"))
+	if len(pos) != 3 {
+		return nil, fmt.Errorf("world: %s without a position", e.Type)
+	}
+	e.Pos = Vec3{pos[0], pos[1], pos[2]}
+	if motion := doubles(take("Motion")); len(motion) == 3 {
+		e.Motion = Vec3{motion[0], motion[1], motion[2]}
+	}
+	if rotation, ok := take("Rotation").(*nbt.ListTag); ok && len(rotation.Value) == 2 {
+		yaw, _ := rotation.Value[0].(*nbt.FloatTag)
+		pitch, _ := rotation.Value[1].(*nbt.FloatTag)
+		if yaw != nil && pitch != nil {
+			e.Yaw, e.Pitch = yaw.Value, pitch.Value
+		}
+	}
+	e.HeadYaw = e.Yaw
+	if uuid, ok := take("UUID").(*nbt.IntArrayTag); ok && len(uuid.Value) == 4 {
+		for i, v := range uuid.Value {
+			binary.BigEndian.PutUint32(e.UUID[i*4:], uint32(v))
+		}
+	} else {
+		e.UUID = protocol.RandomUUID()
+	}
+
+	e.OnGround = intValue(take("OnGround")) != 0
+	if f, ok := take("FallDistance").(*nbt.FloatTag); ok {
+		e.FallDistance = f.Value
+	}
+	e.Fire = -1
+	if fire := take("Fire"); fire != nil {
+		e.Fire = int16(intValue(fire))
+	}
+	e.Air = defaultAir
+	if air := take("Air"); air != nil {
+		e.Air = int16(intValue(air))
+	}
+	e.PortalCooldown = int32(intValue(take("PortalCooldown")))
+	e.TicksFrozen = int32(intValue(take("TicksFrozen")))
+	e.Invulnerable = intValue(take("Invulnerable")) != 0
+	e.Silent = intValue(take("Silent")) != 0
+	e.NoGravity = intValue(take("NoGravity")) != 0
+	e.Glowing = intValue(take("Glowing")) != 0
+	e.HasVisualFire = intValue(take("HasVisualFire")) != 0
+	e.CustomName = stringValue(take("CustomName"))
+	e.CustomNameVisible = intValue(take("CustomNameVisible")) != 0
+	if tags, ok := take("Tags").(*nbt.ListTag); ok {
+		for _, v := range tags.Value {
+			if s, ok := v.(*nbt.StringTag); ok {
+				e.Tags = append(e.Tags, s.Value)
+			}
+		}
+	}
+	if passengers, ok := take("Passengers").(*nbt.ListTag); ok {
+		for _, v := range passengers.Value {
+			if st, ok := v.(*nbt.CompoundTag); ok {
+				p, err := EntityFromNBT(st)
+				if err != nil {
+					return nil, err
+				}
+				e.Passengers = append(e.Passengers, p)
+			}
+		}
+	}
+	return e, nil
+}
+
+// ToNBT returns the entity in the form vanilla saves it. Like vanilla, it
+// leaves out the flags that are not set.
+func (e *Entity) ToNBT() *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	if e.Extra != nil {
+		for k, v := range e.Extra.Value {
+			tag.Put(k, v)
+		}
+	}
+	tag.Put("id", &nbt.StringTag{Value: e.Type})
+	tag.Put("Pos", doubleList(e.Pos.X, e.Pos.Y, e.Pos.Z))
+	tag.Put("Motion", doubleList(e.Motion.X, e.Motion.Y, e.Motion.Z))
+	rotation := &nbt.ListTag{Type: nbt.TagFloat}
+	rotation.Add(&nbt.FloatTag{Value: e.Yaw})
+	rotation.Add(&nbt.FloatTag{Value: e.Pitch})
+	tag.Put("Rotation", rotation)
+	uuid := make([]int32, 4)
+	for i := range uuid {
+		uuid[i] = int32(binary.BigEndian.Uint32(e.UUID[i*4:]))
+	}
+	tag.Put("UUID", &nbt.IntArrayTag{Value: uuid})
+	tag.Put("OnGround", boolTag(e.OnGround))
+	tag.Put("FallDistance", &nbt.FloatTag{Value: e.FallDistance})
+	tag.Put("Fire", &nbt.ShortTag{Value: e.Fire})
+	tag.Put("Air", &nbt.ShortTag{Value: e.Air})
+	tag.Put("PortalCooldown", &nbt.IntTag{Value: e.PortalCooldown})
+	tag.Put("Invulnerable", boolTag(e.Invulnerable))
+	if e.TicksFrozen > 0 {
+		tag.Put("TicksFrozen", &nbt.IntTag{Value: e.TicksFrozen})
+	}
+	for key, set := range map[string]bool{
+		"Silent": e.Silent, "NoGravity": e.NoGravity, "Glowing": e.Glowing,
+		"HasVisualFire": e.HasVisualFire, "CustomNameVisible": e.CustomNameVisible,
+	} {
+		if set {
+			tag.Put(key, boolTag(true))
+		}
+	}
+	if e.CustomName != "" {
+		tag.Put("CustomName", &nbt.StringTag{Value: e.CustomName})
+	}
+	if len(e.Tags) > 0 {
+		tags := &nbt.ListTag{Type: nbt.TagString}
+		for _, t := range e.Tags {
+			tags.Add(&nbt.StringTag{Value: t})
+		}
+		tag.Put("Tags", tags)
+	}
+	if len(e.Passengers) > 0 {
+		passengers := &nbt.ListTag{Type: nbt.TagCompound}
+		for _, p := range e.Passengers {
+			passengers.Add(p.ToNBT())
+		}
+		tag.Put("Passengers", passengers)
+	}
+	return tag
+}
+
+// EntitiesFromNBT reads a chunk of the entities region files: the entities
+// saved in chunk pos.
+func EntitiesFromNBT(tag *nbt.CompoundTag) (ChunkPos, []*Entity, error) {
+	position, ok := tag.Value["Position"].(*nbt.IntArrayTag)
+	if !ok || len(position.Value) != 2 {
+		return ChunkPos{}, nil, fmt.Errorf("world: entity chunk without a position")
+	}
+	pos := ChunkPos{X: position.Value[0], Z: position.Value[1]}
+	var entities []*Entity
+	if list, ok := tag.GetList("Entities"); ok {
+		for _, v := range list.Value {
+			st, ok := v.(*nbt.CompoundTag)
+			if !ok {
+				continue
+			}
+			e, err := EntityFromNBT(st)
+			if err != nil {
+				return pos, nil, fmt.Errorf("world: entities of chunk %d, %d: %w", pos.X, pos.Z, err)
+			}
+			entities = append(entities, e)
+		}
+	}
+	return pos, entities, nil
+}
+
+// EntitiesToNBT returns the entities of chunk pos in the form the entities
+// region files store, stamped with dataVersion.
+func EntitiesToNBT(pos ChunkPos, entities []*Entity, dataVersion int32) *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	tag.Put("DataVersion", &nbt.IntTag{Value: dataVersion})
+	tag.Put("Position", &nbt.IntArrayTag{Value: []int32{pos.X, pos.Z}})
+	list := &nbt.ListTag{Type: nbt.TagCompound}
+	for _, e := range entities {
+		list.Add(e.ToNBT())
+	}
+	tag.Put("Entities", list)
+	return tag
+}
+
+// doubles returns the values of a list of doubles, or nil.
+func doubles(tag nbt.Tag) []float64 {
+	list, ok := tag.(*nbt.ListTag)
+	if !ok {
+		return nil
+	}
+	values := make([]float64, 0, len(list.Value))
+	for _, v := range list.Value {
+		d, ok := v.(*nbt.DoubleTag)
+		if !ok {
+			return nil
+		}
+		values = append(values, d.Value)
+	}
+	return values
+}
+
+func doubleList(values ...float64) *nbt.ListTag {
+	list := &nbt.ListTag{Type: nbt.TagDouble}
+	for _, v := range values {
+		list.Add(&nbt.DoubleTag{Value: v})
+	}
+	return list
+}
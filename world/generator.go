@@ -0,0 +1,261 @@
+package world
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// ChunkGenerator fills the chunks of a dimension that were never saved.
+// Generators are deterministic: a chunk generated twice with the same seed
+// is the same. They are called from several goroutines at once.
+type ChunkGenerator interface {
+	// Generate fills an empty chunk.
+	Generate(c *Chunk) error
+}
+
+// spawnFinder is implemented by generators that know where new worlds
+// should spawn.
+type spawnFinder interface {
+	// FindSpawn returns a spawn point in a dimension of the given type.
+	FindSpawn(typ DimensionType) BlockPos
+}
+
+// NewGenerator returns the generator a dimension's saved settings describe:
+// minecraft:flat, or minecraft:noise with the minecraft:overworld settings.
+// Others, such as the nether's and the end's noise, are not supported yet;
+// their chunks are left empty.
+func NewGenerator(reg Registry, seed int64, settings DimensionSettings) (ChunkGenerator, error) {
+	tag := settings.Generator
+	if tag == nil {
+		return NewVoidGenerator(reg, DefaultBiome), nil
+	}
+	typ, _ := tag.GetString("type")
+	switch typ {
+	case "minecraft:flat":
+		flat, _ := tag.GetCompound("settings")
+		return flatFromNBT(reg, flat)
+	case "minecraft:noise":
+		if name, _ := tag.GetString("settings"); name == "minecraft:overworld" {
+			return NewNoiseGenerator(reg, seed)
+		}
+	}
+	biome := DefaultBiome
+	if source, ok := tag.GetCompound("biome_source"); ok {
+		if b, ok := source.GetString("biome"); ok {
+			biome = b
+		} else if t, _ := source.GetString("type"); t == "minecraft:the_end" {
+			biome = "minecraft:the_end"
+		}
+	}
+	return NewVoidGenerator(reg, biome), nil
+}
+
+// stateOf returns the ID of a block state given by name and property pairs.
+func stateOf(reg Registry, name string, props ...string) (uint32, error) {
+	tag := nbt.NewCompoundTag()
+	tag.Put("Name", &nbt.StringTag{Value: name})
+	if len(props) > 0 {
+		p := nbt.NewCompoundTag()
+		for i := 0; i+1 < len(props); i += 2 {
+			p.Put(props[i], &nbt.StringTag{Value: props[i+1]})
+		}
+		tag.Put("Properties", p)
+	}
+	id, ok := reg.BlockStateID(tag)
+	if !ok {
+		return 0, fmt.Errorf("world: unknown block %s", name)
+	}
+	return id, nil
+}
+
+// biomeOf returns the ID of a biome, falling back to DefaultBiome like
+// chunks loading an unknown one.
+func biomeOf(reg Registry, name string) uint32 {
+	if id, ok := reg.BiomeID(name); ok {
+		return id
+	}
+	id, _ := reg.BiomeID(DefaultBiome)
+	return id
+}
+
+// fillBiome sets every biome cell of a chunk.
+func fillBiome(c *Chunk, biome uint32) {
+	for _, s := range c.Sections {
+		s.Biomes.Fill(biome)
+	}
+}
+
+// trackHeightmaps computes the heightmaps clients are sent once a chunk is
+// generated.
+func trackHeightmaps(c *Chunk) {
+	reg := c.Registry()
+	for _, t := range ClientHeightmaps {
+		c.TrackHeightmap(t, func(state uint32) bool { return !reg.IsAir(state) })
+	}
+}
+
+// VoidGenerator generates empty chunks of one biome.
+type VoidGenerator struct {
+	Biome uint32
+}
+
+// NewVoidGenerator returns a generator of empty chunks of the given biome.
+func NewVoidGenerator(reg Registry, biome string) *VoidGenerator {
+	return &VoidGenerator{Biome: biomeOf(reg, biome)}
+}
+
+func (g *VoidGenerator) Generate(c *Chunk) error {
+	fillBiome(c, g.Biome)
+	trackHeightmaps(c)
+	return nil
+}
+
+// FlatLayer is a layer of a superflat world.
+type FlatLayer struct {
+	Block  uint32
+	Height int
+}
+
+// FlatGenerator generates superflat chunks: layers of blocks from the
+// bottom of the world up, all of one biome.
+type FlatGenerator struct {
+	reg    Registry
+	Layers []FlatLayer
+	Biome  uint32
+}
+
+// FlatPresets are vanilla's superflat presets by name.
+var FlatPresets = map[string]string{
+	"classic_flat":    "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains",
+	"tunnelers_dream": "minecraft:bedrock,230*minecraft:stone,5*minecraft:dirt,minecraft:grass_block;minecraft:windswept_hills",
+	"water_world":     "minecraft:bedrock,5*minecraft:stone,5*minecraft:dirt,5*minecraft:sand,90*minecraft:water;minecraft:deep_ocean",
+	"overworld":       "minecraft:bedrock,59*minecraft:stone,3*minecraft:dirt,minecraft:grass_block;minecraft:plains",
+	"snowy_kingdom":   "minecraft:bedrock,59*minecraft:stone,3*minecraft:dirt,minecraft:grass_block,minecraft:snow;minecraft:snowy_plains",
+	"bottomless_pit":  "2*minecraft:cobblestone,3*minecraft:dirt,minecraft:grass_block;minecraft:plains",
+	"desert":          "minecraft:bedrock,3*minecraft:stone,52*minecraft:sandstone,8*minecraft:sand;minecraft:desert",
+	"redstone_ready":  "minecraft:bedrock,3*minecraft:stone,116*minecraft:sandstone;minecraft:desert",
+	"the_void":        "minecraft:air;minecraft:the_void",
+}
+
+// ParseFlatPreset parses a superflat preset: layers from the bottom up, each
+// a block with an optional count such as 2*minecraft:dirt, then the biome
+// after a semicolon. The name of a vanilla preset works too.
+func ParseFlatPreset(reg Registry, preset string) (*FlatGenerator, error) {
+	if p, ok := FlatPresets[strings.TrimPrefix(preset, "minecraft:")]; ok {
+		preset = p
+	}
+	layers, biome, _ := strings.Cut(preset, ";")
+	// Anything after the biome, such as structures, is ignored.
+	biome, _, _ = strings.Cut(biome, ";")
+	if biome = strings.TrimSpace(biome); biome == "" {
+		biome = DefaultBiome
+	}
+	g := &FlatGenerator{reg: reg, Biome: biomeOf(reg, biome)}
+	if strings.TrimSpace(layers) == "" {
+		return g, nil
+	}
+	for _, layer := range strings.Split(layers, ",") {
+		layer = strings.TrimSpace(layer)
+		height := 1
+		if n, name, ok := strings.Cut(layer, "*"); ok {
+			var err error
+			if height, err = strconv.Atoi(n); err != nil || height < 1 {
+				return nil, fmt.Errorf("world: bad layer height in %q", layer)
+			}
+			layer = name
+		}
+		if !strings.Contains(layer, ":") {
+			layer = "minecraft:" + layer
+		}
+		state, err := stateOf(reg, layer)
+		if err != nil {
+			return nil, err
+		}
+		g.Layers = append(g.Layers, FlatLayer{Block: state, Height: height})
+	}
+	return g, nil
+}
+
+// flatFromNBT reads the settings of a minecraft:flat generator.
+func flatFromNBT(reg Registry, tag *nbt.CompoundTag) (*FlatGenerator, error) {
+	g := &FlatGenerator{reg: reg, Biome: biomeOf(reg, DefaultBiome)}
+	if tag == nil {
+		return g, nil
+	}
+	if biome, ok := tag.GetString("biome"); ok {
+		g.Biome = biomeOf(reg, biome)
+	}
+	layers, _ := tag.GetList("layers")
+	if layers == nil {
+		return g, nil
+	}
+	for _, t := range layers.Value {
+		layer, ok := t.(*nbt.CompoundTag)
+		if !ok {
+			return nil, fmt.Errorf("world: flat layer is not a compound")
+		}
+		name, _ := layer.GetString("block")
+		state, err := stateOf(reg, name)
+		if err != nil {
+			return nil, err
+		}
+		height, _ := layer.GetInt("height")
+		g.Layers = append(g.Layers, FlatLayer{Block: state, Height: int(height)})
+	}
+	return g, nil
+}
+
+// ToNBT returns the generator in the form level.dat saves it.
+func (g *FlatGenerator) ToNBT() *nbt.CompoundTag {
+	layers := &nbt.ListTag{Type: nbt.TagCompound}
+	for _, l := range g.Layers {
+		layer := nbt.NewCompoundTag()
+		state, _ := g.reg.BlockState(l.Block)
+		name, _ := state.GetString("Name")
+		layer.Put("block", &nbt.StringTag{Value: name})
+		layer.Put("height", &nbt.IntTag{Value: int32(l.Height)})
+		layers.Value = append(layers.Value, layer)
+	}
+	settings := nbt.NewCompoundTag()
+	settings.Put("layers", layers)
+	biome, _ := g.reg.Biome(g.Biome)
+	settings.Put("biome", &nbt.StringTag{Value: biome})
+	settings.Put("features", &nbt.ByteTag{Value: 0})
+	settings.Put("lakes", &nbt.ByteTag{Value: 0})
+	tag := nbt.NewCompoundTag()
+	tag.Put("type", &nbt.StringTag{Value: "minecraft:flat"})
+	tag.Put("settings", settings)
+	return tag
+}
+
+// FindSpawn returns the position on top of the layers at the origin.
+func (g *FlatGenerator) FindSpawn(typ DimensionType) BlockPos {
+	y := typ.MinY
+	for _, l := range g.Layers {
+		y += l.Height
+	}
+	return BlockPos{Y: min(y, typ.MinY+typ.Height)}
+}
+
+func (g *FlatGenerator) Generate(c *Chunk) error {
+	fillBiome(c, g.Biome)
+	y := c.MinY()
+	for _, l := range g.Layers {
+		for top := y + l.Height; y < top && y < c.MaxY(); y++ {
+			if g.reg.IsAir(l.Block) {
+				continue
+			}
+			s := c.Section(y)
+			for z := 0; z < 16; z++ {
+				for x := 0; x < 16; x++ {
+					s.setBlock(g.reg, x, y&15, z, l.Block)
+				}
+			}
+		}
+	}
+	trackHeightmaps(c)
+	return nil
+}
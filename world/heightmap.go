@@ -0,0 +1,97 @@
+package world
+
+// HeightmapType names a heightmap as chunks save and send it.
+type HeightmapType string
+
+// The heightmaps of a chunk. The _WG ones are only used during generation.
+const (
+	WorldSurfaceWG         HeightmapType = "WORLD_SURFACE_WG"
+	WorldSurface           HeightmapType = "WORLD_SURFACE"
+	OceanFloorWG           HeightmapType = "OCEAN_FLOOR_WG"
+	OceanFloor             HeightmapType = "OCEAN_FLOOR"
+	MotionBlocking         HeightmapType = "MOTION_BLOCKING"
+	MotionBlockingNoLeaves HeightmapType = "MOTION_BLOCKING_NO_LEAVES"
+)
+
+// ClientHeightmaps are the heightmaps the Chunk Data packet sends.
+var ClientHeightmaps = []HeightmapType{MotionBlocking, WorldSurface}
+
+// Heightmap records, for each column of a chunk, the lowest Y above every
+// block that matches its predicate.
+type Heightmap struct {
+	minY int
+	data *BitStorage
+	// blocks reports whether a block state counts. Heightmaps loaded without
+	// one are kept as they are.
+	blocks func(state uint32) bool
+}
+
+// newHeightmap returns an empty heightmap for a chunk of the given height.
+func newHeightmap(minY, height int) *Heightmap {
+	return &Heightmap{minY: minY, data: NewBitStorage(ceilLog2(height+1), 256)}
+}
+
+// Get returns the lowest Y at column x, z above every counted block, or the
+// chunk's bottom if there is none.
+func (h *Heightmap) Get(x, z int) int {
+	return int(h.data.Get(x+z*16)) + h.minY
+}
+
+func (h *Heightmap) set(x, z, y int) {
+	h.data.Set(x+z*16, uint32(y-h.minY))
+}
+
+// Data returns the packed heights, as saved and sent.
+func (h *Heightmap) Data() *BitStorage { return h.data }
+
+// update adjusts column x, z after the block at y changed to state. It
+// mirrors vanilla: only a change at or above the top block matters, and
+// removing the top block scans down for the next one.
+func (h *Heightmap) update(c *Chunk, x, y, z int, state uint32) {
+	if h.blocks == nil {
+		return
+	}
+	top := h.Get(x, z)
+	if y <= top-2 {
+		return
+	}
+	if h.blocks(state) {
+		if y >= top {
+			h.set(x, z, y+1)
+		}
+		return
+	}
+	if top-1 == y {
+		for below := y - 1; below >= h.minY; below-- {
+			if h.blocks(c.Block(x, below, z)) {
+				h.set(x, z, below+1)
+				return
+			}
+		}
+		h.set(x, z, h.minY)
+	}
+}
+
+// recalculate recomputes every column from the chunk's blocks.
+func (h *Heightmap) recalculate(c *Chunk) {
+	// Sections of air are skipped unless air itself counts.
+	skipAir := !h.blocks(Air)
+	for z := 0; z < 16; z++ {
+		for x := 0; x < 16; x++ {
+			h.set(x, z, h.minY)
+		column:
+			for i := len(c.Sections) - 1; i >= 0; i-- {
+				s := c.Sections[i]
+				if skipAir && s.IsEmpty() {
+					continue
+				}
+				for y := 15; y >= 0; y-- {
+					if h.blocks(s.Blocks.Get(s.Blocks.Index(x, y, z))) {
+						h.set(x, z, h.minY+i*16+y+1)
+						break column
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,436 @@
+package world
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// DataVersion and VersionName are the game version this package saves
+// worlds as.
+const (
+	DataVersion = 3955
+	VersionName = "1.21.1"
+)
+
+// anvilVersion is the "version" tag of level.dat, which marks the Anvil
+// format.
+const anvilVersion = 19133
+
+// Weather is the weather of a world and how many ticks are left until it
+// changes.
+type Weather struct {
+	Raining     bool
+	RainTime    int32
+	Thundering  bool
+	ThunderTime int32
+	// ClearTime is set by /weather clear and holds the weather clear.
+	ClearTime int32
+}
+
+// DimensionSettings is a dimension as level.dat lists it: its dimension type
+// and its generator settings in their saved form.
+type DimensionSettings struct {
+	Type      string
+	Generator *nbt.CompoundTag
+}
+
+// WorldGenSettings are the settings worlds are generated with.
+type WorldGenSettings struct {
+	Seed             int64
+	GenerateFeatures bool
+	BonusChest       bool
+	// Dimensions are keyed by dimension name, such as minecraft:overworld.
+	Dimensions map[string]DimensionSettings
+}
+
+// LevelData is the content of level.dat.
+type LevelData struct {
+	Name        string
+	DataVersion int32
+	// VersionName is the game version that last saved the world.
+	VersionName string
+	Spawn       BlockPos
+	SpawnAngle  float32
+	// Time counts the ticks the world has run and DayTime the time of day,
+	// which commands and sleeping can change.
+	Time    int64
+	DayTime int64
+	Weather Weather
+	// GameType is the default game mode: 0 survival, 1 creative, 2
+	// adventure and 3 spectator.
+	GameType         int32
+	Difficulty       int8
+	DifficultyLocked bool
+	Hardcore         bool
+	AllowCommands    bool
+	Initialized      bool
+	// GameRules hold every game rule's value as a string, as saved.
+	GameRules map[string]string
+	WorldGen  WorldGenSettings
+	// LastPlayed is when the world was last saved, in Unix milliseconds.
+	LastPlayed int64
+
+	// extra holds the tags this type does not model, so saving keeps them.
+	extra *nbt.CompoundTag
+}
+
+// DefaultGameRules are the game rules of a new world.
+var DefaultGameRules = map[string]string{
+	"announceAdvancements":             "true",
+	"blockExplosionDropDecay":          "true",
+	"commandBlockOutput":               "true",
+	"commandModificationBlockLimit":    "32768",
+	"disableElytraMovementCheck":       "false",
+	"disableRaids":                     "false",
+	"doDaylightCycle":                  "true",
+	"doEntityDrops":                    "true",
+	"doFireTick":                       "true",
+	"doImmediateRespawn":               "false",
+	"doInsomnia":                       "true",
+	"doLimitedCrafting":                "false",
+	"doMobLoot":                        "true",
+	"doMobSpawning":                    "true",
+	"doPatrolSpawning":                 "true",
+	"doTileDrops":                      "true",
+	"doTraderSpawning":                 "true",
+	"doVinesSpread":                    "true",
+	"doWardenSpawning":                 "true",
+	"doWeatherCycle":                   "true",
+	"drowningDamage":                   "true",
+	"enderPearlsVanishOnDeath":         "true",
+	"fallDamage":                       "true",
+	"fireDamage":                       "true",
+	"forgiveDeadPlayers":               "true",
+	"freezeDamage":                     "true",
+	"globalSoundEvents":                "true",
+	"keepInventory":                    "false",
+	"lavaSourceConversion":             "false",
+	"logAdminCommands":                 "true",
+	"maxCommandChainLength":            "65536",
+	"maxCommandForkCount":              "65536",
+	"maxEntityCramming":                "24",
+	"mobExplosionDropDecay":            "true",
+	"mobGriefing":                      "true",
+	"naturalRegeneration":              "true",
+	"playersNetherPortalCreativeDelay": "1",
+	"playersNetherPortalDefaultDelay":  "80",
+	"playersSleepingPercentage":        "100",
+	"projectilesCanBreakBlocks":        "true",
+	"randomTickSpeed":                  "3",
+	"reducedDebugInfo":                 "false",
+	"sendCommandFeedback":              "true",
+	"showDeathMessages":                "true",
+	"snowAccumulationHeight":           "1",
+	"spawnChunkRadius":                 "2",
+	"spawnRadius":                      "10",
+	"spectatorsGenerateChunks":         "true",
+	"tntExplosionDropDecay":            "false",
+	"universalAnger":                   "false",
+	"waterSourceConversion":            "true",
+}
+
+// NewLevelData returns the level data of a new world with the vanilla
+// dimensions and their default generators.
+func NewLevelData(name string, seed int64) *LevelData {
+	return &LevelData{
+		Name:        name,
+		DataVersion: DataVersion,
+		VersionName: VersionName,
+		Spawn:       BlockPos{Y: 64},
+		Difficulty:  2,
+		GameRules:   maps.Clone(DefaultGameRules),
+		WorldGen: WorldGenSettings{
+			Seed:             seed,
+			GenerateFeatures: true,
+			Dimensions: map[string]DimensionSettings{
+				Overworld: {Type: Overworld, Generator: noiseGenerator("minecraft:overworld", multiNoise("minecraft:overworld"))},
+				TheNether: {Type: TheNether, Generator: noiseGenerator("minecraft:nether", multiNoise("minecraft:nether"))},
+				TheEnd:    {Type: TheEnd, Generator: noiseGenerator("minecraft:end", biomeSource("minecraft:the_end"))},
+			},
+		},
+	}
+}
+
+// ParseSeed returns the seed a level-seed setting names, as vanilla reads
+// it: a number is used as is, other text by its Java string hash, and an
+// empty setting picks a random seed.
+func ParseSeed(s string) int64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Now().UnixNano()
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n != 0 {
+		return n
+	}
+	var h int32
+	for _, c := range utf16.Encode([]rune(s)) {
+		h = 31*h + int32(c)
+	}
+	return int64(h)
+}
+
+func noiseGenerator(settings string, biomes *nbt.CompoundTag) *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	tag.Put("type", &nbt.StringTag{Value: "minecraft:noise"})
+	tag.Put("settings", &nbt.StringTag{Value: settings})
+	tag.Put("biome_source", biomes)
+	return tag
+}
+
+func biomeSource(typ string) *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	tag.Put("type", &nbt.StringTag{Value: typ})
+	return tag
+}
+
+func multiNoise(preset string) *nbt.CompoundTag {
+	tag := biomeSource("minecraft:multi_noise")
+	tag.Put("preset", &nbt.StringTag{Value: preset})
+	return tag
+}
+
+// GameRuleBool returns a boolean game rule, or false if it is not set.
+func (l *LevelData) GameRuleBool(name string) bool {
+	return l.GameRules[name] == "true"
+}
+
+// GameRuleInt returns an integer game rule, or 0 if it is not set.
+func (l *LevelData) GameRuleInt(name string) int {
+	n, _ := strconv.Atoi(l.GameRules[name])
+	return n
+}
+
+// Clone returns a deep copy of the level data.
+func (l *LevelData) Clone() *LevelData {
+	c := *l
+	c.GameRules = maps.Clone(l.GameRules)
+	c.WorldGen.Dimensions = make(map[string]DimensionSettings, len(l.WorldGen.Dimensions))
+	for name, d := range l.WorldGen.Dimensions {
+		if d.Generator != nil {
+			d.Generator = d.Generator.Copy().(*nbt.CompoundTag)
+		}
+		c.WorldGen.Dimensions[name] = d
+	}
+	if l.extra != nil {
+		c.extra = l.extra.Copy().(*nbt.CompoundTag)
+	}
+	return &c
+}
+
+// ReadLevelData reads a level.dat file.
+func ReadLevelData(path string) (*LevelData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	root, err := nbt.ReadCompressed(f)
+	if err != nil {
+		return nil, fmt.Errorf("world: %s: %w", path, err)
+	}
+	compound, ok := root.Tag.(*nbt.CompoundTag)
+	if !ok {
+		return nil, fmt.Errorf("world: %s is not a compound", path)
+	}
+	data, ok := compound.GetCompound("Data")
+	if !ok {
+		return nil, fmt.Errorf("world: %s has no Data", path)
+	}
+	return LevelDataFromNBT(data), nil
+}
+
+// WriteLevelData writes level.dat to path, first moving the file there to
+// a backup path, such as level.dat_old, if backup is not empty. The new file
+// is complete on disk before it replaces the old one.
+func WriteLevelData(path, backup string, l *LevelData) error {
+	root := nbt.NewCompoundTag()
+	root.Put("Data", l.ToNBT())
+	var buf bytes.Buffer
+	if err := nbt.WriteCompressed(&buf, nbt.NamedTag{Tag: root}); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "level*.dat")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if backup != "" {
+		if err := os.Rename(path, backup); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LevelDataFromNBT reads the Data compound of level.dat.
+func LevelDataFromNBT(tag *nbt.CompoundTag) *LevelData {
+	l := &LevelData{
+		GameRules: make(map[string]string),
+		extra:     tag.Copy().(*nbt.CompoundTag),
+	}
+	take := func(key string) nbt.Tag {
+		v := l.extra.Value[key]
+		delete(l.extra.Value, key)
+		return v
+	}
+	l.Name = stringValue(take("LevelName"))
+	l.DataVersion = int32(intValue(take("DataVersion")))
+	if version, ok := l.extra.GetCompound("Version"); ok {
+		l.VersionName, _ = version.GetString("Name")
+	}
+	l.Spawn = BlockPos{
+		X: int(intValue(take("SpawnX"))),
+		Y: int(intValue(take("SpawnY"))),
+		Z: int(intValue(take("SpawnZ"))),
+	}
+	if f, ok := take("SpawnAngle").(*nbt.FloatTag); ok {
+		l.SpawnAngle = f.Value
+	}
+	l.Time = intValue(take("Time"))
+	l.DayTime = intValue(take("DayTime"))
+	l.Weather = Weather{
+		Raining:     intValue(take("raining")) != 0,
+		RainTime:    int32(intValue(take("rainTime"))),
+		Thundering:  intValue(take("thundering")) != 0,
+		ThunderTime: int32(intValue(take("thunderTime"))),
+		ClearTime:   int32(intValue(take("clearWeatherTime"))),
+	}
+	l.GameType = int32(intValue(take("GameType")))
+	l.Difficulty = int8(intValue(take("Difficulty")))
+	l.DifficultyLocked = intValue(take("DifficultyLocked")) != 0
+	l.Hardcore = intValue(take("hardcore")) != 0
+	l.AllowCommands = intValue(take("allowCommands")) != 0
+	l.Initialized = intValue(take("initialized")) != 0
+	l.LastPlayed = intValue(take("LastPlayed"))
+	if rules, ok := take("GameRules").(*nbt.CompoundTag); ok {
+		for name, v := range rules.Value {
+			l.GameRules[name] = stringValue(v)
+		}
+	}
+	l.WorldGen.Dimensions = make(map[string]DimensionSettings)
+	if gen, ok := take("WorldGenSettings").(*nbt.CompoundTag); ok {
+		l.WorldGen.Seed = intValue(gen.Value["seed"])
+		l.WorldGen.GenerateFeatures = intValue(gen.Value["generate_features"]) != 0
+		l.WorldGen.BonusChest = intValue(gen.Value["bonus_chest"]) != 0
+		if dims, ok := gen.GetCompound("dimensions"); ok {
+			for name, v := range dims.Value {
+				dim, ok := v.(*nbt.CompoundTag)
+				if !ok {
+					continue
+				}
+				settings := DimensionSettings{}
+				settings.Type, _ = dim.GetString("type")
+				settings.Generator, _ = dim.GetCompound("generator")
+				l.WorldGen.Dimensions[name] = settings
+			}
+		}
+	}
+	return l
+}
+
+// ToNBT returns the Data compound of level.dat.
+func (l *LevelData) ToNBT() *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	if l.extra != nil {
+		tag = l.extra.Copy().(*nbt.CompoundTag)
+	}
+	version := nbt.NewCompoundTag()
+	if v, ok := tag.GetCompound("Version"); ok {
+		version = v
+	}
+	version.Put("Id", &nbt.IntTag{Value: l.DataVersion})
+	version.Put("Name", &nbt.StringTag{Value: l.VersionName})
+	if _, ok := version.Get("Series"); !ok {
+		version.Put("Series", &nbt.StringTag{Value: "main"})
+		version.Put("Snapshot", &nbt.ByteTag{})
+	}
+	tag.Put("Version", version)
+	tag.Put("version", &nbt.IntTag{Value: anvilVersion})
+	tag.Put("LevelName", &nbt.StringTag{Value: l.Name})
+	tag.Put("DataVersion", &nbt.IntTag{Value: l.DataVersion})
+	tag.Put("SpawnX", &nbt.IntTag{Value: int32(l.Spawn.X)})
+	tag.Put("SpawnY", &nbt.IntTag{Value: int32(l.Spawn.Y)})
+	tag.Put("SpawnZ", &nbt.IntTag{Value: int32(l.Spawn.Z)})
+	tag.Put("SpawnAngle", &nbt.FloatTag{Value: l.SpawnAngle})
+	tag.Put("Time", &nbt.LongTag{Value: l.Time})
+	tag.Put("DayTime", &nbt.LongTag{Value: l.DayTime})
+	tag.Put("raining", boolTag(l.Weather.Raining))
+	tag.Put("rainTime", &nbt.IntTag{Value: l.Weather.RainTime})
+	tag.Put("thundering", boolTag(l.Weather.Thundering))
+	tag.Put("thunderTime", &nbt.IntTag{Value: l.Weather.ThunderTime})
+	tag.Put("clearWeatherTime", &nbt.IntTag{Value: l.Weather.ClearTime})
+	tag.Put("GameType", &nbt.IntTag{Value: l.GameType})
+	tag.Put("Difficulty", &nbt.ByteTag{Value: l.Difficulty})
+	tag.Put("DifficultyLocked", boolTag(l.DifficultyLocked))
+	tag.Put("hardcore", boolTag(l.Hardcore))
+	tag.Put("allowCommands", boolTag(l.AllowCommands))
+	tag.Put("initialized", boolTag(l.Initialized))
+	tag.Put("LastPlayed", &nbt.LongTag{Value: l.LastPlayed})
+
+	rules := nbt.NewCompoundTag()
+	for name, v := range l.GameRules {
+		rules.Put(name, &nbt.StringTag{Value: v})
+	}
+	tag.Put("GameRules", rules)
+
+	gen := nbt.NewCompoundTag()
+	gen.Put("seed", &nbt.LongTag{Value: l.WorldGen.Seed})
+	gen.Put("generate_features", boolTag(l.WorldGen.GenerateFeatures))
+	gen.Put("bonus_chest", boolTag(l.WorldGen.BonusChest))
+	dims := nbt.NewCompoundTag()
+	for name, d := range l.WorldGen.Dimensions {
+		dim := nbt.NewCompoundTag()
+		dim.Put("type", &nbt.StringTag{Value: d.Type})
+		if d.Generator != nil {
+			dim.Put("generator", d.Generator)
+		}
+		dims.Put(name, dim)
+	}
+	gen.Put("dimensions", dims)
+	tag.Put("WorldGenSettings", gen)
+	return tag
+}
+
+// intValue returns the value of any integer tag, or 0.
+func intValue(tag nbt.Tag) int64 {
+	switch t := tag.(type) {
+	case *nbt.ByteTag:
+		return int64(t.Value)
+	case *nbt.ShortTag:
+		return int64(t.Value)
+	case *nbt.IntTag:
+		return int64(t.Value)
+	case *nbt.LongTag:
+		return t.Value
+	}
+	return 0
+}
+
+func stringValue(tag nbt.Tag) string {
+	if s, ok := tag.(*nbt.StringTag); ok {
+		return s.Value
+	}
+	return ""
+}
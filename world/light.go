@@ -0,0 +1,368 @@
+package world
+
+// MaxLight is the brightest light level, that of the open sky.
+const MaxLight = 15
+
+// LightEngine computes the sky light and block light of a dimension's
+// chunks. Light spreads breadth first from the sky and from blocks that
+// give off light, losing at least one level per block and more through
+// blocks with an opacity. Sky light at full strength goes straight down
+// through transparent blocks without losing any.
+//
+// Light crosses into the neighbouring chunks the engine's lookup returns.
+// Those chunks are changed, so the engine must run on the goroutine that
+// owns them.
+type LightEngine struct {
+	reg   Registry
+	sky   bool
+	chunk func(pos ChunkPos) *Chunk
+}
+
+// NewLightEngine returns a light engine for a dimension with or without
+// sky light. chunk returns the loaded chunk at a position, or nil; light
+// does not spread into chunks that are not loaded.
+func NewLightEngine(reg Registry, sky bool, chunk func(pos ChunkPos) *Chunk) *LightEngine {
+	return &LightEngine{reg: reg, sky: sky, chunk: chunk}
+}
+
+// lightNode is a block whose light spreads to or darkens its neighbours.
+type lightNode struct {
+	x, y, z int
+	level   int
+}
+
+// lightDirs are the six neighbours of a block. Down comes first.
+var lightDirs = [6][3]int{{0, -1, 0}, {0, 1, 0}, {-1, 0, 0}, {1, 0, 0}, {0, 0, -1}, {0, 0, 1}}
+
+// lightPass is one run of the engine over one kind of light.
+type lightPass struct {
+	e   *LightEngine
+	sky bool
+	// lone is the only chunk a pass lighting a chunk on its own reaches.
+	lone    *Chunk
+	chunks  map[ChunkPos]*Chunk
+	last    *Chunk
+	changed map[ChunkPos]bool
+	inc     []lightNode
+	dec     []lightNode
+}
+
+func (e *LightEngine) newPass(lone *Chunk) *lightPass {
+	return &lightPass{e: e, lone: lone, chunks: make(map[ChunkPos]*Chunk), changed: make(map[ChunkPos]bool)}
+}
+
+// chunkAt returns the chunk holding block column x, z, or nil.
+func (p *lightPass) chunkAt(x, z int) *Chunk {
+	pos := ChunkPosOf(x, z)
+	if p.last != nil && p.last.Pos == pos {
+		return p.last
+	}
+	var c *Chunk
+	if p.lone != nil {
+		if p.lone.Pos == pos {
+			c = p.lone
+		}
+	} else if cached, ok := p.chunks[pos]; ok {
+		c = cached
+	} else {
+		c = p.e.chunk(pos)
+		p.chunks[pos] = c
+	}
+	if c != nil {
+		p.last = c
+	}
+	return c
+}
+
+func (p *lightPass) layers(c *Chunk) [][]byte {
+	if p.sky {
+		return c.SkyLight
+	}
+	return c.BlockLight
+}
+
+// get returns the light at x, y, z; ok is false outside the loaded chunks
+// and the light sections.
+func (p *lightPass) get(x, y, z int) (level int, ok bool) {
+	c := p.chunkAt(x, z)
+	if c == nil {
+		return 0, false
+	}
+	i := c.lightIndex(y)
+	if i < 0 {
+		return 0, false
+	}
+	return Light(p.layers(c)[i], x&15, y&15, z&15), true
+}
+
+func (p *lightPass) set(x, y, z, level int) {
+	c := p.chunkAt(x, z)
+	c.setLight(p.layers(c), x, y, z, level)
+	p.changed[c.Pos] = true
+}
+
+func (p *lightPass) opacity(x, y, z int) int {
+	return p.e.reg.LightOpacity(p.chunkAt(x, z).Block(x, y, z))
+}
+
+// spread raises the light around the queued blocks until it settles.
+func (p *lightPass) spread() {
+	for i := 0; i < len(p.inc); i++ {
+		n := p.inc[i]
+		if cur, _ := p.get(n.x, n.y, n.z); cur != n.level {
+			// Raised again after it was queued, or darkened.
+			continue
+		}
+		for d, dir := range lightDirs {
+			x, y, z := n.x+dir[0], n.y+dir[1], n.z+dir[2]
+			cur, ok := p.get(x, y, z)
+			if !ok || cur >= n.level {
+				continue
+			}
+			opacity := p.opacity(x, y, z)
+			level := n.level - max(1, opacity)
+			if p.sky && d == 0 && n.level == MaxLight && opacity == 0 {
+				level = MaxLight
+			}
+			if level > cur {
+				p.set(x, y, z, level)
+				p.inc = append(p.inc, lightNode{x, y, z, level})
+			}
+		}
+	}
+	p.inc = p.inc[:0]
+}
+
+// darken removes the light that came from the queued blocks, which were
+// set to 0, and queues the light bordering the darkened blocks and the
+// blocks within that give off light, for spread to fill the gap.
+func (p *lightPass) darken() {
+	for i := 0; i < len(p.dec); i++ {
+		n := p.dec[i]
+		for d, dir := range lightDirs {
+			x, y, z := n.x+dir[0], n.y+dir[1], n.z+dir[2]
+			cur, ok := p.get(x, y, z)
+			if !ok || cur == 0 {
+				continue
+			}
+			if cur >= n.level && !(p.sky && d == 0 && n.level == MaxLight) {
+				// Lit from elsewhere.
+				p.inc = append(p.inc, lightNode{x, y, z, cur})
+				continue
+			}
+			p.set(x, y, z, 0)
+			p.dec = append(p.dec, lightNode{x, y, z, cur})
+			if !p.sky {
+				if e := p.e.reg.LightEmission(p.chunkAt(x, z).Block(x, y, z)); e > 0 {
+					p.set(x, y, z, e)
+					p.inc = append(p.inc, lightNode{x, y, z, e})
+				}
+			}
+		}
+	}
+	p.dec = p.dec[:0]
+}
+
+func (p *lightPass) changedChunks() []ChunkPos {
+	list := make([]ChunkPos, 0, len(p.changed))
+	for pos := range p.changed {
+		list = append(list, pos)
+	}
+	return list
+}
+
+// LightChunk computes the light of a chunk from its own blocks, as if its
+// neighbours were not there, and sets LightOn. Join then lets light cross
+// its borders. It only uses c, so it may run before c is handed to the
+// goroutine owning the loaded chunks.
+func (e *LightEngine) LightChunk(c *Chunk) {
+	p := e.newPass(c)
+	for i := range c.BlockLight {
+		c.BlockLight[i] = nil
+	}
+	for i := range c.SkyLight {
+		c.SkyLight[i] = nil
+	}
+	if e.sky {
+		p.sky = true
+		e.lightSky(p, c)
+	}
+	p.sky = false
+	e.lightBlocks(p, c)
+	c.LightOn = true
+}
+
+// lightSky fills the columns of a chunk with sky light from the top down to
+// the first block that takes some of it, then spreads it sideways and on
+// down from there.
+func (e *LightEngine) lightSky(p *lightPass, c *Chunk) {
+	// Every section has sky light data, so clients are told the dark ones
+	// are dark.
+	top := -1
+	for i, s := range c.Sections {
+		if !s.IsEmpty() {
+			top = i
+		}
+	}
+	for i := range c.SkyLight {
+		c.SkyLight[i] = make([]byte, LightLength)
+		if i > top+1 {
+			// Above every block.
+			for j := range c.SkyLight[i] {
+				c.SkyLight[i][j] = 0xFF
+			}
+		}
+	}
+	if top < 0 {
+		return
+	}
+	bx, bz := int(c.Pos.X)*16, int(c.Pos.Z)*16
+	// bottom holds the lowest block of each column the sky reaches fully.
+	var bottom [16][16]int
+	lowest := c.minY - 16
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			y := c.minY + (top+1)*16 - 1
+			for ; y >= lowest && e.reg.LightOpacity(c.Block(x, y, z)) == 0; y-- {
+				c.SetSkyLight(x, y, z, MaxLight)
+			}
+			bottom[x][z] = y + 1
+		}
+	}
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			b := bottom[x][z]
+			// The fully lit blocks beside darker ones in the neighbouring
+			// columns, and the lowest one, light what the sky misses.
+			high := b + 1
+			for _, dir := range lightDirs[2:] {
+				nx, nz := x+dir[0], z+dir[2]
+				if nx >= 0 && nx < 16 && nz >= 0 && nz < 16 {
+					high = max(high, bottom[nx][nz])
+				}
+			}
+			for y := b; y < high; y++ {
+				p.inc = append(p.inc, lightNode{bx + x, y, bz + z, MaxLight})
+			}
+		}
+	}
+	p.spread()
+}
+
+// lightBlocks lights a chunk from the blocks in it that give off light.
+func (e *LightEngine) lightBlocks(p *lightPass, c *Chunk) {
+	bx, bz := int(c.Pos.X)*16, int(c.Pos.Z)*16
+	for i, s := range c.Sections {
+		if s.IsEmpty() {
+			continue
+		}
+		glows := false
+		s.Blocks.Count(func(v uint32, _ int) {
+			if e.reg.LightEmission(v) > 0 {
+				glows = true
+			}
+		})
+		if !glows {
+			continue
+		}
+		for y := 0; y < 16; y++ {
+			for z := 0; z < 16; z++ {
+				for x := 0; x < 16; x++ {
+					level := e.reg.LightEmission(s.Blocks.Get(s.Blocks.Index(x, y, z)))
+					if level == 0 {
+						continue
+					}
+					wy := c.minY + i*16 + y
+					c.SetBlockLight(x, wy, z, level)
+					p.inc = append(p.inc, lightNode{bx + x, wy, bz + z, level})
+				}
+			}
+		}
+	}
+	p.spread()
+}
+
+// Join lets light cross the borders between a chunk lit by LightChunk and
+// its loaded neighbours, both ways. It returns the chunks whose light
+// changed.
+func (e *LightEngine) Join(c *Chunk) []ChunkPos {
+	p := e.newPass(nil)
+	p.chunks[c.Pos] = c
+	bx, bz := int(c.Pos.X)*16, int(c.Pos.Z)*16
+	low, high := c.minY-16, c.MaxY()+16
+	for _, sky := range e.kinds() {
+		p.sky = sky
+		for _, dir := range lightDirs[2:] {
+			if p.chunkAt(bx+dir[0]*16, bz+dir[2]*16) == nil {
+				continue
+			}
+			for i := 0; i < 16; i++ {
+				// The blocks on each side of the border.
+				x, z := bx+i, bz+i
+				if dir[0] != 0 {
+					x = bx + (dir[0]+1)/2*15
+				} else {
+					z = bz + (dir[2]+1)/2*15
+				}
+				for y := low; y < high; y++ {
+					in, _ := p.get(x, y, z)
+					out, _ := p.get(x+dir[0], y, z+dir[2])
+					switch {
+					case in > out+1:
+						p.inc = append(p.inc, lightNode{x, y, z, in})
+					case out > in+1:
+						p.inc = append(p.inc, lightNode{x + dir[0], y, z + dir[2], out})
+					}
+				}
+			}
+		}
+		p.spread()
+	}
+	return p.changedChunks()
+}
+
+// BlockChanged relights the blocks around x, y, z after the block there
+// changed, in its chunk and the loaded chunks beside it. It returns the
+// chunks whose light changed.
+func (e *LightEngine) BlockChanged(x, y, z int) []ChunkPos {
+	p := e.newPass(nil)
+	c := p.chunkAt(x, z)
+	if c == nil {
+		return nil
+	}
+	state := c.Block(x, y, z)
+	for _, sky := range e.kinds() {
+		p.sky = sky
+		cur, ok := p.get(x, y, z)
+		if !ok {
+			continue
+		}
+		if cur > 0 {
+			p.set(x, y, z, 0)
+			p.dec = append(p.dec, lightNode{x, y, z, cur})
+			p.darken()
+		}
+		if level := e.reg.LightEmission(state); !sky && level > 0 {
+			p.set(x, y, z, level)
+			p.inc = append(p.inc, lightNode{x, y, z, level})
+		}
+		// Light flows back in from the neighbours.
+		for _, dir := range lightDirs {
+			nx, ny, nz := x+dir[0], y+dir[1], z+dir[2]
+			if level, ok := p.get(nx, ny, nz); ok && level > 0 {
+				p.inc = append(p.inc, lightNode{nx, ny, nz, level})
+			}
+		}
+		p.spread()
+	}
+	return p.changedChunks()
+}
+
+// kinds returns the kinds of light the engine computes: sky light, which
+// is true, if the dimension has it, and block light.
+func (e *LightEngine) kinds() []bool {
+	if e.sky {
+		return []bool{true, false}
+	}
+	return []bool{false}
+}
@@ -0,0 +1,697 @@
+package world
+
+import (
+	"container/heap"
+	"container/list"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/nbt"
+	"github.com/Advik-B/Golem/region"
+)
+
+// TicketType says why a ticket keeps chunks loaded.
+type TicketType int
+
+const (
+	// TicketPlayer keeps the chunks around a player loaded.
+	TicketPlayer TicketType = iota
+	// TicketSpawn keeps the spawn chunks loaded.
+	TicketSpawn
+	// TicketForced is a chunk forced loaded with /forceload.
+	TicketForced
+	// TicketPlugin is held by a plugin.
+	TicketPlugin
+)
+
+func (t TicketType) String() string {
+	switch t {
+	case TicketPlayer:
+		return "player"
+	case TicketSpawn:
+		return "spawn"
+	case TicketForced:
+		return "forced"
+	case TicketPlugin:
+		return "plugin"
+	}
+	return fmt.Sprintf("TicketType(%d)", int(t))
+}
+
+// Chunk levels, as in vanilla: a ticket gives its chunk a level and each
+// chunk further away one more. Lower levels do more; chunks above FullLevel
+// are not loaded.
+const (
+	// EntityTickingLevel and below tick entities.
+	EntityTickingLevel = 31
+	// BlockTickingLevel and below tick blocks.
+	BlockTickingLevel = 32
+	// FullLevel and below are loaded.
+	FullLevel = 33
+	// unloadedLevel is the level of chunks no ticket reaches.
+	unloadedLevel = FullLevel + 1
+)
+
+// Ticket keeps the chunks within its radius loaded. Tickets are created
+// with ChunkManager.AddTicket.
+type Ticket struct {
+	Type   TicketType
+	Pos    ChunkPos
+	Radius int
+}
+
+// Level returns the level the ticket gives its own chunk.
+func (t *Ticket) Level() int { return FullLevel - t.Radius }
+
+// Covers reports whether the ticket keeps a chunk loaded.
+func (t *Ticket) Covers(pos ChunkPos) bool {
+	return chebyshev(t.Pos, pos) <= t.Radius
+}
+
+func chebyshev(a, b ChunkPos) int {
+	return int(max(abs(a.X-b.X), abs(a.Z-b.Z)))
+}
+
+func abs(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ManagerConfig configures a ChunkManager.
+type ManagerConfig struct {
+	// Workers is the number of goroutines loading, generating and saving
+	// chunks.
+	Workers int
+	// MaxCached is how many chunks no ticket reaches stay in memory, in case
+	// they are needed again, before the least recently used are unloaded.
+	MaxCached int
+	// AutosaveTicks is the number of ticks between saves of the changed
+	// chunks; 0 saves them only when they are unloaded.
+	AutosaveTicks int
+}
+
+// ManagerStats describes the work of a ChunkManager, for spotting when
+// loading falls behind.
+type ManagerStats struct {
+	// Loaded chunks are within a ticket's reach and Cached ones wait to be
+	// unloaded.
+	Loaded, Cached int
+	// Queued chunks wait for a worker, which is Loading the others. The
+	// longest queue since the manager started is QueuePeak.
+	Queued, Loading, QueuePeak int
+	// PendingSaves are chunks handed to the workers but not written yet.
+	PendingSaves int
+	// Totals since the manager started.
+	Loads, Generated, Saves, Failures int
+	// LoadTime is the average time a worker took to load or generate a
+	// chunk.
+	LoadTime time.Duration
+}
+
+type holderStatus int
+
+const (
+	statusQueued holderStatus = iota
+	statusLoading
+	statusLoaded
+)
+
+// holder tracks a chunk the manager knows about.
+type holder struct {
+	pos    ChunkPos
+	level  int
+	status holderStatus
+	chunk  *Chunk
+	dirty  bool
+	// cached is the holder's element in the LRU list of chunks no ticket
+	// reaches, or nil.
+	cached *list.Element
+	// index is the holder's position in the load queue while queued.
+	index int
+}
+
+// loadQueue orders the chunks waiting for a worker by level, so the chunks
+// closest to a ticket load first.
+type loadQueue []*holder
+
+func (q loadQueue) Len() int           { return len(q) }
+func (q loadQueue) Less(i, j int) bool { return q[i].level < q[j].level }
+func (q loadQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+func (q *loadQueue) Push(x any) {
+	h := x.(*holder)
+	h.index = len(*q)
+	*q = append(*q, h)
+}
+func (q *loadQueue) Pop() any {
+	old := *q
+	h := old[len(old)-1]
+	*q = old[:len(old)-1]
+	h.index = -1
+	return h
+}
+
+type saveJob struct {
+	pos ChunkPos
+	tag *nbt.CompoundTag
+}
+
+type loadResult struct {
+	h     *holder
+	chunk *Chunk
+	dirty bool
+}
+
+// ChunkManager loads the chunks of a dimension that tickets reach. Workers
+// load them from the region files or have the dimension's generator make
+// them in the background and light them, and Tick hands them over, so the
+// game never waits for the disk. Chunks are owned by the goroutine that
+// calls Tick: only it may use them, and it must report changes with
+// MarkDirty or make them with SetBlock. The other methods are safe for
+// concurrent use.
+type ChunkManager struct {
+	dim *Dimension
+	cfg ManagerConfig
+
+	mu   sync.Mutex
+	work *sync.Cond
+	// finished is signalled when a worker hands over a chunk.
+	finished *sync.Cond
+	tickets  map[*Ticket]struct{}
+	holders  map[ChunkPos]*holder
+	queue    loadQueue
+	lru      *list.List
+	done     []loadResult
+	saves    []saveJob
+	// saving holds the chunks being written, which loads must read from
+	// here rather than from the region files.
+	saving  map[ChunkPos]*nbt.CompoundTag
+	events  []chunkEvent
+	closed  bool
+	stats   ManagerStats
+	loadSum time.Duration
+	ticks   int
+	saveErr error
+	light   *LightEngine
+	// relit holds the loaded chunks whose light changed since the last
+	// tick.
+	relit map[ChunkPos]bool
+
+	onLoad        []func(c *Chunk)
+	onUnload      []func(pos ChunkPos)
+	onLightChange []func(c *Chunk)
+	workers       sync.WaitGroup
+}
+
+type chunkEvent struct {
+	pos    ChunkPos
+	loaded bool
+}
+
+// NewChunkManager starts the workers of a chunk manager for d.
+func NewChunkManager(d *Dimension, cfg ManagerConfig) *ChunkManager {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	// A negative cache would have Tick unload chunks that are not there.
+	cfg.MaxCached = max(cfg.MaxCached, 0)
+	m := &ChunkManager{
+		dim:     d,
+		cfg:     cfg,
+		tickets: make(map[*Ticket]struct{}),
+		holders: make(map[ChunkPos]*holder),
+		lru:     list.New(),
+		saving:  make(map[ChunkPos]*nbt.CompoundTag),
+		relit:   make(map[ChunkPos]bool),
+	}
+	m.light = NewLightEngine(d.reg, d.Type.SkyLight, m.inMemory)
+	m.work = sync.NewCond(&m.mu)
+	m.finished = sync.NewCond(&m.mu)
+	m.workers.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go m.worker()
+	}
+	return m
+}
+
+// Dimension returns the dimension whose chunks the manager loads.
+func (m *ChunkManager) Dimension() *Dimension { return m.dim }
+
+// OnLoad registers a function that Tick calls with every chunk that becomes
+// loaded.
+func (m *ChunkManager) OnLoad(fn func(c *Chunk)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLoad = append(m.onLoad, fn)
+}
+
+// OnUnload registers a function that Tick calls with every chunk that no
+// ticket reaches any more.
+func (m *ChunkManager) OnUnload(fn func(pos ChunkPos)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onUnload = append(m.onUnload, fn)
+}
+
+// OnLightChange registers a function that Tick calls with every loaded
+// chunk whose light changed since the last tick, because a block changed or
+// light crossed in from a chunk that loaded beside it.
+func (m *ChunkManager) OnLightChange(fn func(c *Chunk)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLightChange = append(m.onLightChange, fn)
+}
+
+// AddTicket keeps the chunks within radius of pos loaded until the ticket
+// is removed.
+func (m *ChunkManager) AddTicket(typ TicketType, pos ChunkPos, radius int) *Ticket {
+	t := &Ticket{Type: typ, Pos: pos, Radius: max(radius, 0)}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tickets[t] = struct{}{}
+	m.updateLevels(t.Pos, t.Radius)
+	return t
+}
+
+// RemoveTicket removes a ticket. Its chunks stay loaded while other tickets
+// reach them.
+func (m *ChunkManager) RemoveTicket(t *Ticket) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.tickets[t]; !ok {
+		return
+	}
+	delete(m.tickets, t)
+	m.updateLevels(t.Pos, t.Radius)
+}
+
+// MoveTicket moves a ticket, such as a player's, to another chunk.
+func (m *ChunkManager) MoveTicket(t *Ticket, pos ChunkPos) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.tickets[t]; !ok || t.Pos == pos {
+		return
+	}
+	old := t.Pos
+	t.Pos = pos
+	m.updateLevels(old, t.Radius)
+	m.updateLevels(pos, t.Radius)
+}
+
+// Level returns the level of a chunk.
+func (m *ChunkManager) Level(pos ChunkPos) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.level(pos)
+}
+
+func (m *ChunkManager) level(pos ChunkPos) int {
+	level := unloadedLevel
+	for t := range m.tickets {
+		if t.Covers(pos) {
+			level = min(level, t.Level()+chebyshev(t.Pos, pos))
+		}
+	}
+	return level
+}
+
+// updateLevels recomputes the levels of the chunks within radius of center
+// and queues or releases the chunks whose level crossed FullLevel.
+func (m *ChunkManager) updateLevels(center ChunkPos, radius int) {
+	r := int32(radius)
+	for x := center.X - r; x <= center.X+r; x++ {
+		for z := center.Z - r; z <= center.Z+r; z++ {
+			pos := ChunkPos{X: x, Z: z}
+			level := m.level(pos)
+			h := m.holders[pos]
+			switch {
+			case h == nil && level <= FullLevel:
+				h = &holder{pos: pos, level: level, status: statusQueued}
+				m.holders[pos] = h
+				m.enqueue(h)
+			case h == nil:
+			default:
+				m.setLevel(h, level)
+			}
+		}
+	}
+}
+
+func (m *ChunkManager) setLevel(h *holder, level int) {
+	wasFull := h.level <= FullLevel
+	h.level = level
+	if h.status == statusQueued {
+		if level <= FullLevel {
+			heap.Fix(&m.queue, h.index)
+		} else {
+			// Nobody needs it any more; it was never loaded.
+			heap.Remove(&m.queue, h.index)
+			delete(m.holders, h.pos)
+		}
+		return
+	}
+	if h.status != statusLoaded || wasFull == (level <= FullLevel) {
+		return
+	}
+	if level <= FullLevel {
+		m.lru.Remove(h.cached)
+		h.cached = nil
+		m.events = append(m.events, chunkEvent{h.pos, true})
+	} else {
+		h.cached = m.lru.PushFront(h)
+		m.events = append(m.events, chunkEvent{h.pos, false})
+	}
+}
+
+func (m *ChunkManager) enqueue(h *holder) {
+	heap.Push(&m.queue, h)
+	m.stats.QueuePeak = max(m.stats.QueuePeak, len(m.queue))
+	m.work.Signal()
+}
+
+// Chunk returns a loaded chunk, or nil.
+func (m *ChunkManager) Chunk(pos ChunkPos) *Chunk {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h := m.holders[pos]; h != nil && h.status == statusLoaded && h.level <= FullLevel {
+		return h.chunk
+	}
+	return nil
+}
+
+// Loaded returns the positions of the loaded chunks.
+func (m *ChunkManager) Loaded() []ChunkPos {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var list []ChunkPos
+	for pos, h := range m.holders {
+		if h.status == statusLoaded && h.level <= FullLevel {
+			list = append(list, pos)
+		}
+	}
+	return list
+}
+
+// inMemory returns a chunk that was handed over, loaded or cached, for
+// light to spread into.
+func (m *ChunkManager) inMemory(pos ChunkPos) *Chunk {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h := m.holders[pos]; h != nil && h.status == statusLoaded {
+		return h.chunk
+	}
+	return nil
+}
+
+// SetBlock sets the block state at x, y, z in a loaded chunk, relights the
+// blocks around it and marks the chunks that changed dirty. It returns the
+// old state, and false if the chunk is not loaded. Like the chunks, it
+// belongs to the goroutine that calls Tick.
+func (m *ChunkManager) SetBlock(x, y, z int, state uint32) (uint32, bool) {
+	c := m.Chunk(ChunkPosOf(x, z))
+	if c == nil {
+		return Air, false
+	}
+	old := c.SetBlock(x, y, z, state)
+	if old == state {
+		return old, true
+	}
+	relit := m.light.BlockChanged(x, y, z)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.holders[c.Pos].dirty = true
+	m.markRelit(relit)
+	return old, true
+}
+
+// markRelit marks chunks whose light changed dirty and queues them for the
+// OnLightChange functions.
+func (m *ChunkManager) markRelit(relit []ChunkPos) {
+	for _, pos := range relit {
+		if h := m.holders[pos]; h != nil && h.status == statusLoaded {
+			h.dirty = true
+			m.relit[pos] = true
+		}
+	}
+}
+
+// MarkDirty records that a loaded chunk changed and must be saved.
+func (m *ChunkManager) MarkDirty(pos ChunkPos) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h := m.holders[pos]; h != nil && h.status == statusLoaded {
+		h.dirty = true
+	}
+}
+
+// Stats returns what the manager is doing.
+func (m *ChunkManager) Stats() ManagerStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stats
+	s.Loaded, s.Cached, s.Loading = 0, m.lru.Len(), 0
+	for _, h := range m.holders {
+		switch {
+		case h.status == statusLoading:
+			s.Loading++
+		case h.status == statusLoaded && h.level <= FullLevel:
+			s.Loaded++
+		}
+	}
+	s.Queued = len(m.queue)
+	s.PendingSaves = len(m.saves) + len(m.saving)
+	if s.Loads+s.Generated > 0 {
+		s.LoadTime = m.loadSum / time.Duration(s.Loads+s.Generated)
+	}
+	return s
+}
+
+// Tick hands over the chunks the workers finished, joining their light with
+// their neighbours', unloads the least recently used chunks beyond
+// MaxCached and autosaves. It calls the OnLoad, OnUnload and OnLightChange
+// functions, and must be called from the goroutine that owns the chunks.
+func (m *ChunkManager) Tick() {
+	m.mu.Lock()
+	handed := make([]*Chunk, 0, len(m.done))
+	for _, r := range m.done {
+		handed = append(handed, r.chunk)
+		r.h.chunk, r.h.dirty, r.h.status = r.chunk, r.dirty, statusLoaded
+		if r.h.level <= FullLevel {
+			m.events = append(m.events, chunkEvent{r.h.pos, true})
+		} else {
+			r.h.cached = m.lru.PushFront(r.h)
+		}
+	}
+	m.done = m.done[:0]
+
+	for m.lru.Len() > m.cfg.MaxCached {
+		h := m.lru.Remove(m.lru.Back()).(*holder)
+		delete(m.holders, h.pos)
+		if h.dirty {
+			m.save(h)
+		}
+	}
+
+	m.mu.Unlock()
+
+	for _, c := range handed {
+		relit := m.light.Join(c)
+		m.mu.Lock()
+		m.markRelit(relit)
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	m.ticks++
+	if m.cfg.AutosaveTicks > 0 && m.ticks%m.cfg.AutosaveTicks == 0 {
+		m.saveDirty()
+	}
+
+	events := m.events
+	m.events = nil
+	relit := m.relit
+	m.relit = make(map[ChunkPos]bool)
+	onLoad, onUnload, onLightChange := m.onLoad, m.onUnload, m.onLightChange
+	m.mu.Unlock()
+
+	loaded := make(map[ChunkPos]bool)
+	for _, e := range events {
+		if e.loaded {
+			loaded[e.pos] = true
+			c := m.Chunk(e.pos)
+			if c == nil {
+				// Released again before it was handed over.
+				continue
+			}
+			for _, fn := range onLoad {
+				fn(c)
+			}
+		} else {
+			for _, fn := range onUnload {
+				fn(e.pos)
+			}
+		}
+	}
+	for pos := range relit {
+		c := m.Chunk(pos)
+		if c == nil || loaded[pos] {
+			// Not loaded, or its light was sent with it.
+			continue
+		}
+		for _, fn := range onLightChange {
+			fn(c)
+		}
+	}
+}
+
+// TickUntilLoaded waits for every chunk the tickets reach to be loaded,
+// ticking as they come in, such as to prepare the spawn chunks before
+// players join.
+func (m *ChunkManager) TickUntilLoaded() {
+	for {
+		m.mu.Lock()
+		for len(m.done) == 0 && !m.closed && (len(m.queue) > 0 || m.loading()) {
+			m.finished.Wait()
+		}
+		idle := len(m.done) == 0
+		m.mu.Unlock()
+		if idle {
+			return
+		}
+		m.Tick()
+	}
+}
+
+func (m *ChunkManager) loading() bool {
+	for _, h := range m.holders {
+		if h.status == statusLoading {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveAll hands every changed chunk to the workers to be saved.
+func (m *ChunkManager) SaveAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saveDirty()
+}
+
+func (m *ChunkManager) saveDirty() {
+	for _, h := range m.holders {
+		if h.status == statusLoaded && h.dirty {
+			m.save(h)
+		}
+	}
+}
+
+// save serializes a chunk on the calling goroutine, which owns it, and
+// queues the write.
+func (m *ChunkManager) save(h *holder) {
+	tag := h.chunk.ToNBT(DataVersion)
+	h.dirty = false
+	m.saving[h.pos] = tag
+	m.saves = append(m.saves, saveJob{h.pos, tag})
+	m.work.Signal()
+}
+
+// Close saves every changed chunk, waits for the workers to write them and
+// stops them. It returns the first error a save ran into.
+func (m *ChunkManager) Close() error {
+	m.mu.Lock()
+	m.saveDirty()
+	m.closed = true
+	m.work.Broadcast()
+	m.finished.Broadcast()
+	m.mu.Unlock()
+	m.workers.Wait()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveErr
+}
+
+func (m *ChunkManager) worker() {
+	defer m.workers.Done()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		switch {
+		case len(m.saves) > 0:
+			// Saves go first, so chunks leave memory.
+			job := m.saves[0]
+			m.saves = m.saves[1:]
+			m.mu.Unlock()
+			err := m.dim.regions.WriteChunk(int(job.pos.X), int(job.pos.Z), job.tag)
+			m.mu.Lock()
+			if m.saving[job.pos] == job.tag {
+				delete(m.saving, job.pos)
+			}
+			if err != nil {
+				m.stats.Failures++
+				if m.saveErr == nil {
+					m.saveErr = err
+				}
+				log.Printf("world: saving chunk %d, %d in %s: %v", job.pos.X, job.pos.Z, m.dim.Name, err)
+			} else {
+				m.stats.Saves++
+			}
+		case len(m.queue) > 0 && !m.closed:
+			h := heap.Pop(&m.queue).(*holder)
+			h.status = statusLoading
+			pending := m.saving[h.pos]
+			m.mu.Unlock()
+			start := time.Now()
+			chunk, generated, err := m.load(h.pos, pending)
+			relit := !chunk.LightOn
+			if relit {
+				m.light.LightChunk(chunk)
+			}
+			m.mu.Lock()
+			m.loadSum += time.Since(start)
+			if generated {
+				m.stats.Generated++
+			} else {
+				m.stats.Loads++
+			}
+			if err != nil {
+				m.stats.Failures++
+				log.Printf("world: loading chunk %d, %d in %s: %v", h.pos.X, h.pos.Z, m.dim.Name, err)
+			}
+			m.done = append(m.done, loadResult{h: h, chunk: chunk, dirty: generated || relit})
+			m.finished.Broadcast()
+		case m.closed:
+			return
+		default:
+			m.work.Wait()
+		}
+	}
+}
+
+// load reads a chunk, from a pending save if there is one, or generates it.
+// Like vanilla, a chunk that cannot be read is generated anew.
+func (m *ChunkManager) load(pos ChunkPos, pending *nbt.CompoundTag) (c *Chunk, generated bool, err error) {
+	if pending != nil {
+		c, err = FromNBT(m.dim.reg, pending, m.dim.Type.MinY, m.dim.Type.Height)
+	} else {
+		c, err = m.dim.LoadChunk(pos)
+	}
+	if err == nil {
+		return c, false, nil
+	}
+	if errors.Is(err, region.ErrNotFound) {
+		err = nil
+	}
+	c, genErr := m.dim.GenerateChunk(pos)
+	if genErr != nil {
+		c = m.dim.NewChunk(pos)
+	}
+	return c, true, errors.Join(err, genErr)
+}
@@ -0,0 +1,281 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/Advik-B/Golem/nbt"
+)
+
+// Tags of a saved chunk that Chunk models; every other tag goes to Extra.
+var modelledTags = map[string]bool{
+	"DataVersion": true, "xPos": true, "yPos": true, "zPos": true,
+	"Status": true, "LastUpdate": true, "InhabitedTime": true, "isLightOn": true,
+	"sections": true, "Heightmaps": true, "block_entities": true,
+}
+
+// ToNBT returns the chunk in the form region files store, stamped with
+// dataVersion, the data version of the game that saves it.
+func (c *Chunk) ToNBT(dataVersion int32) *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	if c.Extra != nil {
+		for k, v := range c.Extra.Value {
+			tag.Put(k, v)
+		}
+	}
+	tag.Put("DataVersion", &nbt.IntTag{Value: dataVersion})
+	tag.Put("xPos", &nbt.IntTag{Value: c.Pos.X})
+	tag.Put("yPos", &nbt.IntTag{Value: int32(c.MinSection())})
+	tag.Put("zPos", &nbt.IntTag{Value: c.Pos.Z})
+	tag.Put("Status", &nbt.StringTag{Value: c.Status})
+	tag.Put("LastUpdate", &nbt.LongTag{Value: c.LastUpdate})
+	tag.Put("InhabitedTime", &nbt.LongTag{Value: c.InhabitedTime})
+	tag.Put("isLightOn", boolTag(c.LightOn))
+
+	sections := &nbt.ListTag{Type: nbt.TagCompound}
+	for i := range c.SkyLight {
+		y := c.MinSection() - 1 + i
+		st := nbt.NewCompoundTag()
+		st.Put("Y", &nbt.ByteTag{Value: int8(y)})
+		if i > 0 && i <= len(c.Sections) {
+			s := c.Sections[i-1]
+			st.Put("block_states", c.blockStatesToNBT(s.Blocks))
+			st.Put("biomes", c.biomesToNBT(s.Biomes))
+		}
+		if c.BlockLight[i] != nil {
+			st.Put("BlockLight", &nbt.ByteArrayTag{Value: toInt8s(c.BlockLight[i])})
+		}
+		if c.SkyLight[i] != nil {
+			st.Put("SkyLight", &nbt.ByteArrayTag{Value: toInt8s(c.SkyLight[i])})
+		}
+		// Vanilla leaves out the light-only sections that have no light.
+		if len(st.Value) > 1 {
+			sections.Add(st)
+		}
+	}
+	tag.Put("sections", sections)
+
+	heightmaps := nbt.NewCompoundTag()
+	for t, h := range c.Heightmaps {
+		heightmaps.Put(string(t), &nbt.LongArrayTag{Value: toInt64s(h.data.Longs())})
+	}
+	tag.Put("Heightmaps", heightmaps)
+
+	blockEntities := &nbt.ListTag{Type: nbt.TagCompound}
+	for _, be := range c.BlockEntities {
+		blockEntities.Add(be)
+	}
+	tag.Put("block_entities", blockEntities)
+	return tag
+}
+
+func (c *Chunk) blockStatesToNBT(container *PalettedContainer) *nbt.CompoundTag {
+	palette, data := container.Compact()
+	list := &nbt.ListTag{Type: nbt.TagCompound}
+	for _, id := range palette {
+		state, ok := c.reg.BlockState(id)
+		if !ok {
+			state, _ = c.reg.BlockState(Air)
+		}
+		list.Add(state)
+	}
+	return containerTag(list, data)
+}
+
+func (c *Chunk) biomesToNBT(container *PalettedContainer) *nbt.CompoundTag {
+	palette, data := container.Compact()
+	list := &nbt.ListTag{Type: nbt.TagString}
+	for _, id := range palette {
+		name, ok := c.reg.Biome(id)
+		if !ok {
+			name = DefaultBiome
+		}
+		list.Add(&nbt.StringTag{Value: name})
+	}
+	return containerTag(list, data)
+}
+
+func containerTag(palette *nbt.ListTag, data *BitStorage) *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	tag.Put("palette", palette)
+	if data != nil {
+		tag.Put("data", &nbt.LongArrayTag{Value: toInt64s(data.Longs())})
+	}
+	return tag
+}
+
+// FromNBT reads a chunk saved in a region file into a chunk spanning height
+// blocks from minY. Sections outside that range are dropped. As in vanilla,
+// unknown block states load as air and unknown biomes as DefaultBiome.
+func FromNBT(reg Registry, tag *nbt.CompoundTag, minY, height int) (*Chunk, error) {
+	x, okX := tag.GetInt("xPos")
+	z, okZ := tag.GetInt("zPos")
+	if !okX || !okZ {
+		return nil, fmt.Errorf("world: chunk without a position")
+	}
+	c := NewChunk(reg, ChunkPos{X: x, Z: z}, minY, height)
+	c.Extra = nbt.NewCompoundTag()
+	for k, v := range tag.Value {
+		if !modelledTags[k] {
+			c.Extra.Put(k, v)
+		}
+	}
+	if status, ok := tag.GetString("Status"); ok {
+		c.Status = status
+	}
+	c.LastUpdate = longValue(tag, "LastUpdate")
+	c.InhabitedTime = longValue(tag, "InhabitedTime")
+	if b, ok := tag.Value["isLightOn"].(*nbt.ByteTag); ok {
+		c.LightOn = b.Value != 0
+	}
+
+	if sections, ok := tag.GetList("sections"); ok {
+		for _, v := range sections.Value {
+			st, ok := v.(*nbt.CompoundTag)
+			if !ok {
+				continue
+			}
+			y, ok := st.Value["Y"].(*nbt.ByteTag)
+			if !ok {
+				return nil, fmt.Errorf("world: chunk %d, %d has a section without Y", x, z)
+			}
+			if err := c.loadSection(int(y.Value), st); err != nil {
+				return nil, fmt.Errorf("world: chunk %d, %d section %d: %w", x, z, y.Value, err)
+			}
+		}
+	}
+
+	if heightmaps, ok := tag.GetCompound("Heightmaps"); ok {
+		for name, v := range heightmaps.Value {
+			arr, ok := v.(*nbt.LongArrayTag)
+			if !ok {
+				continue
+			}
+			h := newHeightmap(minY, height)
+			data, err := NewBitStorageFrom(h.data.Bits(), 256, toUint64s(arr.Value))
+			if err != nil {
+				// Vanilla recomputes a heightmap of the wrong size.
+				continue
+			}
+			h.data = data
+			c.Heightmaps[HeightmapType(name)] = h
+		}
+	}
+
+	if blockEntities, ok := tag.GetList("block_entities"); ok {
+		for _, v := range blockEntities.Value {
+			if be, ok := v.(*nbt.CompoundTag); ok {
+				if err := c.SetBlockEntity(be); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return c, nil
+}
+
+// loadSection loads the section at section Y y.
+func (c *Chunk) loadSection(y int, st *nbt.CompoundTag) error {
+	li := y - c.MinSection() + 1
+	if li < 0 || li >= len(c.SkyLight) {
+		return nil
+	}
+	if light, err := lightArray(st, "BlockLight"); err != nil {
+		return err
+	} else if light != nil {
+		c.BlockLight[li] = light
+	}
+	if light, err := lightArray(st, "SkyLight"); err != nil {
+		return err
+	} else if light != nil {
+		c.SkyLight[li] = light
+	}
+	if li == 0 || li > len(c.Sections) {
+		return nil
+	}
+	s := c.Sections[li-1]
+	if states, ok := st.GetCompound("block_states"); ok {
+		container, err := c.readContainer(BlockStates, states)
+		if err != nil {
+			return fmt.Errorf("block states: %w", err)
+		}
+		s.Blocks = container
+		s.recount(c.reg)
+	}
+	if biomes, ok := st.GetCompound("biomes"); ok {
+		container, err := c.readContainer(Biomes, biomes)
+		if err != nil {
+			return fmt.Errorf("biomes: %w", err)
+		}
+		s.Biomes = container
+	}
+	return nil
+}
+
+func (c *Chunk) readContainer(kind ContainerKind, tag *nbt.CompoundTag) (*PalettedContainer, error) {
+	list, ok := tag.GetList("palette")
+	if !ok || len(list.Value) == 0 {
+		return nil, fmt.Errorf("missing palette")
+	}
+	palette := make([]uint32, len(list.Value))
+	defaultBiome, _ := c.reg.BiomeID(DefaultBiome)
+	for i, v := range list.Value {
+		switch entry := v.(type) {
+		case *nbt.CompoundTag:
+			id, ok := c.reg.BlockStateID(entry)
+			if !ok {
+				id = Air
+			}
+			palette[i] = id
+		case *nbt.StringTag:
+			id, ok := c.reg.BiomeID(entry.Value)
+			if !ok {
+				id = defaultBiome
+			}
+			palette[i] = id
+		default:
+			return nil, fmt.Errorf("invalid palette entry %s", v)
+		}
+	}
+	var data []uint64
+	if arr, ok := tag.Value["data"].(*nbt.LongArrayTag); ok {
+		data = toUint64s(arr.Value)
+	}
+	return newPalettedContainerFrom(kind, palette, data)
+}
+
+func lightArray(st *nbt.CompoundTag, key string) ([]byte, error) {
+	arr, ok := st.Value[key].(*nbt.ByteArrayTag)
+	if !ok {
+		return nil, nil
+	}
+	if len(arr.Value) != LightLength {
+		return nil, fmt.Errorf("%s has %d bytes", key, len(arr.Value))
+	}
+	out := make([]byte, LightLength)
+	for i, v := range arr.Value {
+		out[i] = byte(v)
+	}
+	return out, nil
+}
+
+func longValue(tag *nbt.CompoundTag, key string) int64 {
+	if l, ok := tag.Value[key].(*nbt.LongTag); ok {
+		return l.Value
+	}
+	return 0
+}
+
+func boolTag(b bool) *nbt.ByteTag {
+	if b {
+		return &nbt.ByteTag{Value: 1}
+	}
+	return &nbt.ByteTag{}
+}
+
+func toInt8s(b []byte) []int8 {
+	out := make([]int8, len(b))
+	for i, v := range b {
+		out[i] = int8(v)
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package world
+
+import (
+	"github.com/Advik-B/Golem/nbt"
+	"github.com/Advik-B/Golem/protocol"
+)
+
+// NetworkIDs translates the IDs a chunk holds into those of a client's
+// protocol version.
+type NetworkIDs struct {
+	// BlockState and Biome map global IDs to network IDs; nil keeps them.
+	BlockState func(id uint32) uint32
+	Biome      func(id uint32) uint32
+	// BlockStateBits and BiomeBits are the bits per entry of the direct
+	// palettes, the base-2 logarithm of the registry sizes rounded up.
+	BlockStateBits int
+	BiomeBits      int
+	// BlockEntityType returns the network ID of a block entity type. Block
+	// entities of types it does not know are not sent.
+	BlockEntityType func(name string) (int32, bool)
+}
+
+// ChunkDataPacket returns the fields of a Chunk Data and Update Light packet
+// for the chunk. The client expects as many sections as its dimension type
+// is high, so the chunk must span the same height.
+func (c *Chunk) ChunkDataPacket(ids *NetworkIDs) [][]byte {
+	heightmaps := nbt.NewCompoundTag()
+	for _, t := range ClientHeightmaps {
+		if h := c.Heightmaps[t]; h != nil {
+			heightmaps.Put(string(t), &nbt.LongArrayTag{Value: toInt64s(h.data.Longs())})
+		}
+	}
+
+	var sections []byte
+	for _, s := range c.Sections {
+		sections = append(sections, protocol.WriteShort(int16(s.blockCount))...)
+		sections = appendContainer(sections, s.Blocks, ids.BlockStateBits, ids.BlockState)
+		sections = appendContainer(sections, s.Biomes, ids.BiomeBits, ids.Biome)
+	}
+
+	var blockEntities []byte
+	count := 0
+	for pos, tag := range c.BlockEntities {
+		id, _ := tag.GetString("id")
+		typ, ok := ids.BlockEntityType(id)
+		if !ok {
+			continue
+		}
+		// The client knows the type and position already.
+		data := nbt.NewCompoundTag()
+		for k, v := range tag.Value {
+			switch k {
+			case "id", "x", "y", "z", "keepPacked":
+			default:
+				data.Put(k, v)
+			}
+		}
+		blockEntities = append(blockEntities, byte(pos.X&15<<4|pos.Z&15))
+		blockEntities = append(blockEntities, protocol.WriteShort(int16(pos.Y))...)
+		blockEntities = append(blockEntities, protocol.WriteVarInt(int(typ))...)
+		blockEntities = append(blockEntities, protocol.WriteNBT(data)...)
+		count++
+	}
+
+	fields := [][]byte{
+		protocol.WriteInt(int(c.Pos.X)), protocol.WriteInt(int(c.Pos.Z)),
+		protocol.WriteNBT(heightmaps),
+		protocol.WriteByteArray(sections),
+		protocol.WriteVarInt(count), blockEntities,
+	}
+	return append(fields, c.lightData()...)
+}
+
+// UpdateLightPacket returns the fields of an Update Light packet, which
+// resends the chunk's light.
+func (c *Chunk) UpdateLightPacket() [][]byte {
+	fields := [][]byte{protocol.WriteVarInt(int(c.Pos.X)), protocol.WriteVarInt(int(c.Pos.Z))}
+	return append(fields, c.lightData()...)
+}
+
+// lightData encodes the light arrays as both light packets end: a mask of
+// the sections with light, one of the sections known to be dark, and the
+// arrays of the first. Sections without a light array are in neither.
+func (c *Chunk) lightData() [][]byte {
+	sky, emptySky, skyArrays := lightLayers(c.SkyLight)
+	block, emptyBlock, blockArrays := lightLayers(c.BlockLight)
+	return [][]byte{
+		writeBitSet(sky), writeBitSet(block),
+		writeBitSet(emptySky), writeBitSet(emptyBlock),
+		skyArrays, blockArrays,
+	}
+}
+
+func lightLayers(layers [][]byte) (mask, empty []uint64, arrays []byte) {
+	mask = make([]uint64, (len(layers)+63)/64)
+	empty = make([]uint64, len(mask))
+	count := 0
+	for i, l := range layers {
+		if l == nil {
+			continue
+		}
+		if isZero(l) {
+			empty[i/64] |= 1 << (i % 64)
+			continue
+		}
+		mask[i/64] |= 1 << (i % 64)
+		arrays = append(arrays, protocol.WriteByteArray(l)...)
+		count++
+	}
+	return mask, empty, append(protocol.WriteVarInt(count), arrays...)
+}
+
+// writeBitSet encodes a BitSet as a VarInt-prefixed array of longs, without
+// the trailing zero longs, as Java's BitSet.toLongArray leaves them out.
+func writeBitSet(words []uint64) []byte {
+	for len(words) > 0 && words[len(words)-1] == 0 {
+		words = words[:len(words)-1]
+	}
+	out := protocol.WriteVarInt(len(words))
+	for _, w := range words {
+		out = append(out, protocol.WriteLong(int64(w))...)
+	}
+	return out
+}
+
+// appendContainer appends the wire form of a paletted container: the bits
+// per entry, the palette unless it is direct, and the packed entries. A
+// palette too large for the indirect form is replaced by network IDs packed
+// with globalBits.
+func appendContainer(buf []byte, c *PalettedContainer, globalBits int, toNetwork func(uint32) uint32) []byte {
+	if toNetwork == nil {
+		toNetwork = func(id uint32) uint32 { return id }
+	}
+	palette, data := c.Compact()
+	bits := c.kind.Bits(len(palette))
+	switch {
+	case bits == 0:
+		buf = append(buf, 0)
+		buf = append(buf, protocol.WriteVarInt(int(toNetwork(palette[0])))...)
+		return append(buf, protocol.WriteVarInt(0)...)
+	case bits > c.kind.MaxIndirectBits():
+		direct := NewBitStorage(globalBits, c.kind.Size())
+		for i := 0; i < c.kind.Size(); i++ {
+			direct.Set(i, toNetwork(palette[data.Get(i)]))
+		}
+		data = direct
+		buf = append(buf, byte(globalBits))
+	default:
+		buf = append(buf, byte(bits))
+		buf = append(buf, protocol.WriteVarInt(len(palette))...)
+		for _, id := range palette {
+			buf = append(buf, protocol.WriteVarInt(int(toNetwork(id)))...)
+		}
+	}
+	buf = append(buf, protocol.WriteVarInt(len(data.Longs()))...)
+	for _, l := range data.Longs() {
+		buf = append(buf, protocol.WriteLong(int64(l))...)
+	}
+	return buf
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package world
+
+import "math"
+
+// random is a SplitMix64 generator. Terrain only depends on the seed and
+// this generator, so it is the same on every platform and Go version.
+type random struct {
+	state uint64
+}
+
+func newRandom(seed int64) *random {
+	return &random{state: uint64(seed)}
+}
+
+// chunkRandom returns the generator for decorating one chunk, so chunks
+// generated in any order come out the same.
+func chunkRandom(seed int64, pos ChunkPos, salt int64) *random {
+	return newRandom(seed ^ int64(pos.X)*341873128712 ^ int64(pos.Z)*132897987541 ^ salt*0x5DEECE66D)
+}
+
+func (r *random) next() uint64 {
+	r.state += 0x9E3779B97F4A7C15
+	z := r.state
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	return z ^ z>>31
+}
+
+// intn returns a number in [0, n).
+func (r *random) intn(n int) int { return int(r.next() % uint64(n)) }
+
+// float returns a number in [0, 1).
+func (r *random) float() float64 { return float64(r.next()>>11) / (1 << 53) }
+
+// perlinNoise is Ken Perlin's improved noise with a seeded permutation and
+// offset, like vanilla's ImprovedNoise. It returns values in about [-1, 1].
+type perlinNoise struct {
+	perm       [512]uint8
+	xo, yo, zo float64
+}
+
+func newPerlinNoise(r *random) *perlinNoise {
+	n := &perlinNoise{xo: r.float() * 256, yo: r.float() * 256, zo: r.float() * 256}
+	for i := 0; i < 256; i++ {
+		n.perm[i] = uint8(i)
+	}
+	for i := 255; i > 0; i-- {
+		j := r.intn(i + 1)
+		n.perm[i], n.perm[j] = n.perm[j], n.perm[i]
+	}
+	copy(n.perm[256:], n.perm[:256])
+	return n
+}
+
+func (n *perlinNoise) noise(x, y, z float64) float64 {
+	x, y, z = x+n.xo, y+n.yo, z+n.zo
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	xi, yi, zi := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+	p := &n.perm
+	a := int(p[xi]) + yi
+	aa, ab := int(p[a])+zi, int(p[a+1])+zi
+	b := int(p[xi+1]) + yi
+	ba, bb := int(p[b])+zi, int(p[b+1])+zi
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(p[aa], x, y, z), grad(p[ba], x-1, y, z)),
+			lerp(u, grad(p[ab], x, y-1, z), grad(p[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad(p[aa+1], x, y, z-1), grad(p[ba+1], x-1, y, z-1)),
+			lerp(u, grad(p[ab+1], x, y-1, z-1), grad(p[bb+1], x-1, y-1, z-1))))
+}
+
+func fade(t float64) float64 { return t * t * t * (t*(t*6-15) + 10) }
+
+func lerp(t, a, b float64) float64 { return a + t*(b-a) }
+
+// grad returns the dot product of one of the 12 edge gradients with x, y, z.
+func grad(hash uint8, x, y, z float64) float64 {
+	h := hash & 15
+	u, v := x, y
+	if h >= 8 {
+		u = y
+	}
+	if h >= 4 {
+		if h == 12 || h == 14 {
+			v = x
+		} else {
+			v = z
+		}
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// octaveNoise sums octaves of Perlin noise, each at twice the frequency and
+// half the amplitude of the one before, scaled back into about [-1, 1].
+type octaveNoise struct {
+	octaves []*perlinNoise
+	norm    float64
+}
+
+func newOctaveNoise(r *random, octaves int) *octaveNoise {
+	n := &octaveNoise{}
+	amplitude := 1.0
+	for i := 0; i < octaves; i++ {
+		n.octaves = append(n.octaves, newPerlinNoise(r))
+		n.norm += amplitude
+		amplitude /= 2
+	}
+	return n
+}
+
+func (n *octaveNoise) noise(x, y, z float64) float64 {
+	sum, frequency, amplitude := 0.0, 1.0, 1.0
+	for _, o := range n.octaves {
+		sum += o.noise(x*frequency, y*frequency, z*frequency) * amplitude
+		frequency *= 2
+		amplitude /= 2
+	}
+	return sum / n.norm
+}
+
+// noise2 samples the noise in the plane, for values that vary only across
+// the map.
+func (n *octaveNoise) noise2(x, z float64) float64 { return n.noise(x, 0, z) }
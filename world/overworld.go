@@ -0,0 +1,417 @@
+package world
+
+// SeaLevel is the Y of the overworld's sea surface: water fills the
+// terrain's hollows below it.
+const SeaLevel = 63
+
+// The noise generator samples density on a grid of cells this many blocks
+// wide and high and interpolates between them, as vanilla does.
+const (
+	cellWidth  = 4
+	cellHeight = 8
+)
+
+// lavaLevel is the Y below which caves fill with lava.
+const lavaLevel = -54
+
+// ore is a kind of ore vein placed while decorating a chunk.
+type ore struct {
+	name, deepslate string
+	// count veins of up to size blocks between minY and maxY.
+	count, size, minY, maxY int
+}
+
+var ores = []ore{
+	{"minecraft:coal_ore", "minecraft:deepslate_coal_ore", 20, 17, 0, 192},
+	{"minecraft:iron_ore", "minecraft:deepslate_iron_ore", 10, 9, -64, 72},
+	{"minecraft:copper_ore", "minecraft:deepslate_copper_ore", 8, 10, -16, 112},
+	{"minecraft:gold_ore", "minecraft:deepslate_gold_ore", 4, 9, -64, 32},
+	{"minecraft:redstone_ore", "minecraft:deepslate_redstone_ore", 4, 8, -64, 15},
+	{"minecraft:lapis_ore", "minecraft:deepslate_lapis_ore", 2, 7, -64, 64},
+	{"minecraft:diamond_ore", "minecraft:deepslate_diamond_ore", 3, 8, -64, 16},
+}
+
+// oreStates are an ore's states in stone and in deepslate.
+type oreStates struct {
+	ore
+	stone, deepslate uint32
+}
+
+// The biomes the noise generator places.
+type overworldBiomes struct {
+	ocean, beach, desert, plains, forest, taiga, snowyPlains uint32
+}
+
+// The blocks the noise generator places.
+type overworldBlocks struct {
+	air, stone, deepslate, bedrock, water, lava            uint32
+	dirt, grass, snowyGrass, snow, sand, sandstone, gravel uint32
+}
+
+// NoiseGenerator generates an overworld from noise: continents and hills
+// from a height noise, overhangs from a 3D density noise, biomes from
+// temperature and humidity, a surface of grass, sand or snow by biome,
+// winding caves and veins of ore.
+type NoiseGenerator struct {
+	reg  Registry
+	seed int64
+
+	continents, erosion, hills *octaveNoise
+	density                    *octaveNoise
+	temperature, humidity      *octaveNoise
+	caveA, caveB, caverns      *octaveNoise
+
+	blocks overworldBlocks
+	biomes overworldBiomes
+	ores   []oreStates
+}
+
+// NewNoiseGenerator returns an overworld generator for a world seed. It
+// needs the vanilla blocks it places to be in reg.
+func NewNoiseGenerator(reg Registry, seed int64) (*NoiseGenerator, error) {
+	r := newRandom(seed)
+	g := &NoiseGenerator{
+		reg:         reg,
+		seed:        seed,
+		continents:  newOctaveNoise(r, 4),
+		erosion:     newOctaveNoise(r, 3),
+		hills:       newOctaveNoise(r, 4),
+		density:     newOctaveNoise(r, 3),
+		temperature: newOctaveNoise(r, 3),
+		humidity:    newOctaveNoise(r, 3),
+		caveA:       newOctaveNoise(r, 2),
+		caveB:       newOctaveNoise(r, 2),
+		caverns:     newOctaveNoise(r, 2),
+	}
+	b := &g.blocks
+	var err error
+	for _, s := range []struct {
+		id    *uint32
+		name  string
+		props []string
+	}{
+		{&b.air, "minecraft:air", nil},
+		{&b.stone, "minecraft:stone", nil},
+		{&b.deepslate, "minecraft:deepslate", nil},
+		{&b.bedrock, "minecraft:bedrock", nil},
+		{&b.water, "minecraft:water", nil},
+		{&b.lava, "minecraft:lava", nil},
+		{&b.dirt, "minecraft:dirt", nil},
+		{&b.grass, "minecraft:grass_block", nil},
+		{&b.snowyGrass, "minecraft:grass_block", []string{"snowy", "true"}},
+		{&b.snow, "minecraft:snow", nil},
+		{&b.sand, "minecraft:sand", nil},
+		{&b.sandstone, "minecraft:sandstone", nil},
+		{&b.gravel, "minecraft:gravel", nil},
+	} {
+		if *s.id, err = stateOf(reg, s.name, s.props...); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range ores {
+		s := oreStates{ore: o}
+		if s.stone, err = stateOf(reg, o.name); err != nil {
+			return nil, err
+		}
+		if s.deepslate, err = stateOf(reg, o.deepslate); err != nil {
+			return nil, err
+		}
+		g.ores = append(g.ores, s)
+	}
+	g.biomes = overworldBiomes{
+		ocean:       biomeOf(reg, "minecraft:ocean"),
+		beach:       biomeOf(reg, "minecraft:beach"),
+		desert:      biomeOf(reg, "minecraft:desert"),
+		plains:      biomeOf(reg, "minecraft:plains"),
+		forest:      biomeOf(reg, "minecraft:forest"),
+		taiga:       biomeOf(reg, "minecraft:taiga"),
+		snowyPlains: biomeOf(reg, "minecraft:snowy_plains"),
+	}
+	return g, nil
+}
+
+// Seed returns the world seed the generator was made with.
+func (g *NoiseGenerator) Seed() int64 { return g.seed }
+
+// terrainHeight returns the height the terrain settles around at a column:
+// oceans where the continents noise is low, plains around sea level and
+// hills and mountains where it is high and erosion is low.
+func (g *NoiseGenerator) terrainHeight(x, z float64) float64 {
+	c := g.continents.noise2(x/1024, z/1024)*1.6 + 0.15
+	e := g.erosion.noise2(x/512, z/512)
+	h := g.hills.noise2(x/128, z/128)
+	height := SeaLevel + 2 + c*40
+	if c > 0 {
+		// Inland, the less eroded the land, the higher the hills.
+		height += c * (1 - e) * 48 * (0.6 + h)
+	} else {
+		height += h * 4
+	}
+	return height
+}
+
+// densityAt returns how solid the terrain is at a block: positive below
+// the surface and negative above, with 3D noise making overhangs, and
+// caves cut out of it.
+func (g *NoiseGenerator) densityAt(x, y, z, height float64) float64 {
+	d := (height-y)/16 + g.density.noise(x/96, y/64, z/96)*0.8
+	if y > height-8 || d <= 0 {
+		// Caves do not break through the surface or the sea floor.
+		return d
+	}
+	// Spaghetti caves run where two noises are both close to zero; caverns
+	// open where a third is high, deep down.
+	a := g.caveA.noise(x/64, y/32, z/64)
+	b := g.caveB.noise(x/64, y/32, z/64)
+	spaghetti := (a*a+b*b)*60 - 0.12
+	cavern := (0.42 - g.caverns.noise(x/128, y/48, z/128)) * 4
+	if y > 30 {
+		cavern += (y - 30) / 16
+	}
+	return min(d, spaghetti, cavern)
+}
+
+// biomeAt picks a column's biome from its climate and terrain height.
+func (g *NoiseGenerator) biomeAt(x, z, height float64) uint32 {
+	t := g.temperature.noise2(x/768, z/768)
+	h := g.humidity.noise2(x/768, z/768)
+	snowy := t < -0.3
+	switch {
+	case height < SeaLevel-3:
+		return g.biomes.ocean
+	case height < SeaLevel+2 && !snowy:
+		return g.biomes.beach
+	case snowy:
+		return g.biomes.snowyPlains
+	case t < -0.1:
+		return g.biomes.taiga
+	case t > 0.25 && h < 0:
+		return g.biomes.desert
+	case h > 0.1:
+		return g.biomes.forest
+	}
+	return g.biomes.plains
+}
+
+// Generate fills a chunk with terrain, surface, caves and ores.
+func (g *NoiseGenerator) Generate(c *Chunk) error {
+	baseX, baseZ := int(c.Pos.X)*16, int(c.Pos.Z)*16
+	minY, maxY := c.MinY(), c.MaxY()
+
+	// Terrain height and biome of every column, and the biome of every
+	// 4x4 cell column.
+	var heights [16][16]float64
+	for z := 0; z < 16; z++ {
+		for x := 0; x < 16; x++ {
+			heights[x][z] = g.terrainHeight(float64(baseX+x), float64(baseZ+z))
+		}
+	}
+	var biomes [4][4]uint32
+	for z := 0; z < 4; z++ {
+		for x := 0; x < 4; x++ {
+			cx, cz := x*4+2, z*4+2
+			biomes[x][z] = g.biomeAt(float64(baseX+cx), float64(baseZ+cz), heights[cx][cz])
+		}
+	}
+	for _, s := range c.Sections {
+		for z := 0; z < 4; z++ {
+			for x := 0; x < 4; x++ {
+				for y := 0; y < 4; y++ {
+					s.Biomes.Set(s.Biomes.Index(x, y, z), biomes[x][z])
+				}
+			}
+		}
+	}
+
+	// Density on the corners of the cells, interpolated inside them.
+	const nx, nz = 16/cellWidth + 1, 16/cellWidth + 1
+	ny := (maxY-minY)/cellHeight + 1
+	corners := make([]float64, nx*ny*nz)
+	cornerHeight := func(cx, cz int) float64 {
+		return g.terrainHeight(float64(baseX+cx*cellWidth), float64(baseZ+cz*cellWidth))
+	}
+	for cx := 0; cx < nx; cx++ {
+		for cz := 0; cz < nz; cz++ {
+			height := cornerHeight(cx, cz)
+			for cy := 0; cy < ny; cy++ {
+				x, y, z := float64(baseX+cx*cellWidth), float64(minY+cy*cellHeight), float64(baseZ+cz*cellWidth)
+				corners[(cx*nz+cz)*ny+cy] = g.densityAt(x, y, z, height)
+			}
+		}
+	}
+	corner := func(cx, cy, cz int) float64 { return corners[(cx*nz+cz)*ny+cy] }
+
+	b := &g.blocks
+	for x := 0; x < 16; x++ {
+		cx, fx := x/cellWidth, float64(x%cellWidth)/cellWidth
+		for z := 0; z < 16; z++ {
+			cz, fz := z/cellWidth, float64(z%cellWidth)/cellWidth
+			for y := minY; y < maxY; y++ {
+				cy, fy := (y-minY)/cellHeight, float64((y-minY)%cellHeight)/cellHeight
+				d := lerp(fx,
+					lerp(fz,
+						lerp(fy, corner(cx, cy, cz), corner(cx, cy+1, cz)),
+						lerp(fy, corner(cx, cy, cz+1), corner(cx, cy+1, cz+1))),
+					lerp(fz,
+						lerp(fy, corner(cx+1, cy, cz), corner(cx+1, cy+1, cz)),
+						lerp(fy, corner(cx+1, cy, cz+1), corner(cx+1, cy+1, cz+1))))
+				var state uint32
+				switch {
+				case d > 0:
+					state = b.stone
+					if y < 0 || y < 8 && int(hash3(g.seed, baseX+x, y, baseZ+z)%8) >= y {
+						state = b.deepslate
+					}
+				case y < lavaLevel:
+					state = b.lava
+				case y < SeaLevel && float64(y) > heights[x][z]-8:
+					// Water fills the sea down to the floor, not the caves
+					// below it.
+					state = b.water
+				default:
+					continue
+				}
+				c.Section(y).setBlock(g.reg, x, y&15, z, state)
+			}
+		}
+	}
+
+	r := chunkRandom(g.seed, c.Pos, 1)
+	g.surface(c, biomes)
+	g.bedrock(c, r)
+	g.placeOres(c, r)
+	trackHeightmaps(c)
+	return nil
+}
+
+// surface replaces the top of each column's stone by its biome's blocks:
+// grass over dirt, sand over sandstone in deserts and on beaches, snowy
+// grass under snow when cold and sand or gravel under water.
+func (g *NoiseGenerator) surface(c *Chunk, biomes [4][4]uint32) {
+	b := &g.blocks
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			biome := biomes[x/4][z/4]
+			top, under := b.grass, b.dirt
+			switch biome {
+			case g.biomes.desert, g.biomes.beach:
+				top, under = b.sand, b.sandstone
+			case g.biomes.snowyPlains:
+				top = b.snowyGrass
+			}
+			for y := c.MaxY() - 1; y > c.MinY(); y-- {
+				if c.Block(x, y, z) != b.stone {
+					continue
+				}
+				above := c.Block(x, y+1, z)
+				underwater := above == b.water
+				if above != b.air && !underwater {
+					break
+				}
+				top, under := top, under
+				if underwater {
+					top, under = b.sand, b.sand
+					if y < SeaLevel-12 {
+						top, under = b.gravel, b.gravel
+					}
+				}
+				s := c.Section(y)
+				s.setBlock(g.reg, x, y&15, z, top)
+				if top == b.snowyGrass && y+1 < c.MaxY() {
+					c.Section(y+1).setBlock(g.reg, x, (y+1)&15, z, b.snow)
+				}
+				depth := 3
+				if under == b.sandstone {
+					depth = 6
+				}
+				for d := 1; d <= depth && c.Block(x, y-d, z) == b.stone; d++ {
+					state := under
+					if under == b.sandstone && d <= 2 {
+						state = b.sand
+					}
+					c.Section(y-d).setBlock(g.reg, x, (y-d)&15, z, state)
+				}
+				break
+			}
+		}
+	}
+}
+
+// bedrock lays the world floor: solid at the bottom and thinning out over
+// the four blocks above.
+func (g *NoiseGenerator) bedrock(c *Chunk, r *random) {
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for i := 0; i < 5; i++ {
+				if i == 0 || r.intn(5) < 5-i {
+					y := c.MinY() + i
+					c.Section(y).setBlock(g.reg, x, y&15, z, g.blocks.bedrock)
+				}
+			}
+		}
+	}
+}
+
+// placeOres places ore veins as random walks through the chunk's stone and
+// deepslate. Veins stay inside the chunk, so chunks are decorated alone.
+func (g *NoiseGenerator) placeOres(c *Chunk, r *random) {
+	for _, o := range g.ores {
+		minY, maxY := max(o.minY, c.MinY()), min(o.maxY, c.MaxY()-1)
+		if minY > maxY {
+			continue
+		}
+		for i := 0; i < o.count; i++ {
+			x, y, z := r.intn(16), minY+r.intn(maxY-minY+1), r.intn(16)
+			for n := 1 + r.intn(o.size); n > 0; n-- {
+				switch c.Block(x, y, z) {
+				case g.blocks.stone:
+					c.Section(y).setBlock(g.reg, x, y&15, z, o.stone)
+				case g.blocks.deepslate:
+					c.Section(y).setBlock(g.reg, x, y&15, z, o.deepslate)
+				}
+				switch r.intn(3) {
+				case 0:
+					x = min(max(x+r.intn(3)-1, 0), 15)
+				case 1:
+					y = min(max(y+r.intn(3)-1, minY), maxY)
+				default:
+					z = min(max(z+r.intn(3)-1, 0), 15)
+				}
+			}
+		}
+	}
+}
+
+// hash3 mixes a seed and a block position into a number, for choices that
+// must not depend on the order blocks are generated in.
+func hash3(seed int64, x, y, z int) uint64 {
+	h := uint64(seed) ^ uint64(x)*0x9E3779B97F4A7C15 ^ uint64(y)*0xC2B2AE3D27D4EB4F ^ uint64(z)*0x165667B19E3779F9
+	h = (h ^ h>>33) * 0xFF51AFD7ED558CCD
+	return h ^ h>>33
+}
+
+// FindSpawn returns where new worlds spawn: on the first land found
+// going out from the origin, or at the origin if it is all sea.
+func (g *NoiseGenerator) FindSpawn(typ DimensionType) BlockPos {
+	for r := 0; r <= 64; r++ {
+		for x := -r; x <= r; x++ {
+			for z := -r; z <= r; z++ {
+				if max(abs(int32(x)), abs(int32(z))) != int32(r) {
+					continue
+				}
+				if g.terrainHeight(float64(x*16+8), float64(z*16+8)) > SeaLevel+2 {
+					return g.surfaceAt(typ, x*16+8, z*16+8)
+				}
+			}
+		}
+	}
+	return g.surfaceAt(typ, 0, 0)
+}
+
+// surfaceAt returns the position just above the terrain, or the sea, at a
+// column.
+func (g *NoiseGenerator) surfaceAt(typ DimensionType, x, z int) BlockPos {
+	c := NewChunk(g.reg, ChunkPosOf(x, z), typ.MinY, typ.Height)
+	g.Generate(c)
+	return BlockPos{X: x, Y: c.Heightmap(MotionBlocking).Get(x&15, z&15), Z: z}
+}
@@ -0,0 +1,200 @@
+package world
+
+// ContainerKind is what a paletted container holds. It decides the
+// container's size and how many bits each palette size takes.
+type ContainerKind int
+
+const (
+	// BlockStates holds the 16x16x16 block states of a section.
+	BlockStates ContainerKind = iota
+	// Biomes holds the 4x4x4 biomes of a section, one per 4x4x4 blocks.
+	Biomes
+)
+
+// Edge returns the number of entries along each axis.
+func (k ContainerKind) Edge() int {
+	if k == Biomes {
+		return 4
+	}
+	return 16
+}
+
+// Size returns the number of entries.
+func (k ContainerKind) Size() int {
+	e := k.Edge()
+	return e * e * e
+}
+
+// Bits returns the bits per entry vanilla uses for a palette of n entries:
+// none for a single value, and for block states at least 4. Vanilla switches
+// to global IDs above 8 bits for block states and 3 for biomes; the wire
+// format does that too, while saved chunks and memory keep a palette.
+func (k ContainerKind) Bits(n int) int {
+	b := ceilLog2(n)
+	if k == BlockStates && b > 0 {
+		return max(b, 4)
+	}
+	return b
+}
+
+// MaxIndirectBits is the widest palette the wire format sends before it
+// uses global IDs instead.
+func (k ContainerKind) MaxIndirectBits() int {
+	if k == Biomes {
+		return 3
+	}
+	return 8
+}
+
+// PalettedContainer stores the entries of a section as indexes into a
+// palette of global IDs, packed as tightly as the palette allows. The zero
+// value is not usable; create containers with NewPalettedContainer.
+type PalettedContainer struct {
+	kind    ContainerKind
+	palette []uint32
+	// data is nil while the palette has a single value.
+	data *BitStorage
+}
+
+// NewPalettedContainer returns a container of the given kind filled with
+// value.
+func NewPalettedContainer(kind ContainerKind, value uint32) *PalettedContainer {
+	return &PalettedContainer{kind: kind, palette: []uint32{value}}
+}
+
+// newPalettedContainerFrom returns a container with the given palette and
+// packed indexes, which may be nil for a single-value palette.
+func newPalettedContainerFrom(kind ContainerKind, palette []uint32, data []uint64) (*PalettedContainer, error) {
+	c := &PalettedContainer{kind: kind, palette: palette}
+	if b := kind.Bits(len(palette)); b > 0 {
+		var err error
+		if c.data, err = NewBitStorageFrom(b, kind.Size(), data); err != nil {
+			return nil, err
+		}
+		// Indexes past the palette would make Get panic; treat them as the
+		// first entry, as vanilla does.
+		for i := 0; i < kind.Size(); i++ {
+			if int(c.data.Get(i)) >= len(palette) {
+				c.data.Set(i, 0)
+			}
+		}
+	}
+	return c, nil
+}
+
+// Kind returns what the container holds.
+func (c *PalettedContainer) Kind() ContainerKind { return c.kind }
+
+// Index returns the entry index of x, y, z, each within the edge.
+func (c *PalettedContainer) Index(x, y, z int) int {
+	e := c.kind.Edge()
+	return (y*e+z)*e + x
+}
+
+// Get returns entry i.
+func (c *PalettedContainer) Get(i int) uint32 {
+	if c.data == nil {
+		return c.palette[0]
+	}
+	return c.palette[c.data.Get(i)]
+}
+
+// Set stores entry i and returns the old value. The palette grows as needed
+// and is compacted when the container is saved or sent.
+func (c *PalettedContainer) Set(i int, v uint32) uint32 {
+	idx := c.paletteIndex(v)
+	if c.data == nil {
+		if idx == 0 {
+			return c.palette[0]
+		}
+		c.data = NewBitStorage(c.kind.Bits(len(c.palette)), c.kind.Size())
+	}
+	return c.palette[c.data.Set(i, uint32(idx))]
+}
+
+// paletteIndex returns the index of v, adding it to the palette and widening
+// the storage if needed.
+func (c *PalettedContainer) paletteIndex(v uint32) int {
+	for i, p := range c.palette {
+		if p == v {
+			return i
+		}
+	}
+	if len(c.palette) >= c.kind.Size() {
+		// Drop the values no entry holds any more before growing further.
+		c.palette, c.data = c.Compact()
+	}
+	c.palette = append(c.palette, v)
+	if c.data != nil {
+		if b := c.kind.Bits(len(c.palette)); b != c.data.Bits() {
+			wider := NewBitStorage(b, c.kind.Size())
+			for i := 0; i < c.kind.Size(); i++ {
+				wider.Set(i, c.data.Get(i))
+			}
+			c.data = wider
+		}
+	}
+	return len(c.palette) - 1
+}
+
+// Fill sets every entry to v.
+func (c *PalettedContainer) Fill(v uint32) {
+	c.palette = []uint32{v}
+	c.data = nil
+}
+
+// Count calls fn with each distinct value and how many entries hold it.
+func (c *PalettedContainer) Count(fn func(v uint32, n int)) {
+	if c.data == nil {
+		fn(c.palette[0], c.kind.Size())
+		return
+	}
+	counts := make([]int, len(c.palette))
+	for i := 0; i < c.kind.Size(); i++ {
+		counts[c.data.Get(i)]++
+	}
+	for i, n := range counts {
+		if n > 0 {
+			fn(c.palette[i], n)
+		}
+	}
+}
+
+// Compact returns the palette of the values in use, in order of first use,
+// and the entries packed as indexes into it; data is nil for a single value.
+func (c *PalettedContainer) Compact() (palette []uint32, data *BitStorage) {
+	if c.data == nil {
+		return []uint32{c.palette[0]}, nil
+	}
+	remap := make([]int, len(c.palette))
+	for i := range remap {
+		remap[i] = -1
+	}
+	indexes := make([]uint32, c.kind.Size())
+	for i := range indexes {
+		old := c.data.Get(i)
+		if remap[old] < 0 {
+			remap[old] = len(palette)
+			palette = append(palette, c.palette[old])
+		}
+		indexes[i] = uint32(remap[old])
+	}
+	b := c.kind.Bits(len(palette))
+	if b == 0 {
+		return palette, nil
+	}
+	data = NewBitStorage(b, c.kind.Size())
+	for i, idx := range indexes {
+		data.Set(i, idx)
+	}
+	return palette, data
+}
+
+// Clone returns a copy of the container.
+func (c *PalettedContainer) Clone() *PalettedContainer {
+	clone := &PalettedContainer{kind: c.kind, palette: append([]uint32(nil), c.palette...)}
+	if c.data != nil {
+		clone.data = c.data.Clone()
+	}
+	return clone
+}
@@ -0,0 +1,33 @@
+package world
+
+import "github.com/Advik-B/Golem/nbt"
+
+// Air is the global ID of minecraft:air, which is 0 in every version.
+const Air uint32 = 0
+
+// DefaultBiome fills sections whose biomes are missing or unknown, as in
+// vanilla.
+const DefaultBiome = "minecraft:plains"
+
+// Registry maps the global block state and biome IDs that chunks hold to the
+// names they are saved under.
+type Registry interface {
+	// BlockState returns the saved form of a block state: a compound with
+	// its Name and, for blocks that have any, its Properties.
+	BlockState(id uint32) (*nbt.CompoundTag, bool)
+	// BlockStateID returns the ID of a block state in its saved form.
+	BlockStateID(state *nbt.CompoundTag) (uint32, bool)
+	// IsAir reports whether a block state is air, cave air or void air,
+	// which sections do not count as blocks.
+	IsAir(id uint32) bool
+	// Biome returns the name of a biome.
+	Biome(id uint32) (string, bool)
+	// BiomeID returns the ID of a biome.
+	BiomeID(name string) (uint32, bool)
+	// LightEmission returns the light a block state gives off, from 0 to
+	// 15.
+	LightEmission(id uint32) int
+	// LightOpacity returns how much light loses passing through a block
+	// state, from 0 for air to 15 for blocks that stop it.
+	LightOpacity(id uint32) int
+}
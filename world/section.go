@@ -0,0 +1,77 @@
+package world
+
+// LightLength is the size of a section's light array: one nibble per block.
+const LightLength = 2048
+
+// Section is a 16x16x16 slice of a chunk.
+type Section struct {
+	Blocks *PalettedContainer
+	Biomes *PalettedContainer
+
+	// blockCount is the number of blocks that are not air.
+	blockCount int
+}
+
+// NewSection returns a section of air in the given biome.
+func NewSection(biome uint32) *Section {
+	return &Section{
+		Blocks: NewPalettedContainer(BlockStates, Air),
+		Biomes: NewPalettedContainer(Biomes, biome),
+	}
+}
+
+// BlockCount returns the number of blocks that are not air, as the Chunk
+// Data packet sends it.
+func (s *Section) BlockCount() int { return s.blockCount }
+
+// IsEmpty reports whether the section holds nothing but air.
+func (s *Section) IsEmpty() bool { return s.blockCount == 0 }
+
+// recount recomputes the block count after the blocks were replaced.
+func (s *Section) recount(reg Registry) {
+	s.blockCount = 0
+	s.Blocks.Count(func(v uint32, n int) {
+		if !reg.IsAir(v) {
+			s.blockCount += n
+		}
+	})
+}
+
+// setBlock sets the block at x, y, z within the section and returns the old
+// one.
+func (s *Section) setBlock(reg Registry, x, y, z int, state uint32) uint32 {
+	old := s.Blocks.Set(s.Blocks.Index(x, y, z), state)
+	if reg.IsAir(old) != reg.IsAir(state) {
+		if reg.IsAir(state) {
+			s.blockCount--
+		} else {
+			s.blockCount++
+		}
+	}
+	return old
+}
+
+// Light returns the level at x, y, z of a light array, treating a nil array
+// as dark.
+func Light(light []byte, x, y, z int) int {
+	if light == nil {
+		return 0
+	}
+	i := y<<8 | z<<4 | x
+	return int(light[i>>1] >> ((i & 1) << 2) & 0x0F)
+}
+
+// SetLight stores a level at x, y, z of a light array, which must not be nil.
+func SetLight(light []byte, x, y, z, level int) {
+	i := y<<8 | z<<4 | x
+	shift := (i & 1) << 2
+	light[i>>1] = light[i>>1]&^(0x0F<<shift) | byte(level&0x0F)<<shift
+}
+
+// Clone returns a copy of the section.
+func (s *Section) Clone() *Section {
+	c := *s
+	c.Blocks = s.Blocks.Clone()
+	c.Biomes = s.Biomes.Clone()
+	return &c
+}
@@ -0,0 +1,320 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Advik-B/Golem/region"
+)
+
+// The names of the vanilla dimensions, which are also their dimension types.
+const (
+	Overworld = "minecraft:overworld"
+	TheNether = "minecraft:the_nether"
+	TheEnd    = "minecraft:the_end"
+)
+
+// DimensionType is the shape of a dimension: the blocks its chunks span,
+// and whether the sky lights them.
+type DimensionType struct {
+	Name     string
+	MinY     int
+	Height   int
+	SkyLight bool
+}
+
+// The vanilla dimension types.
+var (
+	OverworldType = DimensionType{Name: Overworld, MinY: -64, Height: 384, SkyLight: true}
+	NetherType    = DimensionType{Name: TheNether, MinY: 0, Height: 256}
+	EndType       = DimensionType{Name: TheEnd, MinY: 0, Height: 256}
+)
+
+var builtinTypes = map[string]DimensionType{
+	Overworld: OverworldType,
+	TheNether: NetherType,
+	TheEnd:    EndType,
+}
+
+// Dimension is one dimension of a world, with its chunks in its own region
+// directory.
+type Dimension struct {
+	Name string
+	Type DimensionType
+	// Generator fills the chunks that were never saved. It is made from the
+	// dimension's settings in level.dat and may be replaced before chunks
+	// are loaded.
+	Generator ChunkGenerator
+
+	dir     string
+	reg     Registry
+	regions *region.Storage
+	// entities holds the entities of each chunk, in the entities directory
+	// as since 1.17.
+	entities *region.Storage
+}
+
+// Dir returns the directory holding the dimension's data, whose region
+// subdirectory holds its chunks.
+func (d *Dimension) Dir() string { return d.dir }
+
+// LoadChunk reads a chunk from the dimension's region files. It returns
+// region.ErrNotFound if the chunk was never saved.
+func (d *Dimension) LoadChunk(pos ChunkPos) (*Chunk, error) {
+	tag, err := d.regions.ReadChunk(int(pos.X), int(pos.Z))
+	if err != nil {
+		return nil, err
+	}
+	return FromNBT(d.reg, tag, d.Type.MinY, d.Type.Height)
+}
+
+// SaveChunk writes a chunk to the dimension's region files.
+func (d *Dimension) SaveChunk(c *Chunk) error {
+	return d.regions.WriteChunk(int(c.Pos.X), int(c.Pos.Z), c.ToNBT(DataVersion))
+}
+
+// LoadEntities reads the entities saved in a chunk from the dimension's
+// entities region files. It returns region.ErrNotFound if none were saved.
+func (d *Dimension) LoadEntities(pos ChunkPos) ([]*Entity, error) {
+	tag, err := d.entities.ReadChunk(int(pos.X), int(pos.Z))
+	if err != nil {
+		return nil, err
+	}
+	saved, entities, err := EntitiesFromNBT(tag)
+	if err != nil {
+		return nil, err
+	}
+	if saved != pos {
+		return nil, fmt.Errorf("world: entities of chunk %d, %d are saved as chunk %d, %d", pos.X, pos.Z, saved.X, saved.Z)
+	}
+	return entities, nil
+}
+
+// SaveEntities writes the entities of a chunk to the dimension's entities
+// region files. Like vanilla, a chunk without entities is removed.
+func (d *Dimension) SaveEntities(pos ChunkPos, entities []*Entity) error {
+	if len(entities) == 0 {
+		return d.entities.DeleteChunk(int(pos.X), int(pos.Z))
+	}
+	return d.entities.WriteChunk(int(pos.X), int(pos.Z), EntitiesToNBT(pos, entities, DataVersion))
+}
+
+// NewChunk returns an empty chunk spanning the dimension's height.
+func (d *Dimension) NewChunk(pos ChunkPos) *Chunk {
+	return NewChunk(d.reg, pos, d.Type.MinY, d.Type.Height)
+}
+
+// GenerateChunk returns a new chunk filled by the dimension's generator,
+// or an empty one if it has none.
+func (d *Dimension) GenerateChunk(pos ChunkPos) (*Chunk, error) {
+	c := d.NewChunk(pos)
+	if d.Generator == nil {
+		return c, nil
+	}
+	return c, d.Generator.Generate(c)
+}
+
+// World is a world directory: its level.dat and its dimensions. It is safe
+// for concurrent use.
+type World struct {
+	dir string
+	reg Registry
+
+	saveMu     sync.Mutex
+	mu         sync.RWMutex
+	level      *LevelData
+	dimensions map[string]*Dimension
+}
+
+// Open opens the world in dir, creating a new one with a random seed if it
+// has no level.dat. Like vanilla, it falls back to level.dat_old when
+// level.dat cannot be read. The vanilla dimensions and every dimension of
+// level.dat with a vanilla dimension type are opened; others must be added
+// with AddDimension.
+func Open(dir string, reg Registry) (*World, error) {
+	return OpenWith(dir, reg, nil)
+}
+
+// OpenWith is Open, but a new world is created with the given level data,
+// such as one with a chosen seed or generator. Its spawn is placed where
+// the overworld's generator finds land.
+func OpenWith(dir string, reg Registry, newLevel *LevelData) (*World, error) {
+	w := &World{dir: dir, reg: reg, dimensions: make(map[string]*Dimension)}
+	level, err := ReadLevelData(filepath.Join(dir, "level.dat"))
+	created := false
+	if err != nil {
+		var oldErr error
+		if level, oldErr = ReadLevelData(filepath.Join(dir, "level.dat_old")); oldErr != nil {
+			if !errors.Is(err, fs.ErrNotExist) || !errors.Is(oldErr, fs.ErrNotExist) {
+				return nil, err
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, err
+			}
+			level, created = newLevel, true
+			if level == nil {
+				level = NewLevelData(filepath.Base(dir), time.Now().UnixNano())
+			}
+		}
+	}
+	w.level = level
+
+	for _, name := range []string{Overworld, TheNether, TheEnd} {
+		if _, ok := level.WorldGen.Dimensions[name]; !ok {
+			level.WorldGen.Dimensions[name] = DimensionSettings{Type: name}
+		}
+	}
+	for name, settings := range level.WorldGen.Dimensions {
+		if typ, ok := builtinTypes[settings.Type]; ok {
+			if _, err := w.addDimension(name, typ, settings); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if created {
+		overworld := w.dimensions[Overworld]
+		if f, ok := overworld.Generator.(spawnFinder); ok {
+			level.Spawn = f.FindSpawn(overworld.Type)
+		}
+	}
+	if err := w.Save(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Dir returns the world directory.
+func (w *World) Dir() string { return w.dir }
+
+// Registry returns the registry the world's chunks use.
+func (w *World) Registry() Registry { return w.reg }
+
+// Level returns a copy of the level data.
+func (w *World) Level() *LevelData {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.level.Clone()
+}
+
+// UpdateLevel changes the level data, which the next Save writes.
+func (w *World) UpdateLevel(fn func(l *LevelData)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fn(w.level)
+}
+
+// Dimension returns the dimension with the given name.
+func (w *World) Dimension(name string) (*Dimension, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	d, ok := w.dimensions[name]
+	return d, ok
+}
+
+// Dimensions returns every dimension, sorted by name.
+func (w *World) Dimensions() []*Dimension {
+	w.mu.RLock()
+	list := make([]*Dimension, 0, len(w.dimensions))
+	for _, d := range w.dimensions {
+		list = append(list, d)
+	}
+	w.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// AddDimension adds a dimension of the given type, or returns the existing
+// one of that name. Dimensions level.dat does not list yet are added there
+// without generator settings.
+func (w *World) AddDimension(name string, typ DimensionType) (*Dimension, error) {
+	if typ.Height <= 0 || typ.Height%16 != 0 || typ.MinY%16 != 0 {
+		return nil, fmt.Errorf("world: dimension type %s spans %d blocks from %d", typ.Name, typ.Height, typ.MinY)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if d, ok := w.dimensions[name]; ok {
+		if d.Type != typ {
+			return nil, fmt.Errorf("world: dimension %s has type %s", name, d.Type.Name)
+		}
+		return d, nil
+	}
+	settings, ok := w.level.WorldGen.Dimensions[name]
+	if !ok {
+		settings = DimensionSettings{Type: typ.Name}
+		w.level.WorldGen.Dimensions[name] = settings
+	}
+	return w.addDimension(name, typ, settings)
+}
+
+func (w *World) addDimension(name string, typ DimensionType, settings DimensionSettings) (*Dimension, error) {
+	gen, err := NewGenerator(w.reg, w.level.WorldGen.Seed, settings)
+	if err != nil {
+		return nil, fmt.Errorf("world: generator of %s: %w", name, err)
+	}
+	dir := DimensionDir(w.dir, name)
+	d := &Dimension{
+		Name:      name,
+		Type:      typ,
+		Generator: gen,
+		dir:       dir,
+		reg:       w.reg,
+		regions:   region.NewStorage(filepath.Join(dir, "region")),
+		entities:  region.NewStorage(filepath.Join(dir, "entities")),
+	}
+	w.dimensions[name] = d
+	return d, nil
+}
+
+// DimensionDir returns where a world in dir keeps a dimension's data: the
+// world directory itself for the overworld, DIM-1 and DIM1 for the nether
+// and the end, and dimensions/<namespace>/<path> for the others.
+func DimensionDir(dir, name string) string {
+	switch name {
+	case Overworld:
+		return dir
+	case TheNether:
+		return filepath.Join(dir, "DIM-1")
+	case TheEnd:
+		return filepath.Join(dir, "DIM1")
+	}
+	namespace, path, ok := strings.Cut(name, ":")
+	if !ok {
+		namespace, path = "minecraft", name
+	}
+	return filepath.Join(dir, "dimensions", namespace, filepath.FromSlash(path))
+}
+
+// Save writes level.dat, keeping the previous one as level.dat_old. The
+// world is then marked as saved by this package's game version.
+func (w *World) Save() error {
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+	w.mu.Lock()
+	w.level.DataVersion, w.level.VersionName = DataVersion, VersionName
+	w.level.LastPlayed = time.Now().UnixMilli()
+	level := w.level.Clone()
+	w.mu.Unlock()
+	return WriteLevelData(filepath.Join(w.dir, "level.dat"), filepath.Join(w.dir, "level.dat_old"), level)
+}
+
+// Close saves level.dat and closes the region and entities files of every
+// dimension.
+func (w *World) Close() error {
+	err := w.Save()
+	for _, d := range w.Dimensions() {
+		if cerr := d.regions.Close(); err == nil {
+			err = cerr
+		}
+		if cerr := d.entities.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,950 @@
+package world
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Advik-B/Golem/nbt"
+	"github.com/Advik-B/Golem/protocol"
+	"github.com/Advik-B/Golem/region"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRegistry knows air, the blocks the generators place, two more for
+// light and a few biomes.
+type testRegistry struct{}
+
+var testBlocks = []string{
+	"minecraft:air", "minecraft:stone", "minecraft:dirt", "minecraft:cave_air",
+	"minecraft:deepslate", "minecraft:bedrock", "minecraft:water", "minecraft:lava",
+	"minecraft:grass_block", "minecraft:snow", "minecraft:sand", "minecraft:sandstone", "minecraft:gravel",
+	"minecraft:coal_ore", "minecraft:deepslate_coal_ore", "minecraft:iron_ore", "minecraft:deepslate_iron_ore",
+	"minecraft:copper_ore", "minecraft:deepslate_copper_ore", "minecraft:gold_ore", "minecraft:deepslate_gold_ore",
+	"minecraft:redstone_ore", "minecraft:deepslate_redstone_ore", "minecraft:lapis_ore", "minecraft:deepslate_lapis_ore",
+	"minecraft:diamond_ore", "minecraft:deepslate_diamond_ore", "minecraft:glowstone", "minecraft:glass",
+}
+var testBiomes = []string{"minecraft:the_void", "minecraft:plains", "minecraft:desert"}
+
+func (testRegistry) BlockState(id uint32) (*nbt.CompoundTag, bool) {
+	if int(id) >= len(testBlocks) {
+		return nil, false
+	}
+	tag := nbt.NewCompoundTag()
+	tag.Put("Name", &nbt.StringTag{Value: testBlocks[id]})
+	return tag, true
+}
+
+func (testRegistry) BlockStateID(state *nbt.CompoundTag) (uint32, bool) {
+	name, _ := state.GetString("Name")
+	for i, b := range testBlocks {
+		if b == name {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+func (testRegistry) IsAir(id uint32) bool { return id == 0 || id == 3 }
+
+func (testRegistry) LightEmission(id uint32) int {
+	switch testBlocks[id] {
+	case "minecraft:lava", "minecraft:glowstone":
+		return 15
+	}
+	return 0
+}
+
+func (r testRegistry) LightOpacity(id uint32) int {
+	switch testBlocks[id] {
+	case "minecraft:water", "minecraft:lava":
+		return 1
+	case "minecraft:snow", "minecraft:glass":
+		return 0
+	}
+	if r.IsAir(id) {
+		return 0
+	}
+	return 15
+}
+
+func (testRegistry) Biome(id uint32) (string, bool) {
+	if int(id) >= len(testBiomes) {
+		return "", false
+	}
+	return testBiomes[id], true
+}
+
+func (testRegistry) BiomeID(name string) (uint32, bool) {
+	for i, b := range testBiomes {
+		if b == name {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+func TestBitStorage(t *testing.T) {
+	// 5 bits leave 4 unused bits in each long; entries never straddle longs.
+	s := NewBitStorage(5, 4096)
+	assert.Equal(t, 4096/12+1, len(s.Longs()))
+	for i := 0; i < 4096; i++ {
+		s.Set(i, uint32(i%32))
+	}
+	for i := 0; i < 4096; i++ {
+		require.Equal(t, uint32(i%32), s.Get(i))
+	}
+	assert.Equal(t, uint64(0)|1<<5|2<<10, s.Longs()[0]&(1<<15-1))
+
+	_, err := NewBitStorageFrom(5, 4096, make([]uint64, 10))
+	assert.Error(t, err)
+	loaded, err := NewBitStorageFrom(5, 4096, s.Longs())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(31), loaded.Get(31))
+}
+
+func TestPalettedContainer(t *testing.T) {
+	c := NewPalettedContainer(BlockStates, Air)
+	palette, data := c.Compact()
+	assert.Equal(t, []uint32{Air}, palette)
+	assert.Nil(t, data)
+
+	// The first extra value goes straight to the 4-bit minimum.
+	assert.Equal(t, Air, c.Set(0, 1))
+	_, data = c.Compact()
+	assert.Equal(t, 4, data.Bits())
+
+	// Seventeen values need 5 bits.
+	for i := 0; i < 17; i++ {
+		c.Set(i, uint32(100+i))
+	}
+	palette, data = c.Compact()
+	assert.Len(t, palette, 18)
+	assert.Equal(t, 5, data.Bits())
+	assert.Equal(t, uint32(116), c.Get(16))
+
+	// Values no entry holds any more are dropped from the compact form.
+	for i := 0; i < 17; i++ {
+		c.Set(i, 7)
+	}
+	palette, data = c.Compact()
+	assert.Equal(t, []uint32{7, Air}, palette)
+	assert.Equal(t, 4, data.Bits())
+
+	// Filling every entry with as many values as there are entries stays
+	// within the container's size.
+	for i := 0; i < 4096; i++ {
+		c.Set(i, uint32(i+1000))
+	}
+	for i := 0; i < 4096; i++ {
+		require.Equal(t, uint32(i+1000), c.Get(i))
+	}
+	_, data = c.Compact()
+	assert.Equal(t, 12, data.Bits())
+
+	c.Fill(Air)
+	palette, data = c.Compact()
+	assert.Equal(t, []uint32{Air}, palette)
+	assert.Nil(t, data)
+
+	biomes := NewPalettedContainer(Biomes, 1)
+	biomes.Set(biomes.Index(3, 3, 3), 2)
+	palette, data = biomes.Compact()
+	assert.Equal(t, []uint32{1, 2}, palette)
+	assert.Equal(t, 1, data.Bits())
+	assert.Equal(t, 1, len(data.Longs()))
+}
+
+func TestChunkBlocks(t *testing.T) {
+	c := NewChunk(testRegistry{}, ChunkPos{X: -1, Z: 2}, -64, 384)
+	assert.Equal(t, 24, len(c.Sections))
+	assert.Equal(t, 320, c.MaxY())
+	assert.Equal(t, -4, c.MinSection())
+
+	assert.Equal(t, Air, c.SetBlock(-1, -64, 33, 1))
+	assert.Equal(t, uint32(1), c.Block(15, -64, 1))
+	assert.Equal(t, 1, c.Sections[0].BlockCount())
+	// Cave air is air too.
+	c.SetBlock(15, -64, 1, 3)
+	assert.True(t, c.Sections[0].IsEmpty())
+	// Outside the chunk's height nothing changes.
+	assert.Equal(t, Air, c.SetBlock(0, 320, 0, 1))
+	assert.Equal(t, Air, c.Block(0, -65, 0))
+
+	c.SetBiome(5, 10, 5, 2)
+	assert.Equal(t, uint32(2), c.Biome(4, 8, 7))
+	assert.Equal(t, uint32(1), c.Biome(8, 8, 7))
+}
+
+func TestHeightmap(t *testing.T) {
+	reg := testRegistry{}
+	c := NewChunk(reg, ChunkPos{}, -64, 384)
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			c.SetBlock(x, -60, z, 1)
+		}
+	}
+	h := c.TrackHeightmap(MotionBlocking, func(state uint32) bool { return !reg.IsAir(state) })
+	assert.Equal(t, 9, h.Data().Bits())
+	assert.Equal(t, -59, h.Get(3, 4))
+
+	c.SetBlock(3, 100, 4, 2)
+	assert.Equal(t, 101, h.Get(3, 4))
+	c.SetBlock(3, 50, 4, 2)
+	assert.Equal(t, 101, h.Get(3, 4))
+	// Removing the top block finds the next one down.
+	c.SetBlock(3, 100, 4, Air)
+	assert.Equal(t, 51, h.Get(3, 4))
+	c.SetBlock(3, 50, 4, Air)
+	assert.Equal(t, -59, h.Get(3, 4))
+	c.SetBlock(3, -60, 4, Air)
+	assert.Equal(t, -64, h.Get(3, 4))
+	assert.Equal(t, -59, h.Get(4, 4))
+}
+
+func TestLight(t *testing.T) {
+	c := NewChunk(testRegistry{}, ChunkPos{}, 0, 256)
+	assert.Equal(t, 18, len(c.SkyLight))
+	// Setting darkness does not allocate.
+	c.SetSkyLight(0, 0, 0, 0)
+	assert.Nil(t, c.SkyLight[1])
+
+	c.SetSkyLight(1, 0, 0, 15)
+	c.SetSkyLight(2, 0, 0, 7)
+	assert.Equal(t, byte(0xF0), c.SkyLight[1][0])
+	assert.Equal(t, byte(0x07), c.SkyLight[1][1])
+	assert.Equal(t, 15, c.SkyLightAt(1, 0, 0))
+
+	// Light reaches the sections just outside the blocks, but no further.
+	c.SetBlockLight(0, -1, 0, 12)
+	c.SetBlockLight(0, 256, 0, 13)
+	c.SetBlockLight(0, 272, 0, 14)
+	assert.Equal(t, 12, c.BlockLightAt(0, -1, 0))
+	assert.Equal(t, 13, c.BlockLightAt(0, 256, 0))
+	assert.Equal(t, 0, c.BlockLightAt(0, 272, 0))
+}
+
+func TestLightEngine(t *testing.T) {
+	const stone, glowstone, glass = 1, 27, 28
+	reg := testRegistry{}
+	chunks := make(map[ChunkPos]*Chunk)
+	e := NewLightEngine(reg, true, func(pos ChunkPos) *Chunk { return chunks[pos] })
+	for _, pos := range []ChunkPos{{}, {X: 1}} {
+		c := NewChunk(reg, pos, 0, 64)
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 16; x++ {
+				for z := 0; z < 16; z++ {
+					c.SetBlock(x, y, z, stone)
+				}
+			}
+		}
+		chunks[pos] = c
+	}
+	a, b := chunks[ChunkPos{}], chunks[ChunkPos{X: 1}]
+	// A glass roof lets the sky through; a stone one shades the blocks
+	// below, which the sky reaches from the side.
+	for x := 0; x < 8; x++ {
+		for z := 0; z < 16; z++ {
+			a.SetBlock(x, 8, z, stone)
+			a.SetBlock(x, 12, z, glass)
+		}
+	}
+	a.SetBlock(15, 10, 8, glowstone)
+	e.LightChunk(a)
+	e.LightChunk(b)
+	assert.True(t, a.LightOn)
+	assert.Equal(t, 15, a.SkyLightAt(12, 4, 3))
+	assert.Equal(t, 0, a.SkyLightAt(12, 3, 3))
+	assert.Equal(t, 15, a.SkyLightAt(0, 9, 3))
+	assert.Equal(t, 15, a.SkyLightAt(0, 70, 3))
+	assert.Equal(t, 14, a.SkyLightAt(7, 5, 3))
+	assert.Equal(t, 7, a.SkyLightAt(0, 5, 3))
+	assert.Equal(t, 15, a.BlockLightAt(15, 10, 8))
+	assert.Equal(t, 14, a.BlockLightAt(15, 11, 8))
+	assert.Equal(t, 11, a.BlockLightAt(11, 10, 8))
+	assert.Equal(t, 9, a.BlockLightAt(15, 4, 8))
+	assert.Equal(t, 0, a.BlockLightAt(15, 3, 8))
+	// Each chunk was lit on its own.
+	assert.Zero(t, b.BlockLightAt(16, 10, 8))
+
+	changed := e.Join(b)
+	assert.ElementsMatch(t, []ChunkPos{{X: 1}}, changed)
+	assert.Equal(t, 14, b.BlockLightAt(16, 10, 8))
+	assert.Equal(t, 10, b.BlockLightAt(20, 10, 8))
+
+	// Taking the glowstone away darkens both chunks.
+	a.SetBlock(15, 10, 8, Air)
+	changed = e.BlockChanged(15, 10, 8)
+	assert.ElementsMatch(t, []ChunkPos{{}, {X: 1}}, changed)
+	assert.Zero(t, a.BlockLightAt(15, 11, 8))
+	assert.Zero(t, b.BlockLightAt(16, 10, 8))
+
+	// A hole in the ground fills with sky light, and stone over it shades
+	// it again.
+	b.SetBlock(24, 3, 8, Air)
+	e.BlockChanged(24, 3, 8)
+	assert.Equal(t, 15, b.SkyLightAt(24, 3, 8))
+	b.SetBlock(24, 4, 8, stone)
+	e.BlockChanged(24, 4, 8)
+	assert.Equal(t, 0, b.SkyLightAt(24, 4, 8))
+	assert.Equal(t, 0, b.SkyLightAt(24, 3, 8))
+	assert.Equal(t, 15, b.SkyLightAt(24, 5, 8))
+	// Removing the stone roof lets the sky straight down again.
+	for x := 0; x < 8; x++ {
+		for z := 0; z < 16; z++ {
+			a.SetBlock(x, 8, z, Air)
+			e.BlockChanged(x, 8, z)
+		}
+	}
+	assert.Equal(t, 15, a.SkyLightAt(0, 5, 3))
+}
+
+func TestChunkNBT(t *testing.T) {
+	reg := testRegistry{}
+	c := NewChunk(reg, ChunkPos{X: 3, Z: -7}, -64, 384)
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			c.SetBlock(x, -64, z, 1)
+			c.SetBlock(x, -63+(x+z)%5, z, 2)
+		}
+	}
+	c.SetBiome(0, 0, 0, 2)
+	c.TrackHeightmap(WorldSurface, func(state uint32) bool { return !reg.IsAir(state) })
+	c.SetSkyLight(5, 319, 5, 15)
+	c.SetBlockLight(5, -65, 5, 4)
+	c.LightOn = true
+	c.InhabitedTime = 1200
+	be := nbt.NewCompoundTag()
+	be.Put("id", &nbt.StringTag{Value: "minecraft:chest"})
+	be.Put("x", &nbt.IntTag{Value: 48})
+	be.Put("y", &nbt.IntTag{Value: -63})
+	be.Put("z", &nbt.IntTag{Value: -112})
+	require.NoError(t, c.SetBlockEntity(be))
+	c.Extra = nbt.NewCompoundTag()
+	c.Extra.Put("PostProcessing", &nbt.ListTag{Type: nbt.TagList})
+
+	tag := c.ToNBT(3955)
+	// Survive a trip through the binary format.
+	var buf bytes.Buffer
+	require.NoError(t, nbt.Write(&buf, nbt.NamedTag{Tag: tag}))
+	read, err := nbt.Read(&buf)
+	require.NoError(t, err)
+	tag = read.Tag.(*nbt.CompoundTag)
+
+	yPos, _ := tag.GetInt("yPos")
+	assert.Equal(t, int32(-4), yPos)
+	sections, _ := tag.GetList("sections")
+	// The section below the chunk has light, the one above does not.
+	assert.Len(t, sections.Value, 25)
+	bottom := sections.Value[1].(*nbt.CompoundTag)
+	states, _ := bottom.GetCompound("block_states")
+	palette, _ := states.GetList("palette")
+	assert.Len(t, palette.Value, 3)
+	top := sections.Value[24].(*nbt.CompoundTag)
+	states, _ = top.GetCompound("block_states")
+	_, hasData := states.Get("data")
+	assert.False(t, hasData)
+
+	loaded, err := FromNBT(reg, tag, -64, 384)
+	require.NoError(t, err)
+	assert.Equal(t, c.Pos, loaded.Pos)
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := -64; y < -56; y++ {
+				require.Equal(t, c.Block(x, y, z), loaded.Block(x, y, z))
+			}
+		}
+	}
+	assert.Equal(t, c.Sections[0].BlockCount(), loaded.Sections[0].BlockCount())
+	assert.Equal(t, uint32(2), loaded.Biome(0, 0, 0))
+	assert.Equal(t, uint32(1), loaded.Biome(4, 0, 0))
+	assert.Equal(t, c.Heightmap(WorldSurface).Get(4, 0), loaded.Heightmap(WorldSurface).Get(4, 0))
+	assert.Equal(t, 15, loaded.SkyLightAt(5, 319, 5))
+	assert.Equal(t, 4, loaded.BlockLightAt(5, -65, 5))
+	assert.True(t, loaded.LightOn)
+	assert.Equal(t, int64(1200), loaded.InhabitedTime)
+	assert.Contains(t, loaded.BlockEntities, BlockPos{48, -63, -112})
+	_, ok := loaded.Extra.Get("PostProcessing")
+	assert.True(t, ok)
+	_, ok = loaded.Extra.Get("sections")
+	assert.False(t, ok)
+}
+
+func TestChunkNBTUnknown(t *testing.T) {
+	reg := testRegistry{}
+	unknown := nbt.NewCompoundTag()
+	unknown.Put("Name", &nbt.StringTag{Value: "minecraft:future_block"})
+	stone, _ := reg.BlockState(1)
+	states := nbt.NewCompoundTag()
+	states.Put("palette", &nbt.ListTag{Type: nbt.TagCompound, Value: []nbt.Tag{stone, unknown}})
+	data := make([]int64, 256)
+	data[0] = 1
+	states.Put("data", &nbt.LongArrayTag{Value: data})
+	biomes := nbt.NewCompoundTag()
+	biomes.Put("palette", &nbt.ListTag{Type: nbt.TagString, Value: []nbt.Tag{&nbt.StringTag{Value: "minecraft:future_biome"}}})
+	section := nbt.NewCompoundTag()
+	section.Put("Y", &nbt.ByteTag{Value: 0})
+	section.Put("block_states", states)
+	section.Put("biomes", biomes)
+	tag := nbt.NewCompoundTag()
+	tag.Put("xPos", &nbt.IntTag{})
+	tag.Put("zPos", &nbt.IntTag{})
+	tag.Put("sections", &nbt.ListTag{Type: nbt.TagCompound, Value: []nbt.Tag{section}})
+
+	c, err := FromNBT(reg, tag, 0, 256)
+	require.NoError(t, err)
+	assert.Equal(t, Air, c.Block(0, 0, 0))
+	assert.Equal(t, uint32(1), c.Block(1, 0, 0))
+	assert.Equal(t, 4095, c.Sections[0].BlockCount())
+	assert.Equal(t, uint32(1), c.Biome(0, 0, 0))
+
+	// A data array of the wrong length is an error.
+	states.Put("data", &nbt.LongArrayTag{Value: data[:10]})
+	_, err = FromNBT(reg, tag, 0, 256)
+	assert.Error(t, err)
+}
+
+// readContainer decodes a paletted container in its wire form, returning
+// the network ID of every entry.
+func readContainer(t *testing.T, r *bytes.Reader, size int) (bits int, entries []uint32) {
+	b, err := r.ReadByte()
+	require.NoError(t, err)
+	bits = int(b)
+	var palette []uint32
+	switch {
+	case bits == 0:
+		v, err := protocol.ReadVarInt(r)
+		require.NoError(t, err)
+		n, _ := protocol.ReadVarInt(r)
+		require.Zero(t, n)
+		for i := 0; i < size; i++ {
+			entries = append(entries, uint32(v))
+		}
+		return bits, entries
+	case bits <= 8:
+		n, err := protocol.ReadVarInt(r)
+		require.NoError(t, err)
+		for i := 0; i < int(n); i++ {
+			v, _ := protocol.ReadVarInt(r)
+			palette = append(palette, uint32(v))
+		}
+	}
+	n, err := protocol.ReadVarInt(r)
+	require.NoError(t, err)
+	longs := make([]uint64, n)
+	for i := range longs {
+		l, err := protocol.ReadLong(r)
+		require.NoError(t, err)
+		longs[i] = uint64(l)
+	}
+	data, err := NewBitStorageFrom(bits, size, longs)
+	require.NoError(t, err)
+	for i := 0; i < size; i++ {
+		v := data.Get(i)
+		if palette != nil {
+			v = palette[v]
+		}
+		entries = append(entries, v)
+	}
+	return bits, entries
+}
+
+func TestChunkDataPacket(t *testing.T) {
+	reg := testRegistry{}
+	c := NewChunk(reg, ChunkPos{X: 1, Z: -2}, 0, 32)
+	c.TrackHeightmap(MotionBlocking, func(state uint32) bool { return !reg.IsAir(state) })
+	c.SetBlock(0, 0, 0, 1)
+	c.SetBlock(1, 0, 0, 2)
+	// More than 256 distinct states need the direct palette.
+	for i := 0; i < 300; i++ {
+		c.SetBlock(i&15, 16+i>>8, i>>4&15, uint32(10+i))
+	}
+	c.SetBiome(0, 0, 0, 2)
+	c.SetSkyLight(0, 16, 0, 15)
+	c.SkyLight[0] = make([]byte, LightLength)
+	be := nbt.NewCompoundTag()
+	be.Put("id", &nbt.StringTag{Value: "minecraft:chest"})
+	be.Put("x", &nbt.IntTag{Value: 17})
+	be.Put("y", &nbt.IntTag{Value: 5})
+	be.Put("z", &nbt.IntTag{Value: -30})
+	be.Put("CustomName", &nbt.StringTag{Value: `"Loot"`})
+	require.NoError(t, c.SetBlockEntity(be))
+	ignored := nbt.NewCompoundTag()
+	ignored.Put("id", &nbt.StringTag{Value: "example:unknown"})
+	ignored.Put("x", &nbt.IntTag{Value: 16})
+	ignored.Put("y", &nbt.IntTag{Value: 0})
+	ignored.Put("z", &nbt.IntTag{Value: -32})
+	require.NoError(t, c.SetBlockEntity(ignored))
+
+	ids := &NetworkIDs{
+		BlockState:     func(id uint32) uint32 { return id * 2 },
+		Biome:          func(id uint32) uint32 { return id + 100 },
+		BlockStateBits: 15,
+		BiomeBits:      7,
+		BlockEntityType: func(name string) (int32, bool) {
+			return 1, name == "minecraft:chest"
+		},
+	}
+	r := bytes.NewReader(bytes.Join(c.ChunkDataPacket(ids), nil))
+	x, _ := protocol.ReadInt(r)
+	z, _ := protocol.ReadInt(r)
+	assert.Equal(t, []int32{1, -2}, []int32{x, z})
+	heightmaps, err := protocol.ReadNBT(r)
+	require.NoError(t, err)
+	_, ok := heightmaps.(*nbt.CompoundTag).Get(string(MotionBlocking))
+	assert.True(t, ok)
+
+	data, err := protocol.ReadByteArray(r, 1<<20)
+	require.NoError(t, err)
+	sr := bytes.NewReader(data)
+	count, _ := protocol.ReadShort(sr)
+	assert.Equal(t, int16(2), count)
+	bits, blocks := readContainer(t, sr, 4096)
+	assert.Equal(t, 4, bits)
+	assert.Equal(t, []uint32{2, 4, 0}, blocks[:3])
+	bits, biomes := readContainer(t, sr, 64)
+	assert.Equal(t, 1, bits)
+	assert.Equal(t, []uint32{102, 101}, biomes[:2])
+	count, _ = protocol.ReadShort(sr)
+	assert.Equal(t, int16(300), count)
+	bits, blocks = readContainer(t, sr, 4096)
+	assert.Equal(t, 15, bits)
+	assert.Equal(t, uint32(20+2*299), blocks[299])
+	bits, biomes = readContainer(t, sr, 64)
+	assert.Equal(t, 0, bits)
+	assert.Equal(t, uint32(101), biomes[63])
+	assert.Zero(t, sr.Len())
+
+	n, _ := protocol.ReadVarInt(r)
+	assert.Equal(t, int32(1), n)
+	xz, _ := r.ReadByte()
+	y, _ := protocol.ReadShort(r)
+	typ, _ := protocol.ReadVarInt(r)
+	assert.Equal(t, []int{0x12, 5, 1}, []int{int(xz), int(y), int(typ)})
+	tag, err := protocol.ReadNBT(r)
+	require.NoError(t, err)
+	assert.Len(t, tag.(*nbt.CompoundTag).Value, 1)
+
+	// Light: the section below the chunk is dark, the second section lit.
+	readMask := func() []int64 {
+		n, _ := protocol.ReadVarInt(r)
+		mask := make([]int64, n)
+		for i := range mask {
+			mask[i], _ = protocol.ReadLong(r)
+		}
+		return mask
+	}
+	assert.Equal(t, []int64{1 << 2}, readMask())
+	assert.Empty(t, readMask())
+	assert.Equal(t, []int64{1}, readMask())
+	assert.Empty(t, readMask())
+	n, _ = protocol.ReadVarInt(r)
+	assert.Equal(t, int32(1), n)
+	light, err := protocol.ReadByteArray(r, LightLength)
+	require.NoError(t, err)
+	assert.Equal(t, byte(15), light[0])
+	n, _ = protocol.ReadVarInt(r)
+	assert.Zero(t, n)
+	assert.Zero(t, r.Len())
+}
+
+func TestLevelData(t *testing.T) {
+	l := NewLevelData("Test", 42)
+	l.Spawn = BlockPos{X: 10, Y: 70, Z: -5}
+	l.DayTime = 6000
+	l.Weather.Raining = true
+	l.GameRules["keepInventory"] = "true"
+	tag := l.ToNBT()
+	tag.Put("WanderingTraderSpawnChance", &nbt.IntTag{Value: 25})
+
+	loaded := LevelDataFromNBT(tag)
+	assert.Equal(t, "Test", loaded.Name)
+	assert.Equal(t, int32(DataVersion), loaded.DataVersion)
+	assert.Equal(t, VersionName, loaded.VersionName)
+	assert.Equal(t, l.Spawn, loaded.Spawn)
+	assert.Equal(t, int64(6000), loaded.DayTime)
+	assert.True(t, loaded.Weather.Raining)
+	assert.True(t, loaded.GameRuleBool("keepInventory"))
+	assert.Equal(t, 3, loaded.GameRuleInt("randomTickSpeed"))
+	assert.Equal(t, int64(42), loaded.WorldGen.Seed)
+	assert.Equal(t, TheNether, loaded.WorldGen.Dimensions[TheNether].Type)
+	settings, _ := loaded.WorldGen.Dimensions[Overworld].Generator.GetString("settings")
+	assert.Equal(t, "minecraft:overworld", settings)
+
+	// Tags the type does not model survive.
+	again := loaded.ToNBT()
+	v, _ := again.GetInt("WanderingTraderSpawnChance")
+	assert.Equal(t, int32(25), v)
+	assert.True(t, nbt.CompareTags(tag, again, false))
+}
+
+func TestWorld(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "world")
+	w, err := Open(dir, testRegistry{})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "level.dat"))
+	assert.Equal(t, "world", w.Level().Name)
+	var names []string
+	for _, d := range w.Dimensions() {
+		names = append(names, d.Name)
+	}
+	assert.Equal(t, []string{Overworld, TheEnd, TheNether}, names)
+	nether, _ := w.Dimension(TheNether)
+	assert.Equal(t, filepath.Join(dir, "DIM-1"), nether.Dir())
+
+	custom, err := w.AddDimension("example:mining", DimensionType{Name: "example:deep", MinY: -128, Height: 512})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "dimensions", "example", "mining"), custom.Dir())
+	_, err = w.AddDimension("example:mining", OverworldType)
+	assert.Error(t, err)
+	_, err = w.AddDimension("example:odd", DimensionType{Name: "example:odd", Height: 100})
+	assert.Error(t, err)
+
+	// Chunks go to the dimension's own region files.
+	c := custom.NewChunk(ChunkPos{X: 40, Z: -3})
+	c.SetBlock(0, -128, 0, 1)
+	require.NoError(t, custom.SaveChunk(c))
+	assert.FileExists(t, filepath.Join(custom.Dir(), "region", "r.1.-1.mca"))
+	loaded, err := custom.LoadChunk(ChunkPos{X: 40, Z: -3})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), loaded.Block(0, -128, 0))
+	_, err = nether.LoadChunk(ChunkPos{X: 40, Z: -3})
+	assert.ErrorIs(t, err, region.ErrNotFound)
+
+	w.UpdateLevel(func(l *LevelData) { l.DayTime = 1234 })
+	require.NoError(t, w.Close())
+	assert.FileExists(t, filepath.Join(dir, "level.dat_old"))
+
+	w, err = Open(dir, testRegistry{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), w.Level().DayTime)
+	seed := w.Level().WorldGen.Seed
+	assert.Equal(t, "example:deep", w.Level().WorldGen.Dimensions["example:mining"].Type)
+	// Dimensions of types the world does not know wait for AddDimension.
+	_, ok := w.Dimension("example:mining")
+	assert.False(t, ok)
+	require.NoError(t, w.Close())
+
+	// A broken level.dat falls back to the backup.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "level.dat"), []byte("broken"), 0o644))
+	w, err = Open(dir, testRegistry{})
+	require.NoError(t, err)
+	assert.Equal(t, seed, w.Level().WorldGen.Seed)
+	require.NoError(t, w.Close())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "level.dat"), []byte("broken"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "level.dat_old")))
+	_, err = Open(dir, testRegistry{})
+	assert.Error(t, err)
+}
+
+// markerGenerator puts stone at the corner of each chunk.
+type markerGenerator struct{}
+
+func (markerGenerator) Generate(c *Chunk) error {
+	c.SetBlock(int(c.Pos.X)*16, 0, int(c.Pos.Z)*16, 1)
+	return nil
+}
+
+func TestChunkManager(t *testing.T) {
+	w, err := Open(t.TempDir(), testRegistry{})
+	require.NoError(t, err)
+	defer w.Close()
+	overworld, _ := w.Dimension(Overworld)
+	saved := overworld.NewChunk(ChunkPos{X: 1})
+	saved.SetBlock(16, 0, 0, 2)
+	require.NoError(t, overworld.SaveChunk(saved))
+
+	overworld.Generator = markerGenerator{}
+	m := NewChunkManager(overworld, ManagerConfig{Workers: 2, MaxCached: 1})
+	var loaded []ChunkPos
+	var unloaded []ChunkPos
+	m.OnLoad(func(c *Chunk) { loaded = append(loaded, c.Pos) })
+	m.OnUnload(func(pos ChunkPos) { unloaded = append(unloaded, pos) })
+
+	// Levels fall off by one per chunk from the ticket.
+	spawn := m.AddTicket(TicketSpawn, ChunkPos{}, 1)
+	assert.Equal(t, FullLevel-1, m.Level(ChunkPos{}))
+	assert.Equal(t, FullLevel, m.Level(ChunkPos{X: 1, Z: -1}))
+	assert.Greater(t, m.Level(ChunkPos{X: 2}), FullLevel)
+	m.TickUntilLoaded()
+	assert.Len(t, loaded, 9)
+	assert.Equal(t, uint32(2), m.Chunk(ChunkPos{X: 1}).Block(16, 0, 0))
+	assert.Equal(t, uint32(1), m.Chunk(ChunkPos{X: -1}).Block(-16, 0, 0))
+	assert.Nil(t, m.Chunk(ChunkPos{X: 2}))
+	stats := m.Stats()
+	assert.Equal(t, 9, stats.Loaded)
+	assert.Equal(t, 1, stats.Loads)
+	assert.Equal(t, 8, stats.Generated)
+	assert.Zero(t, stats.Queued+stats.Loading)
+
+	// A moved ticket loads new chunks and releases the old ones, which wait
+	// in the cache; those beyond it are saved and unloaded.
+	forced := m.AddTicket(TicketForced, ChunkPos{X: 10}, 0)
+	m.RemoveTicket(spawn)
+	m.TickUntilLoaded()
+	assert.Len(t, unloaded, 9)
+	assert.Equal(t, []ChunkPos{{X: 10}}, m.Loaded())
+	m.MoveTicket(forced, ChunkPos{X: -1})
+	m.TickUntilLoaded()
+	assert.Equal(t, []ChunkPos{{X: -1}}, m.Loaded())
+	// Closing saves the chunks still in memory: the generated ones and the
+	// one loaded from disk, which was saved before it had light.
+	require.NoError(t, m.Close())
+	stats = m.Stats()
+	assert.Equal(t, 1, stats.Cached)
+	assert.Equal(t, 10, stats.Saves)
+	assert.Zero(t, stats.PendingSaves)
+
+	// The saved chunks load back.
+	c, err := overworld.LoadChunk(ChunkPos{X: 10})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), c.Block(160, 0, 0))
+}
+
+func TestChunkManagerDirty(t *testing.T) {
+	w, err := Open(t.TempDir(), testRegistry{})
+	require.NoError(t, err)
+	defer w.Close()
+	overworld, _ := w.Dimension(Overworld)
+	m := NewChunkManager(overworld, ManagerConfig{AutosaveTicks: 2})
+	m.AddTicket(TicketPlugin, ChunkPos{}, 0)
+	m.TickUntilLoaded()
+	// Generated chunks are saved by the next autosave.
+	m.Tick()
+	require.NoError(t, m.Close())
+	assert.Equal(t, 1, m.Stats().Saves)
+
+	m = NewChunkManager(overworld, ManagerConfig{AutosaveTicks: 2})
+	m.AddTicket(TicketPlugin, ChunkPos{}, 0)
+	m.TickUntilLoaded()
+	m.Tick()
+	// Loaded chunks are saved only once changed.
+	m.Chunk(ChunkPos{}).SetBlock(0, 0, 0, 2)
+	m.MarkDirty(ChunkPos{})
+	require.NoError(t, m.Close())
+	assert.Equal(t, 1, m.Stats().Saves)
+	c, err := overworld.LoadChunk(ChunkPos{})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), c.Block(0, 0, 0))
+	assert.True(t, c.LightOn)
+
+	// Changing blocks relights the chunks, which the next tick reports.
+	m = NewChunkManager(overworld, ManagerConfig{})
+	defer m.Close()
+	m.AddTicket(TicketPlugin, ChunkPos{}, 1)
+	m.TickUntilLoaded()
+	var relit []ChunkPos
+	m.OnLightChange(func(c *Chunk) { relit = append(relit, c.Pos) })
+	old, ok := m.SetBlock(-1, 300, 0, 27)
+	require.True(t, ok)
+	assert.Equal(t, Air, old)
+	assert.Equal(t, 14, m.Chunk(ChunkPos{}).BlockLightAt(0, 300, 0))
+	m.Tick()
+	assert.ElementsMatch(t, []ChunkPos{{}, {X: -1}, {Z: -1}, {X: -1, Z: -1}}, relit)
+	_, ok = m.SetBlock(100, 300, 0, 27)
+	assert.False(t, ok)
+
+	// A negative cache keeps no chunks rather than failing.
+	m = NewChunkManager(overworld, ManagerConfig{Workers: -1, MaxCached: -1})
+	defer m.Close()
+	ticket := m.AddTicket(TicketPlugin, ChunkPos{}, 0)
+	m.TickUntilLoaded()
+	m.RemoveTicket(ticket)
+	m.Tick()
+	assert.Zero(t, m.Stats().Cached)
+	assert.Empty(t, m.Loaded())
+}
+
+func TestFlatGenerator(t *testing.T) {
+	reg := testRegistry{}
+	g, err := ParseFlatPreset(reg, "minecraft:bedrock,2*dirt, minecraft:stone;minecraft:desert;village")
+	require.NoError(t, err)
+	assert.Equal(t, []FlatLayer{{Block: 5, Height: 1}, {Block: 2, Height: 2}, {Block: 1, Height: 1}}, g.Layers)
+	c := NewChunk(reg, ChunkPos{X: 3}, -64, 384)
+	require.NoError(t, g.Generate(c))
+	assert.Equal(t, uint32(5), c.Block(48, -64, 0))
+	assert.Equal(t, uint32(2), c.Block(50, -62, 7))
+	assert.Equal(t, uint32(1), c.Block(63, -61, 15))
+	assert.Equal(t, Air, c.Block(48, -60, 0))
+	assert.Equal(t, uint32(2), c.Biome(48, 100, 0))
+	assert.Equal(t, -60, c.Heightmap(MotionBlocking).Get(5, 5))
+	assert.Equal(t, BlockPos{Y: -60}, g.FindSpawn(OverworldType))
+
+	// The saved form builds the same generator.
+	back, err := NewGenerator(reg, 0, DimensionSettings{Type: Overworld, Generator: g.ToNBT()})
+	require.NoError(t, err)
+	assert.Equal(t, g.Layers, back.(*FlatGenerator).Layers)
+	assert.Equal(t, g.Biome, back.(*FlatGenerator).Biome)
+
+	g, err = ParseFlatPreset(reg, "the_void")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), g.Biome)
+	c = NewChunk(reg, ChunkPos{}, -64, 384)
+	require.NoError(t, g.Generate(c))
+	assert.True(t, c.Section(-64).IsEmpty())
+
+	for _, bad := range []string{"0*minecraft:stone", "x*minecraft:stone", "minecraft:nothing"} {
+		_, err := ParseFlatPreset(reg, bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestNoiseGenerator(t *testing.T) {
+	reg := testRegistry{}
+	generate := func(seed int64, pos ChunkPos) *Chunk {
+		g, err := NewNoiseGenerator(reg, seed)
+		require.NoError(t, err)
+		c := NewChunk(reg, pos, -64, 384)
+		require.NoError(t, g.Generate(c))
+		return c
+	}
+	// The same seed gives the same chunk, whatever was generated before.
+	a := generate(42, ChunkPos{X: 5, Z: -9})
+	generate(42, ChunkPos{})
+	b := generate(42, ChunkPos{X: 5, Z: -9})
+	assert.Equal(t, nbt.ToCompactSNBT(a.ToNBT(DataVersion)), nbt.ToCompactSNBT(b.ToNBT(DataVersion)))
+	other := generate(43, ChunkPos{X: 5, Z: -9})
+	assert.NotEqual(t, nbt.ToCompactSNBT(a.ToNBT(DataVersion)), nbt.ToCompactSNBT(other.ToNBT(DataVersion)))
+
+	// Bedrock floors the world, deepslate and stone fill it and the top is
+	// land or sea.
+	bedrock, _ := reg.BlockStateID(blockNBT("minecraft:bedrock"))
+	deepslate, _ := reg.BlockStateID(blockNBT("minecraft:deepslate"))
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			require.Equal(t, bedrock, a.Block(x, -64, z))
+			top := a.Heightmap(MotionBlocking).Get(x, z)
+			require.GreaterOrEqual(t, top, SeaLevel)
+		}
+	}
+	counts := map[uint32]int{}
+	for _, s := range a.Sections {
+		s.Blocks.Count(func(v uint32, n int) { counts[v] += n })
+	}
+	assert.Greater(t, counts[deepslate], 16*16*32)
+	assert.Greater(t, counts[1], 16*16*32)
+
+	g, _ := NewNoiseGenerator(reg, 42)
+	spawn := g.FindSpawn(OverworldType)
+	c := NewChunk(reg, ChunkPosOf(spawn.X, spawn.Z), -64, 384)
+	require.NoError(t, g.Generate(c))
+	assert.Greater(t, spawn.Y, SeaLevel)
+	assert.NotEqual(t, Air, c.Block(spawn.X, spawn.Y-1, spawn.Z))
+	assert.Equal(t, Air, c.Block(spawn.X, spawn.Y, spawn.Z))
+}
+
+func TestNewGenerator(t *testing.T) {
+	reg := testRegistry{}
+	level := NewLevelData("world", 7)
+	g, err := NewGenerator(reg, 7, level.WorldGen.Dimensions[Overworld])
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), g.(*NoiseGenerator).Seed())
+	// The nether's noise is not supported yet.
+	g, err = NewGenerator(reg, 7, level.WorldGen.Dimensions[TheNether])
+	require.NoError(t, err)
+	assert.IsType(t, &VoidGenerator{}, g)
+
+	// New worlds spawn on land.
+	dir := t.TempDir()
+	w, err := OpenWith(dir, reg, level)
+	require.NoError(t, err)
+	defer w.Close()
+	assert.Greater(t, w.Level().Spawn.Y, SeaLevel)
+	overworld, _ := w.Dimension(Overworld)
+	c, err := overworld.GenerateChunk(ChunkPosOf(w.Level().Spawn.X, w.Level().Spawn.Z))
+	require.NoError(t, err)
+	assert.NotEqual(t, Air, c.Block(w.Level().Spawn.X, w.Level().Spawn.Y-1, w.Level().Spawn.Z))
+}
+
+func blockNBT(name string) *nbt.CompoundTag {
+	tag := nbt.NewCompoundTag()
+	tag.Put("Name", &nbt.StringTag{Value: name})
+	return tag
+}
+
+func TestEntityNBT(t *testing.T) {
+	// A pig as vanilla saves it.
+	tag, err := nbt.ParseSNBT(`{id: "minecraft:pig", Pos: [1.5d, 64.0d, -2.25d], Motion: [0.0d, -0.0784d, 0.0d],
+		Rotation: [90.0f, 10.0f], UUID: [I; 1, -2, 3, -4], OnGround: 1b, FallDistance: 0.0f, Fire: -1s, Air: 300s,
+		PortalCooldown: 0, Invulnerable: 0b, CustomName: '{"text":"Wilbur"}', Silent: 1b, Tags: ["farm"],
+		Health: 10.0f, Saddle: 1b, Passengers: [{id: "minecraft:zombie", Pos: [1.5d, 65.0d, -2.25d], IsBaby: 1b}]}`)
+	require.NoError(t, err)
+	e, err := EntityFromNBT(tag)
+	require.NoError(t, err)
+	assert.Equal(t, "minecraft:pig", e.Type)
+	assert.Equal(t, Vec3{1.5, 64, -2.25}, e.Pos)
+	assert.Equal(t, Vec3{0, -0.0784, 0}, e.Motion)
+	assert.Equal(t, []float32{90, 10, 90}, []float32{e.Yaw, e.Pitch, e.HeadYaw})
+	assert.Equal(t, "00000001-ffff-fffe-0000-0003fffffffc", e.UUID.String())
+	assert.True(t, e.OnGround)
+	assert.True(t, e.Silent)
+	assert.Equal(t, int16(-1), e.Fire)
+	assert.Equal(t, []string{"farm"}, e.Tags)
+	require.Len(t, e.Passengers, 1)
+	assert.Equal(t, "minecraft:zombie", e.Passengers[0].Type)
+	// The zombie saved no air, so it has the default.
+	assert.Equal(t, int16(defaultAir), e.Passengers[0].Air)
+	assert.Len(t, e.Extra.Value, 2)
+	assert.Contains(t, e.Extra.Value, "Health")
+	assert.Contains(t, e.Extra.Value, "Saddle")
+
+	// Saving it gives back what was loaded, with the defaults of the
+	// passenger filled in.
+	out := e.ToNBT()
+	passengers, _ := out.GetList("Passengers")
+	require.Len(t, passengers.Value, 1)
+	zombie := passengers.Value[0].(*nbt.CompoundTag)
+	assert.Equal(t, "minecraft:zombie", stringValue(zombie.Value["id"]))
+	assert.Equal(t, int64(1), intValue(zombie.Value["IsBaby"]))
+	delete(out.Value, "Passengers")
+	delete(tag.Value, "Passengers")
+	assert.True(t, nbt.CompareTags(tag, out, false), "got %s", out)
+
+	// Metadata holds the fields every entity has.
+	meta := e.Metadata()
+	require.Len(t, meta, 8)
+	assert.Equal(t, protocol.MetadataBoolEntry(4, true), meta[4])
+	assert.Equal(t, protocol.MetadataOptionalText, meta[2].Type)
+	assert.Equal(t, byte(1), meta[2].Value[0])
+	e.Fire, e.Glowing = 20, true
+	assert.Equal(t, protocol.MetadataByteEntry(0, EntityOnFire|EntityGlowing), e.Metadata()[0])
+
+	_, err = EntityFromNBT(nbt.NewCompoundTag())
+	assert.Error(t, err)
+}
+
+func TestEntityStorage(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(dir, testRegistry{})
+	require.NoError(t, err)
+	overworld, _ := w.Dimension(Overworld)
+	pos := ChunkPos{X: 3, Z: -1}
+	_, err = overworld.LoadEntities(pos)
+	assert.ErrorIs(t, err, region.ErrNotFound)
+
+	pig := NewEntity("minecraft:pig", Vec3{X: 50, Y: 64, Z: -10})
+	cow := NewEntity("minecraft:cow", Vec3{X: 51, Y: 64, Z: -11})
+	assert.NotEqual(t, pig.UUID, cow.UUID)
+	require.NoError(t, overworld.SaveEntities(pos, []*Entity{pig, cow}))
+	assert.FileExists(t, filepath.Join(dir, "entities", "r.0.-1.mca"))
+	require.NoError(t, w.Close())
+
+	w, err = Open(dir, testRegistry{})
+	require.NoError(t, err)
+	defer w.Close()
+	overworld, _ = w.Dimension(Overworld)
+	loaded, err := overworld.LoadEntities(pos)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, []string{"minecraft:pig", "minecraft:cow"}, []string{loaded[0].Type, loaded[1].Type})
+	assert.Equal(t, pig.UUID, loaded[0].UUID)
+	assert.Equal(t, cow.Pos, loaded[1].Pos)
+
+	// A chunk without entities is removed.
+	require.NoError(t, overworld.SaveEntities(pos, nil))
+	_, err = overworld.LoadEntities(pos)
+	assert.ErrorIs(t, err, region.ErrNotFound)
+}